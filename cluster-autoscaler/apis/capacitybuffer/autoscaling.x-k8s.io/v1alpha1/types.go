@@ -89,6 +89,34 @@ type ScalableRef struct {
 	Name string `json:"name" protobuf:"bytes,3,opt,name=name"`
 }
 
+// BufferSchedule sizes the buffer during a recurring time window, e.g. more buffer chunks during business hours.
+type BufferSchedule struct {
+	// Name of the schedule, reported in the buffer status while the schedule is active.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name" protobuf:"bytes,1,opt,name=name"`
+	// Start is a standard 5 fields cron expression (minute, hour, day of month, month and day of week)
+	// of when the schedule's window starts, e.g. "0 9 * * mon-fri".
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Start string `json:"start" protobuf:"bytes,2,opt,name=start"`
+	// Duration of the schedule's window, e.g. "8h".
+	// +kubebuilder:validation:Required
+	Duration metav1.Duration `json:"duration" protobuf:"bytes,3,opt,name=duration"`
+	// TimeZone is the IANA name of the time zone of the start cron expression, e.g. "America/New_York".
+	// Defaults to UTC.
+	// +optional
+	TimeZone *string `json:"timeZone,omitempty" protobuf:"bytes,4,opt,name=timeZone"`
+	// Replicas replaces the buffer's `replicas` while the schedule is active.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Replicas *int32 `json:"replicas,omitempty" protobuf:"varint,5,opt,name=replicas"`
+	// Percentage replaces the buffer's `percentage` while the schedule is active.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Percentage *int32 `json:"percentage,omitempty" protobuf:"varint,6,opt,name=percentage"`
+}
+
 // ResourceList is a set of (resource name, quantity) pairs.
 // This is mirroring k8s.io/api/core/v1.ResourceList to avoid direct dependency.
 // +k8s:openapi-gen=true
@@ -100,6 +128,7 @@ type ResourceList map[ResourceName]resource.Quantity
 type CapacityBufferSpec struct {
 	// ProvisioningStrategy defines how the buffer is utilized.
 	// "buffer.x-k8s.io/active-capacity" is the default strategy, where the buffer actively scales up the cluster by creating placeholder pods.
+	// "buffer.x-k8s.io/standby-capacity" holds the buffer as standby nodes, tainted until pending pods need them.
 	// +kubebuilder:default="buffer.x-k8s.io/active-capacity"
 	// +optional
 	ProvisioningStrategy *string `json:"provisioningStrategy,omitempty" protobuf:"bytes,1,opt,name=provisioningStrategy"`
@@ -144,6 +173,14 @@ type CapacityBufferSpec struct {
 	// this will be used to create as many chunks as fit into these limits.
 	// +optional
 	Limits *ResourceList `json:"limits,omitempty" protobuf:"bytes,6,opt,name=limits"`
+
+	// Schedules size the buffer differently during recurring time windows. While a schedule is
+	// active, its `replicas` and `percentage` replace the buffer's ones. When several schedules
+	// are active, the first one in the list is used.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Schedules []BufferSchedule `json:"schedules,omitempty" protobuf:"bytes,7,rep,name=schedules"`
 }
 
 // CapacityBufferStatus defines the observed state of CapacityBuffer.
@@ -176,6 +213,12 @@ type CapacityBufferStatus struct {
 	// ProvisioningStrategy defines how the buffer should be utilized.
 	// +optional
 	ProvisioningStrategy *string `json:"provisioningStrategy,omitempty" protobuf:"bytes,5,opt,name=provisioningStrategy"`
+	// ActiveSchedule is the name of the schedule the buffer is currently sized by, if any.
+	// +optional
+	ActiveSchedule *string `json:"activeSchedule,omitempty" protobuf:"bytes,6,opt,name=activeSchedule"`
+	// NextScheduleTransition is the next time a schedule of the buffer starts or ends, changing its size.
+	// +optional
+	NextScheduleTransition *metav1.Time `json:"nextScheduleTransition,omitempty" protobuf:"bytes,7,opt,name=nextScheduleTransition"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
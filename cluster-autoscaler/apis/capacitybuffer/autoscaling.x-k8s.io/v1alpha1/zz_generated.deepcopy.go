@@ -27,6 +27,38 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BufferSchedule) DeepCopyInto(out *BufferSchedule) {
+	*out = *in
+	out.Duration = in.Duration
+	if in.TimeZone != nil {
+		in, out := &in.TimeZone, &out.TimeZone
+		*out = new(string)
+		**out = **in
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Percentage != nil {
+		in, out := &in.Percentage, &out.Percentage
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BufferSchedule.
+func (in *BufferSchedule) DeepCopy() *BufferSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(BufferSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CapacityBuffer) DeepCopyInto(out *CapacityBuffer) {
 	*out = *in
@@ -127,6 +159,13 @@ func (in *CapacityBufferSpec) DeepCopyInto(out *CapacityBufferSpec) {
 			}
 		}
 	}
+	if in.Schedules != nil {
+		in, out := &in.Schedules, &out.Schedules
+		*out = make([]BufferSchedule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -170,6 +209,15 @@ func (in *CapacityBufferStatus) DeepCopyInto(out *CapacityBufferStatus) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.ActiveSchedule != nil {
+		in, out := &in.ActiveSchedule, &out.ActiveSchedule
+		*out = new(string)
+		**out = **in
+	}
+	if in.NextScheduleTransition != nil {
+		in, out := &in.NextScheduleTransition, &out.NextScheduleTransition
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
@@ -31,6 +31,7 @@ type CapacityBufferSpecApplyConfiguration struct {
 	Replicas             *int32                                  `json:"replicas,omitempty"`
 	Percentage           *int32                                  `json:"percentage,omitempty"`
 	Limits               *autoscalingxk8siov1alpha1.ResourceList `json:"limits,omitempty"`
+	Schedules            []BufferScheduleApplyConfiguration      `json:"schedules,omitempty"`
 }
 
 // CapacityBufferSpecApplyConfiguration constructs a declarative configuration of the CapacityBufferSpec type for use with
@@ -86,3 +87,16 @@ func (b *CapacityBufferSpecApplyConfiguration) WithLimits(value autoscalingxk8si
 	b.Limits = &value
 	return b
 }
+
+// WithSchedules adds the given value to the Schedules field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Schedules field.
+func (b *CapacityBufferSpecApplyConfiguration) WithSchedules(values ...*BufferScheduleApplyConfiguration) *CapacityBufferSpecApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithSchedules")
+		}
+		b.Schedules = append(b.Schedules, *values[i])
+	}
+	return b
+}
@@ -19,17 +19,20 @@ limitations under the License.
 package v1alpha1
 
 import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	v1 "k8s.io/client-go/applyconfigurations/meta/v1"
 )
 
 // CapacityBufferStatusApplyConfiguration represents a declarative configuration of the CapacityBufferStatus type for use
 // with apply.
 type CapacityBufferStatusApplyConfiguration struct {
-	PodTemplateRef        *LocalObjectRefApplyConfiguration `json:"podTemplateRef,omitempty"`
-	Replicas              *int32                            `json:"replicas,omitempty"`
-	PodTemplateGeneration *int64                            `json:"podTemplateGeneration,omitempty"`
-	Conditions            []v1.ConditionApplyConfiguration  `json:"conditions,omitempty"`
-	ProvisioningStrategy  *string                           `json:"provisioningStrategy,omitempty"`
+	PodTemplateRef         *LocalObjectRefApplyConfiguration `json:"podTemplateRef,omitempty"`
+	Replicas               *int32                            `json:"replicas,omitempty"`
+	PodTemplateGeneration  *int64                            `json:"podTemplateGeneration,omitempty"`
+	Conditions             []v1.ConditionApplyConfiguration  `json:"conditions,omitempty"`
+	ProvisioningStrategy   *string                           `json:"provisioningStrategy,omitempty"`
+	ActiveSchedule         *string                           `json:"activeSchedule,omitempty"`
+	NextScheduleTransition *metav1.Time                      `json:"nextScheduleTransition,omitempty"`
 }
 
 // CapacityBufferStatusApplyConfiguration constructs a declarative configuration of the CapacityBufferStatus type for use with
@@ -82,3 +85,19 @@ func (b *CapacityBufferStatusApplyConfiguration) WithProvisioningStrategy(value
 	b.ProvisioningStrategy = &value
 	return b
 }
+
+// WithActiveSchedule sets the ActiveSchedule field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ActiveSchedule field is set to the value of the last call.
+func (b *CapacityBufferStatusApplyConfiguration) WithActiveSchedule(value string) *CapacityBufferStatusApplyConfiguration {
+	b.ActiveSchedule = &value
+	return b
+}
+
+// WithNextScheduleTransition sets the NextScheduleTransition field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the NextScheduleTransition field is set to the value of the last call.
+func (b *CapacityBufferStatusApplyConfiguration) WithNextScheduleTransition(value metav1.Time) *CapacityBufferStatusApplyConfiguration {
+	b.NextScheduleTransition = &value
+	return b
+}
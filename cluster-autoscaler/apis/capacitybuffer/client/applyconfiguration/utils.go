@@ -32,6 +32,8 @@ import (
 func ForKind(kind schema.GroupVersionKind) interface{} {
 	switch kind {
 	// Group=autoscaling.x-k8s.io, Version=v1alpha1
+	case v1alpha1.SchemeGroupVersion.WithKind("BufferSchedule"):
+		return &autoscalingxk8siov1alpha1.BufferScheduleApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("CapacityBuffer"):
 		return &autoscalingxk8siov1alpha1.CapacityBufferApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("CapacityBufferSpec"):
@@ -201,6 +201,9 @@ const (
 	// ProvisioningClassBestEffortAtomicScaleUp denotes that CA try to provision the capacity
 	// in an atomic manner.
 	ProvisioningClassBestEffortAtomicScaleUp string = "best-effort-atomic-scale-up.autoscaling.x-k8s.io"
+	// ProvisioningClassCapacityReservation denotes that CA will provision the capacity in an atomic manner
+	// and reserve it until the pods consuming the request are scheduled or the reservation TTL expires.
+	ProvisioningClassCapacityReservation string = "capacity-reservation.autoscaling.x-k8s.io"
 	// ProvisioningRequestPodAnnotationKey is a key used to annotate pods consuming provisioning request.
 	ProvisioningRequestPodAnnotationKey = "autoscaling.x-k8s.io/consume-provisioning-request"
 	// ProvisioningClassPodAnnotationKey is a key used to add annotation about Provisioning Class
@@ -40,6 +40,7 @@ import (
 	autoscalingapi "k8s.io/api/autoscaling/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/discovery/cached/memory"
@@ -56,6 +57,7 @@ type CapacityBufferClient struct {
 	kubernetesClient      kubernetes.Interface
 	scaleGetter           scaleclient.ScalesGetter
 	scaleMapper           meta.RESTMapper
+	dynamicClient         dynamic.Interface
 	buffersLister         bufferslisters.CapacityBufferLister
 	podTemplateLister     corev1listers.PodTemplateLister
 	replicaSetsLister     appsv1listers.ReplicaSetLister
@@ -99,7 +101,11 @@ func NewCapacityBufferClientFromConfig(kubeConfig *rest.Config) (*CapacityBuffer
 	if err != nil {
 		return nil, fmt.Errorf("Failed to create scale getter for capacity buffer: %v", err)
 	}
-	return NewCapacityBufferClientFromClients(buffersClient, kubernetesClient, scaleGetter, scaleMapper)
+	dynamicClient, err := dynamic.NewForConfig(kubeConfig)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create dynamic client for capacity buffer: %v", err)
+	}
+	return NewCapacityBufferClientFromClients(buffersClient, kubernetesClient, scaleGetter, scaleMapper, dynamicClient)
 }
 
 func createScaleSubresourceClientGetter(kubeConfig *rest.Config) (scaleclient.ScalesGetter, meta.RESTMapper, error) {
@@ -117,7 +123,7 @@ func createScaleSubresourceClientGetter(kubeConfig *rest.Config) (scaleclient.Sc
 }
 
 // NewCapacityBufferClientFromClients returns a CapacityBufferClient based on the passed clients
-func NewCapacityBufferClientFromClients(buffersClient capacitybuffer.Interface, kubernetesClient kubernetes.Interface, scaleGetter scaleclient.ScalesGetter, scaleMapper meta.RESTMapper) (*CapacityBufferClient, error) {
+func NewCapacityBufferClientFromClients(buffersClient capacitybuffer.Interface, kubernetesClient kubernetes.Interface, scaleGetter scaleclient.ScalesGetter, scaleMapper meta.RESTMapper, dynamicClient dynamic.Interface) (*CapacityBufferClient, error) {
 	if buffersClient == nil || kubernetesClient == nil {
 		return nil, fmt.Errorf("Couldn't create capacity buffer client")
 	}
@@ -134,6 +140,7 @@ func NewCapacityBufferClientFromClients(buffersClient capacitybuffer.Interface,
 		kubernetesClient:      kubernetesClient,
 		scaleGetter:           scaleGetter,
 		scaleMapper:           scaleMapper,
+		dynamicClient:         dynamicClient,
 		buffersLister:         buffersLister,
 		podTemplateLister:     factory.Core().V1().PodTemplates().Lister(),
 		replicaSetsLister:     factory.Apps().V1().ReplicaSets().Lister(),
@@ -325,6 +332,22 @@ func (c *CapacityBufferClient) GetScaleObject(namespace, group, kind, name strin
 	return obj, nil
 }
 
+// GetScalableObject resolves the api group and kind to a resource and gets the object with the passed name from the passed namespace
+func (c *CapacityBufferClient) GetScalableObject(namespace, group, kind, name string) (*unstructured.Unstructured, error) {
+	if c.scaleMapper == nil || c.dynamicClient == nil {
+		return nil, fmt.Errorf("Capacity buffer client is not configured for getting scalable objects")
+	}
+	mapping, err := c.scaleMapper.RESTMapping(schema.GroupKind{Group: group, Kind: kind}, "")
+	if err != nil {
+		return nil, err
+	}
+	obj, err := c.dynamicClient.Resource(mapping.Resource).Namespace(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scalable object: %w", err)
+	}
+	return obj, nil
+}
+
 // GetPodsBySelector resolves the api group and kind to group resource and use it to get the scale sub-resource with passed name from the passed namespace
 func (c *CapacityBufferClient) GetPodsBySelector(namespace, selector string) ([]corev1.Pod, error) {
 	if c.kubernetesClient == nil {
@@ -69,7 +69,7 @@ func TestClientGetPodTemplate(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			fakeKubernetesClient := fakeclient.NewSimpleClientset(test.objectsInKubernetesClient...)
 			fakeBuffersClient := buffersfake.NewSimpleClientset()
-			fakeCapacityBuffersClient, _ := NewCapacityBufferClientFromClients(fakeBuffersClient, fakeKubernetesClient, nil, nil)
+			fakeCapacityBuffersClient, _ := NewCapacityBufferClientFromClients(fakeBuffersClient, fakeKubernetesClient, nil, nil, nil)
 			pt, err := fakeCapacityBuffersClient.GetPodTemplate("default", test.objectName)
 			assert.Equal(t, err != nil, test.expectError)
 			assert.Equal(t, pt, test.expectedValue)
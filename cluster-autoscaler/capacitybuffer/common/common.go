@@ -19,14 +19,19 @@ package common
 import (
 	"time"
 
+	apiv1 "k8s.io/api/core/v1"
 	v1 "k8s.io/autoscaler/cluster-autoscaler/apis/capacitybuffer/autoscaling.x-k8s.io/v1alpha1"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // Constants to use in Capacity Buffers objects
 const (
 	ActiveProvisioningStrategy    = "buffer.x-k8s.io/active-capacity"
+	StandbyProvisioningStrategy   = "buffer.x-k8s.io/standby-capacity"
+	StandbyNodeTaintKey           = "buffer.x-k8s.io/standby"
 	CapacityBufferKind            = "CapacityBuffer"
 	CapacityBufferApiVersion      = "autoscaling.x-k8s.io/v1alpha1"
 	ReadyForProvisioningCondition = "ReadyForProvisioning"
@@ -35,6 +40,36 @@ const (
 	ConditionFalse                = "False"
 )
 
+// Reasons of the ReadyForProvisioning condition of buffers not ready for provisioning
+const (
+	// ForbiddenReason is used when the autoscaler isn't allowed to get the objects the buffer references, e.g. the scale subresource of a custom resource
+	ForbiddenReason = "Forbidden"
+	// NotFoundReason is used when the objects the buffer references don't exist
+	NotFoundReason = "NotFound"
+	// UnsupportedKindReason is used when the kind the buffer references isn't served by the API server
+	UnsupportedKindReason = "UnsupportedKind"
+	// ErrorReason is used for any other error
+	ErrorReason = "error"
+)
+
+// StandbyNodeTaint returns the taint keeping pods off standby nodes, holding the capacity of standby buffers
+func StandbyNodeTaint() apiv1.Taint {
+	return apiv1.Taint{
+		Key:    StandbyNodeTaintKey,
+		Effect: apiv1.TaintEffectNoSchedule,
+	}
+}
+
+// IsStandbyNode returns whether the node is a standby node, holding the capacity of standby buffers
+func IsStandbyNode(node *apiv1.Node) bool {
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == StandbyNodeTaintKey {
+			return true
+		}
+	}
+	return false
+}
+
 // SetBufferAsReadyForProvisioning updates the passed buffer object with the rest of the attributes and sets its condition to ready
 func SetBufferAsReadyForProvisioning(buffer *v1.CapacityBuffer, PodTemplateRef *v1.LocalObjectRef, podTemplateGeneration *int64, replicas *int32, provStrategy *string) {
 	buffer.Status.PodTemplateRef = PodTemplateRef
@@ -66,12 +101,27 @@ func SetBufferAsNotReadyForProvisioning(buffer *v1.CapacityBuffer, PodTemplateRe
 		Type:               ReadyForProvisioningCondition,
 		Status:             ConditionFalse,
 		Message:            errorMessage,
-		Reason:             "error",
+		Reason:             notReadyReason(err),
 		LastTransitionTime: metav1.Time{Time: time.Now()},
 	}
 	buffer.Status.Conditions = []metav1.Condition{notReadyCondition}
 }
 
+// notReadyReason returns the reason of the ReadyForProvisioning condition for the passed error
+func notReadyReason(err error) string {
+	switch {
+	case err == nil:
+		return ErrorReason
+	case apierrors.IsForbidden(err):
+		return ForbiddenReason
+	case meta.IsNoMatchError(err):
+		return UnsupportedKindReason
+	case apierrors.IsNotFound(err):
+		return NotFoundReason
+	}
+	return ErrorReason
+}
+
 func mapEmptyProvStrategyToDefault(ps *string) *string {
 	if ps != nil && *ps == "" {
 		defaultProvStrategy := ActiveProvisioningStrategy
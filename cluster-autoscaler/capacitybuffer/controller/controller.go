@@ -19,6 +19,7 @@ package controller
 import (
 	"time"
 
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/klog/v2"
 
 	cbclient "k8s.io/autoscaler/cluster-autoscaler/capacitybuffer/client"
@@ -70,14 +71,16 @@ func NewBufferController(
 	}
 }
 
-// NewDefaultBufferController creates bufferController with default configs
+// NewDefaultBufferController creates bufferController with default configs, reading the pod templates
+// of the scalable objects of the kinds in podTemplatePaths at their field path
 func NewDefaultBufferController(
 	client *cbclient.CapacityBufferClient,
+	podTemplatePaths map[schema.GroupKind]string,
 ) BufferController {
 	return &bufferController{
 		client: client,
 		// Accepting empty string as it represents nil value for ProvisioningStrategy
-		strategyFilter: filters.NewStrategyFilter([]string{common.ActiveProvisioningStrategy, ""}),
+		strategyFilter: filters.NewStrategyFilter([]string{common.ActiveProvisioningStrategy, common.StandbyProvisioningStrategy, ""}),
 		statusFilter: filter.NewCombinedAnyFilter(
 			[]filters.Filter{
 				filters.NewStatusFilter(map[string]string{
@@ -86,12 +89,13 @@ func NewDefaultBufferController(
 				}),
 				filters.NewBufferGenerationChangedFilter(),
 				filters.NewPodTemplateGenerationChangedFilter(client),
+				filters.NewScheduleTransitionFilter(),
 			},
 		),
 		translator: translators.NewCombinedTranslator(
 			[]translators.Translator{
 				translators.NewPodTemplateBufferTranslator(client),
-				translators.NewDefaultScalableObjectsTranslator(client),
+				translators.NewDefaultScalableObjectsTranslator(client, podTemplatePaths),
 				translators.NewResourceLimitsTranslator(client),
 			},
 		),
@@ -0,0 +1,55 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"time"
+
+	v1 "k8s.io/autoscaler/cluster-autoscaler/apis/capacitybuffer/autoscaling.x-k8s.io/v1alpha1"
+)
+
+// scheduleTransitionFilter filters in buffers that a schedule started or ended for since they were translated
+type scheduleTransitionFilter struct {
+	now func() time.Time
+}
+
+// NewScheduleTransitionFilter creates an instance of scheduleTransitionFilter that filters the buffers that need to be resized.
+func NewScheduleTransitionFilter() *scheduleTransitionFilter {
+	return &scheduleTransitionFilter{
+		now: time.Now,
+	}
+}
+
+// Filter returns the buffers that passed their next schedule transition as the filtered buffers
+func (f *scheduleTransitionFilter) Filter(buffersToFilter []*v1.CapacityBuffer) ([]*v1.CapacityBuffer, []*v1.CapacityBuffer) {
+	var buffers []*v1.CapacityBuffer
+	var filteredOutBuffers []*v1.CapacityBuffer
+
+	now := f.now()
+	for _, buffer := range buffersToFilter {
+		if transition := buffer.Status.NextScheduleTransition; transition != nil && !transition.After(now) {
+			buffers = append(buffers, buffer)
+		} else {
+			filteredOutBuffers = append(filteredOutBuffers, buffer)
+		}
+	}
+	return buffers, filteredOutBuffers
+}
+
+// CleanUp cleans up the filter's internal structures.
+func (f *scheduleTransitionFilter) CleanUp() {
+}
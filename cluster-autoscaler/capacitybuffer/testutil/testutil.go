@@ -116,3 +116,10 @@ func GetConditionNotReady() []metav1.Condition {
 	}
 	return []metav1.Condition{notReadyCondition}
 }
+
+// GetConditionNotReadyWithReason returns a list of conditions with a condition not ready with the passed reason and empty message, should be used for testing purposes only
+func GetConditionNotReadyWithReason(reason string) []metav1.Condition {
+	conditions := GetConditionNotReady()
+	conditions[0].Reason = reason
+	return conditions
+}
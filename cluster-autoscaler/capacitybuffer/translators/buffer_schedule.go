@@ -0,0 +1,69 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package translator
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	v1 "k8s.io/autoscaler/cluster-autoscaler/apis/capacitybuffer/autoscaling.x-k8s.io/v1alpha1"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/schedule"
+)
+
+// bufferSizing is the number of chunks a buffer asks for, as replicas and as a percentage of its scalable object.
+type bufferSizing struct {
+	replicas   *int32
+	percentage *int32
+}
+
+// resolveBufferSchedule publishes in the buffer status its schedule active at the given time and its next schedule
+// transition, and returns the buffer sizing at that time: the one of its first active schedule, or its spec's one.
+func resolveBufferSchedule(buffer *v1.CapacityBuffer, now time.Time) (bufferSizing, error) {
+	buffer.Status.ActiveSchedule = nil
+	buffer.Status.NextScheduleTransition = nil
+	sizing := bufferSizing{replicas: buffer.Spec.Replicas, percentage: buffer.Spec.Percentage}
+	var nextTransition time.Time
+	for _, bufferSchedule := range buffer.Spec.Schedules {
+		window, err := scheduleWindow(bufferSchedule)
+		if err != nil {
+			return bufferSizing{}, fmt.Errorf("Invalid schedule %v for buffer %v: %v", bufferSchedule.Name, buffer.Name, err)
+		}
+		if buffer.Status.ActiveSchedule == nil && window.Active(now) {
+			buffer.Status.ActiveSchedule = &bufferSchedule.Name
+			sizing = bufferSizing{replicas: bufferSchedule.Replicas, percentage: bufferSchedule.Percentage}
+		}
+		if transition := window.NextTransition(now); !transition.IsZero() && (nextTransition.IsZero() || transition.Before(nextTransition)) {
+			nextTransition = transition
+		}
+	}
+	if !nextTransition.IsZero() {
+		buffer.Status.NextScheduleTransition = &metav1.Time{Time: nextTransition}
+	}
+	return sizing, nil
+}
+
+func scheduleWindow(bufferSchedule v1.BufferSchedule) (schedule.Window, error) {
+	location := time.UTC
+	if bufferSchedule.TimeZone != nil {
+		var err error
+		if location, err = time.LoadLocation(*bufferSchedule.TimeZone); err != nil {
+			return schedule.Window{}, err
+		}
+	}
+	return schedule.NewWindow(bufferSchedule.Start, bufferSchedule.Duration.Duration, location)
+}
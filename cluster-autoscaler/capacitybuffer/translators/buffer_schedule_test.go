@@ -0,0 +1,144 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package translator
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakeClient "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/autoscaler/cluster-autoscaler/apis/capacitybuffer/autoscaling.x-k8s.io/v1alpha1"
+	cbclient "k8s.io/autoscaler/cluster-autoscaler/capacitybuffer/client"
+	"k8s.io/autoscaler/cluster-autoscaler/capacitybuffer/testutil"
+)
+
+var (
+	businessHoursReplicas = int32(20)
+	weekendPercentage     = int32(50)
+	newYork               = "America/New_York"
+	businessHours         = v1.BufferSchedule{
+		Name:     "business-hours",
+		Start:    "0 9 * * mon-fri",
+		Duration: metav1.Duration{Duration: 8 * time.Hour},
+		TimeZone: &newYork,
+		Replicas: &businessHoursReplicas,
+	}
+	weekend = v1.BufferSchedule{
+		Name:       "weekend",
+		Start:      "0 0 * * sat",
+		Duration:   metav1.Duration{Duration: 48 * time.Hour},
+		Percentage: &weekendPercentage,
+	}
+)
+
+func TestResolveBufferSchedule(t *testing.T) {
+	tests := []struct {
+		name                   string
+		schedules              []v1.BufferSchedule
+		now                    time.Time
+		expectedSizing         bufferSizing
+		expectedActiveSchedule *string
+		expectedNextTransition *metav1.Time
+		expectError            bool
+	}{
+		{
+			name:           "no schedules",
+			now:            time.Date(2026, time.October, 16, 14, 0, 0, 0, time.UTC),
+			expectedSizing: bufferSizing{replicas: &testutil.SomeNumberOfReplicas},
+		},
+		{
+			name:                   "during business hours",
+			schedules:              []v1.BufferSchedule{businessHours, weekend},
+			now:                    time.Date(2026, time.October, 16, 14, 0, 0, 0, time.UTC),
+			expectedSizing:         bufferSizing{replicas: &businessHoursReplicas},
+			expectedActiveSchedule: &businessHours.Name,
+			expectedNextTransition: &metav1.Time{Time: time.Date(2026, time.October, 16, 21, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:                   "overnight",
+			schedules:              []v1.BufferSchedule{businessHours, weekend},
+			now:                    time.Date(2026, time.October, 16, 22, 0, 0, 0, time.UTC),
+			expectedSizing:         bufferSizing{replicas: &testutil.SomeNumberOfReplicas},
+			expectedNextTransition: &metav1.Time{Time: time.Date(2026, time.October, 17, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:                   "on weekends",
+			schedules:              []v1.BufferSchedule{businessHours, weekend},
+			now:                    time.Date(2026, time.October, 17, 10, 0, 0, 0, time.UTC),
+			expectedSizing:         bufferSizing{percentage: &weekendPercentage},
+			expectedActiveSchedule: &weekend.Name,
+			expectedNextTransition: &metav1.Time{Time: time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:        "invalid schedule",
+			schedules:   []v1.BufferSchedule{{Name: "invalid", Start: "0 9 * *", Duration: metav1.Duration{Duration: time.Hour}}},
+			now:         time.Date(2026, time.October, 16, 14, 0, 0, 0, time.UTC),
+			expectError: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			buffer := testutil.GetPodTemplateRefBuffer(&v1.LocalObjectRef{Name: testutil.SomePodTemplateRefName}, &testutil.SomeNumberOfReplicas)
+			buffer.Spec.Schedules = test.schedules
+			sizing, err := resolveBufferSchedule(buffer, test.now)
+			if test.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedSizing, sizing)
+			assert.Equal(t, test.expectedActiveSchedule, buffer.Status.ActiveSchedule)
+			if test.expectedNextTransition == nil {
+				assert.Nil(t, buffer.Status.NextScheduleTransition)
+			} else {
+				assert.True(t, test.expectedNextTransition.Equal(buffer.Status.NextScheduleTransition), "next transition %v", buffer.Status.NextScheduleTransition)
+			}
+		})
+	}
+}
+
+func TestPodTemplateBufferTranslatorSchedules(t *testing.T) {
+	registeredPodTemplate := &corev1.PodTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       testutil.SomePodTemplateRefName,
+			Namespace:  "default",
+			Generation: 1,
+		},
+	}
+	fakeClient := fakeClient.NewSimpleClientset(registeredPodTemplate)
+	fakeCapacityBuffersClient, _ := cbclient.NewCapacityBufferClient(nil, fakeClient, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	buffer := testutil.GetPodTemplateRefBuffer(&v1.LocalObjectRef{Name: registeredPodTemplate.Name}, &testutil.SomeNumberOfReplicas)
+	buffer.Spec.Schedules = []v1.BufferSchedule{businessHours}
+	translator := NewPodTemplateBufferTranslator(fakeCapacityBuffersClient)
+	translator.now = func() time.Time { return time.Date(2026, time.October, 16, 14, 0, 0, 0, time.UTC) }
+	errors := translator.Translate([]*v1.CapacityBuffer{buffer})
+	assert.Empty(t, errors)
+	assert.Equal(t, &businessHoursReplicas, buffer.Status.Replicas)
+	assert.Equal(t, &businessHours.Name, buffer.Status.ActiveSchedule)
+
+	// Once business hours end, the buffer shrinks back to its replicas.
+	translator.now = func() time.Time { return time.Date(2026, time.October, 16, 21, 0, 0, 0, time.UTC) }
+	errors = translator.Translate([]*v1.CapacityBuffer{buffer})
+	assert.Empty(t, errors)
+	assert.Equal(t, &testutil.SomeNumberOfReplicas, buffer.Status.Replicas)
+	assert.Nil(t, buffer.Status.ActiveSchedule)
+}
@@ -18,6 +18,7 @@ package translator
 
 import (
 	"fmt"
+	"time"
 
 	v1 "k8s.io/autoscaler/cluster-autoscaler/apis/capacitybuffer/autoscaling.x-k8s.io/v1alpha1"
 	cbclient "k8s.io/autoscaler/cluster-autoscaler/capacitybuffer/client"
@@ -27,12 +28,14 @@ import (
 // podTemplateBufferTranslator translates podTemplateRef buffers specs to fill their status.
 type podTemplateBufferTranslator struct {
 	client *cbclient.CapacityBufferClient
+	now    func() time.Time
 }
 
 // NewPodTemplateBufferTranslator creates an instance of podTemplateBufferTranslator.
 func NewPodTemplateBufferTranslator(client *cbclient.CapacityBufferClient) *podTemplateBufferTranslator {
 	return &podTemplateBufferTranslator{
 		client: client,
+		now:    time.Now,
 	}
 }
 
@@ -50,7 +53,13 @@ func (t *podTemplateBufferTranslator) Translate(buffers []*v1.CapacityBuffer) []
 				errors = append(errors, err)
 				continue
 			}
-			numberOfPods = t.getNumberOfReplicas(buffer)
+			sizing, err := resolveBufferSchedule(buffer, t.now())
+			if err != nil {
+				common.SetBufferAsNotReadyForProvisioning(buffer, podTemplateRef, &podTemplate.Generation, nil, buffer.Spec.ProvisioningStrategy, err)
+				errors = append(errors, err)
+				continue
+			}
+			numberOfPods = t.getNumberOfReplicas(sizing)
 			if numberOfPods == nil {
 				common.SetBufferAsNotReadyForProvisioning(buffer, podTemplateRef, &podTemplate.Generation, nil, buffer.Spec.ProvisioningStrategy, fmt.Errorf("Failed to get buffer's number of pods"))
 				continue
@@ -61,9 +70,9 @@ func (t *podTemplateBufferTranslator) Translate(buffers []*v1.CapacityBuffer) []
 	return errors
 }
 
-func (t *podTemplateBufferTranslator) getNumberOfReplicas(buffer *v1.CapacityBuffer) *int32 {
-	if buffer.Spec.Replicas != nil {
-		replicas := max(0, int32(*buffer.Spec.Replicas))
+func (t *podTemplateBufferTranslator) getNumberOfReplicas(sizing bufferSizing) *int32 {
+	if sizing.replicas != nil {
+		replicas := max(0, int32(*sizing.replicas))
 		return &replicas
 	}
 	return nil
@@ -19,9 +19,13 @@ package scalableobject
 import (
 	"fmt"
 	"sort"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	cbclient "k8s.io/autoscaler/cluster-autoscaler/capacitybuffer/client"
 )
 
@@ -37,15 +41,24 @@ const (
 	ApiGroupCore              = "core"
 )
 
-// ScaleObjectPodResolver resolves scale objects into pod specs and number of replicas only if there is at least one exiting pod
+// ScaleObjectPodResolver resolves objects of any kind with a scale subresource into pod specs and number of replicas.
+// The pod spec is read from the object at the pod template path configured for its kind if any, or else taken from
+// the most recent of its existing pods, found using the selector of its scale subresource.
 type ScaleObjectPodResolver struct {
-	client *cbclient.CapacityBufferClient
+	client           *cbclient.CapacityBufferClient
+	podTemplatePaths map[schema.GroupKind][]string
 }
 
-// NewScaleObjectPodResolver returns new ScaleObjectPodResolver
-func NewScaleObjectPodResolver(client *cbclient.CapacityBufferClient) *ScaleObjectPodResolver {
+// NewScaleObjectPodResolver returns new ScaleObjectPodResolver, reading the pod templates of the objects of the
+// passed kinds at the passed dot separated field paths, e.g. "spec.template".
+func NewScaleObjectPodResolver(client *cbclient.CapacityBufferClient, podTemplatePaths map[schema.GroupKind]string) *ScaleObjectPodResolver {
+	paths := map[schema.GroupKind][]string{}
+	for groupKind, path := range podTemplatePaths {
+		paths[groupKind] = strings.Split(path, ".")
+	}
 	return &ScaleObjectPodResolver{
-		client: client,
+		client:           client,
+		podTemplatePaths: paths,
 	}
 }
 
@@ -55,6 +68,13 @@ func (s *ScaleObjectPodResolver) GetTemplateAndReplicas(namespace, group, kind,
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get scale object: %w", err)
 	}
+	if path, found := s.podTemplatePaths[schema.GroupKind{Group: group, Kind: kind}]; found {
+		template, err := s.getTemplateFromPath(namespace, group, kind, name, path)
+		if err != nil {
+			return nil, nil, err
+		}
+		return template, &obj.Status.Replicas, nil
+	}
 	podsList, err := s.client.GetPodsBySelector(namespace, obj.Status.Selector)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get existing pod for scale object: %w", err)
@@ -66,6 +86,25 @@ func (s *ScaleObjectPodResolver) GetTemplateAndReplicas(namespace, group, kind,
 	return buildPodTemplateFromPod(pod), &obj.Status.Replicas, nil
 }
 
+func (s *ScaleObjectPodResolver) getTemplateFromPath(namespace, group, kind, name string, path []string) (*corev1.PodTemplateSpec, error) {
+	obj, err := s.client.GetScalableObject(namespace, group, kind, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scalable object: %w", err)
+	}
+	rawTemplate, found, err := unstructured.NestedMap(obj.Object, path...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pod template of %v %v at %v: %w", kind, name, strings.Join(path, "."), err)
+	}
+	if !found {
+		return nil, fmt.Errorf("%v %v has no pod template at %v", kind, name, strings.Join(path, "."))
+	}
+	template := &corev1.PodTemplateSpec{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rawTemplate, template); err != nil {
+		return nil, fmt.Errorf("failed to convert pod template of %v %v at %v: %w", kind, name, strings.Join(path, "."), err)
+	}
+	return template, nil
+}
+
 func getMostRecentPod(podList []corev1.Pod) *corev1.Pod {
 	sort.Slice(podList, func(i, j int) bool {
 		return podList[i].CreationTimestamp.After(podList[j].CreationTimestamp.Time)
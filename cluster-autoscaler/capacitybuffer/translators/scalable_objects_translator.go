@@ -18,12 +18,14 @@ package translator
 
 import (
 	"fmt"
+	"time"
 
 	cbclient "k8s.io/autoscaler/cluster-autoscaler/capacitybuffer/client"
 	"k8s.io/autoscaler/cluster-autoscaler/capacitybuffer/common"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	apiv1 "k8s.io/autoscaler/cluster-autoscaler/apis/capacitybuffer/autoscaling.x-k8s.io/v1alpha1"
 	scalableobject "k8s.io/autoscaler/cluster-autoscaler/capacitybuffer/translators/scalable_objects"
 )
@@ -33,20 +35,23 @@ type ScalableObjectsTranslator struct {
 	client             *cbclient.CapacityBufferClient
 	scaleResolver      *scalableobject.ScaleObjectPodResolver
 	supportedResolvers map[string]scalableobject.ScalableObjectTemplateResolver
+	now                func() time.Time
 }
 
-// NewDefaultScalableObjectsTranslator creates an instance of ScalableObjectsTranslator.
-func NewDefaultScalableObjectsTranslator(client *cbclient.CapacityBufferClient) *ScalableObjectsTranslator {
+// NewDefaultScalableObjectsTranslator creates an instance of ScalableObjectsTranslator, reading the pod templates
+// of objects of the kinds in podTemplatePaths at their field path.
+func NewDefaultScalableObjectsTranslator(client *cbclient.CapacityBufferClient, podTemplatePaths map[schema.GroupKind]string) *ScalableObjectsTranslator {
 	supportedResolvers := map[string]scalableobject.ScalableObjectTemplateResolver{}
 	for _, scalableObject := range scalableobject.GetSupportedScalableObjectResolvers(client) {
 		supportedResolvers[scalableObject.GetResolverKey()] = scalableObject
 	}
-	scaleResolver := scalableobject.NewScaleObjectPodResolver(client)
+	scaleResolver := scalableobject.NewScaleObjectPodResolver(client, podTemplatePaths)
 
 	return &ScalableObjectsTranslator{
 		client:             client,
 		supportedResolvers: supportedResolvers,
 		scaleResolver:      scaleResolver,
+		now:                time.Now,
 	}
 }
 
@@ -90,7 +95,7 @@ func (t *ScalableObjectsTranslator) Translate(buffers []*apiv1.CapacityBuffer) [
 			}
 
 			if supportedResolversErr != nil {
-				err := fmt.Errorf("Couldn't resolve buffer %v, error resolving scale object: %v, and error resolving supported objects %v", buffer.Name, ScaleResolverErr.Error(), supportedResolversErr.Error())
+				err := fmt.Errorf("Couldn't resolve buffer %v, error resolving scale object: %w, and error resolving supported objects %w", buffer.Name, ScaleResolverErr, supportedResolversErr)
 				common.SetBufferAsNotReadyForProvisioning(buffer, nil, nil, nil, nil, err)
 				errors = append(errors, err)
 				continue
@@ -103,12 +108,18 @@ func (t *ScalableObjectsTranslator) Translate(buffers []*apiv1.CapacityBuffer) [
 			}
 
 			if podTempErr != nil {
-				err := fmt.Errorf("Failed to create pod template object for buffer %v with error: %v", buffer.Name, podTempErr.Error())
+				err := fmt.Errorf("Failed to create pod template object for buffer %v with error: %w", buffer.Name, podTempErr)
 				common.SetBufferAsNotReadyForProvisioning(buffer, nil, nil, nil, nil, err)
 				errors = append(errors, err)
 				continue
 			}
-			numberOfPods := t.getBufferNumberOfPods(buffer, replicasFromScalable)
+			sizing, err := resolveBufferSchedule(buffer, t.now())
+			if err != nil {
+				common.SetBufferAsNotReadyForProvisioning(buffer, &apiv1.LocalObjectRef{Name: createdPodTemplate.Name}, &createdPodTemplate.Generation, nil, buffer.Spec.ProvisioningStrategy, err)
+				errors = append(errors, err)
+				continue
+			}
+			numberOfPods := t.getBufferNumberOfPods(sizing, replicasFromScalable)
 			if numberOfPods == nil {
 				common.SetBufferAsNotReadyForProvisioning(buffer, &apiv1.LocalObjectRef{Name: createdPodTemplate.Name}, &createdPodTemplate.Generation, nil, buffer.Spec.ProvisioningStrategy, fmt.Errorf("Couldn't get number of replicas for buffer %v, replicas and percentage are not defined", buffer.Name))
 				continue
@@ -120,20 +131,20 @@ func (t *ScalableObjectsTranslator) Translate(buffers []*apiv1.CapacityBuffer) [
 	return errors
 }
 
-func (t *ScalableObjectsTranslator) getBufferNumberOfPods(buffer *apiv1.CapacityBuffer, scalableReplicas *int32) *int32 {
+func (t *ScalableObjectsTranslator) getBufferNumberOfPods(sizing bufferSizing, scalableReplicas *int32) *int32 {
 
 	var numberOfPodsFromPercentage *int32
 	var numberOfPodsFromReplicas *int32
 
-	if buffer.Spec.Percentage != nil {
+	if sizing.percentage != nil {
 		if scalableReplicas != nil {
-			percentValue := buffer.Spec.Percentage
+			percentValue := sizing.percentage
 			numberOfPods := max(0, int32(int32(*percentValue)*(*scalableReplicas)/100.0))
 			numberOfPodsFromPercentage = &numberOfPods
 		}
 	}
-	if buffer.Spec.Replicas != nil {
-		numberOfPods := max(0, int32(*buffer.Spec.Replicas))
+	if sizing.replicas != nil {
+		numberOfPods := max(0, int32(*sizing.replicas))
 		numberOfPodsFromReplicas = &numberOfPods
 	}
 	if numberOfPodsFromPercentage != nil && numberOfPodsFromReplicas != nil {
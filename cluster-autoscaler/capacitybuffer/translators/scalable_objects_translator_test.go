@@ -17,17 +17,29 @@ limitations under the License.
 package translator
 
 import (
+	"context"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	v1 "k8s.io/autoscaler/cluster-autoscaler/apis/capacitybuffer/autoscaling.x-k8s.io/v1alpha1"
 	buffersfake "k8s.io/autoscaler/cluster-autoscaler/apis/capacitybuffer/client/clientset/versioned/fake"
 	cbclient "k8s.io/autoscaler/cluster-autoscaler/capacitybuffer/client"
+	"k8s.io/autoscaler/cluster-autoscaler/capacitybuffer/common"
 	"k8s.io/autoscaler/cluster-autoscaler/capacitybuffer/testutil"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
 	fakeclient "k8s.io/client-go/kubernetes/fake"
+	scalefake "k8s.io/client-go/scale/fake"
+	k8stesting "k8s.io/client-go/testing"
 )
 
 const defaultNamespace = "default"
@@ -58,7 +70,7 @@ func TestScalabaleObjectsTranslator(t *testing.T) {
 	}
 	fakeKubernetesClient := fakeclient.NewSimpleClientset(podTemplate1, podTemplate2, replicaSet1)
 	fakeBuffersClient := buffersfake.NewSimpleClientset()
-	fakeCapacityBuffersClient, _ := cbclient.NewCapacityBufferClientFromClients(fakeBuffersClient, fakeKubernetesClient, nil, nil)
+	fakeCapacityBuffersClient, _ := cbclient.NewCapacityBufferClientFromClients(fakeBuffersClient, fakeKubernetesClient, nil, nil, nil)
 	tests := []struct {
 		name                   string
 		buffers                []*v1.CapacityBuffer
@@ -148,7 +160,7 @@ func TestScalabaleObjectsTranslator(t *testing.T) {
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			translator := NewDefaultScalableObjectsTranslator(fakeCapacityBuffersClient)
+			translator := NewDefaultScalableObjectsTranslator(fakeCapacityBuffersClient, nil)
 			errors := translator.Translate(test.buffers)
 			assert.Equal(t, test.expectedNumberOfErrors, len(errors))
 			assert.ElementsMatch(t, test.expectedStatus, testutil.SanitizeBuffersStatus(test.buffers))
@@ -156,6 +168,91 @@ func TestScalabaleObjectsTranslator(t *testing.T) {
 	}
 }
 
+func TestScalableObjectsTranslatorCustomResources(t *testing.T) {
+	rollout := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "Rollout",
+		"metadata": map[string]interface{}{
+			"name":      "rollout",
+			"namespace": defaultNamespace,
+		},
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels": map[string]interface{}{"app": "rollout"},
+				},
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{"name": "app", "image": "app:v1"},
+					},
+				},
+			},
+		},
+	}}
+	restMapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{{Group: "argoproj.io", Version: "v1alpha1"}})
+	restMapper.Add(schema.GroupVersionKind{Group: "argoproj.io", Version: "v1alpha1", Kind: "Rollout"}, meta.RESTScopeNamespace)
+	scaleClient := &scalefake.FakeScaleClient{}
+	scaleClient.AddReactor("get", "rollouts", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		name := action.(k8stesting.GetAction).GetName()
+		if name == "forbidden" {
+			return true, nil, apierrors.NewForbidden(schema.GroupResource{Group: "argoproj.io", Resource: "rollouts/scale"}, name, fmt.Errorf("not allowed"))
+		}
+		return true, &autoscalingv1.Scale{Status: autoscalingv1.ScaleStatus{Replicas: 4, Selector: "app=rollout"}}, nil
+	})
+	fakeKubernetesClient := fakeclient.NewSimpleClientset()
+	fakeCapacityBuffersClient, _ := cbclient.NewCapacityBufferClientFromClients(buffersfake.NewSimpleClientset(), fakeKubernetesClient, scaleClient, restMapper,
+		dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), rollout))
+
+	tests := []struct {
+		name                   string
+		scalableRef            *v1.ScalableRef
+		expectedStatus         *v1.CapacityBufferStatus
+		expectedContainer      string
+		expectedNumberOfErrors int
+	}{
+		{
+			name:              "pod template read at the configured path",
+			scalableRef:       &v1.ScalableRef{Name: "rollout", Kind: "Rollout", APIGroup: "argoproj.io"},
+			expectedStatus:    testutil.GetBufferStatus(&v1.LocalObjectRef{Name: "capacitybuffer-buffer-pod-template"}, pointerToInt32(2), pointerToInt64(0), nil, testutil.GetConditionReady()),
+			expectedContainer: "app",
+		},
+		{
+			name:                   "scale subresource access forbidden",
+			scalableRef:            &v1.ScalableRef{Name: "forbidden", Kind: "Rollout", APIGroup: "argoproj.io"},
+			expectedStatus:         testutil.GetBufferStatus(nil, nil, nil, nil, testutil.GetConditionNotReadyWithReason(common.ForbiddenReason)),
+			expectedNumberOfErrors: 1,
+		},
+		{
+			name:                   "scalable object not found",
+			scalableRef:            &v1.ScalableRef{Name: "missing", Kind: "Rollout", APIGroup: "argoproj.io"},
+			expectedStatus:         testutil.GetBufferStatus(nil, nil, nil, nil, testutil.GetConditionNotReadyWithReason(common.NotFoundReason)),
+			expectedNumberOfErrors: 1,
+		},
+		{
+			name:                   "kind not served",
+			scalableRef:            &v1.ScalableRef{Name: "worker", Kind: "Worker", APIGroup: "example.com"},
+			expectedStatus:         testutil.GetBufferStatus(nil, nil, nil, nil, testutil.GetConditionNotReadyWithReason(common.UnsupportedKindReason)),
+			expectedNumberOfErrors: 1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			translator := NewDefaultScalableObjectsTranslator(fakeCapacityBuffersClient, map[schema.GroupKind]string{
+				{Group: "argoproj.io", Kind: "Rollout"}: "spec.template",
+			})
+			buffer := getTestBufferWithScalableAttributes("buffer", test.scalableRef, pointerToInt32(50), nil)
+			errors := translator.Translate([]*v1.CapacityBuffer{buffer})
+			assert.Equal(t, test.expectedNumberOfErrors, len(errors))
+			assert.Equal(t, []*v1.CapacityBufferStatus{test.expectedStatus}, testutil.SanitizeBuffersStatus([]*v1.CapacityBuffer{buffer}))
+			if test.expectedContainer != "" {
+				podTemplate, err := fakeKubernetesClient.CoreV1().PodTemplates(defaultNamespace).Get(context.TODO(), "capacitybuffer-buffer-pod-template", metav1.GetOptions{})
+				assert.NoError(t, err)
+				assert.Equal(t, test.expectedContainer, podTemplate.Template.Spec.Containers[0].Name)
+			}
+		})
+	}
+}
+
 func getTestBufferWithScalableAttributes(bufferName string, scalableRef *v1.ScalableRef, percentage *int32, replicas *int32) *v1.CapacityBuffer {
 	buffer := &v1.CapacityBuffer{}
 	buffer.Name = bufferName
@@ -159,6 +159,9 @@ type ScaleDownCondition struct {
 	LastProbeTime metav1.Time `json:"lastProbeTime,omitempty" yaml:"lastProbeTime,omitempty"`
 	// LastTransitionTime is the time since when the condition was in the given state.
 	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty" yaml:"lastTransitionTime,omitempty"`
+	// NextAllowedWindow is the next time the scale-down windows of the node group allow scale-down at,
+	// set only when they currently don't.
+	NextAllowedWindow *metav1.Time `json:"nextAllowedWindow,omitempty" yaml:"nextAllowedWindow,omitempty"`
 }
 
 // ClusterWideStatus contains status that apply to the whole cluster.
@@ -34,6 +34,7 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/utils/backoff"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/gpu"
 	kube_util "k8s.io/autoscaler/cluster-autoscaler/utils/kubernetes"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/schedule"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/taints"
 
 	apiv1 "k8s.io/api/core/v1"
@@ -817,6 +818,7 @@ func (csr *ClusterStateRegistry) GetStatus(now time.Time) *api.ClusterAutoscaler
 		// Scale down.
 		nodeGroupStatus.ScaleDown = buildScaleDownStatusNodeGroup(
 			csr.candidatesForScaleDown[nodeGroup.Id()], csr.lastScaleDownUpdateTime, nodeGroupLastStatus.ScaleDown)
+		nodeGroupStatus.ScaleDown.NextAllowedWindow = csr.nextAllowedScaleDownWindow(nodeGroup, now)
 
 		result.NodeGroups = append(result.NodeGroups, nodeGroupStatus)
 	}
@@ -836,6 +838,12 @@ func (csr *ClusterStateRegistry) GetClusterReadiness() Readiness {
 	return csr.totalReadiness
 }
 
+// GetNodeGroupReadiness returns current readiness stats of the node group, and whether they were found.
+func (csr *ClusterStateRegistry) GetNodeGroupReadiness(nodeGroupName string) (Readiness, bool) {
+	readiness, found := csr.perNodeGroupReadiness[nodeGroupName]
+	return readiness, found
+}
+
 func buildNodeCount(readiness Readiness) api.NodeCount {
 	return api.NodeCount{
 		Registered: api.RegisteredNodeCount{
@@ -901,6 +909,24 @@ func (csr *ClusterStateRegistry) buildScaleUpStatusNodeGroup(nodeGroup cloudprov
 	return condition
 }
 
+// nextAllowedScaleDownWindow returns the next time the scale-down windows of the node group allow scale-down at,
+// or nil if they currently allow it.
+func (csr *ClusterStateRegistry) nextAllowedScaleDownWindow(nodeGroup cloudprovider.NodeGroup, now time.Time) *metav1.Time {
+	windows, err := csr.nodeGroupConfigProcessor.GetScaleDownWindows(nodeGroup)
+	if err != nil {
+		klog.Warningf("Failed to get scale-down windows for node group %s: %v", nodeGroup.Id(), err)
+		return nil
+	}
+	if schedule.Allowed(windows, now) {
+		return nil
+	}
+	next, found := schedule.NextAllowed(windows, now)
+	if !found {
+		return nil
+	}
+	return &metav1.Time{Time: next}
+}
+
 func buildScaleDownStatusNodeGroup(candidates []string, lastProbed time.Time, lastStatus api.ScaleDownCondition) api.ScaleDownCondition {
 	condition := api.ScaleDownCondition{
 		Candidates:    len(candidates),
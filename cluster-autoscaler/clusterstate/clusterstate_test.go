@@ -32,6 +32,7 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/processors/nodegroupconfig"
 	"k8s.io/autoscaler/cluster-autoscaler/processors/nodegroups/asyncnodegroups"
 
+	"k8s.io/autoscaler/cluster-autoscaler/utils/schedule"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/taints"
 	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
 	"k8s.io/client-go/kubernetes/fake"
@@ -362,6 +363,45 @@ func TestOKOneUnreadyNodeWithScaleDownCandidate(t *testing.T) {
 	assert.True(t, ng2Checked)
 }
 
+func TestScaleDownWindowsStatus(t *testing.T) {
+	now := time.Date(2026, time.October, 16, 10, 0, 0, 0, time.UTC)
+
+	ng1_1 := BuildTestNode("ng1-1", 1000, 1000)
+	SetNodeReadyState(ng1_1, true, now.Add(-time.Minute))
+	ng2_1 := BuildTestNode("ng2-1", 1000, 1000)
+	SetNodeReadyState(ng2_1, true, now.Add(-time.Minute))
+
+	provider := testprovider.NewTestCloudProviderBuilder().Build()
+	provider.AddNodeGroup("ng1", 1, 10, 1)
+	provider.AddNodeGroup("ng2", 1, 10, 1)
+	provider.AddNode("ng1", ng1_1)
+	provider.AddNode("ng2", ng2_1)
+	// ng2 overrides the default windows with none.
+	provider.GetNodeGroup("ng2").(*testprovider.TestNodeGroup).SetOptions(&config.NodeGroupAutoscalingOptions{MaxNodeProvisionTime: 15 * time.Minute})
+
+	windows, err := schedule.ParseWindows("deny 0 9 * * mon-fri 8h")
+	assert.NoError(t, err)
+	fakeClient := &fake.Clientset{}
+	fakeLogRecorder, _ := utils.NewStatusMapRecorder(fakeClient, "kube-system", kube_record.NewFakeRecorder(5), false, "my-cool-configmap")
+	clusterstate := NewClusterStateRegistry(provider, ClusterStateRegistryConfig{
+		MaxTotalUnreadyPercentage: 10,
+		OkTotalUnreadyCount:       1,
+	}, fakeLogRecorder, newBackoff(), nodegroupconfig.NewDefaultNodeGroupConfigProcessor(config.NodeGroupAutoscalingOptions{MaxNodeProvisionTime: 15 * time.Minute, ScaleDownWindows: windows}), asyncnodegroups.NewDefaultAsyncNodeGroupStateChecker())
+	err = clusterstate.UpdateNodes([]*apiv1.Node{ng1_1, ng2_1}, nil, now)
+	assert.NoError(t, err)
+
+	status := clusterstate.GetStatus(now)
+	assert.Equal(t, 2, len(status.NodeGroups))
+	for _, nodeGroupStatus := range status.NodeGroups {
+		if nodeGroupStatus.Name == "ng1" {
+			assert.Equal(t, &metav1.Time{Time: now.Add(7 * time.Hour)}, nodeGroupStatus.ScaleDown.NextAllowedWindow)
+		}
+		if nodeGroupStatus.Name == "ng2" {
+			assert.Nil(t, nodeGroupStatus.ScaleDown.NextAllowedWindow)
+		}
+	}
+}
+
 func TestMissingNodes(t *testing.T) {
 	now := time.Now()
 
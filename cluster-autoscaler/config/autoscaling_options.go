@@ -19,7 +19,9 @@ package config
 import (
 	"time"
 
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	gce_localssdsize "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/gce/localssdsize"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/schedule"
 	kubelet_config "k8s.io/kubernetes/pkg/kubelet/apis/config"
 	scheduler_config "k8s.io/kubernetes/pkg/scheduler/apis/config"
 )
@@ -56,6 +58,8 @@ type NodeGroupAutoscalingOptions struct {
 	AllowNonAtomicScaleUpToMax bool
 	// IgnoreDaemonSetsUtilization sets if daemonsets utilization should be considered during node scale-down
 	IgnoreDaemonSetsUtilization bool
+	// ScaleDownWindows are the time windows scale-down of the node group is allowed or denied in.
+	ScaleDownWindows []schedule.Window
 }
 
 // GCEOptions contain autoscaling options specific to GCE cloud provider.
@@ -73,6 +77,39 @@ type GCEOptions struct {
 	BulkMigInstancesListingEnabled bool
 }
 
+// CostAwareBinpackingOptions contain the options of the binpacking limiter ranking node groups up front
+// and stopping once enough good options are found.
+type CostAwareBinpackingOptions struct {
+	// Ranking is how node groups are ranked before binpacking: "price" or "fit". Empty disables the limiter.
+	Ranking string
+	// Options is the number of options within Margin of the best one after which binpacking stops.
+	Options int
+	// Margin is the fraction of the best option score other options must be within to count as good enough.
+	Margin float64
+	// AuditInterval is how often binpacking is run in full, to measure the options the limiter would have missed.
+	// Zero disables the audits.
+	AuditInterval time.Duration
+}
+
+// PredictivePrescalingOptions contain the options of the pod list processor injecting fake pods
+// ahead of the pod arrivals forecast from the pending pods history.
+type PredictivePrescalingOptions struct {
+	// Enabled tells if fake pods are injected ahead of forecast pod arrivals.
+	Enabled bool
+	// Key is what pod arrivals are learnt per: "namespace" or "owner".
+	Key string
+	// Period is the period over which pod arrivals repeat, e.g. a day.
+	Period time.Duration
+	// SlotDuration is the granularity at which pod arrivals are learnt within Period.
+	SlotDuration time.Duration
+	// Lookahead is how far ahead forecast pod arrivals are provisioned, typically the node boot time.
+	Lookahead time.Duration
+	// Smoothing is the weight, between 0 and 1, of the latest period in the learnt pod arrivals.
+	Smoothing float64
+	// MaxPods is the maximum number of fake pods injected in a loop.
+	MaxPods int
+}
+
 const (
 	// DefaultMaxAllocatableDifferenceRatio describes how Node.Status.Allocatable can differ between groups in the same NodeGroupSet
 	DefaultMaxAllocatableDifferenceRatio = 0.05
@@ -158,6 +195,10 @@ type AutoscalingOptions struct {
 	EnforceNodeGroupMinSize bool
 	// NodeInfosProcessorPodTemplates Enable or disable PodTemplate in the NodeInfosProcessor
 	NodeInfosProcessorPodTemplates bool
+	// NodeInfosHybridTemplates overlays the allocatable and DaemonSet pods measured on healthy live nodes onto node groups templates
+	NodeInfosHybridTemplates bool
+	// LocalStorageClasses lists the storage classes backed by node local volumes, and their labels and virtual resources mapping
+	LocalStorageClasses []string
 	// ScaleDownEnabled is used to allow CA to scale down the cluster
 	ScaleDownEnabled bool
 	// ScaleDownUnreadyEnabled is used to allow CA to scale down unready nodes of the cluster
@@ -171,6 +212,9 @@ type AutoscalingOptions struct {
 	// ScaleDownDelayTypeLocal sets if the --scale-down-delay-after-* flags should be applied locally per nodegroup
 	// or globally across all nodegroups
 	ScaleDownDelayTypeLocal bool
+	// ScaleDownWindowsConfigMapEnabled sets if per node group scale-down windows should be read from a ConfigMap,
+	// on top of the defaults and node groups' own options.
+	ScaleDownWindowsConfigMapEnabled bool
 	// ScaleDownNonEmptyCandidatesCount is the maximum number of non empty nodes
 	// considered at once as candidates for scale down.
 	ScaleDownNonEmptyCandidatesCount int
@@ -271,12 +315,16 @@ type AutoscalingOptions struct {
 	// MaxBinpackingTime is the maximum time spend on binpacking for a single scale-up.
 	// If binpacking is limited by this, scale-up will continue with the already calculated scale-up options.
 	MaxBinpackingTime time.Duration
+	// CostAwareBinpacking contains the options of the binpacking limiter ranking node groups by price or fit.
+	CostAwareBinpacking CostAwareBinpackingOptions
 	// NodeDeletionBatcherInterval is a time for how long CA ScaleDown gather nodes to delete them in batch.
 	NodeDeletionBatcherInterval time.Duration
 	// SkipNodesWithSystemPods tells if nodes with pods from kube-system should be deleted (except for DaemonSet or mirror pods)
 	SkipNodesWithSystemPods bool
 	// SkipNodesWithLocalStorage tells if nodes with pods with local storage, e.g. EmptyDir or HostPath, should be deleted
 	SkipNodesWithLocalStorage bool
+	// SkipNodesWithLocalPVs tells if nodes with pods using local persistent volumes of the local storage classes should be kept
+	SkipNodesWithLocalPVs bool
 	// SkipNodesWithCustomControllerPods tells if nodes with custom-controller owned pods should be skipped from deletion (skip if 'true')
 	SkipNodesWithCustomControllerPods bool
 	// MinReplicaCount controls the minimum number of replicas that a replica set or replication controller should have
@@ -328,6 +376,8 @@ type AutoscalingOptions struct {
 	MaxFailingTime time.Duration
 	// DebuggingSnapshotEnabled is used to enable/disable debugging snapshot creation.
 	DebuggingSnapshotEnabled bool
+	// DecisionJournalSize is the number of loops summaries kept in the decision journal, 0 disables it.
+	DecisionJournalSize int
 	// EnableProfiling is debug/pprof endpoint enabled.
 	EnableProfiling bool
 	// Address is the address of an auxiliary endpoint exposing process information like metrics, health checks and profiling data.
@@ -346,6 +396,8 @@ type AutoscalingOptions struct {
 	ProactiveScaleupEnabled bool
 	// PodInjectionLimit limits total number of pods while injecting fake pods.
 	PodInjectionLimit int
+	// PredictivePrescaling contains the options of the fake pods injected ahead of forecast pod arrivals.
+	PredictivePrescaling PredictivePrescalingOptions
 	// NodeDeletionCandidateTTL is the maximum time a node can be marked as removable without being deleted.
 	// This is used to prevent nodes from being stuck in the removable state during if the CA deployment becomes inactive.
 	NodeDeletionCandidateTTL time.Duration
@@ -353,6 +405,9 @@ type AutoscalingOptions struct {
 	CapacitybufferControllerEnabled bool
 	// CapacitybufferPodInjectionEnabled tells if CA should injects fake pods for capacity buffers that are ready for provisioning
 	CapacitybufferPodInjectionEnabled bool
+	// CapacitybufferPodTemplatePaths are the field paths of the pod templates of scalable objects referenced by capacity buffers, by kind.
+	// Scalable objects of other kinds are resolved from their existing pods.
+	CapacitybufferPodTemplatePaths map[schema.GroupKind]string
 }
 
 // KubeClientOptions specify options for kube client
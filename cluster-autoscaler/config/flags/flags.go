@@ -30,9 +30,13 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/config"
 	"k8s.io/autoscaler/cluster-autoscaler/estimator"
 	"k8s.io/autoscaler/cluster-autoscaler/expander"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/binpacking"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/prescaling"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/schedule"
 	scheduler_util "k8s.io/autoscaler/cluster-autoscaler/utils/scheduler"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/units"
 
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
 	kubelet_config "k8s.io/kubernetes/pkg/kubelet/apis/config"
@@ -71,6 +75,10 @@ var (
 	namespace               = flag.String("namespace", "kube-system", "Namespace in which cluster-autoscaler run.")
 	enforceNodeGroupMinSize = flag.Bool("enforce-node-group-min-size", false, "Should CA scale up the node group to the configured min size if needed.")
 	podTemplatesProcessor   = flag.Bool("node-infos-processor-podtemplate", true, "Enable PodTemplate NodeInfoProcessor to consider specific PodTemplate as DaemonSet")
+	hybridTemplates         = flag.Bool("node-infos-hybrid-templates", false, "Overlay the allocatable and DaemonSet pods measured on healthy live nodes onto node groups templates")
+	localStorageClasses     = multiStringFlag("local-storage-class", "Storage class backed by node local volumes, mapped to node labels and virtual resources. "+
+		"Format: <storage class>[:<label>[:<capacity label>[:<exists resource>[:<capacity resource>[:<count label>]]]]], omitted fields are derived from the storage class name. "+
+		"Can be used multiple times. Defaults to the local-data storage class.")
 	scaleDownEnabled        = flag.Bool("scale-down-enabled", true, "Should CA scale down the cluster")
 	scaleDownUnreadyEnabled = flag.Bool("scale-down-unready-enabled", true, "Should CA scale down unready nodes of the cluster")
 	scaleDownDelayAfterAdd  = flag.Duration("scale-down-delay-after-add", 10*time.Minute,
@@ -149,6 +157,11 @@ var (
 
 	ignoreDaemonSetsUtilization = flag.Bool("ignore-daemonsets-utilization", false,
 		"Should CA ignore DaemonSet pods when calculating resource utilization for scaling down")
+	scaleDownWindows = flag.String("scale-down-windows", "",
+		"Default time windows scale-down of node groups is allowed or denied in, separated by semicolons. Each window is \"<allow|deny> <cron expression> <duration> [time zone]\", "+
+			"e.g. \"deny 0 9 * * mon-fri 8h Europe/Paris\". When there are allow windows, scale-down is only allowed within them. Can be overridden per node group.")
+	scaleDownWindowsConfigMapEnabled = flag.Bool("scale-down-windows-config-map-enabled", false,
+		"Should CA read per node group scale-down windows from the cluster-autoscaler-scale-down-windows ConfigMap in the CA namespace, overriding other scale-down windows for the node groups it covers")
 	ignoreMirrorPodsUtilization = flag.Bool("ignore-mirror-pods-utilization", false,
 		"Should CA ignore Mirror pods when calculating resource utilization for scaling down")
 
@@ -156,6 +169,10 @@ var (
 	statusConfigMapName          = flag.String("status-config-map-name", "cluster-autoscaler-status", "Status configmap name")
 	maxInactivityTimeFlag        = flag.Duration("max-inactivity", 10*time.Minute, "Maximum time from last recorded autoscaler activity before automatic restart")
 	maxBinpackingTimeFlag        = flag.Duration("max-binpacking-time", 5*time.Minute, "Maximum time spend on binpacking for a single scale-up. If binpacking is limited by this, scale-up will continue with the already calculated scale-up options.")
+	costAwareBinpackingRanking   = flag.String("cost-aware-binpacking-ranking", "", "How node groups are ranked before binpacking, to stop once enough good options are found. Available values: [price,fit]. Empty disables it.")
+	costAwareBinpackingOptions   = flag.Int("cost-aware-binpacking-options", 3, "Number of options within --cost-aware-binpacking-margin of the best one after which binpacking stops, when --cost-aware-binpacking-ranking is set.")
+	costAwareBinpackingMargin    = flag.Float64("cost-aware-binpacking-margin", 0.1, "Fraction of the best option score other options must be within to count as good enough for --cost-aware-binpacking-ranking.")
+	costAwareBinpackingAudit     = flag.Duration("cost-aware-binpacking-audit-interval", 30*time.Minute, "How often binpacking is run in full to measure the options missed because of --cost-aware-binpacking-ranking. 0 disables audits.")
 	maxFailingTimeFlag           = flag.Duration("max-failing-time", 15*time.Minute, "Maximum time from last recorded successful autoscaler run before automatic restart")
 	balanceSimilarNodeGroupsFlag = flag.Bool("balance-similar-node-groups", false, "Detect similar node groups and balance the number of nodes between them")
 
@@ -182,6 +199,7 @@ var (
 	userAgent                          = flag.String("user-agent", "cluster-autoscaler", "User agent used for HTTP calls.")
 	emitPerNodeGroupMetrics            = flag.Bool("emit-per-nodegroup-metrics", false, "If true, emit per node group metrics.")
 	debuggingSnapshotEnabled           = flag.Bool("debugging-snapshot-enabled", false, "Whether the debugging snapshot of cluster autoscaler feature is enabled")
+	decisionJournalSize                = flag.Int("decision-journal-size", 100, "Number of autoscaler loops summaries kept in the decision journal. 0 disables the journal")
 	nodeInfoCacheExpireTime            = flag.Duration("node-info-cache-expire-time", 87600*time.Hour, "Node Info cache expire time for each item. Default value is 10 years.")
 
 	initialNodeGroupBackoffDuration = flag.Duration("initial-node-group-backoff-duration", 5*time.Minute,
@@ -197,6 +215,7 @@ var (
 	maxNodeGroupBinpackingDuration          = flag.Duration("max-nodegroup-binpacking-duration", 10*time.Second, "Maximum time that will be spent in binpacking simulation for each NodeGroup.")
 	skipNodesWithSystemPods                 = flag.Bool("skip-nodes-with-system-pods", true, "If true cluster autoscaler will wait for --blocking-system-pod-distruption-timeout before deleting nodes with pods from kube-system (except for DaemonSet or mirror pods)")
 	skipNodesWithLocalStorage               = flag.Bool("skip-nodes-with-local-storage", true, "If true cluster autoscaler will never delete nodes with pods with local storage, e.g. EmptyDir or HostPath")
+	skipNodesWithLocalPVs                   = flag.Bool("skip-nodes-with-local-pvs", false, "If true cluster autoscaler will never delete nodes with pods using local persistent volumes of the --local-storage-class storage classes, unless they're annotated as disposable")
 	skipNodesWithCustomControllerPods       = flag.Bool("skip-nodes-with-custom-controller-pods", true, "If true cluster autoscaler will never delete nodes with pods owned by custom controllers")
 	minReplicaCount                         = flag.Int("min-replica-count", 0, "Minimum number or replicas that a replica set or replication controller should have to allow their pods deletion in scale down")
 	bspDisruptionTimeout                    = flag.Duration("blocking-system-pod-distruption-timeout", time.Hour, "The timeout after which CA will evict non-pdb-assigned blocking system pods, applicable only when --skip-nodes-with-system-pods is set to true")
@@ -221,6 +240,13 @@ var (
 	asyncNodeGroupsEnabled                       = flag.Bool("async-node-groups", false, "Whether clusterautoscaler creates and deletes node groups asynchronously. Experimental: requires cloud provider supporting async node group operations, enable at your own risk.")
 	proactiveScaleupEnabled                      = flag.Bool("enable-proactive-scaleup", false, "Whether to enable/disable proactive scale-ups, defaults to false")
 	podInjectionLimit                            = flag.Int("pod-injection-limit", 5000, "Limits total number of pods while injecting fake pods. If unschedulable pods already exceeds the limit, pod injection is disabled but pods are not truncated.")
+	predictivePrescalingEnabled                  = flag.Bool("predictive-prescaling-enabled", false, "Whether to inject fake pods ahead of the pod arrivals forecast from the pending pods history, so that capacity is provisioned before predictable waves of pods.")
+	predictivePrescalingKey                      = flag.String("predictive-prescaling-key", "namespace", "What pod arrivals are learnt per for --predictive-prescaling-enabled. Available values: [namespace,owner].")
+	predictivePrescalingPeriod                   = flag.Duration("predictive-prescaling-period", 24*time.Hour, "Period over which pod arrivals repeat, for --predictive-prescaling-enabled.")
+	predictivePrescalingSlot                     = flag.Duration("predictive-prescaling-slot", 10*time.Minute, "Granularity at which pod arrivals are learnt within --predictive-prescaling-period.")
+	predictivePrescalingLookahead                = flag.Duration("predictive-prescaling-lookahead", 10*time.Minute, "How far ahead forecast pod arrivals are provisioned, typically the node boot time.")
+	predictivePrescalingSmoothing                = flag.Float64("predictive-prescaling-smoothing", 0.5, "Weight, between 0 and 1, of the latest period in the learnt pod arrivals.")
+	predictivePrescalingMaxPods                  = flag.Int("predictive-prescaling-max-pods", 100, "Maximum number of fake pods injected in a loop for forecast pod arrivals.")
 	checkCapacityBatchProcessing                 = flag.Bool("check-capacity-batch-processing", false, "Whether to enable batch processing for check capacity requests.")
 	checkCapacityProvisioningRequestMaxBatchSize = flag.Int("check-capacity-provisioning-request-max-batch-size", 10, "Maximum number of provisioning requests to process in a single batch.")
 	checkCapacityProvisioningRequestBatchTimebox = flag.Duration("check-capacity-provisioning-request-batch-timebox", 10*time.Second, "Maximum time to process a batch of provisioning requests.")
@@ -232,6 +258,7 @@ var (
 	nodeDeletionCandidateTTL                     = flag.Duration("node-deletion-candidate-ttl", time.Duration(0), "Maximum time a node can be marked as removable before the marking becomes stale. This sets the TTL of Cluster-Autoscaler's state if the Cluste-Autoscaler deployment becomes inactive")
 	capacitybufferControllerEnabled              = flag.Bool("capacity-buffer-controller-enabled", false, "Whether to enable the default controller for capacity buffers or not")
 	capacitybufferPodInjectionEnabled            = flag.Bool("capacity-buffer-pod-injection-enabled", false, "Whether to enable pod list processor that processes ready capacity buffers and injects fake pods accordingly")
+	capacitybufferPodTemplatePaths               = multiStringFlag("capacity-buffer-pod-template-path", "Field path of the pod template of objects of a kind with a scale subresource referenced by capacity buffers, in the format <kind>.<api group>=<field path>, e.g. Rollout.argoproj.io=spec.template. Objects of kinds without one are resolved from their existing pods. Can be passed multiple times.")
 
 	// Deprecated flags
 	ignoreTaintsFlag = multiStringFlag("ignore-taint", "Specifies a taint to ignore in node templates when considering to scale a node group (Deprecated, use startup-taints instead)")
@@ -279,6 +306,34 @@ func createAutoscalingOptions() config.AutoscalingOptions {
 		klog.Fatalf("Invalid configuration, could not use --drain-priority-config together with --max-graceful-termination-sec")
 	}
 
+	switch *costAwareBinpackingRanking {
+	case "", binpacking.PriceRanking, binpacking.FitRanking:
+	default:
+		klog.Fatalf("Invalid configuration, unknown --cost-aware-binpacking-ranking %q", *costAwareBinpackingRanking)
+	}
+
+	if *predictivePrescalingEnabled {
+		if *predictivePrescalingKey != prescaling.NamespaceKey && *predictivePrescalingKey != prescaling.OwnerKey {
+			klog.Fatalf("Invalid configuration, unknown --predictive-prescaling-key %q", *predictivePrescalingKey)
+		}
+		if *predictivePrescalingSlot <= 0 || *predictivePrescalingPeriod < *predictivePrescalingSlot {
+			klog.Fatalf("Invalid configuration, --predictive-prescaling-slot must be positive and at most --predictive-prescaling-period")
+		}
+		if *predictivePrescalingSmoothing <= 0 || *predictivePrescalingSmoothing > 1 {
+			klog.Fatalf("Invalid configuration, --predictive-prescaling-smoothing must be in (0, 1]")
+		}
+	}
+
+	parsedScaleDownWindows, err := schedule.ParseWindows(*scaleDownWindows)
+	if err != nil {
+		klog.Fatalf("Invalid configuration, parsing --scale-down-windows: %v", err)
+	}
+
+	parsedCapacitybufferPodTemplatePaths, err := parseCapacityBufferPodTemplatePaths(*capacitybufferPodTemplatePaths)
+	if err != nil {
+		klog.Fatalf("Invalid configuration, parsing --capacity-buffer-pod-template-path: %v", err)
+	}
+
 	var drainPriorityConfigMap []kubelet_config.ShutdownGracePeriodByPodPriority
 	if pflag.CommandLine.Changed("drain-priority-config") {
 		drainPriorityConfigMap = parseShutdownGracePeriodsAndPriorities(*drainPriorityConfig)
@@ -295,6 +350,7 @@ func createAutoscalingOptions() config.AutoscalingOptions {
 			ScaleDownUnreadyTime:             *scaleDownUnreadyTime,
 			IgnoreDaemonSetsUtilization:      *ignoreDaemonSetsUtilization,
 			MaxNodeProvisionTime:             *maxNodeProvisionTime,
+			ScaleDownWindows:                 parsedScaleDownWindows,
 		},
 		CloudConfig:                      *cloudConfig,
 		CloudProviderName:                *cloudProviderFlag,
@@ -321,8 +377,11 @@ func createAutoscalingOptions() config.AutoscalingOptions {
 		NodeGroups:                       *nodeGroupsFlag,
 		EnforceNodeGroupMinSize:          *enforceNodeGroupMinSize,
 		NodeInfosProcessorPodTemplates:   *podTemplatesProcessor,
+		NodeInfosHybridTemplates:         *hybridTemplates,
+		LocalStorageClasses:              *localStorageClasses,
 		ScaleDownDelayAfterAdd:           *scaleDownDelayAfterAdd,
 		ScaleDownDelayTypeLocal:          *scaleDownDelayTypeLocal,
+		ScaleDownWindowsConfigMapEnabled: *scaleDownWindowsConfigMapEnabled,
 		ScaleDownDelayAfterDelete:        *scaleDownDelayAfterDelete,
 		ScaleDownDelayAfterFailure:       *scaleDownDelayAfterFailure,
 		ScaleDownEnabled:                 *scaleDownEnabled,
@@ -377,6 +436,7 @@ func createAutoscalingOptions() config.AutoscalingOptions {
 		NodeDeletionBatcherInterval:        *nodeDeletionBatcherInterval,
 		SkipNodesWithSystemPods:            *skipNodesWithSystemPods,
 		SkipNodesWithLocalStorage:          *skipNodesWithLocalStorage,
+		SkipNodesWithLocalPVs:              *skipNodesWithLocalPVs,
 		MinReplicaCount:                    *minReplicaCount,
 		BspDisruptionTimeout:               *bspDisruptionTimeout,
 		NodeDeleteDelayAfterTaint:          *nodeDeleteDelayAfterTaint,
@@ -387,6 +447,21 @@ func createAutoscalingOptions() config.AutoscalingOptions {
 			MaxAllocatableDifferenceRatio:    *maxAllocatableDifferenceRatio,
 			MaxFreeDifferenceRatio:           *maxFreeDifferenceRatio,
 		},
+		CostAwareBinpacking: config.CostAwareBinpackingOptions{
+			Ranking:       *costAwareBinpackingRanking,
+			Options:       *costAwareBinpackingOptions,
+			Margin:        *costAwareBinpackingMargin,
+			AuditInterval: *costAwareBinpackingAudit,
+		},
+		PredictivePrescaling: config.PredictivePrescalingOptions{
+			Enabled:      *predictivePrescalingEnabled,
+			Key:          *predictivePrescalingKey,
+			Period:       *predictivePrescalingPeriod,
+			SlotDuration: *predictivePrescalingSlot,
+			Lookahead:    *predictivePrescalingLookahead,
+			Smoothing:    *predictivePrescalingSmoothing,
+			MaxPods:      *predictivePrescalingMaxPods,
+		},
 		SkipSimilarNodeGroupRecomputation:            *skipSimilarNodeGroupRecomputation,
 		DynamicNodeDeleteDelayAfterTaintEnabled:      *dynamicNodeDeleteDelayAfterTaintEnabled,
 		BypassedSchedulers:                           scheduler_util.GetBypassedSchedulersMap(*bypassedSchedulers),
@@ -406,6 +481,7 @@ func createAutoscalingOptions() config.AutoscalingOptions {
 		MaxInactivityTime:                            *maxInactivityTimeFlag,
 		MaxFailingTime:                               *maxFailingTimeFlag,
 		DebuggingSnapshotEnabled:                     *debuggingSnapshotEnabled,
+		DecisionJournalSize:                          *decisionJournalSize,
 		EnableProfiling:                              *enableProfiling,
 		Address:                                      *address,
 		EmitPerNodeGroupMetrics:                      *emitPerNodeGroupMetrics,
@@ -418,6 +494,7 @@ func createAutoscalingOptions() config.AutoscalingOptions {
 		NodeDeletionCandidateTTL:                     *nodeDeletionCandidateTTL,
 		CapacitybufferControllerEnabled:              *capacitybufferControllerEnabled,
 		CapacitybufferPodInjectionEnabled:            *capacitybufferPodInjectionEnabled,
+		CapacitybufferPodTemplatePaths:               parsedCapacitybufferPodTemplatePaths,
 	}
 }
 
@@ -534,3 +611,17 @@ func parseShutdownGracePeriodsAndPriorities(priorityGracePeriodStr string) []kub
 	}
 	return priorityGracePeriodMap
 }
+
+// parseCapacityBufferPodTemplatePaths parses <kind>.<api group>=<field path> flags and returns the pod template
+// field path by scalable object kind if succeeded.
+func parseCapacityBufferPodTemplatePaths(flags MultiStringFlag) (map[schema.GroupKind]string, error) {
+	paths := map[schema.GroupKind]string{}
+	for _, flag := range flags {
+		kind, path, found := strings.Cut(flag, "=")
+		if !found || kind == "" || path == "" {
+			return nil, fmt.Errorf("%q must be in the format <kind>.<api group>=<field path>", flag)
+		}
+		paths[schema.ParseGroupKind(kind)] = path
+	}
+	return paths, nil
+}
@@ -20,6 +20,7 @@ import (
 	"flag"
 	"testing"
 
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/autoscaler/cluster-autoscaler/config"
 	kubelet_config "k8s.io/kubernetes/pkg/kubelet/apis/config"
 
@@ -151,6 +152,20 @@ func TestParseShutdownGracePeriodsAndPriorities(t *testing.T) {
 	}
 }
 
+func TestParseCapacityBufferPodTemplatePaths(t *testing.T) {
+	paths, err := parseCapacityBufferPodTemplatePaths(MultiStringFlag{"Rollout.argoproj.io=spec.template", "Worker.example.com=spec.worker.template"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[schema.GroupKind]string{
+		{Group: "argoproj.io", Kind: "Rollout"}: "spec.template",
+		{Group: "example.com", Kind: "Worker"}:  "spec.worker.template",
+	}, paths)
+
+	for _, flag := range []string{"Rollout.argoproj.io", "=spec.template", "Rollout.argoproj.io="} {
+		_, err := parseCapacityBufferPodTemplatePaths(MultiStringFlag{flag})
+		assert.Error(t, err, flag)
+	}
+}
+
 func TestCreateAutoscalingOptions(t *testing.T) {
 	for _, tc := range []struct {
 		testName            string
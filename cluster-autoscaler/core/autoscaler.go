@@ -17,6 +17,7 @@ limitations under the License.
 package core
 
 import (
+	"slices"
 	"strings"
 	"time"
 
@@ -27,9 +28,11 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/core/scaledown/pdb"
 	"k8s.io/autoscaler/cluster-autoscaler/core/scaleup"
 	"k8s.io/autoscaler/cluster-autoscaler/debuggingsnapshot"
+	"k8s.io/autoscaler/cluster-autoscaler/debuggingsnapshot/journal"
 	"k8s.io/autoscaler/cluster-autoscaler/estimator"
 	"k8s.io/autoscaler/cluster-autoscaler/expander"
 	"k8s.io/autoscaler/cluster-autoscaler/expander/factory"
+	"k8s.io/autoscaler/cluster-autoscaler/expander/spotfallback"
 	"k8s.io/autoscaler/cluster-autoscaler/observers/loopstart"
 	ca_processors "k8s.io/autoscaler/cluster-autoscaler/processors"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/clustersnapshot"
@@ -60,6 +63,7 @@ type AutoscalerOptions struct {
 	LoopStartNotifier      *loopstart.ObserversList
 	Backoff                backoff.Backoff
 	DebuggingSnapshotter   debuggingsnapshot.DebuggingSnapshotter
+	DecisionJournal        *journal.Journal
 	RemainingPdbTracker    pdb.RemainingPdbTracker
 	ScaleUpOrchestrator    scaleup.Orchestrator
 	DeleteOptions          options.NodeDeleteOptions
@@ -100,6 +104,7 @@ func NewAutoscaler(opts AutoscalerOptions, informerFactory informers.SharedInfor
 		opts.EstimatorBuilder,
 		opts.Backoff,
 		opts.DebuggingSnapshotter,
+		opts.DecisionJournal,
 		opts.RemainingPdbTracker,
 		opts.ScaleUpOrchestrator,
 		opts.DeleteOptions,
@@ -135,9 +140,11 @@ func initializeDefaultOptions(opts *AutoscalerOptions, informerFactory informers
 	if opts.CloudProvider == nil {
 		opts.CloudProvider = cloudBuilder.NewCloudProvider(opts.AutoscalingOptions, informerFactory)
 	}
+	// The spot-fallback expander learns from the errors node groups are backed off for.
+	availabilityTracker := spotfallback.NewAvailabilityTracker(spotfallback.DefaultHalfLife)
 	if opts.ExpanderStrategy == nil {
 		expanderFactory := factory.NewFactory()
-		expanderFactory.RegisterDefaultExpanders(opts.CloudProvider, opts.AutoscalingKubeClients, opts.KubeClient, opts.ConfigNamespace, opts.GRPCExpanderCert, opts.GRPCExpanderURL)
+		expanderFactory.RegisterDefaultExpanders(opts.CloudProvider, opts.AutoscalingKubeClients, opts.KubeClient, opts.ConfigNamespace, opts.GRPCExpanderCert, opts.GRPCExpanderURL, availabilityTracker)
 		expanderStrategy, err := expanderFactory.Build(strings.Split(opts.ExpanderNames, ","))
 		if err != nil {
 			return err
@@ -165,6 +172,9 @@ func initializeDefaultOptions(opts *AutoscalerOptions, informerFactory informers
 		opts.Backoff =
 			backoff.NewIdBasedExponentialBackoff(opts.InitialNodeGroupBackoffDuration, opts.MaxNodeGroupBackoffDuration, opts.NodeGroupBackoffResetTimeout)
 	}
+	if slices.Contains(strings.Split(opts.ExpanderNames, ","), expander.SpotFallbackExpanderName) {
+		opts.Backoff = spotfallback.NewLearningBackoff(opts.Backoff, availabilityTracker)
+	}
 	if opts.DrainabilityRules == nil {
 		opts.DrainabilityRules = rules.Default(opts.DeleteOptions)
 	}
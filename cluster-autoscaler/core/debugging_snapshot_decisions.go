@@ -0,0 +1,156 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"fmt"
+	"sort"
+
+	apiv1 "k8s.io/api/core/v1"
+	scaledownstatus "k8s.io/autoscaler/cluster-autoscaler/core/scaledown/status"
+	"k8s.io/autoscaler/cluster-autoscaler/debuggingsnapshot"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/status"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator"
+)
+
+// The debugging snapshot can't depend on the status processors (they depend on the
+// autoscaling context, which holds the snapshotter), so decisions are converted here.
+
+var scaleUpResultNames = map[status.ScaleUpResult]string{
+	status.ScaleUpSuccessful:             "Successful",
+	status.ScaleUpError:                  "Error",
+	status.ScaleUpNoOptionsAvailable:     "NoOptionsAvailable",
+	status.ScaleUpNotNeeded:              "NotNeeded",
+	status.ScaleUpNotTried:               "NotTried",
+	status.ScaleUpInCooldown:             "InCooldown",
+	status.ScaleUpLimitedByMaxNodesTotal: "LimitedByMaxNodesTotal",
+}
+
+var scaleDownResultNames = map[scaledownstatus.ScaleDownResult]string{
+	scaledownstatus.ScaleDownError:             "Error",
+	scaledownstatus.ScaleDownNoNodeDeleted:     "NoNodeDeleted",
+	scaledownstatus.ScaleDownNodeDeleteStarted: "NodeDeleteStarted",
+	scaledownstatus.ScaleDownNotTried:          "NotTried",
+	scaledownstatus.ScaleDownInCooldown:        "InCooldown",
+	scaledownstatus.ScaleDownInProgress:        "InProgress",
+	scaledownstatus.ScaleDownNoCandidates:      "NoCandidates",
+}
+
+var unremovableReasonNames = map[simulator.UnremovableReason]string{
+	simulator.NoReason:                         "NoReason",
+	simulator.ScaleDownDisabledAnnotation:      "ScaleDownDisabledAnnotation",
+	simulator.ScaleDownUnreadyDisabled:         "ScaleDownUnreadyDisabled",
+	simulator.NotAutoscaled:                    "NotAutoscaled",
+	simulator.NotUnneededLongEnough:            "NotUnneededLongEnough",
+	simulator.NotUnreadyLongEnough:             "NotUnreadyLongEnough",
+	simulator.NodeGroupMinSizeReached:          "NodeGroupMinSizeReached",
+	simulator.NodeGroupMaxDeletionCountReached: "NodeGroupMaxDeletionCountReached",
+	simulator.AtomicScaleDownFailed:            "AtomicScaleDownFailed",
+	simulator.MinimalResourceLimitExceeded:     "MinimalResourceLimitExceeded",
+	simulator.CurrentlyBeingDeleted:            "CurrentlyBeingDeleted",
+	simulator.NotUnderutilized:                 "NotUnderutilized",
+	simulator.NotUnneededOtherReason:           "NotUnneededOtherReason",
+	simulator.RecentlyUnremovable:              "RecentlyUnremovable",
+	simulator.NoPlaceToMovePods:                "NoPlaceToMovePods",
+	simulator.BlockedByPod:                     "BlockedByPod",
+	simulator.UnexpectedError:                  "UnexpectedError",
+	simulator.NoNodeInfo:                       "NoNodeInfo",
+	simulator.OutsideScaleDownWindow:           "OutsideScaleDownWindow",
+}
+
+// resultName returns the name of a result or reason, or its number when unknown.
+func resultName[T comparable](names map[T]string, value T) string {
+	if name, found := names[value]; found {
+		return name
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+func scaleUpDecision(scaleUpStatus *status.ScaleUpStatus) *debuggingsnapshot.ScaleUpDecision {
+	decision := &debuggingsnapshot.ScaleUpDecision{
+		Result:               resultName(scaleUpResultNames, scaleUpStatus.Result),
+		PodsTriggeredScaleUp: podRefs(scaleUpStatus.PodsTriggeredScaleUp),
+		PodsAwaitEvaluation:  podRefs(scaleUpStatus.PodsAwaitEvaluation),
+	}
+	if scaleUpStatus.ScaleUpError != nil && *scaleUpStatus.ScaleUpError != nil {
+		decision.Error = (*scaleUpStatus.ScaleUpError).Error()
+	}
+	for _, info := range scaleUpStatus.ScaleUpInfos {
+		decision.ScaleUps = append(decision.ScaleUps, &debuggingsnapshot.NodeGroupScaleUp{
+			NodeGroup:   info.Group.Id(),
+			CurrentSize: info.CurrentSize,
+			NewSize:     info.NewSize,
+			MaxSize:     info.MaxSize,
+		})
+	}
+	for _, noScaleUp := range scaleUpStatus.PodsRemainUnschedulable {
+		decision.PodsRemainUnschedulable = append(decision.PodsRemainUnschedulable, &debuggingsnapshot.NoScaleUpReasons{
+			Pod:                debuggingsnapshot.PodRef(noScaleUp.Pod),
+			RejectedNodeGroups: nodeGroupsReasons(noScaleUp.RejectedNodeGroups),
+			SkippedNodeGroups:  nodeGroupsReasons(noScaleUp.SkippedNodeGroups),
+		})
+	}
+	return decision
+}
+
+func scaleDownDecision(scaleDownStatus *scaledownstatus.ScaleDownStatus, unneededNodes []*apiv1.Node) *debuggingsnapshot.ScaleDownDecision {
+	decision := &debuggingsnapshot.ScaleDownDecision{
+		Result:        resultName(scaleDownResultNames, scaleDownStatus.Result),
+		UnneededNodes: nodeNames(unneededNodes),
+	}
+	sort.Strings(decision.UnneededNodes)
+	for _, node := range scaleDownStatus.UnremovableNodes {
+		unremovable := &debuggingsnapshot.UnremovableNode{
+			Node:   node.Node.Name,
+			Reason: resultName(unremovableReasonNames, node.Reason),
+		}
+		if node.NodeGroup != nil {
+			unremovable.NodeGroup = node.NodeGroup.Id()
+		}
+		if node.BlockingPod != nil && node.BlockingPod.Pod != nil {
+			unremovable.BlockingPod = debuggingsnapshot.PodRef(node.BlockingPod.Pod)
+			unremovable.BlockingPodReason = node.BlockingPod.Reason.String()
+		}
+		decision.UnremovableNodes = append(decision.UnremovableNodes, unremovable)
+	}
+	for _, node := range scaleDownStatus.ScaledDownNodes {
+		decision.ScaledDownNodes = append(decision.ScaledDownNodes, node.Node.Name)
+	}
+	for _, nodeGroup := range scaleDownStatus.RemovedNodeGroups {
+		decision.RemovedNodeGroups = append(decision.RemovedNodeGroups, nodeGroup.Id())
+	}
+	return decision
+}
+
+func nodeGroupsReasons(reasons map[string]status.Reasons) map[string][]string {
+	if len(reasons) == 0 {
+		return nil
+	}
+	result := make(map[string][]string, len(reasons))
+	for id, r := range reasons {
+		result[id] = r.Reasons()
+	}
+	return result
+}
+
+func podRefs(pods []*apiv1.Pod) []string {
+	var refs []string
+	for _, pod := range pods {
+		refs = append(refs, debuggingsnapshot.PodRef(pod))
+	}
+	return refs
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
+	testprovider "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
+	scaledownstatus "k8s.io/autoscaler/cluster-autoscaler/core/scaledown/status"
+	"k8s.io/autoscaler/cluster-autoscaler/core/scaleup/orchestrator"
+	"k8s.io/autoscaler/cluster-autoscaler/debuggingsnapshot"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/nodegroupset"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/status"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/drain"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/errors"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+)
+
+func TestScaleUpDecision(t *testing.T) {
+	provider := testprovider.NewTestCloudProviderBuilder().Build()
+	provider.AddNodeGroup("ng1", 0, 10, 1)
+	p1 := BuildTestPod("p1", 100, 100)
+	p2 := BuildTestPod("p2", 100, 100)
+
+	scaleUpErr := errors.NewAutoscalerError(errors.CloudProviderError, "boom")
+	decision := scaleUpDecision(&status.ScaleUpStatus{
+		Result:               status.ScaleUpSuccessful,
+		ScaleUpError:         &scaleUpErr,
+		ScaleUpInfos:         []nodegroupset.ScaleUpInfo{{Group: provider.GetNodeGroup("ng1"), CurrentSize: 1, NewSize: 3, MaxSize: 10}},
+		PodsTriggeredScaleUp: []*apiv1.Pod{p1},
+		PodsRemainUnschedulable: []status.NoScaleUpInfo{{
+			Pod:                p2,
+			RejectedNodeGroups: map[string]status.Reasons{"ng1": orchestrator.NewRejectedReasons("too big")},
+		}},
+	})
+	assert.Equal(t, &debuggingsnapshot.ScaleUpDecision{
+		Result:               "Successful",
+		Error:                "boom",
+		ScaleUps:             []*debuggingsnapshot.NodeGroupScaleUp{{NodeGroup: "ng1", CurrentSize: 1, NewSize: 3, MaxSize: 10}},
+		PodsTriggeredScaleUp: []string{"default/p1"},
+		PodsRemainUnschedulable: []*debuggingsnapshot.NoScaleUpReasons{{
+			Pod:                "default/p2",
+			RejectedNodeGroups: map[string][]string{"ng1": {"too big"}},
+		}},
+	}, decision)
+
+	decision = scaleUpDecision(&status.ScaleUpStatus{Result: status.ScaleUpResult(42)})
+	assert.Equal(t, "42", decision.Result)
+}
+
+func TestScaleDownDecision(t *testing.T) {
+	provider := testprovider.NewTestCloudProviderBuilder().Build()
+	provider.AddNodeGroup("ng1", 0, 10, 3)
+	n1 := BuildTestNode("n1", 1000, 1000)
+	n2 := BuildTestNode("n2", 1000, 1000)
+	n3 := BuildTestNode("n3", 1000, 1000)
+	pod := BuildTestPod("p1", 100, 100)
+
+	decision := scaleDownDecision(&scaledownstatus.ScaleDownStatus{
+		Result: scaledownstatus.ScaleDownNodeDeleteStarted,
+		UnremovableNodes: []*scaledownstatus.UnremovableNode{
+			{Node: n1, NodeGroup: provider.GetNodeGroup("ng1"), Reason: simulator.BlockedByPod, BlockingPod: &drain.BlockingPod{Pod: pod, Reason: drain.NotReplicated}},
+			{Node: n2, Reason: simulator.NotAutoscaled},
+		},
+		ScaledDownNodes: []*scaledownstatus.ScaleDownNode{{Node: n3}},
+	}, []*apiv1.Node{n3, n2})
+	assert.Equal(t, &debuggingsnapshot.ScaleDownDecision{
+		Result:        "NodeDeleteStarted",
+		UnneededNodes: []string{"n2", "n3"},
+		UnremovableNodes: []*debuggingsnapshot.UnremovableNode{
+			{Node: "n1", NodeGroup: "ng1", Reason: "BlockedByPod", BlockingPod: "default/p1", BlockingPodReason: "NotReplicated"},
+			{Node: "n2", Reason: "NotAutoscaled"},
+		},
+		ScaledDownNodes: []string{"n3"},
+	}, decision)
+}
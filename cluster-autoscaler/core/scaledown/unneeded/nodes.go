@@ -31,6 +31,7 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/simulator"
 	"k8s.io/autoscaler/cluster-autoscaler/utils"
 	kube_util "k8s.io/autoscaler/cluster-autoscaler/utils/kubernetes"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/schedule"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/taints"
 
 	apiv1 "k8s.io/api/core/v1"
@@ -55,6 +56,8 @@ type scaleDownTimeGetter interface {
 	GetScaleDownUnneededTime(nodeGroup cloudprovider.NodeGroup) (time.Duration, error)
 	// GetScaleDownUnreadyTime returns ScaleDownUnreadyTime value that should be used for a given NodeGroup.
 	GetScaleDownUnreadyTime(nodeGroup cloudprovider.NodeGroup) (time.Duration, error)
+	// GetScaleDownWindows returns ScaleDownWindows value that should be used for a given NodeGroup.
+	GetScaleDownWindows(nodeGroup cloudprovider.NodeGroup) ([]schedule.Window, error)
 }
 
 // NewNodes returns a new initialized Nodes object.
@@ -228,6 +231,16 @@ func (n *Nodes) unremovableReason(context *context.AutoscalingContext, scaleDown
 		return simulator.NotAutoscaled
 	}
 
+	windows, err := n.sdtg.GetScaleDownWindows(nodeGroup)
+	if err != nil {
+		klog.Errorf("Error trying to get ScaleDownWindows for node %s (in group: %s)", node.Name, nodeGroup.Id())
+		return simulator.UnexpectedError
+	}
+	if !schedule.Allowed(windows, ts) {
+		klog.V(4).Infof("Skipping %s - scale down not allowed by the windows of node group %s", node.Name, nodeGroup.Id())
+		return simulator.OutsideScaleDownWindow
+	}
+
 	if ready {
 		// Check how long a ready node was underutilized.
 		unneededTime, err := n.sdtg.GetScaleDownUnneededTime(nodeGroup)
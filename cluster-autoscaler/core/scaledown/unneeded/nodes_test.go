@@ -34,6 +34,7 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/simulator"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/kubernetes"
 	kube_util "k8s.io/autoscaler/cluster-autoscaler/utils/kubernetes"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/schedule"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/taints"
 	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
 	"k8s.io/client-go/kubernetes/fake"
@@ -137,6 +138,7 @@ func TestRemovableAt(t *testing.T) {
 		minSize             int
 		targetSize          int
 		numOngoingDeletions int
+		scaleDownWindows    string
 		numEmptyToRemove    int
 		numDrainToRemove    int
 	}{
@@ -170,9 +172,31 @@ func TestRemovableAt(t *testing.T) {
 			numEmptyToRemove:    2,
 			numDrainToRemove:    0,
 		},
+		{
+			name:             "Node group scale-down windows allow scale-down",
+			numEmpty:         3,
+			numDrain:         2,
+			minSize:          1,
+			targetSize:       10,
+			scaleDownWindows: "allow * * * * * 1h",
+			numEmptyToRemove: 3,
+			numDrainToRemove: 2,
+		},
+		{
+			name:             "Node group scale-down windows deny scale-down",
+			numEmpty:         3,
+			numDrain:         2,
+			minSize:          1,
+			targetSize:       10,
+			scaleDownWindows: "deny * * * * * 1h",
+			numEmptyToRemove: 0,
+			numDrainToRemove: 0,
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
+			windows, err := schedule.ParseWindows(tc.scaleDownWindows)
+			assert.NoError(t, err)
 			ng := testprovider.NewTestNodeGroup("ng", 100, tc.minSize, tc.targetSize, true, false, "", nil, nil)
 			empty := []simulator.NodeToBeRemoved{}
 			for i := 0; i < tc.numEmpty; i++ {
@@ -203,7 +227,7 @@ func TestRemovableAt(t *testing.T) {
 			ctx, err := NewScaleTestAutoscalingContext(config.AutoscalingOptions{ScaleDownSimulationTimeout: 5 * time.Minute}, &fake.Clientset{}, registry, provider, nil, nil)
 			assert.NoError(t, err)
 
-			n := NewNodes(&fakeScaleDownTimeGetter{}, &resource.LimitsFinder{})
+			n := NewNodes(&fakeScaleDownTimeGetter{windows: windows}, &resource.LimitsFinder{})
 			n.Update(removableNodes, time.Now())
 			gotEmptyToRemove, gotDrainToRemove, _ := n.RemovableAt(&ctx, nodeprocessors.ScaleDownContext{
 				ActuationStatus:     as,
@@ -330,7 +354,9 @@ func (f *fakeActuationStatus) DeletionsCount(nodeGroup string) int {
 	return f.deletionCount[nodeGroup]
 }
 
-type fakeScaleDownTimeGetter struct{}
+type fakeScaleDownTimeGetter struct {
+	windows []schedule.Window
+}
 
 func (f *fakeScaleDownTimeGetter) GetScaleDownUnneededTime(cloudprovider.NodeGroup) (time.Duration, error) {
 	return 0 * time.Second, nil
@@ -339,3 +365,7 @@ func (f *fakeScaleDownTimeGetter) GetScaleDownUnneededTime(cloudprovider.NodeGro
 func (f *fakeScaleDownTimeGetter) GetScaleDownUnreadyTime(cloudprovider.NodeGroup) (time.Duration, error) {
 	return 0 * time.Second, nil
 }
+
+func (f *fakeScaleDownTimeGetter) GetScaleDownWindows(cloudprovider.NodeGroup) ([]schedule.Window, error) {
+	return f.windows, nil
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package equivalence
+
+import (
+	"sort"
+	"strconv"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// PodGroupLabelKey is the label used by the co-scheduling scheduler plugin to assign pods to a pod group.
+	PodGroupLabelKey = "scheduling.x-k8s.io/pod-group"
+	// PodGroupMinMemberAnnotationKey is an opt-in pod annotation defining how many pods of a pod group have
+	// to be scheduled together. If not set, all the pending pods of the pod group have to be. The minMember
+	// of the PodGroup object itself isn't read, as Cluster Autoscaler doesn't watch PodGroups: the annotation
+	// has to be set on the pods, usually to the same value.
+	PodGroupMinMemberAnnotationKey = "cluster-autoscaler.kubernetes.io/pod-group-min-member"
+	// JobSetNameLabelKey is the label added by the JobSet controller to the pods of a JobSet.
+	JobSetNameLabelKey = "jobset.sigs.k8s.io/jobset-name"
+)
+
+// Gang is a group of pods that have to be scheduled together.
+type Gang struct {
+	// Key identifies the gang.
+	Key string
+	// MinMember is the number of pending pods of the gang that have to be scheduled together.
+	MinMember int
+	Pods      []*apiv1.Pod
+}
+
+// BuildGangs groups pods belonging to co-scheduled pod groups and JobSets into gangs. Pods which
+// don't belong to any gang are skipped. Gangs are sorted by their keys.
+func BuildGangs(pods []*apiv1.Pod) []*Gang {
+	gangsByKey := map[string]*Gang{}
+	for _, pod := range pods {
+		key, found := gangKey(pod)
+		if !found {
+			continue
+		}
+		gang, found := gangsByKey[key]
+		if !found {
+			gang = &Gang{Key: key}
+			gangsByKey[key] = gang
+		}
+		gang.Pods = append(gang.Pods, pod)
+		if minMember, found := podGroupMinMember(pod); found {
+			gang.MinMember = minMember
+		}
+	}
+	gangs := make([]*Gang, 0, len(gangsByKey))
+	for _, gang := range gangsByKey {
+		if gang.MinMember <= 0 || gang.MinMember > len(gang.Pods) {
+			// Some members may already be running, so only the pending ones can be required to fit.
+			gang.MinMember = len(gang.Pods)
+		}
+		gangs = append(gangs, gang)
+	}
+	sort.Slice(gangs, func(i, j int) bool { return gangs[i].Key < gangs[j].Key })
+	return gangs
+}
+
+func gangKey(pod *apiv1.Pod) (string, bool) {
+	if name, found := pod.Labels[PodGroupLabelKey]; found && name != "" {
+		return "podgroup/" + pod.Namespace + "/" + name, true
+	}
+	if name, found := pod.Labels[JobSetNameLabelKey]; found && name != "" {
+		return "jobset/" + pod.Namespace + "/" + name, true
+	}
+	return "", false
+}
+
+func podGroupMinMember(pod *apiv1.Pod) (int, bool) {
+	value, found := pod.Annotations[PodGroupMinMemberAnnotationKey]
+	if !found {
+		return 0, false
+	}
+	minMember, err := strconv.Atoi(value)
+	if err != nil {
+		klog.Warningf("Pod %s/%s has invalid %s annotation %q: %v", pod.Namespace, pod.Name, PodGroupMinMemberAnnotationKey, value, err)
+		return 0, false
+	}
+	return minMember, true
+}
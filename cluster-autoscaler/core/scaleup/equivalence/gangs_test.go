@@ -0,0 +1,102 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package equivalence
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
+
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+)
+
+func TestBuildGangs(t *testing.T) {
+	buildPod := func(name, namespace string, labels, annotations map[string]string) *apiv1.Pod {
+		pod := BuildTestPod(name, 100, 100)
+		pod.Namespace = namespace
+		pod.Labels = labels
+		pod.Annotations = annotations
+		return pod
+	}
+	podGroup := map[string]string{PodGroupLabelKey: "group"}
+	jobSet := map[string]string{JobSetNameLabelKey: "jobset"}
+	minMember := func(value string) map[string]string {
+		return map[string]string{PodGroupMinMemberAnnotationKey: value}
+	}
+
+	testCases := map[string]struct {
+		pods          []*apiv1.Pod
+		wantGangPods  map[string][]string
+		wantMinMember map[string]int
+	}{
+		"pods without group": {
+			pods: []*apiv1.Pod{buildPod("p1", "ns", nil, nil), buildPod("p2", "ns", map[string]string{"app": "a"}, nil)},
+		},
+		"pod group without min member": {
+			pods: []*apiv1.Pod{buildPod("p1", "ns", podGroup, nil), buildPod("p2", "ns", podGroup, nil), buildPod("p3", "ns", nil, nil)},
+			wantGangPods: map[string][]string{
+				"podgroup/ns/group": {"p1", "p2"},
+			},
+			wantMinMember: map[string]int{"podgroup/ns/group": 2},
+		},
+		"pod group with min member": {
+			pods: []*apiv1.Pod{buildPod("p1", "ns", podGroup, minMember("2")), buildPod("p2", "ns", podGroup, minMember("2")), buildPod("p3", "ns", podGroup, minMember("2"))},
+			wantGangPods: map[string][]string{
+				"podgroup/ns/group": {"p1", "p2", "p3"},
+			},
+			wantMinMember: map[string]int{"podgroup/ns/group": 2},
+		},
+		"min member larger than pending pods": {
+			pods: []*apiv1.Pod{buildPod("p1", "ns", podGroup, minMember("5")), buildPod("p2", "ns", podGroup, minMember("5"))},
+			wantGangPods: map[string][]string{
+				"podgroup/ns/group": {"p1", "p2"},
+			},
+			wantMinMember: map[string]int{"podgroup/ns/group": 2},
+		},
+		"invalid min member": {
+			pods: []*apiv1.Pod{buildPod("p1", "ns", podGroup, minMember("invalid")), buildPod("p2", "ns", podGroup, nil)},
+			wantGangPods: map[string][]string{
+				"podgroup/ns/group": {"p1", "p2"},
+			},
+			wantMinMember: map[string]int{"podgroup/ns/group": 2},
+		},
+		"jobsets and pod groups in different namespaces": {
+			pods: []*apiv1.Pod{buildPod("p1", "ns1", jobSet, nil), buildPod("p2", "ns1", jobSet, nil), buildPod("p3", "ns2", jobSet, nil), buildPod("p4", "ns1", podGroup, nil)},
+			wantGangPods: map[string][]string{
+				"jobset/ns1/jobset":  {"p1", "p2"},
+				"jobset/ns2/jobset":  {"p3"},
+				"podgroup/ns1/group": {"p4"},
+			},
+			wantMinMember: map[string]int{"jobset/ns1/jobset": 2, "jobset/ns2/jobset": 1, "podgroup/ns1/group": 1},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			gangs := BuildGangs(tc.pods)
+			assert.Len(t, gangs, len(tc.wantGangPods))
+			for _, gang := range gangs {
+				var podNames []string
+				for _, pod := range gang.Pods {
+					podNames = append(podNames, pod.Name)
+				}
+				assert.Equal(t, tc.wantGangPods[gang.Key], podNames)
+				assert.Equal(t, tc.wantMinMember[gang.Key], gang.MinMember)
+			}
+		})
+	}
+}
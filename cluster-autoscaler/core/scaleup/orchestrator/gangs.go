@@ -0,0 +1,143 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orchestrator
+
+import (
+	"slices"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/core/scaleup/equivalence"
+	"k8s.io/autoscaler/cluster-autoscaler/estimator"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/status"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
+	"k8s.io/klog/v2"
+)
+
+// estimateWholeGangs estimates how many nodes of the node group are needed for the pods, leaving out
+// gangs of which only some pods would fit. Returns the node count, the pods that fit and the gangs
+// left out.
+func (o *ScaleUpOrchestrator) estimateWholeGangs(
+	podGroups []estimator.PodEquivalenceGroup,
+	gangs []*equivalence.Gang,
+	nodeInfo *framework.NodeInfo,
+	nodeGroup cloudprovider.NodeGroup,
+	similarNodeGroups []cloudprovider.NodeGroup,
+	currentNodeCount int,
+) (int, []*apiv1.Pod, []*equivalence.Gang) {
+	var partialGangs []*equivalence.Gang
+	for {
+		expansionEstimator := o.estimatorBuilder(
+			o.autoscalingContext.ClusterSnapshot,
+			estimator.NewEstimationContext(o.autoscalingContext.MaxNodesTotal, similarNodeGroups, currentNodeCount),
+		)
+		nodeCount, pods := expansionEstimator.Estimate(podGroups, nodeInfo, nodeGroup)
+		wholeGangs, partial := splitPartialGangs(gangs, pods)
+		if len(partial) == 0 {
+			return nodeCount, pods, partialGangs
+		}
+		// Every iteration leaves out at least one gang, so the estimation is repeated at most len(gangs) times.
+		for _, gang := range partial {
+			klog.V(4).Infof("Only some pods of gang %s would fit %s, leaving the gang out", gang.Key, nodeGroup.Id())
+		}
+		partialGangs = append(partialGangs, partial...)
+		gangs = wholeGangs
+		podGroups = withoutGangPods(podGroups, partial)
+	}
+}
+
+// splitPartialGangs splits gangs into the ones that fit whole into the estimated pods, or don't fit at all,
+// and the ones that only partially fit.
+func splitPartialGangs(gangs []*equivalence.Gang, pods []*apiv1.Pod) ([]*equivalence.Gang, []*equivalence.Gang) {
+	if len(gangs) == 0 {
+		return nil, nil
+	}
+	fittingPods := make(map[*apiv1.Pod]bool, len(pods))
+	for _, pod := range pods {
+		fittingPods[pod] = true
+	}
+	var wholeGangs, partialGangs []*equivalence.Gang
+	for _, gang := range gangs {
+		fitting := 0
+		for _, pod := range gang.Pods {
+			if fittingPods[pod] {
+				fitting++
+			}
+		}
+		if fitting > 0 && fitting < gang.MinMember {
+			partialGangs = append(partialGangs, gang)
+		} else {
+			wholeGangs = append(wholeGangs, gang)
+		}
+	}
+	return wholeGangs, partialGangs
+}
+
+func withoutGangPods(podGroups []estimator.PodEquivalenceGroup, gangs []*equivalence.Gang) []estimator.PodEquivalenceGroup {
+	gangPods := make(map[*apiv1.Pod]bool)
+	for _, gang := range gangs {
+		for _, pod := range gang.Pods {
+			gangPods[pod] = true
+		}
+	}
+	var result []estimator.PodEquivalenceGroup
+	for _, podGroup := range podGroups {
+		var pods []*apiv1.Pod
+		for _, pod := range podGroup.Pods {
+			if !gangPods[pod] {
+				pods = append(pods, pod)
+			}
+		}
+		if len(pods) > 0 {
+			result = append(result, estimator.PodEquivalenceGroup{Pods: pods})
+		}
+	}
+	return result
+}
+
+func podsInGroups(podGroups []estimator.PodEquivalenceGroup) []*apiv1.Pod {
+	var pods []*apiv1.Pod
+	for _, podGroup := range podGroups {
+		pods = append(pods, podGroup.Pods...)
+	}
+	return pods
+}
+
+// markPartiallySchedulableGangs marks the pods of gangs which would only partially fit the node group
+// as not schedulable on it.
+func markPartiallySchedulableGangs(egs []*equivalence.PodGroup, gangs []*equivalence.Gang, nodeGroupId string) {
+	if len(gangs) == 0 {
+		return
+	}
+	gangPods := make(map[*apiv1.Pod]bool)
+	for _, gang := range gangs {
+		for _, pod := range gang.Pods {
+			gangPods[pod] = true
+		}
+	}
+	for _, eg := range egs {
+		if !slices.ContainsFunc(eg.Pods, func(pod *apiv1.Pod) bool { return gangPods[pod] }) {
+			continue
+		}
+		if eg.SchedulingErrors == nil {
+			eg.SchedulingErrors = map[string]status.Reasons{}
+		}
+		eg.SchedulingErrors[nodeGroupId] = GroupPartiallySchedulableReason
+		eg.SchedulableGroups = slices.DeleteFunc(eg.SchedulableGroups, func(id string) bool { return id == nodeGroupId })
+		eg.Schedulable = len(eg.SchedulableGroups) > 0
+	}
+}
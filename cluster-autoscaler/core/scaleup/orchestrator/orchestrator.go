@@ -31,6 +31,7 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/expander"
 	"k8s.io/autoscaler/cluster-autoscaler/metrics"
 	ca_processors "k8s.io/autoscaler/cluster-autoscaler/processors"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/binpacking"
 	"k8s.io/autoscaler/cluster-autoscaler/processors/nodegroups"
 	"k8s.io/autoscaler/cluster-autoscaler/processors/nodegroupset"
 	"k8s.io/autoscaler/cluster-autoscaler/processors/status"
@@ -101,6 +102,7 @@ func (o *ScaleUpOrchestrator) ScaleUp(
 
 	buildPodEquivalenceGroupsStart := time.Now()
 	podEquivalenceGroups := equivalence.BuildPodGroups(unschedulablePods)
+	gangs := equivalence.BuildGangs(unschedulablePods)
 	metrics.UpdateDurationFromStart(metrics.BuildPodEquivalenceGroups, buildPodEquivalenceGroupsStart)
 
 	upcomingNodes, aErr := o.UpcomingNodes(nodeInfos)
@@ -136,6 +138,11 @@ func (o *ScaleUpOrchestrator) ScaleUp(
 		o.processors.BinpackingLimiter.MarkProcessed(o.autoscalingContext, nodegroupID)
 	}
 
+	// Let the binpacking limiter evaluate the most promising node groups first.
+	if orderer, ok := o.processors.BinpackingLimiter.(binpacking.NodeGroupOrderer); ok {
+		validNodeGroups = orderer.OrderNodeGroups(o.autoscalingContext, validNodeGroups, nodeInfos, estimatorPodGroups(podEquivalenceGroups))
+	}
+
 	// Calculate expansion options
 	schedulablePodGroups := map[string][]estimator.PodEquivalenceGroup{}
 	var options []expander.Option
@@ -145,7 +152,8 @@ func (o *ScaleUpOrchestrator) ScaleUp(
 	}
 
 	for _, nodeGroup := range validNodeGroups {
-		option := o.ComputeExpansionOption(nodeGroup, schedulablePodGroups, nodeInfos, len(nodes)+len(upcomingNodes), now, allOrNothing)
+		option, partialGangs := o.computeExpansionOption(nodeGroup, schedulablePodGroups, nodeInfos, gangs, len(nodes)+len(upcomingNodes), now, allOrNothing)
+		markPartiallySchedulableGangs(podEquivalenceGroups, partialGangs, nodeGroup.Id())
 		o.processors.BinpackingLimiter.MarkProcessed(o.autoscalingContext, nodeGroup.Id())
 
 		if len(option.Pods) == 0 || option.NodeCount == 0 {
@@ -174,7 +182,13 @@ func (o *ScaleUpOrchestrator) ScaleUp(
 	}
 
 	// Pick some expansion option.
+	if observer, ok := o.autoscalingContext.ExpanderStrategy.(expander.ClusterContextObserver); ok {
+		observer.ObserveClusterContext(o.clusterContext(nodeGroups, now))
+	}
 	bestOption := o.autoscalingContext.ExpanderStrategy.BestOption(options, nodeInfos)
+	if o.autoscalingContext.DebuggingSnapshotter != nil {
+		o.autoscalingContext.DebuggingSnapshotter.SetExpanderOptions(options, bestOption)
+	}
 	if bestOption == nil || bestOption.NodeCount <= 0 {
 		return &status.ScaleUpStatus{
 			Result:                  status.ScaleUpNoOptionsAvailable,
@@ -186,8 +200,14 @@ func (o *ScaleUpOrchestrator) ScaleUp(
 	if len(bestOption.Debug) > 0 {
 		klog.V(1).Info(bestOption.Debug)
 	}
+	if len(bestOption.Explanation) > 0 {
+		klog.V(1).Infof("Expander explanation for %s: %s", bestOption.NodeGroup.Id(), bestOption.Explanation)
+	}
 	klog.V(1).Infof("Estimated %d nodes needed in %s", bestOption.NodeCount, bestOption.NodeGroup.Id())
 
+	// Pod groups have to be provisioned together, so request the capacity atomically if the provider supports it.
+	atomicScaleUp := allOrNothing || len(equivalence.BuildGangs(bestOption.Pods)) > 0
+
 	// Cap new nodes to supported number of nodes in the cluster.
 	newNodes, aErr := o.GetCappedNewNodeCount(bestOption.NodeCount, len(nodes)+len(upcomingNodes))
 	if aErr != nil {
@@ -203,28 +223,22 @@ func (o *ScaleUpOrchestrator) ScaleUp(
 
 	if newNodes < bestOption.NodeCount {
 		klog.V(1).Infof("Only %d nodes can be added to %s due to cluster-wide limits", newNodes, bestOption.NodeGroup.Id())
-		if allOrNothing {
-			// Can't execute a scale-up that will accommodate all pods, so nothing is considered schedulable.
-			klog.V(1).Info("Not attempting scale-up due to all-or-nothing strategy: not all pods would be accommodated")
-			markedEquivalenceGroups := markAllGroupsAsUnschedulable(podEquivalenceGroups, AllOrNothingReason)
-			return buildNoOptionsAvailableStatus(markedEquivalenceGroups, skippedNodeGroups, nodeGroups), nil
+		if atomicScaleUp {
+			return buildAtomicScaleUpRejectedStatus(allOrNothing, podEquivalenceGroups, skippedNodeGroups, nodeGroups), nil
 		}
 	}
 
 	// If necessary, create the node group. This is no longer simulation, an empty node group will be created by cloud provider if supported.
 	createNodeGroupResults := make([]nodegroups.CreateNodeGroupResult, 0)
 	if !bestOption.NodeGroup.Exist() && !o.processors.AsyncNodeGroupStateChecker.IsUpcoming(bestOption.NodeGroup) {
-		if allOrNothing && bestOption.NodeGroup.MaxSize() < newNodes {
+		if atomicScaleUp && bestOption.NodeGroup.MaxSize() < newNodes {
 			klog.V(1).Infof("Can only create a new node group with max %d nodes, need %d nodes", bestOption.NodeGroup.MaxSize(), newNodes)
-			// Can't execute a scale-up that will accommodate all pods, so nothing is considered schedulable.
-			klog.V(1).Info("Not attempting scale-up due to all-or-nothing strategy: not all pods would be accommodated")
-			markedEquivalenceGroups := markAllGroupsAsUnschedulable(podEquivalenceGroups, AllOrNothingReason)
-			return buildNoOptionsAvailableStatus(markedEquivalenceGroups, skippedNodeGroups, nodeGroups), nil
+			return buildAtomicScaleUpRejectedStatus(allOrNothing, podEquivalenceGroups, skippedNodeGroups, nodeGroups), nil
 		}
 		var scaleUpStatus *status.ScaleUpStatus
 		oldId := bestOption.NodeGroup.Id()
 		if o.autoscalingContext.AsyncNodeGroupsEnabled {
-			initializer := NewAsyncNodeGroupInitializer(bestOption, nodeInfos[oldId], o.scaleUpExecutor, o.taintConfig, daemonSets, o.processors.ScaleUpStatusProcessor, o.autoscalingContext, allOrNothing)
+			initializer := NewAsyncNodeGroupInitializer(bestOption, nodeInfos[oldId], o.scaleUpExecutor, o.taintConfig, daemonSets, o.processors.ScaleUpStatusProcessor, o.autoscalingContext, atomicScaleUp)
 			createNodeGroupResults, scaleUpStatus, aErr = o.CreateNodeGroupAsync(bestOption, nodeInfos, schedulablePodGroups, podEquivalenceGroups, daemonSets, initializer)
 		} else {
 			createNodeGroupResults, scaleUpStatus, aErr = o.CreateNodeGroup(bestOption, nodeInfos, schedulablePodGroups, podEquivalenceGroups, daemonSets)
@@ -249,17 +263,14 @@ func (o *ScaleUpOrchestrator) ScaleUp(
 	}
 	if totalCapacity < newNodes {
 		klog.V(1).Infof("Can only add %d nodes due to node group limits, need %d nodes", totalCapacity, newNodes)
-		if allOrNothing {
-			// Can't execute a scale-up that will accommodate all pods, so nothing is considered schedulable.
-			klog.V(1).Info("Not attempting scale-up due to all-or-nothing strategy: not all pods would be accommodated")
-			markedEquivalenceGroups := markAllGroupsAsUnschedulable(podEquivalenceGroups, AllOrNothingReason)
-			return buildNoOptionsAvailableStatus(markedEquivalenceGroups, skippedNodeGroups, nodeGroups), nil
+		if atomicScaleUp {
+			return buildAtomicScaleUpRejectedStatus(allOrNothing, podEquivalenceGroups, skippedNodeGroups, nodeGroups), nil
 		}
 	}
 
 	// Execute scale up.
 	klog.V(1).Infof("Final scale-up plan: %v", scaleUpInfos)
-	aErr, failedNodeGroups := o.scaleUpExecutor.ExecuteScaleUps(scaleUpInfos, nodeInfos, now, allOrNothing)
+	aErr, failedNodeGroups := o.scaleUpExecutor.ExecuteScaleUps(scaleUpInfos, nodeInfos, now, atomicScaleUp)
 	if aErr != nil {
 		return status.UpdateScaleUpError(
 			&status.ScaleUpStatus{
@@ -280,9 +291,44 @@ func (o *ScaleUpOrchestrator) ScaleUp(
 		CreateNodeGroupResults:  createNodeGroupResults,
 		PodsTriggeredScaleUp:    bestOption.Pods,
 		PodsAwaitEvaluation:     GetPodsAwaitingEvaluation(podEquivalenceGroups, bestOption.NodeGroup.Id()),
+		ExpanderExplanation:     bestOption.Explanation,
 	}, nil
 }
 
+// clusterContext describes the node groups considered for the scale-up to the expanders.
+func (o *ScaleUpOrchestrator) clusterContext(nodeGroups []cloudprovider.NodeGroup, now time.Time) *expander.ClusterContext {
+	upcomingCounts, _ := o.clusterStateRegistry.GetUpcomingNodes()
+	clusterContext := &expander.ClusterContext{
+		NodeGroups: make(map[string]expander.NodeGroupContext, len(nodeGroups)),
+		ReadyNodes: len(o.clusterStateRegistry.GetClusterReadiness().Ready),
+	}
+	for _, nodeGroup := range nodeGroups {
+		id := nodeGroup.Id()
+		nodeGroupContext := expander.NodeGroupContext{
+			MinSize:       nodeGroup.MinSize(),
+			MaxSize:       nodeGroup.MaxSize(),
+			UpcomingNodes: upcomingCounts[id],
+		}
+		if targetSize, err := nodeGroup.TargetSize(); err == nil {
+			nodeGroupContext.TargetSize = targetSize
+		}
+		if readiness, found := o.clusterStateRegistry.GetNodeGroupReadiness(id); found {
+			nodeGroupContext.ReadyNodes = len(readiness.Ready)
+			nodeGroupContext.UnreadyNodes = len(readiness.Unready) + len(readiness.NotStarted)
+		}
+		if backoffStatus := o.clusterStateRegistry.BackoffStatusForNodeGroup(nodeGroup, now); backoffStatus.IsBackedOff {
+			nodeGroupContext.BackedOff = true
+			nodeGroupContext.BackoffReason = backoffStatus.ErrorInfo.ErrorMessage
+			if nodeGroupContext.BackoffReason == "" {
+				nodeGroupContext.BackoffReason = backoffStatus.ErrorInfo.ErrorCode
+			}
+		}
+		clusterContext.NodeGroups[id] = nodeGroupContext
+		clusterContext.UpcomingNodes += nodeGroupContext.UpcomingNodes
+	}
+	return clusterContext
+}
+
 func (o *ScaleUpOrchestrator) applyLimits(newNodes int, resourcesLeft resource.Limits, nodeGroup cloudprovider.NodeGroup, nodeInfos map[string]*framework.NodeInfo) (int, errors.AutoscalerError) {
 	nodeInfo, found := nodeInfos[nodeGroup.Id()]
 	if !found {
@@ -460,12 +506,28 @@ func (o *ScaleUpOrchestrator) ComputeExpansionOption(
 	now time.Time,
 	allOrNothing bool,
 ) expander.Option {
+	gangs := equivalence.BuildGangs(podsInGroups(schedulablePodGroups[nodeGroup.Id()]))
+	option, _ := o.computeExpansionOption(nodeGroup, schedulablePodGroups, nodeInfos, gangs, currentNodeCount, now, allOrNothing)
+	return option
+}
+
+// computeExpansionOption computes expansion option based on pending pods and cluster state. Gangs which
+// would only partially fit the node group are left out of the option and returned.
+func (o *ScaleUpOrchestrator) computeExpansionOption(
+	nodeGroup cloudprovider.NodeGroup,
+	schedulablePodGroups map[string][]estimator.PodEquivalenceGroup,
+	nodeInfos map[string]*framework.NodeInfo,
+	gangs []*equivalence.Gang,
+	currentNodeCount int,
+	now time.Time,
+	allOrNothing bool,
+) (expander.Option, []*equivalence.Gang) {
 	option := expander.Option{NodeGroup: nodeGroup}
 	podGroups := schedulablePodGroups[nodeGroup.Id()]
 	nodeInfo := nodeInfos[nodeGroup.Id()]
 
 	if len(podGroups) == 0 {
-		return option
+		return option, nil
 	}
 
 	option.SimilarNodeGroups = o.ComputeSimilarNodeGroups(nodeGroup, nodeInfos, schedulablePodGroups, now)
@@ -482,11 +544,8 @@ func (o *ScaleUpOrchestrator) ComputeExpansionOption(
 	}
 
 	estimateStart := time.Now()
-	expansionEstimator := o.estimatorBuilder(
-		o.autoscalingContext.ClusterSnapshot,
-		estimator.NewEstimationContext(o.autoscalingContext.MaxNodesTotal, option.SimilarNodeGroups, currentNodeCount),
-	)
-	option.NodeCount, option.Pods = expansionEstimator.Estimate(podGroups, nodeInfo, nodeGroup)
+	var partialGangs []*equivalence.Gang
+	option.NodeCount, option.Pods, partialGangs = o.estimateWholeGangs(podGroups, gangs, nodeInfo, nodeGroup, option.SimilarNodeGroups, currentNodeCount)
 	metrics.UpdateDurationFromStart(metrics.Estimate, estimateStart)
 
 	autoscalingOptions, err := nodeGroup.GetOptions(o.autoscalingContext.NodeGroupDefaults)
@@ -509,7 +568,7 @@ func (o *ScaleUpOrchestrator) ComputeExpansionOption(
 		}
 	}
 
-	return option
+	return option, partialGangs
 }
 
 // CreateNodeGroup will try to create a new node group based on the initialOption.
@@ -803,6 +862,14 @@ func matchingSchedulablePodGroups(podGroups []estimator.PodEquivalenceGroup, sim
 	return true
 }
 
+func estimatorPodGroups(egs []*equivalence.PodGroup) []estimator.PodEquivalenceGroup {
+	podGroups := make([]estimator.PodEquivalenceGroup, 0, len(egs))
+	for _, eg := range egs {
+		podGroups = append(podGroups, estimator.PodEquivalenceGroup{Pods: eg.Pods})
+	}
+	return podGroups
+}
+
 func markAllGroupsAsUnschedulable(egs []*equivalence.PodGroup, reason status.Reasons) []*equivalence.PodGroup {
 	for _, eg := range egs {
 		if eg.Schedulable {
@@ -818,6 +885,20 @@ func markAllGroupsAsUnschedulable(egs []*equivalence.PodGroup, reason status.Rea
 	return egs
 }
 
+// buildAtomicScaleUpRejectedStatus gives up on an atomic scale-up that can't accommodate all its pods: either
+// all pods for the all-or-nothing strategy, or whole pod groups. Nothing is then considered schedulable.
+func buildAtomicScaleUpRejectedStatus(allOrNothing bool, egs []*equivalence.PodGroup, skipped map[string]status.Reasons, ngs []cloudprovider.NodeGroup) *status.ScaleUpStatus {
+	reason := AllOrNothingReason
+	if allOrNothing {
+		klog.V(1).Info("Not attempting scale-up due to all-or-nothing strategy: not all pods would be accommodated")
+	} else {
+		klog.V(1).Info("Not attempting scale-up: not all pods of the pod groups would be accommodated")
+		reason = GroupPartiallySchedulableReason
+	}
+	markedEquivalenceGroups := markAllGroupsAsUnschedulable(egs, reason)
+	return buildNoOptionsAvailableStatus(markedEquivalenceGroups, skipped, ngs)
+}
+
 func buildNoOptionsAvailableStatus(egs []*equivalence.PodGroup, skipped map[string]status.Reasons, ngs []cloudprovider.NodeGroup) *status.ScaleUpStatus {
 	return &status.ScaleUpStatus{
 		Result:                  status.ScaleUpNoOptionsAvailable,
@@ -34,10 +34,12 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/clusterstate"
 	"k8s.io/autoscaler/cluster-autoscaler/config"
 	"k8s.io/autoscaler/cluster-autoscaler/context"
+	"k8s.io/autoscaler/cluster-autoscaler/core/scaleup/equivalence"
 	"k8s.io/autoscaler/cluster-autoscaler/core/scaleup/resource"
 	. "k8s.io/autoscaler/cluster-autoscaler/core/test"
 	"k8s.io/autoscaler/cluster-autoscaler/core/utils"
 	"k8s.io/autoscaler/cluster-autoscaler/estimator"
+	"k8s.io/autoscaler/cluster-autoscaler/expander"
 	"k8s.io/autoscaler/cluster-autoscaler/metrics"
 	"k8s.io/autoscaler/cluster-autoscaler/processors"
 	"k8s.io/autoscaler/cluster-autoscaler/processors/nodegroupconfig"
@@ -889,6 +891,126 @@ func TestAllOrNothing(t *testing.T) {
 	simpleNoScaleUpTest(t, config, result)
 }
 
+func TestGangScaleUp(t *testing.T) {
+	podGroup := map[string]string{equivalence.PodGroupLabelKey: "gang"}
+	jobSet := map[string]string{equivalence.JobSetNameLabelKey: "jobset"}
+
+	t.Run("whole pod group fits", func(t *testing.T) {
+		options := defaultOptions
+		config := &ScaleUpTestConfig{
+			Nodes: []NodeConfig{
+				{Name: "n1", Cpu: 1000, Memory: 1000, Gpu: 0, Ready: true, Group: "ng"},
+			},
+			ExtraPods: []PodConfig{
+				{Name: "p1", Cpu: 800, Memory: 100, Labels: jobSet},
+				{Name: "p2", Cpu: 800, Memory: 100, Labels: jobSet},
+				{Name: "p3", Cpu: 800, Memory: 100, Labels: jobSet},
+			},
+			Options: &options,
+		}
+		results := &ScaleTestResults{
+			FinalOption: GroupSizeChange{GroupName: "ng", SizeChange: 3},
+			ScaleUpStatus: ScaleUpStatusInfo{
+				PodsTriggeredScaleUp: []string{"p1", "p2", "p3"},
+			},
+		}
+		simpleScaleUpTest(t, config, results)
+	})
+
+	t.Run("partially schedulable pod group doesn't trigger scale-up", func(t *testing.T) {
+		options := defaultOptions
+		config := &ScaleUpTestConfig{
+			Nodes: []NodeConfig{
+				{Name: "n1", Cpu: 1000, Memory: 1000, Gpu: 0, Ready: true, Group: "ng"},
+			},
+			ExtraPods: []PodConfig{
+				{Name: "p1", Cpu: 800, Memory: 100, Labels: podGroup},
+				{Name: "p2", Cpu: 800, Memory: 100, Labels: podGroup},
+				{Name: "p3", Cpu: 800, Memory: 100, Gpu: 1, Labels: podGroup},
+			},
+			Options: &options,
+		}
+		results := &ScaleTestResults{
+			NoScaleUpReason: "group partially schedulable",
+			ScaleUpStatus: ScaleUpStatusInfo{
+				PodsRemainUnschedulable: []string{"p1", "p2", "p3"},
+			},
+		}
+		simpleNoScaleUpTest(t, config, results)
+	})
+
+	t.Run("partially schedulable pod group is left out of the option", func(t *testing.T) {
+		options := defaultOptions
+		config := &ScaleUpTestConfig{
+			Nodes: []NodeConfig{
+				{Name: "n1", Cpu: 1000, Memory: 1000, Gpu: 0, Ready: true, Group: "ng"},
+			},
+			ExtraPods: []PodConfig{
+				{Name: "p1", Cpu: 800, Memory: 100, Labels: podGroup},
+				{Name: "p2", Cpu: 800, Memory: 100, Gpu: 1, Labels: podGroup},
+				{Name: "p3", Cpu: 800, Memory: 100},
+			},
+			Options: &options,
+		}
+		results := &ScaleTestResults{
+			FinalOption: GroupSizeChange{GroupName: "ng", SizeChange: 1},
+			ScaleUpStatus: ScaleUpStatusInfo{
+				PodsTriggeredScaleUp:    []string{"p3"},
+				PodsRemainUnschedulable: []string{"p1", "p2"},
+			},
+		}
+		simpleScaleUpTest(t, config, results)
+	})
+
+	t.Run("pod group capped by cluster-wide limits doesn't trigger scale-up", func(t *testing.T) {
+		options := defaultOptions
+		options.MaxCoresTotal = 3
+		config := &ScaleUpTestConfig{
+			Nodes: []NodeConfig{
+				{Name: "n1", Cpu: 1000, Memory: 1000, Gpu: 0, Ready: true, Group: "ng"},
+			},
+			ExtraPods: []PodConfig{
+				{Name: "p1", Cpu: 800, Memory: 100, Labels: podGroup},
+				{Name: "p2", Cpu: 800, Memory: 100, Labels: podGroup},
+				{Name: "p3", Cpu: 800, Memory: 100, Labels: podGroup},
+			},
+			Options: &options,
+		}
+		results := &ScaleTestResults{
+			NoScaleUpReason: "group partially schedulable",
+			ScaleUpStatus: ScaleUpStatusInfo{
+				PodsRemainUnschedulable: []string{"p1", "p2", "p3"},
+			},
+		}
+		simpleNoScaleUpTest(t, config, results)
+	})
+
+	t.Run("pod group capped by node group max size doesn't trigger scale-up", func(t *testing.T) {
+		options := defaultOptions
+		config := &ScaleUpTestConfig{
+			Groups: []NodeGroupConfig{
+				{Name: "ng", MinSize: 1, MaxSize: 3},
+			},
+			Nodes: []NodeConfig{
+				{Name: "n1", Cpu: 1000, Memory: 1000, Gpu: 0, Ready: true, Group: "ng"},
+			},
+			ExtraPods: []PodConfig{
+				{Name: "p1", Cpu: 800, Memory: 100, Labels: jobSet},
+				{Name: "p2", Cpu: 800, Memory: 100, Labels: jobSet},
+				{Name: "p3", Cpu: 800, Memory: 100, Labels: jobSet},
+			},
+			Options: &options,
+		}
+		results := &ScaleTestResults{
+			NoScaleUpReason: "group partially schedulable",
+			ScaleUpStatus: ScaleUpStatusInfo{
+				PodsRemainUnschedulable: []string{"p1", "p2", "p3"},
+			},
+		}
+		simpleNoScaleUpTest(t, config, results)
+	})
+}
+
 func simpleScaleUpTest(t *testing.T, config *ScaleUpTestConfig, expectedResults *ScaleTestResults) {
 	results := runSimpleScaleUpTest(t, config)
 	assert.NotNil(t, results.GroupSizeChanges, "Expected scale up event")
@@ -1116,6 +1238,9 @@ func buildTestPod(p PodConfig) *apiv1.Pod {
 	if p.Node != "" {
 		pod.Spec.NodeName = p.Node
 	}
+	if p.Labels != nil {
+		pod.Labels = p.Labels
+	}
 	return pod
 }
 
@@ -1228,6 +1353,61 @@ func TestBinpackingLimiter(t *testing.T) {
 	assert.True(t, len(expansionOptions) == 1)
 }
 
+// explainingStrategy picks the first option, with an explanation, and records the cluster context it observed.
+type explainingStrategy struct {
+	clusterContext *expander.ClusterContext
+}
+
+func (s *explainingStrategy) ObserveClusterContext(clusterContext *expander.ClusterContext) {
+	s.clusterContext = clusterContext
+}
+
+func (s *explainingStrategy) BestOption(options []expander.Option, _ map[string]*framework.NodeInfo) *expander.Option {
+	best := options[0]
+	best.Explanation = "first option"
+	return &best
+}
+
+func TestScaleUpExpanderClusterContext(t *testing.T) {
+	n1 := BuildTestNode("n1", 1000, 1000)
+	now := time.Now()
+	SetNodeReadyState(n1, true, now.Add(-2*time.Minute))
+	nodes := []*apiv1.Node{n1}
+
+	podLister := kube_util.NewTestPodLister([]*apiv1.Pod{})
+	listers := kube_util.NewListerRegistry(nil, nil, podLister, nil, nil, nil, nil, nil, nil)
+	provider := testprovider.NewTestCloudProviderBuilder().WithOnScaleUp(func(nodeGroup string, increase int) error {
+		return nil
+	}).Build()
+	provider.AddNodeGroup("ng1", 1, 10, 1)
+	provider.AddNode("ng1", n1)
+
+	context, err := NewScaleTestAutoscalingContext(defaultOptions, &fake.Clientset{}, listers, provider, nil, nil)
+	assert.NoError(t, err)
+	err = context.ClusterSnapshot.SetClusterState(nodes, nil, nil)
+	assert.NoError(t, err)
+	nodeInfos, err := nodeinfosprovider.NewDefaultTemplateNodeInfoProvider(nil, false).
+		Process(&context, nodes, []*appsv1.DaemonSet{}, taints.TaintConfig{}, now)
+	assert.NoError(t, err)
+	clusterState := clusterstate.NewClusterStateRegistry(provider, clusterstate.ClusterStateRegistryConfig{}, context.LogRecorder, NewBackoff(), nodegroupconfig.NewDefaultNodeGroupConfigProcessor(config.NodeGroupAutoscalingOptions{MaxNodeProvisionTime: 15 * time.Minute}), asyncnodegroups.NewDefaultAsyncNodeGroupStateChecker())
+	clusterState.UpdateNodes(nodes, nodeInfos, now)
+
+	processors := processorstest.NewTestProcessors(&context)
+	suOrchestrator := New()
+	suOrchestrator.Initialize(&context, processors, clusterState, newEstimatorBuilder(), taints.TaintConfig{})
+	strategy := &explainingStrategy{}
+	context.ExpanderStrategy = strategy
+
+	scaleUpStatus, err := suOrchestrator.ScaleUp([]*apiv1.Pod{BuildTestPod("p-new", 500, 0)}, nodes, []*appsv1.DaemonSet{}, nodeInfos, false)
+	assert.NoError(t, err)
+	assert.True(t, scaleUpStatus.WasSuccessful())
+	assert.Equal(t, "first option", scaleUpStatus.ExpanderExplanation)
+	assert.Equal(t, &expander.ClusterContext{
+		NodeGroups: map[string]expander.NodeGroupContext{"ng1": {MinSize: 1, MaxSize: 10, TargetSize: 1, ReadyNodes: 1}},
+		ReadyNodes: 1,
+	}, strategy.clusterContext)
+}
+
 func TestScaleUpNoHelp(t *testing.T) {
 	n1 := BuildTestNode("n1", 100, 1000)
 	now := time.Now()
@@ -34,4 +34,6 @@ func (sr *RejectedReasons) Reasons() []string {
 var (
 	// AllOrNothingReason means the node group was rejected because not all pods would fit it when using all-or-nothing strategy.
 	AllOrNothingReason = NewRejectedReasons("not all pods would fit and scale-up is using all-or-nothing strategy")
+	// GroupPartiallySchedulableReason means the node group was rejected because only some pods of a pod group would fit it.
+	GroupPartiallySchedulableReason = NewRejectedReasons("group partially schedulable: not all pods of the pod group would fit")
 )
@@ -37,6 +37,7 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/core/scaleup/orchestrator"
 	core_utils "k8s.io/autoscaler/cluster-autoscaler/core/utils"
 	"k8s.io/autoscaler/cluster-autoscaler/debuggingsnapshot"
+	"k8s.io/autoscaler/cluster-autoscaler/debuggingsnapshot/journal"
 	"k8s.io/autoscaler/cluster-autoscaler/estimator"
 	"k8s.io/autoscaler/cluster-autoscaler/expander"
 	"k8s.io/autoscaler/cluster-autoscaler/metrics"
@@ -88,6 +89,7 @@ type StaticAutoscaler struct {
 	processorCallbacks      *staticAutoscalerProcessorCallbacks
 	initialized             bool
 	taintConfig             taints.TaintConfig
+	decisionJournal         *journal.Journal
 }
 
 type staticAutoscalerProcessorCallbacks struct {
@@ -137,6 +139,7 @@ func NewStaticAutoscaler(
 	estimatorBuilder estimator.EstimatorBuilder,
 	backoff backoff.Backoff,
 	debuggingSnapshotter debuggingsnapshot.DebuggingSnapshotter,
+	decisionJournal *journal.Journal,
 	remainingPdbTracker pdb.RemainingPdbTracker,
 	scaleUpOrchestrator scaleup.Orchestrator,
 	deleteOptions options.NodeDeleteOptions,
@@ -198,6 +201,7 @@ func NewStaticAutoscaler(
 		processorCallbacks:      processorCallbacks,
 		clusterStateRegistry:    clusterStateRegistry,
 		taintConfig:             taintConfig,
+		decisionJournal:         decisionJournal,
 	}
 }
 
@@ -257,13 +261,23 @@ func (a *StaticAutoscaler) initializeRemainingPdbTracker() caerrors.AutoscalerEr
 }
 
 // RunOnce iterates over node groups and scales them up/down if necessary
-func (a *StaticAutoscaler) RunOnce(currentTime time.Time) caerrors.AutoscalerError {
+func (a *StaticAutoscaler) RunOnce(currentTime time.Time) (loopErr caerrors.AutoscalerError) {
 	a.cleanUpIfRequired()
 	a.processorCallbacks.reset()
 	a.clusterStateRegistry.PeriodicCleanup()
 	a.DebuggingSnapshotter.StartDataCollection()
 	defer a.DebuggingSnapshotter.Flush()
 
+	loopStart := time.Now()
+	journalEntry := journal.NewEntry(currentTime)
+	defer func() {
+		if loopErr != nil {
+			journalEntry.Error = loopErr.Error()
+		}
+		journalEntry.ObserveDuration("total", time.Since(loopStart))
+		a.decisionJournal.Record(journalEntry)
+	}()
+
 	podLister := a.AllPodLister()
 	autoscalingContext := a.AutoscalingContext
 
@@ -296,6 +310,9 @@ func (a *StaticAutoscaler) RunOnce(currentTime time.Time) caerrors.AutoscalerErr
 	if err != nil {
 		return caerrors.ToAutoscalerError(caerrors.ApiCallError, err)
 	}
+	if a.decisionJournal != nil {
+		journalEntry.PendingPods = podRefs(unschedulablePods)
+	}
 
 	coresTotal, memoryTotal := calculateCoresMemoryTotal(allNodes, currentTime)
 	metrics.UpdateClusterCPUCurrentCores(coresTotal)
@@ -317,6 +334,7 @@ func (a *StaticAutoscaler) RunOnce(currentTime time.Time) caerrors.AutoscalerErr
 		a.clusterStateRegistry.Recalculate()
 	}
 	metrics.UpdateDurationFromStart(metrics.CloudProviderRefresh, refreshStart)
+	journalEntry.ObserveDuration(string(metrics.CloudProviderRefresh), time.Since(refreshStart))
 	if err != nil {
 		klog.Errorf("Failed to refresh cloud provider config: %v", err)
 		return caerrors.ToAutoscalerError(caerrors.CloudProviderError, err)
@@ -355,15 +373,21 @@ func (a *StaticAutoscaler) RunOnce(currentTime time.Time) caerrors.AutoscalerErr
 	}
 
 	a.DebuggingSnapshotter.SetTemplateNodes(nodeInfosForGroups)
+	if a.DebuggingSnapshotter.IsDataCollectionAllowed() {
+		a.DebuggingSnapshotter.SetNodeGroups(nodeGroupsStates(a.CloudProvider, allNodes))
+	}
 
 	if typedErr := a.updateClusterState(allNodes, nodeInfosForGroups, currentTime); typedErr != nil {
 		klog.Errorf("Failed to update cluster state: %v", typedErr)
 		return typedErr
 	}
 	metrics.UpdateDurationFromStart(metrics.UpdateState, stateUpdateStart)
+	journalEntry.ObserveDuration(string(metrics.UpdateState), time.Since(stateUpdateStart))
 
 	scaleUpStatus := &status.ScaleUpStatus{Result: status.ScaleUpNotTried}
 	scaleUpStatusProcessorAlreadyCalled := false
+	// loopScaleUpStatus is the scale-up recorded in decisions, when node groups min size enforcement is a no-op
+	var loopScaleUpStatus *status.ScaleUpStatus
 	scaleDownStatus := &scaledownstatus.ScaleDownStatus{Result: scaledownstatus.ScaleDownNotTried}
 
 	defer func() {
@@ -390,6 +414,16 @@ func (a *StaticAutoscaler) RunOnce(currentTime time.Time) caerrors.AutoscalerErr
 			a.processors.ScaleDownStatusProcessor.Process(a.AutoscalingContext, scaleDownStatus)
 		}
 
+		if a.decisionJournal != nil || a.DebuggingSnapshotter.IsDataCollectionAllowed() {
+			if loopScaleUpStatus == nil {
+				loopScaleUpStatus = scaleUpStatus
+			}
+			journalEntry.ScaleUp = scaleUpDecision(loopScaleUpStatus)
+			journalEntry.ScaleDown = scaleDownDecision(scaleDownStatus, a.scaleDownPlanner.UnneededNodes())
+			a.DebuggingSnapshotter.SetScaleUpDecision(journalEntry.ScaleUp)
+			a.DebuggingSnapshotter.SetScaleDownDecision(journalEntry.ScaleDown)
+		}
+
 		if a.processors != nil && a.processors.AutoscalingStatusProcessor != nil {
 			err := a.processors.AutoscalingStatusProcessor.Process(a.AutoscalingContext, a.clusterStateRegistry, currentTime)
 			if err != nil {
@@ -479,6 +513,7 @@ func (a *StaticAutoscaler) RunOnce(currentTime time.Time) caerrors.AutoscalerErr
 	} else {
 		a.AutoscalingContext.DebuggingSnapshotter.SetClusterNodes(l)
 	}
+	a.AutoscalingContext.DebuggingSnapshotter.SetUnschedulablePods(unschedulablePods)
 
 	unschedulablePodsToHelp, err := a.processors.PodListProcessor.Process(a.AutoscalingContext, unschedulablePods)
 
@@ -496,6 +531,7 @@ func (a *StaticAutoscaler) RunOnce(currentTime time.Time) caerrors.AutoscalerErr
 
 	postScaleUp := func(scaleUpStart time.Time) {
 		metrics.UpdateDurationFromStart(metrics.ScaleUp, scaleUpStart)
+		journalEntry.ObserveDuration(string(metrics.ScaleUp), time.Since(scaleUpStart))
 
 		if a.processors != nil && a.processors.ScaleUpStatusProcessor != nil {
 			a.processors.ScaleUpStatusProcessor.Process(autoscalingContext, scaleUpStatus)
@@ -597,6 +633,7 @@ func (a *StaticAutoscaler) RunOnce(currentTime time.Time) caerrors.AutoscalerErr
 		}
 
 		metrics.UpdateDurationFromStart(metrics.FindUnneeded, unneededStart)
+		journalEntry.ObserveDuration(string(metrics.FindUnneeded), time.Since(unneededStart))
 
 		scaleDownInCooldown := a.isScaleDownInCooldown(currentTime)
 		klog.V(4).Infof("Scale down status: lastScaleUpTime=%s lastScaleDownDeleteTime=%v "+
@@ -635,6 +672,7 @@ func (a *StaticAutoscaler) RunOnce(currentTime time.Time) caerrors.AutoscalerErr
 			scaleDownStatus.Result = scaleDownResult
 			scaleDownStatus.ScaledDownNodes = scaledDownNodes
 			metrics.UpdateDurationFromStart(metrics.ScaleDown, scaleDownStart)
+			journalEntry.ObserveDuration(string(metrics.ScaleDown), time.Since(scaleDownStart))
 			metrics.UpdateUnremovableNodesCount(countsByReason(a.scaleDownPlanner.UnremovableNodes()))
 
 			scaleDownStatus.RemovedNodeGroups = removedNodeGroups
@@ -653,9 +691,13 @@ func (a *StaticAutoscaler) RunOnce(currentTime time.Time) caerrors.AutoscalerErr
 	}
 
 	if a.EnforceNodeGroupMinSize {
+		loopScaleUpStatus = scaleUpStatus
 		scaleUpStart := preScaleUp()
 		scaleUpStatus, typedErr = a.scaleUpOrchestrator.ScaleUpToNodeGroupMinSize(readyNodes, nodeInfosForGroups)
 		postScaleUp(scaleUpStart)
+		if scaleUpStatus.Result != status.ScaleUpNotNeeded {
+			loopScaleUpStatus = scaleUpStatus
+		}
 	}
 
 	return nil
@@ -1100,6 +1142,32 @@ func calculateCoresMemoryTotal(nodes []*apiv1.Node, timestamp time.Time) (int64,
 	return coresTotal, memoryTotal
 }
 
+// nodeGroupsStates returns the node groups sizes and members, for the debugging snapshot.
+func nodeGroupsStates(cloudProvider cloudprovider.CloudProvider, nodes []*apiv1.Node) map[string]*debuggingsnapshot.NodeGroupState {
+	states := make(map[string]*debuggingsnapshot.NodeGroupState)
+	for _, nodeGroup := range cloudProvider.NodeGroups() {
+		targetSize, err := nodeGroup.TargetSize()
+		if err != nil {
+			klog.Warningf("Failed to get target size of node group %s for debugging snapshot: %v", nodeGroup.Id(), err)
+		}
+		states[nodeGroup.Id()] = &debuggingsnapshot.NodeGroupState{
+			MinSize:    nodeGroup.MinSize(),
+			MaxSize:    nodeGroup.MaxSize(),
+			TargetSize: targetSize,
+		}
+	}
+	for _, node := range nodes {
+		nodeGroup, err := cloudProvider.NodeGroupForNode(node)
+		if err != nil || nodeGroup == nil || reflect.ValueOf(nodeGroup).IsNil() {
+			continue
+		}
+		if state, found := states[nodeGroup.Id()]; found {
+			state.Nodes = append(state.Nodes, node.Name)
+		}
+	}
+	return states
+}
+
 func countsByReason(nodes []*simulator.UnremovableNode) map[simulator.UnremovableReason]int {
 	counts := make(map[simulator.UnremovableReason]int)
 
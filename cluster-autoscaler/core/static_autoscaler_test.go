@@ -53,6 +53,8 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/core/scaleup/orchestrator"
 	. "k8s.io/autoscaler/cluster-autoscaler/core/test"
 	core_utils "k8s.io/autoscaler/cluster-autoscaler/core/utils"
+	"k8s.io/autoscaler/cluster-autoscaler/debuggingsnapshot"
+	"k8s.io/autoscaler/cluster-autoscaler/debuggingsnapshot/journal"
 	"k8s.io/autoscaler/cluster-autoscaler/estimator"
 	"k8s.io/autoscaler/cluster-autoscaler/observers/loopstart"
 	ca_processors "k8s.io/autoscaler/cluster-autoscaler/processors"
@@ -426,6 +428,7 @@ func TestStaticAutoscalerRunOnce(t *testing.T) {
 		loopStartNotifier:     loopstart.NewObserversList(nil),
 		processorCallbacks:    processorCallbacks,
 		initialized:           true,
+		decisionJournal:       journal.New(10),
 	}
 
 	// MaxNodesTotal reached.
@@ -453,6 +456,15 @@ func TestStaticAutoscalerRunOnce(t *testing.T) {
 	mock.AssertExpectationsForObjects(t, allPodListerMock,
 		podDisruptionBudgetListerMock, daemonSetListerMock, onScaleUpMock, onScaleDownMock)
 
+	// Loops are recorded in the decision journal.
+	entries := autoscaler.decisionJournal.Entries()
+	assert.Equal(t, 2, len(entries))
+	assert.Equal(t, "LimitedByMaxNodesTotal", entries[0].ScaleUp.Result)
+	assert.Equal(t, []string{"default/p2"}, entries[1].PendingPods)
+	assert.Equal(t, "Successful", entries[1].ScaleUp.Result)
+	assert.Equal(t, []*debuggingsnapshot.NodeGroupScaleUp{{NodeGroup: "ng1", CurrentSize: 1, NewSize: 2, MaxSize: 10}}, entries[1].ScaleUp.ScaleUps)
+	assert.Contains(t, entries[1].TimingsSeconds, "scaleUp")
+
 	// Mark unneeded nodes.
 	readyNodeLister.SetNodes([]*apiv1.Node{n1, n2})
 	allNodeLister.SetNodes([]*apiv1.Node{n1, n2})
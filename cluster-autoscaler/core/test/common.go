@@ -70,6 +70,7 @@ type PodConfig struct {
 	Gpu          int64
 	Node         string
 	ToleratesGpu bool
+	Labels       map[string]string
 }
 
 // GroupSizeChange represents a change in group size
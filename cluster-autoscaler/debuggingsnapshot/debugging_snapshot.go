@@ -21,16 +21,85 @@ import (
 	"time"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/expander"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
 	"k8s.io/klog/v2"
 )
 
+// SchemaVersion is the version of the snapshot JSON schema. Adding fields doesn't
+// change it, but removing, renaming or changing the meaning of a field does.
+// Snapshots without a SchemaVersion predate versioning, and are version 1.
+const SchemaVersion = 2
+
 // ClusterNode captures a single entity of nodeInfo. i.e. Node specs and all the pods on that node.
 type ClusterNode struct {
 	Node *v1.Node  `json:"Node"`
 	Pods []*v1.Pod `json:"Pods"`
 }
 
+// NodeGroupState captures the sizes of a node group, and the nodes belonging to it.
+type NodeGroupState struct {
+	MinSize    int      `json:"MinSize"`
+	MaxSize    int      `json:"MaxSize"`
+	TargetSize int      `json:"TargetSize"`
+	Nodes      []string `json:"Nodes"`
+}
+
+// ExpanderOption captures a scale-up option evaluated by the expander.
+type ExpanderOption struct {
+	NodeGroup         string   `json:"NodeGroup"`
+	SimilarNodeGroups []string `json:"SimilarNodeGroups,omitempty"`
+	NodeCount         int      `json:"NodeCount"`
+	Pods              []string `json:"Pods"`
+	Debug             string   `json:"Debug,omitempty"`
+	Best              bool     `json:"Best,omitempty"`
+	Explanation       string   `json:"Explanation,omitempty"`
+}
+
+// NodeGroupScaleUp captures the resize of a node group by a scale-up.
+type NodeGroupScaleUp struct {
+	NodeGroup   string `json:"NodeGroup"`
+	CurrentSize int    `json:"CurrentSize"`
+	NewSize     int    `json:"NewSize"`
+	MaxSize     int    `json:"MaxSize"`
+}
+
+// NoScaleUpReasons captures why a pod didn't trigger a scale-up, per node group.
+type NoScaleUpReasons struct {
+	Pod                string              `json:"Pod"`
+	RejectedNodeGroups map[string][]string `json:"RejectedNodeGroups,omitempty"`
+	SkippedNodeGroups  map[string][]string `json:"SkippedNodeGroups,omitempty"`
+}
+
+// ScaleUpDecision captures the outcome of the loop scale-up. Pods are
+// referenced by their "namespace/name".
+type ScaleUpDecision struct {
+	Result                  string              `json:"Result"`
+	Error                   string              `json:"Error,omitempty"`
+	ScaleUps                []*NodeGroupScaleUp `json:"ScaleUps,omitempty"`
+	PodsTriggeredScaleUp    []string            `json:"PodsTriggeredScaleUp,omitempty"`
+	PodsRemainUnschedulable []*NoScaleUpReasons `json:"PodsRemainUnschedulable,omitempty"`
+	PodsAwaitEvaluation     []string            `json:"PodsAwaitEvaluation,omitempty"`
+}
+
+// UnremovableNode captures why a node can't be scaled down.
+type UnremovableNode struct {
+	Node              string `json:"Node"`
+	NodeGroup         string `json:"NodeGroup,omitempty"`
+	Reason            string `json:"Reason"`
+	BlockingPod       string `json:"BlockingPod,omitempty"`
+	BlockingPodReason string `json:"BlockingPodReason,omitempty"`
+}
+
+// ScaleDownDecision captures the outcome of the loop scale-down.
+type ScaleDownDecision struct {
+	Result            string             `json:"Result"`
+	UnneededNodes     []string           `json:"UnneededNodes,omitempty"`
+	UnremovableNodes  []*UnremovableNode `json:"UnremovableNodes,omitempty"`
+	ScaledDownNodes   []string           `json:"ScaledDownNodes,omitempty"`
+	RemovedNodeGroups []string           `json:"RemovedNodeGroups,omitempty"`
+}
+
 // DebuggingSnapshot is the interface used to define any debugging snapshot
 // implementation, incl. any custom impl. to be used by DebuggingSnapshotter
 type DebuggingSnapshot interface {
@@ -39,9 +108,26 @@ type DebuggingSnapshot interface {
 	// SetUnscheduledPodsCanBeScheduled is a setter for all pods which are unscheduled,
 	// but they can be scheduled. i.e. pods which aren't triggering scale-up
 	SetUnscheduledPodsCanBeScheduled([]*v1.Pod)
+	// SetUnschedulablePods is a setter for all pods which are unschedulable
+	// at the start of the loop, before any filtering
+	SetUnschedulablePods([]*v1.Pod)
+	// SetNodeGroups is a setter for the node groups sizes and members
+	SetNodeGroups(map[string]*NodeGroupState)
 	// SetTemplateNodes is a setter for all the TemplateNodes present in the cluster
 	// incl. templates for which there are no nodes
 	SetTemplateNodes(map[string]*framework.NodeInfo)
+	// SetTemplateNodesExplanations is a setter for the explanations of how
+	// TemplateNodes were built, per node group
+	SetTemplateNodesExplanations(map[string][]string)
+	// SetExpanderOptions is a setter for the scale-up options evaluated by the
+	// expander, and the best one it picked
+	SetExpanderOptions([]expander.Option, *expander.Option)
+	// SetScaleUpDecision is a setter for the outcome of the scale-up
+	SetScaleUpDecision(*ScaleUpDecision)
+	// SetScaleDownDecision is a setter for the outcome of the scale-down
+	SetScaleDownDecision(*ScaleDownDecision)
+	// SetFilter limits the output to some node groups and namespaces
+	SetFilter(*Filter)
 	// SetErrorMessage sets the error message in the snapshot
 	SetErrorMessage(string)
 	// SetEndTimestamp sets the timestamp in the snapshot,
@@ -61,12 +147,21 @@ type DebuggingSnapshot interface {
 // Please add all new output fields in this struct. This is to make the data
 // encoding/decoding easier as the single object going into the decoder
 type DebuggingSnapshotImpl struct {
-	NodeList                      []*ClusterNode          `json:"NodeList"`
-	UnscheduledPodsCanBeScheduled []*v1.Pod               `json:"UnscheduledPodsCanBeScheduled"`
-	Error                         string                  `json:"Error,omitempty"`
-	StartTimestamp                time.Time               `json:"StartTimestamp"`
-	EndTimestamp                  time.Time               `json:"EndTimestamp"`
-	TemplateNodes                 map[string]*ClusterNode `json:"TemplateNodes"`
+	SchemaVersion                 int                        `json:"SchemaVersion"`
+	NodeList                      []*ClusterNode             `json:"NodeList"`
+	UnscheduledPodsCanBeScheduled []*v1.Pod                  `json:"UnscheduledPodsCanBeScheduled"`
+	UnschedulablePods             []*v1.Pod                  `json:"UnschedulablePods,omitempty"`
+	NodeGroups                    map[string]*NodeGroupState `json:"NodeGroups,omitempty"`
+	Error                         string                     `json:"Error,omitempty"`
+	StartTimestamp                time.Time                  `json:"StartTimestamp"`
+	EndTimestamp                  time.Time                  `json:"EndTimestamp"`
+	TemplateNodes                 map[string]*ClusterNode    `json:"TemplateNodes"`
+	TemplateNodesExplanations     map[string][]string        `json:"TemplateNodesExplanations,omitempty"`
+	ExpanderOptions               []*ExpanderOption          `json:"ExpanderOptions,omitempty"`
+	ScaleUp                       *ScaleUpDecision           `json:"ScaleUp,omitempty"`
+	ScaleDown                     *ScaleDownDecision         `json:"ScaleDown,omitempty"`
+
+	filter *Filter
 }
 
 // SetUnscheduledPodsCanBeScheduled is the setter for UnscheduledPodsCanBeScheduled
@@ -81,6 +176,32 @@ func (s *DebuggingSnapshotImpl) SetUnscheduledPodsCanBeScheduled(podList []*v1.P
 	}
 }
 
+// SetUnschedulablePods is the setter for UnschedulablePods
+func (s *DebuggingSnapshotImpl) SetUnschedulablePods(podList []*v1.Pod) {
+	if podList == nil {
+		return
+	}
+
+	s.UnschedulablePods = nil
+	for _, pod := range podList {
+		s.UnschedulablePods = append(s.UnschedulablePods, pod.DeepCopy())
+	}
+}
+
+// SetNodeGroups is the setter for NodeGroups
+func (s *DebuggingSnapshotImpl) SetNodeGroups(nodeGroups map[string]*NodeGroupState) {
+	if nodeGroups == nil {
+		return
+	}
+
+	s.NodeGroups = make(map[string]*NodeGroupState)
+	for id, state := range nodeGroups {
+		stateCopy := *state
+		stateCopy.Nodes = append([]string(nil), state.Nodes...)
+		s.NodeGroups[id] = &stateCopy
+	}
+}
+
 // SetTemplateNodes is the setter for TemplateNodes
 func (s *DebuggingSnapshotImpl) SetTemplateNodes(templates map[string]*framework.NodeInfo) {
 	if templates == nil {
@@ -93,6 +214,71 @@ func (s *DebuggingSnapshotImpl) SetTemplateNodes(templates map[string]*framework
 	}
 }
 
+// SetTemplateNodesExplanations is the setter for TemplateNodesExplanations
+func (s *DebuggingSnapshotImpl) SetTemplateNodesExplanations(explanations map[string][]string) {
+	if explanations == nil {
+		return
+	}
+
+	s.TemplateNodesExplanations = make(map[string][]string)
+	for ng, lines := range explanations {
+		s.TemplateNodesExplanations[ng] = append([]string(nil), lines...)
+	}
+}
+
+// SetExpanderOptions is the setter for ExpanderOptions
+func (s *DebuggingSnapshotImpl) SetExpanderOptions(options []expander.Option, best *expander.Option) {
+	if options == nil {
+		return
+	}
+
+	s.ExpanderOptions = nil
+	for _, option := range options {
+		expanderOption := &ExpanderOption{
+			NodeGroup: option.NodeGroup.Id(),
+			NodeCount: option.NodeCount,
+			Debug:     option.Debug,
+			Best:      best != nil && best.NodeGroup.Id() == option.NodeGroup.Id(),
+		}
+		if expanderOption.Best {
+			expanderOption.Explanation = best.Explanation
+		}
+		for _, similar := range option.SimilarNodeGroups {
+			expanderOption.SimilarNodeGroups = append(expanderOption.SimilarNodeGroups, similar.Id())
+		}
+		for _, pod := range option.Pods {
+			expanderOption.Pods = append(expanderOption.Pods, PodRef(pod))
+		}
+		s.ExpanderOptions = append(s.ExpanderOptions, expanderOption)
+	}
+}
+
+// SetScaleUpDecision is the setter for ScaleUp
+func (s *DebuggingSnapshotImpl) SetScaleUpDecision(decision *ScaleUpDecision) {
+	if decision == nil {
+		return
+	}
+	s.ScaleUp = decision
+}
+
+// SetScaleDownDecision is the setter for ScaleDown
+func (s *DebuggingSnapshotImpl) SetScaleDownDecision(decision *ScaleDownDecision) {
+	if decision == nil {
+		return
+	}
+	s.ScaleDown = decision
+}
+
+// SetFilter sets the filter applied to the output
+func (s *DebuggingSnapshotImpl) SetFilter(filter *Filter) {
+	s.filter = filter
+}
+
+// PodRef returns the "namespace/name" reference of a pod, as used in decisions.
+func PodRef(pod *v1.Pod) string {
+	return pod.Namespace + "/" + pod.Name
+}
+
 // GetClusterNodeCopy is an util func to copy template node and filter values
 func GetClusterNodeCopy(template *framework.NodeInfo) *ClusterNode {
 	cNode := &ClusterNode{}
@@ -141,13 +327,18 @@ func (s *DebuggingSnapshotImpl) GetOutputBytes() ([]byte, bool) {
 	}
 
 	klog.Infof("Debugging snapshot flush ready")
-	marshalOutput, err := json.Marshal(s)
+	s.SchemaVersion = SchemaVersion
+	output := s
+	if s.filter != nil {
+		output = s.filter.Apply(s)
+	}
+	marshalOutput, err := json.Marshal(output)
 
 	// this error captures if the snapshot couldn't be marshalled, hence we create a new object
 	// and return the error message
 	if err != nil {
 		klog.Errorf("Unable to json marshal the debugging snapshot: %v", err)
-		errorSnapshot := DebuggingSnapshotImpl{}
+		errorSnapshot := DebuggingSnapshotImpl{SchemaVersion: SchemaVersion}
 		errorSnapshot.SetErrorMessage("Unable to marshal the snapshot, " + err.Error())
 		errorSnapshot.SetEndTimestamp(s.EndTimestamp)
 		errorSnapshot.SetStartTimestamp(s.StartTimestamp)
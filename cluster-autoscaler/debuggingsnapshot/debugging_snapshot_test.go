@@ -24,6 +24,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	testprovider "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
+	"k8s.io/autoscaler/cluster-autoscaler/expander"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
 )
 
@@ -90,3 +93,31 @@ func TestEmptyDataNoError(t *testing.T) {
 	assert.False(t, err)
 	assert.NotNil(t, op)
 }
+
+func TestSetExpanderOptions(t *testing.T) {
+	provider := testprovider.NewTestCloudProviderBuilder().Build()
+	provider.AddNodeGroup("ng1", 0, 10, 1)
+	provider.AddNodeGroup("ng2", 0, 10, 1)
+	ng1, ng2 := provider.GetNodeGroup("ng1"), provider.GetNodeGroup("ng2")
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "Pod1", Namespace: "default"}}
+
+	snapshot := &DebuggingSnapshotImpl{}
+	options := []expander.Option{
+		{NodeGroup: ng1, NodeCount: 2, Pods: []*v1.Pod{pod}, Debug: "ng1 option"},
+		{NodeGroup: ng2, NodeCount: 1, Pods: []*v1.Pod{pod}, SimilarNodeGroups: []cloudprovider.NodeGroup{ng1}},
+	}
+	best := options[1]
+	best.Explanation = "cheapest option"
+	snapshot.SetExpanderOptions(options, &best)
+	assert.Equal(t, []*ExpanderOption{
+		{NodeGroup: "ng1", NodeCount: 2, Pods: []string{"default/Pod1"}, Debug: "ng1 option"},
+		{NodeGroup: "ng2", NodeCount: 1, Pods: []string{"default/Pod1"}, SimilarNodeGroups: []string{"ng1"}, Best: true, Explanation: "cheapest option"},
+	}, snapshot.ExpanderOptions)
+
+	op, errMsgSet := snapshot.GetOutputBytes()
+	assert.False(t, errMsgSet)
+	var parsed map[string]interface{}
+	assert.NoError(t, json.Unmarshal(op, &parsed))
+	assert.Equal(t, float64(SchemaVersion), parsed["SchemaVersion"])
+	assert.Equal(t, 2, len(parsed["ExpanderOptions"].([]interface{})))
+}
@@ -23,6 +23,7 @@ import (
 	"time"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/expander"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
 	"k8s.io/klog/v2"
 )
@@ -79,9 +80,24 @@ type DebuggingSnapshotter interface {
 	// SetUnscheduledPodsCanBeScheduled is a setter for all pods which are unscheduled
 	// but they can be scheduled. i.e. pods which aren't triggering scale-up
 	SetUnscheduledPodsCanBeScheduled([]*v1.Pod)
+	// SetUnschedulablePods is a setter for all pods which are unschedulable
+	// at the start of the loop, before any filtering
+	SetUnschedulablePods([]*v1.Pod)
+	// SetNodeGroups is a setter for the node groups sizes and members
+	SetNodeGroups(map[string]*NodeGroupState)
 	// SetTemplateNodes is a setter for all the TemplateNodes present in the cluster
 	// incl. templates for which there are no nodes
 	SetTemplateNodes(map[string]*framework.NodeInfo)
+	// SetTemplateNodesExplanations is a setter for the explanations of how
+	// TemplateNodes were built, per node group
+	SetTemplateNodesExplanations(map[string][]string)
+	// SetExpanderOptions is a setter for the scale-up options evaluated by the
+	// expander, and the best one it picked
+	SetExpanderOptions([]expander.Option, *expander.Option)
+	// SetScaleUpDecision is a setter for the outcome of the scale-up
+	SetScaleUpDecision(*ScaleUpDecision)
+	// SetScaleDownDecision is a setter for the outcome of the scale-down
+	SetScaleDownDecision(*ScaleDownDecision)
 	// ResponseHandler is the http response handler to manage incoming requests
 	ResponseHandler(http.ResponseWriter, *http.Request)
 	// IsDataCollectionAllowed checks the internal State of the snapshotter
@@ -124,6 +140,7 @@ func (d *DebuggingSnapshotterImpl) ResponseHandler(w http.ResponseWriter, r *htt
 
 	ctx, cancel := context.WithCancel(r.Context())
 	d.CancelRequest = cancel
+	filter := NewFilter(r.URL.Query())
 
 	klog.Infof("Received a new snapshot, that is accepted")
 	// set the State to trigger enabled, to allow workflow to collect data
@@ -134,6 +151,7 @@ func (d *DebuggingSnapshotterImpl) ResponseHandler(w http.ResponseWriter, r *htt
 	case <-d.Trigger:
 		d.Mutex.Lock()
 		d.DebuggingSnapshot.SetEndTimestamp(time.Now().In(time.UTC))
+		d.DebuggingSnapshot.SetFilter(filter)
 		body, isErrorMessage := d.DebuggingSnapshot.GetOutputBytes()
 		if isErrorMessage {
 			w.WriteHeader(http.StatusInternalServerError)
@@ -240,6 +258,29 @@ func (d *DebuggingSnapshotterImpl) SetUnscheduledPodsCanBeScheduled(podList []*v
 	*d.State = DATA_COLLECTED
 }
 
+// SetUnschedulablePods is the setter for UnschedulablePods
+func (d *DebuggingSnapshotterImpl) SetUnschedulablePods(podList []*v1.Pod) {
+	d.Mutex.Lock()
+	defer d.Mutex.Unlock()
+	if !d.IsDataCollectionAllowedNoLock() {
+		return
+	}
+	klog.V(4).Infof("UnschedulablePods is being set for the debugging snapshot")
+	d.DebuggingSnapshot.SetUnschedulablePods(podList)
+	*d.State = DATA_COLLECTED
+}
+
+// SetNodeGroups is the setter for NodeGroups
+func (d *DebuggingSnapshotterImpl) SetNodeGroups(nodeGroups map[string]*NodeGroupState) {
+	d.Mutex.Lock()
+	defer d.Mutex.Unlock()
+	if !d.IsDataCollectionAllowedNoLock() {
+		return
+	}
+	klog.V(4).Infof("NodeGroups is being set for the debugging snapshot")
+	d.DebuggingSnapshot.SetNodeGroups(nodeGroups)
+}
+
 // SetTemplateNodes is the setter for TemplateNodes
 func (d *DebuggingSnapshotterImpl) SetTemplateNodes(templates map[string]*framework.NodeInfo) {
 	d.Mutex.Lock()
@@ -251,6 +292,50 @@ func (d *DebuggingSnapshotterImpl) SetTemplateNodes(templates map[string]*framew
 	d.DebuggingSnapshot.SetTemplateNodes(templates)
 }
 
+// SetTemplateNodesExplanations is the setter for TemplateNodesExplanations
+func (d *DebuggingSnapshotterImpl) SetTemplateNodesExplanations(explanations map[string][]string) {
+	d.Mutex.Lock()
+	defer d.Mutex.Unlock()
+	if !d.IsDataCollectionAllowedNoLock() {
+		return
+	}
+	klog.V(4).Infof("TemplateNodesExplanations is being set for the debugging snapshot")
+	d.DebuggingSnapshot.SetTemplateNodesExplanations(explanations)
+}
+
+// SetExpanderOptions is the setter for ExpanderOptions
+func (d *DebuggingSnapshotterImpl) SetExpanderOptions(options []expander.Option, best *expander.Option) {
+	d.Mutex.Lock()
+	defer d.Mutex.Unlock()
+	if !d.IsDataCollectionAllowedNoLock() {
+		return
+	}
+	klog.V(4).Infof("ExpanderOptions is being set for the debugging snapshot")
+	d.DebuggingSnapshot.SetExpanderOptions(options, best)
+}
+
+// SetScaleUpDecision is the setter for ScaleUp
+func (d *DebuggingSnapshotterImpl) SetScaleUpDecision(decision *ScaleUpDecision) {
+	d.Mutex.Lock()
+	defer d.Mutex.Unlock()
+	if !d.IsDataCollectionAllowedNoLock() {
+		return
+	}
+	klog.V(4).Infof("ScaleUp is being set for the debugging snapshot")
+	d.DebuggingSnapshot.SetScaleUpDecision(decision)
+}
+
+// SetScaleDownDecision is the setter for ScaleDown
+func (d *DebuggingSnapshotterImpl) SetScaleDownDecision(decision *ScaleDownDecision) {
+	d.Mutex.Lock()
+	defer d.Mutex.Unlock()
+	if !d.IsDataCollectionAllowedNoLock() {
+		return
+	}
+	klog.V(4).Infof("ScaleDown is being set for the debugging snapshot")
+	d.DebuggingSnapshot.SetScaleDownDecision(decision)
+}
+
 // Cleanup clears the internal data sets of the cluster
 func (d *DebuggingSnapshotterImpl) Cleanup() {
 	if d.CancelRequest != nil {
@@ -17,6 +17,7 @@ limitations under the License.
 package debuggingsnapshot
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"sync"
@@ -71,6 +72,37 @@ func TestBasicSnapshotRequest(t *testing.T) {
 	assert.Greater(t, int64(0), resp.ContentLength)
 }
 
+func TestFilteredSnapshotRequest(t *testing.T) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	snapshotter := NewDebuggingSnapshotter(true)
+
+	req := httptest.NewRequest(http.MethodGet, "/?namespaces=kube-system", nil)
+	w := httptest.NewRecorder()
+
+	go func() {
+		snapshotter.ResponseHandler(w, req)
+		wg.Done()
+	}()
+
+	for !snapshotter.IsDataCollectionAllowed() {
+		snapshotter.StartDataCollection()
+	}
+	snapshotter.SetUnschedulablePods([]*v1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "Pod1", Namespace: "default"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "Pod2", Namespace: "kube-system"}},
+	})
+	snapshotter.Flush()
+
+	wg.Wait()
+	assert.Equal(t, http.StatusOK, w.Code)
+	var snapshot DebuggingSnapshotImpl
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &snapshot))
+	assert.Equal(t, SchemaVersion, snapshot.SchemaVersion)
+	assert.Equal(t, 1, len(snapshot.UnschedulablePods))
+	assert.Equal(t, "Pod2", snapshot.UnschedulablePods[0].Name)
+}
+
 func TestFlushWithoutData(t *testing.T) {
 	var wg sync.WaitGroup
 	wg.Add(1)
@@ -0,0 +1,244 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+  Snapshots of large clusters are hard to navigate, and often only a few node
+  groups or namespaces matter when debugging a scale-up or a scale-down. The
+  snapshot request accepts (comma separated, or repeated) filter parameters:
+  * nodegroups: only keeps these node groups, their template and their nodes;
+  * namespaces: only keeps pods from these namespaces.
+
+  Nodes are mapped to their node group with the snapshot NodeGroups, so when
+  filtering on node groups, nodes that don't belong to any known node group are
+  dropped. Decisions are filtered the same way: expander options, scale-ups and
+  rejection reasons on their node group, and pods references on their namespace.
+*/
+
+package debuggingsnapshot
+
+import (
+	"net/url"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// NodeGroupsFilterParam is the request parameter listing node groups to keep in the snapshot
+	NodeGroupsFilterParam = "nodegroups"
+	// NamespacesFilterParam is the request parameter listing namespaces to keep in the snapshot
+	NamespacesFilterParam = "namespaces"
+)
+
+// Filter limits the snapshot output to some node groups and namespaces.
+// An empty set doesn't filter anything.
+type Filter struct {
+	NodeGroups map[string]bool
+	Namespaces map[string]bool
+}
+
+// NewFilter builds a Filter from request parameters, or returns nil when the
+// request doesn't filter anything.
+func NewFilter(query url.Values) *Filter {
+	filter := &Filter{
+		NodeGroups: parseFilterParam(query[NodeGroupsFilterParam]),
+		Namespaces: parseFilterParam(query[NamespacesFilterParam]),
+	}
+	if len(filter.NodeGroups) == 0 && len(filter.Namespaces) == 0 {
+		return nil
+	}
+	return filter
+}
+
+func parseFilterParam(values []string) map[string]bool {
+	result := make(map[string]bool)
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				result[item] = true
+			}
+		}
+	}
+	return result
+}
+
+// Apply returns a filtered shallow copy of the snapshot. Kept objects are shared
+// with the snapshot, so the copy must not outlive it.
+func (f *Filter) Apply(s *DebuggingSnapshotImpl) *DebuggingSnapshotImpl {
+	out := *s
+	out.filter = nil
+
+	nodesGroups := make(map[string]string)
+	for id, state := range s.NodeGroups {
+		for _, node := range state.Nodes {
+			nodesGroups[node] = id
+		}
+	}
+
+	out.NodeList = nil
+	for _, clusterNode := range s.NodeList {
+		if clusterNode.Node != nil && f.keepNodeGroup(nodesGroups[clusterNode.Node.Name]) {
+			out.NodeList = append(out.NodeList, f.clusterNode(clusterNode))
+		}
+	}
+	out.UnscheduledPodsCanBeScheduled = f.pods(s.UnscheduledPodsCanBeScheduled)
+	out.UnschedulablePods = f.pods(s.UnschedulablePods)
+
+	if s.NodeGroups != nil {
+		out.NodeGroups = make(map[string]*NodeGroupState)
+		for id, state := range s.NodeGroups {
+			if f.keepNodeGroup(id) {
+				out.NodeGroups[id] = state
+			}
+		}
+	}
+	if s.TemplateNodes != nil {
+		out.TemplateNodes = make(map[string]*ClusterNode)
+		for id, template := range s.TemplateNodes {
+			if f.keepNodeGroup(id) {
+				out.TemplateNodes[id] = f.clusterNode(template)
+			}
+		}
+	}
+	if s.TemplateNodesExplanations != nil {
+		out.TemplateNodesExplanations = make(map[string][]string)
+		for id, explanations := range s.TemplateNodesExplanations {
+			if f.keepNodeGroup(id) {
+				out.TemplateNodesExplanations[id] = explanations
+			}
+		}
+	}
+
+	out.ExpanderOptions = nil
+	for _, option := range s.ExpanderOptions {
+		if f.keepNodeGroup(option.NodeGroup) {
+			optionCopy := *option
+			optionCopy.Pods = f.podRefs(option.Pods)
+			out.ExpanderOptions = append(out.ExpanderOptions, &optionCopy)
+		}
+	}
+	if s.ScaleUp != nil {
+		out.ScaleUp = f.scaleUp(s.ScaleUp)
+	}
+	if s.ScaleDown != nil {
+		out.ScaleDown = f.scaleDown(s.ScaleDown, nodesGroups)
+	}
+	return &out
+}
+
+func (f *Filter) scaleUp(decision *ScaleUpDecision) *ScaleUpDecision {
+	out := &ScaleUpDecision{
+		Result:               decision.Result,
+		Error:                decision.Error,
+		PodsTriggeredScaleUp: f.podRefs(decision.PodsTriggeredScaleUp),
+		PodsAwaitEvaluation:  f.podRefs(decision.PodsAwaitEvaluation),
+	}
+	for _, scaleUp := range decision.ScaleUps {
+		if f.keepNodeGroup(scaleUp.NodeGroup) {
+			out.ScaleUps = append(out.ScaleUps, scaleUp)
+		}
+	}
+	for _, reasons := range decision.PodsRemainUnschedulable {
+		if f.keepPodRef(reasons.Pod) {
+			out.PodsRemainUnschedulable = append(out.PodsRemainUnschedulable, &NoScaleUpReasons{
+				Pod:                reasons.Pod,
+				RejectedNodeGroups: f.nodeGroupsReasons(reasons.RejectedNodeGroups),
+				SkippedNodeGroups:  f.nodeGroupsReasons(reasons.SkippedNodeGroups),
+			})
+		}
+	}
+	return out
+}
+
+func (f *Filter) scaleDown(decision *ScaleDownDecision, nodesGroups map[string]string) *ScaleDownDecision {
+	out := &ScaleDownDecision{Result: decision.Result}
+	for _, node := range decision.UnneededNodes {
+		if f.keepNodeGroup(nodesGroups[node]) {
+			out.UnneededNodes = append(out.UnneededNodes, node)
+		}
+	}
+	for _, node := range decision.UnremovableNodes {
+		if f.keepNodeGroup(node.NodeGroup) {
+			out.UnremovableNodes = append(out.UnremovableNodes, node)
+		}
+	}
+	for _, node := range decision.ScaledDownNodes {
+		if f.keepNodeGroup(nodesGroups[node]) {
+			out.ScaledDownNodes = append(out.ScaledDownNodes, node)
+		}
+	}
+	for _, id := range decision.RemovedNodeGroups {
+		if f.keepNodeGroup(id) {
+			out.RemovedNodeGroups = append(out.RemovedNodeGroups, id)
+		}
+	}
+	return out
+}
+
+func (f *Filter) clusterNode(clusterNode *ClusterNode) *ClusterNode {
+	return &ClusterNode{Node: clusterNode.Node, Pods: f.pods(clusterNode.Pods)}
+}
+
+func (f *Filter) pods(pods []*v1.Pod) []*v1.Pod {
+	if len(f.Namespaces) == 0 {
+		return pods
+	}
+	var result []*v1.Pod
+	for _, pod := range pods {
+		if f.Namespaces[pod.Namespace] {
+			result = append(result, pod)
+		}
+	}
+	return result
+}
+
+func (f *Filter) podRefs(refs []string) []string {
+	if len(f.Namespaces) == 0 {
+		return refs
+	}
+	var result []string
+	for _, ref := range refs {
+		if f.keepPodRef(ref) {
+			result = append(result, ref)
+		}
+	}
+	return result
+}
+
+func (f *Filter) nodeGroupsReasons(reasons map[string][]string) map[string][]string {
+	if len(f.NodeGroups) == 0 || reasons == nil {
+		return reasons
+	}
+	result := make(map[string][]string)
+	for id, messages := range reasons {
+		if f.NodeGroups[id] {
+			result[id] = messages
+		}
+	}
+	return result
+}
+
+func (f *Filter) keepNodeGroup(id string) bool {
+	return len(f.NodeGroups) == 0 || f.NodeGroups[id]
+}
+
+func (f *Filter) keepPodRef(ref string) bool {
+	if len(f.Namespaces) == 0 {
+		return true
+	}
+	namespace, _, _ := strings.Cut(ref, "/")
+	return f.Namespaces[namespace]
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debuggingsnapshot
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewFilter(t *testing.T) {
+	for desc, test := range map[string]struct {
+		query string
+		want  *Filter
+	}{
+		"no parameters": {
+			query: "",
+		},
+		"empty parameters": {
+			query: "nodegroups=&namespaces=,",
+		},
+		"comma separated parameters": {
+			query: "nodegroups=ng1,ng2&namespaces=default",
+			want: &Filter{
+				NodeGroups: map[string]bool{"ng1": true, "ng2": true},
+				Namespaces: map[string]bool{"default": true},
+			},
+		},
+		"repeated parameters": {
+			query: "namespaces=default&namespaces=kube-system",
+			want: &Filter{
+				NodeGroups: map[string]bool{},
+				Namespaces: map[string]bool{"default": true, "kube-system": true},
+			},
+		},
+	} {
+		t.Run(desc, func(t *testing.T) {
+			query, err := url.ParseQuery(test.query)
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, NewFilter(query))
+		})
+	}
+}
+
+func TestFilterApply(t *testing.T) {
+	snapshot := &DebuggingSnapshotImpl{
+		NodeList: []*ClusterNode{
+			{Node: buildNode("n1"), Pods: []*v1.Pod{buildPod("default", "p1"), buildPod("kube-system", "p2")}},
+			{Node: buildNode("n2"), Pods: []*v1.Pod{buildPod("default", "p3")}},
+			{Node: buildNode("n3")},
+		},
+		UnschedulablePods: []*v1.Pod{buildPod("default", "p4"), buildPod("batch", "p5")},
+		NodeGroups: map[string]*NodeGroupState{
+			"ng1": {Nodes: []string{"n1"}},
+			"ng2": {Nodes: []string{"n2"}},
+		},
+		TemplateNodes: map[string]*ClusterNode{
+			"ng1": {Node: buildNode("template-ng1"), Pods: []*v1.Pod{buildPod("kube-system", "ds")}},
+			"ng2": {Node: buildNode("template-ng2")},
+		},
+		ExpanderOptions: []*ExpanderOption{
+			{NodeGroup: "ng1", NodeCount: 1, Pods: []string{"default/p4", "batch/p5"}, Best: true},
+			{NodeGroup: "ng2", NodeCount: 1, Pods: []string{"default/p4"}},
+		},
+		ScaleUp: &ScaleUpDecision{
+			Result:               "Successful",
+			ScaleUps:             []*NodeGroupScaleUp{{NodeGroup: "ng1", CurrentSize: 1, NewSize: 2}},
+			PodsTriggeredScaleUp: []string{"default/p4", "batch/p5"},
+			PodsRemainUnschedulable: []*NoScaleUpReasons{{
+				Pod:               "batch/p6",
+				SkippedNodeGroups: map[string][]string{"ng1": {"max limit reached"}, "ng2": {"not ready"}},
+			}},
+		},
+		ScaleDown: &ScaleDownDecision{
+			Result:           "NoNodeDeleted",
+			UnneededNodes:    []string{"n2", "n3"},
+			UnremovableNodes: []*UnremovableNode{{Node: "n1", NodeGroup: "ng1", Reason: "NotUnderutilized"}},
+		},
+	}
+
+	// node groups filter keeps the node groups, their nodes and their decisions
+	filtered := (&Filter{NodeGroups: map[string]bool{"ng2": true}}).Apply(snapshot)
+	assert.Equal(t, []string{"n2"}, clusterNodesNames(filtered.NodeList))
+	assert.Equal(t, 2, len(filtered.UnschedulablePods))
+	assert.Equal(t, []string{"ng2"}, keys(filtered.NodeGroups))
+	assert.Equal(t, []string{"ng2"}, keys(filtered.TemplateNodes))
+	assert.Equal(t, 1, len(filtered.ExpanderOptions))
+	assert.Equal(t, "ng2", filtered.ExpanderOptions[0].NodeGroup)
+	assert.Empty(t, filtered.ScaleUp.ScaleUps)
+	assert.Equal(t, map[string][]string{"ng2": {"not ready"}}, filtered.ScaleUp.PodsRemainUnschedulable[0].SkippedNodeGroups)
+	assert.Equal(t, []string{"n2"}, filtered.ScaleDown.UnneededNodes)
+	assert.Empty(t, filtered.ScaleDown.UnremovableNodes)
+
+	// namespaces filter keeps nodes, but only pods from the namespaces
+	filtered = (&Filter{Namespaces: map[string]bool{"batch": true, "kube-system": true}}).Apply(snapshot)
+	assert.Equal(t, []string{"n1", "n2", "n3"}, clusterNodesNames(filtered.NodeList))
+	assert.Equal(t, []*v1.Pod{snapshot.NodeList[0].Pods[1]}, filtered.NodeList[0].Pods)
+	assert.Empty(t, filtered.NodeList[1].Pods)
+	assert.Equal(t, []*v1.Pod{snapshot.UnschedulablePods[1]}, filtered.UnschedulablePods)
+	assert.Equal(t, 1, len(filtered.TemplateNodes["ng1"].Pods))
+	assert.Equal(t, []string{"batch/p5"}, filtered.ExpanderOptions[0].Pods)
+	assert.Empty(t, filtered.ExpanderOptions[1].Pods)
+	assert.Equal(t, []string{"batch/p5"}, filtered.ScaleUp.PodsTriggeredScaleUp)
+	assert.Equal(t, 1, len(filtered.ScaleUp.PodsRemainUnschedulable))
+	assert.Equal(t, []string{"n2", "n3"}, filtered.ScaleDown.UnneededNodes)
+
+	// the snapshot itself is left untouched
+	assert.Equal(t, 3, len(snapshot.NodeList))
+	assert.Equal(t, 2, len(snapshot.NodeList[0].Pods))
+	assert.Equal(t, []string{"default/p4", "batch/p5"}, snapshot.ExpanderOptions[0].Pods)
+}
+
+func buildNode(name string) *v1.Node {
+	return &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}}
+}
+
+func buildPod(namespace, name string) *v1.Pod {
+	return &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+}
+
+func clusterNodesNames(clusterNodes []*ClusterNode) []string {
+	var names []string
+	for _, clusterNode := range clusterNodes {
+		names = append(names, clusterNode.Node.Name)
+	}
+	return names
+}
+
+func keys[T any](m map[string]T) []string {
+	var result []string
+	for key := range m {
+		result = append(result, key)
+	}
+	return result
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+  The debugging snapshot is one-shot: it has to be requested before the loop
+  we want to look at. The decision journal is always on, and keeps a compact
+  summary of the last N autoscaler loops in a ring buffer, so we can find out
+  after the fact why a node group grew (or didn't shrink) some minutes ago.
+
+  Each entry holds the loop's pending pods, the scale-up and scale-down
+  decisions (in the debugging snapshot format) and the phases timings. Entries
+  are served as a JSON array, or as newline-delimited JSON with
+  "?format=ndjson", which can also follow new entries as they're recorded
+  with "&follow=true" (until the client disconnects).
+*/
+
+package journal
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"k8s.io/autoscaler/cluster-autoscaler/debuggingsnapshot"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	// subscriberBuffer is the number of entries buffered for a follower. Slower
+	// followers miss entries rather than slowing down the autoscaler loop.
+	subscriberBuffer = 16
+)
+
+// Entry summarizes an autoscaler loop. Pods are referenced by their "namespace/name".
+type Entry struct {
+	Iteration      uint64                               `json:"Iteration"`
+	LoopTime       time.Time                            `json:"LoopTime"`
+	PendingPods    []string                             `json:"PendingPods,omitempty"`
+	ScaleUp        *debuggingsnapshot.ScaleUpDecision   `json:"ScaleUp,omitempty"`
+	ScaleDown      *debuggingsnapshot.ScaleDownDecision `json:"ScaleDown,omitempty"`
+	TimingsSeconds map[string]float64                   `json:"TimingsSeconds,omitempty"`
+	Error          string                               `json:"Error,omitempty"`
+}
+
+// NewEntry returns an empty entry for a loop started at loopTime.
+func NewEntry(loopTime time.Time) *Entry {
+	return &Entry{LoopTime: loopTime, TimingsSeconds: make(map[string]float64)}
+}
+
+// ObserveDuration records the duration of a loop phase.
+func (e *Entry) ObserveDuration(phase string, duration time.Duration) {
+	e.TimingsSeconds[phase] = duration.Seconds()
+}
+
+// Journal keeps the most recent entries in a fixed size ring buffer.
+type Journal struct {
+	mu          sync.Mutex
+	entries     []*Entry
+	next        int
+	full        bool
+	iteration   uint64
+	subscribers map[chan *Entry]bool
+}
+
+// New returns a Journal keeping the last size entries, or nil (a journal
+// recording nothing) when size isn't positive.
+func New(size int) *Journal {
+	if size <= 0 {
+		return nil
+	}
+	return &Journal{
+		entries:     make([]*Entry, size),
+		subscribers: make(map[chan *Entry]bool),
+	}
+}
+
+// Record adds an entry to the journal, evicting the oldest one when full.
+// Recorded entries must not be modified anymore.
+func (j *Journal) Record(entry *Entry) {
+	if j == nil || entry == nil {
+		return
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.iteration++
+	entry.Iteration = j.iteration
+	j.entries[j.next] = entry
+	j.next = (j.next + 1) % len(j.entries)
+	if j.next == 0 {
+		j.full = true
+	}
+
+	for subscriber := range j.subscribers {
+		select {
+		case subscriber <- entry:
+		default:
+			klog.V(4).Infof("Decision journal follower is too slow, skipping entry %d", entry.Iteration)
+		}
+	}
+}
+
+// Entries returns the journal entries, oldest first.
+func (j *Journal) Entries() []*Entry {
+	if j == nil {
+		return nil
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.entriesNoLock()
+}
+
+func (j *Journal) entriesNoLock() []*Entry {
+	var result []*Entry
+	if j.full {
+		result = append(result, j.entries[j.next:]...)
+	}
+	return append(result, j.entries[:j.next]...)
+}
+
+// subscribe returns the current entries, and a channel receiving the entries recorded from now on.
+func (j *Journal) subscribe() ([]*Entry, chan *Entry) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	subscriber := make(chan *Entry, subscriberBuffer)
+	j.subscribers[subscriber] = true
+	return j.entriesNoLock(), subscriber
+}
+
+func (j *Journal) unsubscribe(subscriber chan *Entry) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	delete(j.subscribers, subscriber)
+}
+
+// ServeHTTP serves the journal entries, either as a JSON array, or (with
+// "?format=ndjson") as newline-delimited JSON, optionally following new entries.
+func (j *Journal) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("format") != "ndjson" {
+		body, err := json.MarshalIndent(j.Entries(), "", "  ")
+		if err != nil {
+			klog.Errorf("failed to marshal decision journal: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(body)
+		return
+	}
+
+	var entries []*Entry
+	var subscriber chan *Entry
+	follow, _ := strconv.ParseBool(query.Get("follow"))
+	if follow {
+		entries, subscriber = j.subscribe()
+		defer j.unsubscribe(subscriber)
+	} else {
+		entries = j.Entries()
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	encoder := json.NewEncoder(w)
+	flusher, _ := w.(http.Flusher)
+	for _, entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			return
+		}
+	}
+	if !follow {
+		return
+	}
+	for {
+		if flusher != nil {
+			flusher.Flush()
+		}
+		select {
+		case entry := <-subscriber:
+			if err := encoder.Encode(entry); err != nil {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package journal
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/autoscaler/cluster-autoscaler/debuggingsnapshot"
+)
+
+func TestRecord(t *testing.T) {
+	j := New(3)
+	assert.Empty(t, j.Entries())
+
+	for i := 0; i < 2; i++ {
+		j.Record(NewEntry(time.Now()))
+	}
+	assert.Equal(t, []uint64{1, 2}, iterations(j.Entries()))
+
+	// oldest entries are evicted once full
+	for i := 0; i < 3; i++ {
+		j.Record(NewEntry(time.Now()))
+	}
+	assert.Equal(t, []uint64{3, 4, 5}, iterations(j.Entries()))
+
+	// a disabled journal records nothing
+	disabled := New(0)
+	assert.Nil(t, disabled)
+	disabled.Record(NewEntry(time.Now()))
+	assert.Empty(t, disabled.Entries())
+}
+
+func TestServeHTTP(t *testing.T) {
+	j := New(10)
+	entry := NewEntry(time.Now())
+	entry.PendingPods = []string{"default/p1"}
+	entry.ScaleUp = &debuggingsnapshot.ScaleUpDecision{Result: "Successful"}
+	entry.ObserveDuration("scaleUp", 1500*time.Millisecond)
+	j.Record(entry)
+	j.Record(NewEntry(time.Now()))
+
+	// JSON array
+	w := httptest.NewRecorder()
+	j.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/journalz", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	var entries []*Entry
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &entries))
+	assert.Equal(t, []uint64{1, 2}, iterations(entries))
+	assert.Equal(t, []string{"default/p1"}, entries[0].PendingPods)
+	assert.Equal(t, "Successful", entries[0].ScaleUp.Result)
+	assert.Equal(t, 1.5, entries[0].TimingsSeconds["scaleUp"])
+
+	// newline-delimited JSON
+	w = httptest.NewRecorder()
+	j.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/journalz?format=ndjson", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+	assert.Equal(t, []uint64{1, 2}, iterations(decodeNDJSON(t, w.Body)))
+}
+
+func TestServeHTTPFollow(t *testing.T) {
+	j := New(10)
+	j.Record(NewEntry(time.Now()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	reader, writer := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		j.ServeHTTP(&pipeResponseWriter{httptest.NewRecorder(), writer}, httptest.NewRequest(http.MethodGet, "/journalz?format=ndjson&follow=true", nil).WithContext(ctx))
+	}()
+
+	decoder := json.NewDecoder(bufio.NewReader(reader))
+	var entry Entry
+	assert.NoError(t, decoder.Decode(&entry))
+	assert.Equal(t, uint64(1), entry.Iteration)
+
+	// entries recorded after the request are streamed
+	j.Record(NewEntry(time.Now()))
+	assert.NoError(t, decoder.Decode(&entry))
+	assert.Equal(t, uint64(2), entry.Iteration)
+
+	// followers are unsubscribed when the client goes away
+	cancel()
+	go func() { _, _ = io.Copy(io.Discard, reader) }()
+	<-done
+	j.mu.Lock()
+	assert.Empty(t, j.subscribers)
+	j.mu.Unlock()
+}
+
+// pipeResponseWriter streams the response body through a pipe, so it can be read while being written.
+type pipeResponseWriter struct {
+	*httptest.ResponseRecorder
+	body io.Writer
+}
+
+func (w *pipeResponseWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func decodeNDJSON(t *testing.T, body io.Reader) []*Entry {
+	var entries []*Entry
+	decoder := json.NewDecoder(body)
+	for decoder.More() {
+		entry := &Entry{}
+		assert.NoError(t, decoder.Decode(entry))
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func iterations(entries []*Entry) []uint64 {
+	var result []uint64
+	for _, entry := range entries {
+		result = append(result, entry.Iteration)
+	}
+	return result
+}
@@ -0,0 +1,498 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+  Replays debugging snapshots offline, through a StaticAutoscaler wired to a
+  fake cloud provider and fake listers, and reports the scale up and scale
+  down decisions it takes for each snapshot.
+
+  Every snapshot replaces the whole cluster state:
+  * nodes (with their pods) come from NodeList, and pending pods from
+    UnschedulablePods (or UnscheduledPodsCanBeScheduled, for older snapshots),
+  * node groups come from NodeGroups when recorded, otherwise from the
+    TemplateNodes keys (with live nodes matched to templates by labels),
+  * template NodeInfos are returned as recorded: they're not rebuilt from the
+    cloud provider, so they already hold the Datadog resources and pods.
+  The autoscaler state (unneeded nodes timers, backoffs, ...) is kept from one
+  snapshot to the next, and loops are run at the snapshots relative times, so
+  scale down can be replayed from a sequence of snapshots.
+
+  Snapshots don't record PVCs, PDBs, workload controllers nor ConfigMaps, so the
+  replay uses the upstream pods list processing, without PDBs, and expanders
+  relying on ConfigMaps (eg. priority) don't find their configuration.
+*/
+
+package replay
+
+import (
+	ctx "context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apiv1 "k8s.io/api/core/v1"
+	kube_errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testprovider "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
+	"k8s.io/autoscaler/cluster-autoscaler/clusterstate/utils"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+	"k8s.io/autoscaler/cluster-autoscaler/context"
+	"k8s.io/autoscaler/cluster-autoscaler/core"
+	"k8s.io/autoscaler/cluster-autoscaler/core/scaledown/status"
+	"k8s.io/autoscaler/cluster-autoscaler/debuggingsnapshot"
+	ca_processors "k8s.io/autoscaler/cluster-autoscaler/processors"
+	ca_status "k8s.io/autoscaler/cluster-autoscaler/processors/status"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/options"
+	caerrors "k8s.io/autoscaler/cluster-autoscaler/utils/errors"
+	kube_util "k8s.io/autoscaler/cluster-autoscaler/utils/kubernetes"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/taints"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	// defaultNodeGroupMaxSize is the max size of node groups whose sizes weren't recorded
+	defaultNodeGroupMaxSize = 1000
+)
+
+// ScaleUp is a node group scale up decision
+type ScaleUp struct {
+	NodeGroup   string `json:"nodeGroup"`
+	CurrentSize int    `json:"currentSize"`
+	NewSize     int    `json:"newSize"`
+}
+
+// ScaleDown is a node removal decision
+type ScaleDown struct {
+	NodeGroup   string   `json:"nodeGroup"`
+	Node        string   `json:"node"`
+	EvictedPods []string `json:"evictedPods,omitempty"`
+}
+
+// Decisions holds what the autoscaler decided while replaying a snapshot
+type Decisions struct {
+	Snapshot                string      `json:"snapshot"`
+	Timestamp               time.Time   `json:"timestamp"`
+	ScaleUps                []ScaleUp   `json:"scaleUps,omitempty"`
+	PodsTriggeredScaleUp    []string    `json:"podsTriggeredScaleUp,omitempty"`
+	PodsRemainUnschedulable []string    `json:"podsRemainUnschedulable,omitempty"`
+	UnneededNodes           []string    `json:"unneededNodes,omitempty"`
+	ScaleDowns              []ScaleDown `json:"scaleDowns,omitempty"`
+	Error                   string      `json:"error,omitempty"`
+}
+
+// LoadSnapshot reads a debugging snapshot, as served by the /snapshotz endpoint, from a file
+func LoadSnapshot(path string) (*debuggingsnapshot.DebuggingSnapshotImpl, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot %s: %v", path, err)
+	}
+	snapshot := &debuggingsnapshot.DebuggingSnapshotImpl{}
+	if err := json.Unmarshal(data, snapshot); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal snapshot %s: %v", path, err)
+	}
+	if snapshot.SchemaVersion > debuggingsnapshot.SchemaVersion {
+		return nil, fmt.Errorf("snapshot %s schema version %d is newer than the supported version %d", path, snapshot.SchemaVersion, debuggingsnapshot.SchemaVersion)
+	}
+	return snapshot, nil
+}
+
+// Replayer runs autoscaler loops against debugging snapshots
+type Replayer struct {
+	autoscaler core.Autoscaler
+	provider   *testprovider.TestCloudProvider
+	kubeClient *fake.Clientset
+	allNodes   *kube_util.TestNodeLister
+	readyNodes *kube_util.TestNodeLister
+	pods       *podLister
+	templates  *snapshotTemplates
+	recorder   *decisionsRecorder
+
+	// start and origin map snapshots times to loops times
+	start  time.Time
+	origin time.Time
+}
+
+// NewReplayer builds a Replayer running an autoscaler configured with the provided options
+func NewReplayer(opts config.AutoscalingOptions) (*Replayer, error) {
+	r := &Replayer{
+		kubeClient: fake.NewSimpleClientset(),
+		allNodes:   kube_util.NewTestNodeLister(nil),
+		readyNodes: kube_util.NewTestNodeLister(nil),
+		pods:       &podLister{},
+		templates:  &snapshotTemplates{},
+		recorder:   &decisionsRecorder{},
+		start:      time.Now(),
+	}
+	r.provider = testprovider.NewTestCloudProviderBuilder().
+		WithOnScaleUp(func(string, int) error { return nil }).
+		WithOnScaleDown(func(string, string) error { return nil }).
+		Build()
+
+	daemonSetLister, err := kube_util.NewTestDaemonSetLister(nil)
+	if err != nil {
+		return nil, err
+	}
+	rcLister, err := kube_util.NewTestReplicationControllerLister(nil)
+	if err != nil {
+		return nil, err
+	}
+	jobLister, err := kube_util.NewTestJobLister(nil)
+	if err != nil {
+		return nil, err
+	}
+	rsLister, err := kube_util.NewTestReplicaSetLister(nil)
+	if err != nil {
+		return nil, err
+	}
+	ssLister, err := kube_util.NewTestStatefulSetLister(nil)
+	if err != nil {
+		return nil, err
+	}
+	listerRegistry := kube_util.NewListerRegistry(r.allNodes, r.readyNodes, r.pods, kube_util.NewTestPodDisruptionBudgetLister(nil),
+		daemonSetLister, rcLister, jobLister, rsLister, ssLister)
+
+	// events are dropped, the decisions are what we report
+	eventRecorder := &record.FakeRecorder{}
+	logRecorder, err := utils.NewStatusMapRecorder(r.kubeClient, opts.ConfigNamespace, eventRecorder, false, opts.StatusConfigMapName)
+	if err != nil {
+		return nil, err
+	}
+
+	informerFactory := informers.NewSharedInformerFactory(r.kubeClient, 0)
+	fwHandle, err := framework.NewHandle(informerFactory, opts.SchedulerConfig, opts.DynamicResourceAllocationEnabled)
+	if err != nil {
+		return nil, err
+	}
+
+	processors := ca_processors.DefaultProcessors(opts)
+	processors.TemplateNodeInfoProvider = r.templates
+	processors.ScaleUpStatusProcessor = r.recorder
+	processors.ScaleDownStatusProcessor = &scaleDownStatusRecorder{r.recorder}
+	processors.ScaleDownCandidatesNotifier.Register(r.recorder)
+
+	autoscaler, err := core.NewAutoscaler(core.AutoscalerOptions{
+		AutoscalingOptions: opts,
+		KubeClient:         r.kubeClient,
+		InformerFactory:    informerFactory,
+		AutoscalingKubeClients: &context.AutoscalingKubeClients{
+			ListerRegistry: listerRegistry,
+			ClientSet:      r.kubeClient,
+			Recorder:       eventRecorder,
+			LogRecorder:    logRecorder,
+		},
+		CloudProvider:        r.provider,
+		FrameworkHandle:      fwHandle,
+		Processors:           processors,
+		DebuggingSnapshotter: debuggingsnapshot.NewDebuggingSnapshotter(false),
+		DeleteOptions:        options.NewNodeDeleteOptions(opts),
+	}, informerFactory)
+	if err != nil {
+		return nil, err
+	}
+	if err := autoscaler.Start(); err != nil {
+		return nil, err
+	}
+	r.autoscaler = autoscaler
+
+	stop := make(chan struct{})
+	informerFactory.Start(stop)
+	informerFactory.WaitForCacheSync(stop)
+
+	return r, nil
+}
+
+// Replay loads a snapshot as the cluster state, runs an autoscaler loop and returns its decisions
+func (r *Replayer) Replay(source string, snapshot *debuggingsnapshot.DebuggingSnapshotImpl) (*Decisions, error) {
+	if err := r.load(snapshot); err != nil {
+		return nil, err
+	}
+
+	snapshotTime := snapshot.StartTimestamp
+	if snapshotTime.IsZero() {
+		snapshotTime = snapshot.EndTimestamp
+	}
+	if r.origin.IsZero() {
+		r.origin = snapshotTime
+	}
+
+	r.recorder.reset()
+	loopTime := r.start.Add(snapshotTime.Sub(r.origin))
+	decisions := &Decisions{
+		Snapshot:  source,
+		Timestamp: snapshotTime,
+	}
+	if err := r.autoscaler.RunOnce(loopTime); err != nil {
+		decisions.Error = err.Error()
+	}
+	r.recorder.fill(decisions)
+
+	return decisions, nil
+}
+
+// load replaces the fake cloud provider and listers content by the snapshot's
+func (r *Replayer) load(snapshot *debuggingsnapshot.DebuggingSnapshotImpl) error {
+	templates := make(map[string]*framework.NodeInfo)
+	for id, template := range snapshot.TemplateNodes {
+		if template == nil || template.Node == nil {
+			continue
+		}
+		templates[id] = toNodeInfo(template)
+	}
+	r.templates.set(snapshot.TemplateNodes)
+	r.provider.SetMachineTemplates(templates)
+
+	var nodes, readyNodes []*apiv1.Node
+	var pods []*apiv1.Pod
+	for _, clusterNode := range snapshot.NodeList {
+		if clusterNode == nil || clusterNode.Node == nil {
+			continue
+		}
+		node := clusterNode.Node.DeepCopy()
+		// the fake cloud provider instances are identified by node names
+		node.Spec.ProviderID = node.Name
+		nodes = append(nodes, node)
+		if ready, _, _ := kube_util.GetReadinessState(node); ready && !node.Spec.Unschedulable {
+			readyNodes = append(readyNodes, node)
+		}
+		for _, pod := range clusterNode.Pods {
+			pods = append(pods, pod.DeepCopy())
+		}
+	}
+	pending := snapshot.UnschedulablePods
+	if pending == nil {
+		pending = snapshot.UnscheduledPodsCanBeScheduled
+	}
+	for _, pod := range pending {
+		pods = append(pods, pod.DeepCopy())
+	}
+
+	for _, nodeGroup := range r.provider.NodeGroups() {
+		r.provider.DeleteNodeGroup(nodeGroup.Id())
+	}
+	previousNodes, _ := r.allNodes.List()
+	for _, node := range previousNodes {
+		r.provider.DeleteNode(node)
+	}
+	for id, state := range nodeGroupsStates(snapshot, nodes) {
+		r.provider.AddNodeGroup(id, state.MinSize, state.MaxSize, state.TargetSize)
+		for _, name := range state.Nodes {
+			r.provider.AddNode(id, &apiv1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}})
+		}
+	}
+
+	r.allNodes.SetNodes(nodes)
+	r.readyNodes.SetNodes(readyNodes)
+	r.pods.set(pods)
+
+	// scale down actuation updates nodes (eg. taints) through the client
+	for _, node := range nodes {
+		_, err := r.kubeClient.CoreV1().Nodes().Create(ctx.TODO(), node, metav1.CreateOptions{})
+		if kube_errors.IsAlreadyExists(err) {
+			_, err = r.kubeClient.CoreV1().Nodes().Update(ctx.TODO(), node, metav1.UpdateOptions{})
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load node %s: %v", node.Name, err)
+		}
+	}
+	return nil
+}
+
+// nodeGroupsStates returns the snapshot node groups, or builds them from templates for older snapshots
+func nodeGroupsStates(snapshot *debuggingsnapshot.DebuggingSnapshotImpl, nodes []*apiv1.Node) map[string]*debuggingsnapshot.NodeGroupState {
+	if len(snapshot.NodeGroups) > 0 {
+		return snapshot.NodeGroups
+	}
+
+	states := make(map[string]*debuggingsnapshot.NodeGroupState)
+	for id := range snapshot.TemplateNodes {
+		states[id] = &debuggingsnapshot.NodeGroupState{MaxSize: defaultNodeGroupMaxSize}
+	}
+	for _, node := range nodes {
+		if id := matchingTemplate(node, snapshot.TemplateNodes); id != "" {
+			states[id].Nodes = append(states[id].Nodes, node.Name)
+			states[id].TargetSize++
+		}
+	}
+	return states
+}
+
+// matchingTemplate returns the node group whose template labels are all carried by the
+// node, preferring the template having the most labels; or "" if none matches.
+func matchingTemplate(node *apiv1.Node, templates map[string]*debuggingsnapshot.ClusterNode) string {
+	var ids []string
+	for id := range templates {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	best, bestScore := "", -1
+	for _, id := range ids {
+		template := templates[id]
+		if template == nil || template.Node == nil {
+			continue
+		}
+		score := 0
+		for key, value := range template.Node.Labels {
+			if key == apiv1.LabelHostname {
+				continue
+			}
+			if node.Labels[key] != value {
+				score = -1
+				break
+			}
+			score++
+		}
+		if score > bestScore {
+			best, bestScore = id, score
+		}
+	}
+	return best
+}
+
+func toNodeInfo(clusterNode *debuggingsnapshot.ClusterNode) *framework.NodeInfo {
+	var pods []*framework.PodInfo
+	for _, pod := range clusterNode.Pods {
+		pods = append(pods, &framework.PodInfo{Pod: pod.DeepCopy()})
+	}
+	return framework.NewNodeInfo(clusterNode.Node.DeepCopy(), nil, pods...)
+}
+
+// snapshotTemplates is a TemplateNodeInfoProvider returning the snapshot templates as recorded
+type snapshotTemplates struct {
+	templates map[string]*debuggingsnapshot.ClusterNode
+}
+
+func (p *snapshotTemplates) set(templates map[string]*debuggingsnapshot.ClusterNode) {
+	p.templates = templates
+}
+
+// Process returns a fresh copy of the snapshot templates
+func (p *snapshotTemplates) Process(_ *context.AutoscalingContext, _ []*apiv1.Node, _ []*appsv1.DaemonSet, _ taints.TaintConfig, _ time.Time) (map[string]*framework.NodeInfo, caerrors.AutoscalerError) {
+	result := make(map[string]*framework.NodeInfo)
+	for id, template := range p.templates {
+		if template == nil || template.Node == nil {
+			continue
+		}
+		result[id] = toNodeInfo(template)
+	}
+	return result, nil
+}
+
+// CleanUp cleans up processor's internal structures.
+func (p *snapshotTemplates) CleanUp() {
+}
+
+// podLister is a PodLister whose pods can be replaced
+type podLister struct {
+	pods []*apiv1.Pod
+}
+
+func (l *podLister) set(pods []*apiv1.Pod) {
+	l.pods = pods
+}
+
+// List returns all the pods
+func (l *podLister) List() ([]*apiv1.Pod, error) {
+	return l.pods, nil
+}
+
+// decisionsRecorder collects the decisions of a loop from the status processors and notifiers
+type decisionsRecorder struct {
+	scaleUpStatus   *ca_status.ScaleUpStatus
+	scaleDownStatus *status.ScaleDownStatus
+	unneededNodes   []*apiv1.Node
+}
+
+func (d *decisionsRecorder) reset() {
+	*d = decisionsRecorder{}
+}
+
+// Process records the scale up status
+func (d *decisionsRecorder) Process(_ *context.AutoscalingContext, scaleUpStatus *ca_status.ScaleUpStatus) {
+	d.scaleUpStatus = scaleUpStatus
+}
+
+// UpdateScaleDownCandidates records the unneeded nodes
+func (d *decisionsRecorder) UpdateScaleDownCandidates(nodes []*apiv1.Node, _ time.Time) {
+	d.unneededNodes = nodes
+}
+
+// CleanUp cleans up processor's internal structures.
+func (d *decisionsRecorder) CleanUp() {
+}
+
+func (d *decisionsRecorder) fill(decisions *Decisions) {
+	if d.scaleUpStatus != nil {
+		for _, info := range d.scaleUpStatus.ScaleUpInfos {
+			decisions.ScaleUps = append(decisions.ScaleUps, ScaleUp{
+				NodeGroup:   info.Group.Id(),
+				CurrentSize: info.CurrentSize,
+				NewSize:     info.NewSize,
+			})
+		}
+		decisions.PodsTriggeredScaleUp = podNames(d.scaleUpStatus.PodsTriggeredScaleUp)
+		for _, noScaleUp := range d.scaleUpStatus.PodsRemainUnschedulable {
+			decisions.PodsRemainUnschedulable = append(decisions.PodsRemainUnschedulable, podName(noScaleUp.Pod))
+		}
+	}
+	for _, node := range d.unneededNodes {
+		decisions.UnneededNodes = append(decisions.UnneededNodes, node.Name)
+	}
+	if d.scaleDownStatus != nil {
+		for _, scaledDown := range d.scaleDownStatus.ScaledDownNodes {
+			decision := ScaleDown{
+				Node:        scaledDown.Node.Name,
+				EvictedPods: podNames(scaledDown.EvictedPods),
+			}
+			if scaledDown.NodeGroup != nil {
+				decision.NodeGroup = scaledDown.NodeGroup.Id()
+			}
+			decisions.ScaleDowns = append(decisions.ScaleDowns, decision)
+		}
+	}
+}
+
+func podName(pod *apiv1.Pod) string {
+	return pod.Namespace + "/" + pod.Name
+}
+
+func podNames(pods []*apiv1.Pod) []string {
+	var names []string
+	for _, pod := range pods {
+		names = append(names, podName(pod))
+	}
+	return names
+}
+
+// scaleDownStatusRecorder is the ScaleDownStatusProcessor side of a decisionsRecorder
+type scaleDownStatusRecorder struct {
+	recorder *decisionsRecorder
+}
+
+// Process records the scale down status
+func (s *scaleDownStatusRecorder) Process(_ *context.AutoscalingContext, scaleDownStatus *status.ScaleDownStatus) {
+	s.recorder.scaleDownStatus = scaleDownStatus
+}
+
+// CleanUp cleans up processor's internal structures.
+func (s *scaleDownStatusRecorder) CleanUp() {
+}
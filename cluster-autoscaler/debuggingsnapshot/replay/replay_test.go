@@ -0,0 +1,160 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replay
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+	"k8s.io/autoscaler/cluster-autoscaler/debuggingsnapshot"
+	"k8s.io/autoscaler/cluster-autoscaler/estimator"
+	"k8s.io/autoscaler/cluster-autoscaler/expander"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+)
+
+func TestLoadSnapshot(t *testing.T) {
+	snapshot := buildTestSnapshot(time.Now())
+	data, err := json.Marshal(snapshot)
+	assert.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	assert.NoError(t, os.WriteFile(path, data, 0644))
+
+	loaded, err := LoadSnapshot(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(loaded.NodeList))
+	assert.Equal(t, 1, len(loaded.UnschedulablePods))
+	assert.Contains(t, loaded.TemplateNodes, "ng1")
+
+	_, err = LoadSnapshot(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+
+	// snapshots of a newer schema might not be understood
+	snapshot.SchemaVersion = debuggingsnapshot.SchemaVersion + 1
+	data, err = json.Marshal(snapshot)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(path, data, 0644))
+	_, err = LoadSnapshot(path)
+	assert.Error(t, err)
+}
+
+func TestReplay(t *testing.T) {
+	replayer, err := NewReplayer(config.AutoscalingOptions{
+		NodeGroupDefaults: config.NodeGroupAutoscalingOptions{
+			ScaleDownUnneededTime:         time.Minute,
+			ScaleDownUnreadyTime:          time.Minute,
+			ScaleDownUtilizationThreshold: 0.5,
+			MaxNodeProvisionTime:          15 * time.Minute,
+		},
+		EstimatorName:                  estimator.BinpackingEstimatorName,
+		ExpanderNames:                  expander.LeastWasteExpanderName,
+		ScaleDownEnabled:               true,
+		ScaleDownSimulationTimeout:     10 * time.Second,
+		MaxNodesTotal:                  100,
+		MaxCoresTotal:                  100,
+		MaxMemoryTotal:                 100000000,
+		MaxNodeGroupBinpackingDuration: 10 * time.Second,
+		MaxNodesPerScaleUp:             100,
+		MaxScaleDownParallelism:        10,
+		MaxDrainParallelism:            1,
+		NodeDeletionBatcherInterval:    0,
+		OkTotalUnreadyCount:            1,
+		MaxTotalUnreadyPercentage:      45,
+	})
+	assert.NoError(t, err)
+
+	// the pending pod doesn't fit on existing nodes, a node is added
+	now := time.Now().Add(-time.Hour)
+	decisions, err := replayer.Replay("first.json", buildTestSnapshot(now))
+	assert.NoError(t, err)
+	assert.Empty(t, decisions.Error)
+	assert.Equal(t, []ScaleUp{{NodeGroup: "ng1", CurrentSize: 2, NewSize: 3}}, decisions.ScaleUps)
+	assert.Equal(t, []string{"default/pending"}, decisions.PodsTriggeredScaleUp)
+
+	// the empty node is unneeded, then removed once unneeded for long enough
+	snapshot := buildTestSnapshot(now.Add(time.Minute))
+	snapshot.UnschedulablePods = nil
+	decisions, err = replayer.Replay("second.json", snapshot)
+	assert.NoError(t, err)
+	assert.Empty(t, decisions.ScaleUps)
+	assert.Equal(t, []string{"n2"}, decisions.UnneededNodes)
+	assert.Empty(t, decisions.ScaleDowns)
+
+	snapshot = buildTestSnapshot(now.Add(3 * time.Minute))
+	snapshot.UnschedulablePods = nil
+	decisions, err = replayer.Replay("third.json", snapshot)
+	assert.NoError(t, err)
+	assert.Equal(t, []ScaleDown{{NodeGroup: "ng1", Node: "n2"}}, decisions.ScaleDowns)
+}
+
+func TestNodeGroupsStates(t *testing.T) {
+	snapshot := buildTestSnapshot(time.Now())
+	var nodes []*apiv1.Node
+	for _, clusterNode := range snapshot.NodeList {
+		nodes = append(nodes, clusterNode.Node)
+	}
+
+	// recorded node groups are used as is
+	assert.Equal(t, snapshot.NodeGroups, nodeGroupsStates(snapshot, nodes))
+
+	// otherwise nodes are matched to templates by labels
+	other := BuildTestNode("template-ng2", 2000, 2000000)
+	other.Labels["pool"] = "other"
+	snapshot.TemplateNodes["ng2"] = &debuggingsnapshot.ClusterNode{Node: other}
+	snapshot.NodeGroups = nil
+	states := nodeGroupsStates(snapshot, nodes)
+	assert.Equal(t, &debuggingsnapshot.NodeGroupState{MaxSize: defaultNodeGroupMaxSize, TargetSize: 2, Nodes: []string{"n1", "n2"}}, states["ng1"])
+	assert.Equal(t, &debuggingsnapshot.NodeGroupState{MaxSize: defaultNodeGroupMaxSize}, states["ng2"])
+}
+
+func buildTestSnapshot(timestamp time.Time) *debuggingsnapshot.DebuggingSnapshotImpl {
+	template := BuildTestNode("template-ng1", 1000, 1000000)
+	template.Labels["pool"] = "main"
+
+	n1 := BuildTestNode("n1", 1000, 1000000)
+	n1.Labels["pool"] = "main"
+	SetNodeReadyState(n1, true, timestamp.Add(-time.Hour))
+	n2 := BuildTestNode("n2", 1000, 1000000)
+	n2.Labels["pool"] = "main"
+	SetNodeReadyState(n2, true, timestamp.Add(-time.Hour))
+
+	running := BuildTestPod("running", 800, 100000)
+	running.Spec.NodeName = "n1"
+	running.OwnerReferences = GenerateOwnerReferences("rs", "ReplicaSet", "apps/v1", "")
+	pending := BuildTestPod("pending", 800, 100000, MarkUnschedulable())
+
+	return &debuggingsnapshot.DebuggingSnapshotImpl{
+		StartTimestamp: timestamp,
+		EndTimestamp:   timestamp,
+		NodeList: []*debuggingsnapshot.ClusterNode{
+			{Node: n1, Pods: []*apiv1.Pod{running}},
+			{Node: n2},
+		},
+		UnschedulablePods: []*apiv1.Pod{pending},
+		TemplateNodes: map[string]*debuggingsnapshot.ClusterNode{
+			"ng1": {Node: template},
+		},
+		NodeGroups: map[string]*debuggingsnapshot.NodeGroupState{
+			"ng1": {MinSize: 1, MaxSize: 10, TargetSize: 2, Nodes: []string{"n1", "n2"}},
+		},
+	}
+}
@@ -0,0 +1,321 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package estimator
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	core_utils "k8s.io/autoscaler/cluster-autoscaler/simulator"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/clustersnapshot"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
+	podutils "k8s.io/autoscaler/cluster-autoscaler/utils/pod"
+	"k8s.io/klog/v2"
+)
+
+// DominantResourceNodeEstimator estimates the number of needed nodes to handle the given amount of pods,
+// packing them across all the resources of the node template.
+type DominantResourceNodeEstimator struct {
+	clusterSnapshot        clustersnapshot.ClusterSnapshot
+	limiter                EstimationLimiter
+	context                EstimationContext
+	estimationAnalyserFunc EstimationAnalyserFunc // optional
+}
+
+// resourceVector holds quantities of the resources of a node template, in the order of their names.
+// CPU is in millicores, other resources in their unit.
+type resourceVector []int64
+
+// dominantResourcePod is a pod to place, with its requests and the share of its dominant resource.
+type dominantResourcePod struct {
+	pod           *apiv1.Pod
+	group         int
+	requests      resourceVector
+	dominantShare float64
+}
+
+// dominantResourceNode is a node added during the estimation, with its remaining resources.
+type dominantResourceNode struct {
+	name string
+	free resourceVector
+}
+
+// NewDominantResourceNodeEstimator builds a new DominantResourceNodeEstimator.
+func NewDominantResourceNodeEstimator(
+	clusterSnapshot clustersnapshot.ClusterSnapshot,
+	limiter EstimationLimiter,
+	context EstimationContext,
+	estimationAnalyserFunc EstimationAnalyserFunc,
+) *DominantResourceNodeEstimator {
+	return &DominantResourceNodeEstimator{
+		clusterSnapshot:        clusterSnapshot,
+		limiter:                limiter,
+		context:                context,
+		estimationAnalyserFunc: estimationAnalyserFunc,
+	}
+}
+
+// Estimate implements a multi-dimensional First-Fit Decreasing bin-packing approximation.
+// Pods are sorted by decreasing dominant share: the largest fraction of a node template
+// resource (cpu, memory, pods, extended resources...) they request. Each pod then goes to
+// the new node it fills the best, i.e. the one whose most available resource would be the
+// least available after adding the pod, so that cpu-heavy and memory-heavy pods end up
+// sharing nodes. Resources only narrow down the candidate nodes: pods are placed with the
+// scheduler predicates, and a new node is added when no candidate node passes them.
+// It is assumed that all pods from the given list can fit to nodeTemplate.
+// Returns the number of nodes needed to accommodate all pods from the list.
+func (e *DominantResourceNodeEstimator) Estimate(
+	podsEquivalenceGroups []PodEquivalenceGroup,
+	nodeTemplate *framework.NodeInfo,
+	nodeGroup cloudprovider.NodeGroup,
+) (int, []*apiv1.Pod) {
+	observeBinpackingHeterogeneity(podsEquivalenceGroups, nodeTemplate)
+
+	e.limiter.StartEstimation(podsEquivalenceGroups, nodeGroup, e.context)
+	defer e.limiter.EndEstimation()
+
+	e.clusterSnapshot.Fork()
+	defer func() {
+		e.clusterSnapshot.Revert()
+	}()
+
+	resourceNames, allocatable := templateResources(nodeTemplate)
+	pods := dominantResourcePods(podsEquivalenceGroups, resourceNames, allocatable)
+
+	estimationState := newEstimationState()
+	var nodes []*dominantResourceNode
+	unschedulableGroups := make(map[int]bool)
+	newNodesAvailable := true
+	for _, pod := range pods {
+		if unschedulableGroups[pod.group] {
+			continue
+		}
+		if !fitsResources(allocatable, pod.requests) {
+			klog.V(4).Infof("Pod %s/%s doesn't fit node template %s", pod.pod.Namespace, pod.pod.Name, nodeTemplate.Node().Name)
+			unschedulableGroups[pod.group] = true
+			continue
+		}
+
+		scheduled, triedEmptyNode, err := e.tryToScheduleOnCandidateNodes(estimationState, nodes, pod, allocatable)
+		if err != nil {
+			klog.Error(err.Error())
+			return 0, nil
+		}
+		if scheduled {
+			continue
+		}
+		// A pod which can't be scheduled on an empty new node won't be scheduled on another one either.
+		if triedEmptyNode {
+			unschedulableGroups[pod.group] = true
+			continue
+		}
+		if !newNodesAvailable {
+			continue
+		}
+
+		// The thresholdBasedEstimationLimiter implementation assumes that for
+		// each call that returns true, one node gets added. Therefore this
+		// must be the last check right before really adding a node.
+		if !e.limiter.PermissionToAddNode() {
+			newNodesAvailable = false
+			continue
+		}
+		node, err := e.addNewNode(estimationState, nodeTemplate, allocatable)
+		if err != nil {
+			klog.Errorf("Error while adding new node for template to ClusterSnapshot; %v", err)
+			return 0, nil
+		}
+		nodes = append(nodes, node)
+		if err := e.clusterSnapshot.SchedulePod(pod.pod, node.name); err != nil && err.Type() == clustersnapshot.SchedulingInternalError {
+			klog.Error(err.Error())
+			return 0, nil
+		} else if err != nil {
+			// The pod can't be scheduled on the new node because of scheduling predicates. We keep
+			// the node in clusterSnapshot, it may still be used by pods of other groups.
+			unschedulableGroups[pod.group] = true
+			continue
+		}
+		node.free.subtract(pod.requests)
+		estimationState.trackScheduledPod(pod.pod, node.name)
+	}
+
+	if e.estimationAnalyserFunc != nil {
+		e.estimationAnalyserFunc(e.clusterSnapshot, nodeGroup, estimationState.newNodesWithPods)
+	}
+	return len(estimationState.newNodesWithPods), estimationState.scheduledPods
+}
+
+// tryToScheduleOnCandidateNodes tries to schedule the pod on the new nodes having enough resources for it,
+// the best fitting ones first. It returns whether the pod was scheduled, and whether an empty node was tried.
+func (e *DominantResourceNodeEstimator) tryToScheduleOnCandidateNodes(
+	estimationState *estimationState,
+	nodes []*dominantResourceNode,
+	pod *dominantResourcePod,
+	allocatable resourceVector,
+) (bool, bool, error) {
+	type candidate struct {
+		node  *dominantResourceNode
+		score float64
+	}
+	var candidates []candidate
+	for _, node := range nodes {
+		if score, fits := fitScore(node.free, pod.requests, allocatable); fits {
+			candidates = append(candidates, candidate{node: node, score: score})
+		}
+	}
+	// A stable sort keeps the nodes in their creation order for equal scores, making it a first fit.
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].score < candidates[j].score })
+
+	triedEmptyNode := false
+	for _, c := range candidates {
+		err := e.clusterSnapshot.SchedulePod(pod.pod, c.node.name)
+		if err != nil && err.Type() == clustersnapshot.SchedulingInternalError {
+			// Unexpected error.
+			return false, false, err
+		} else if err != nil {
+			// The pod can't be scheduled on the node because of scheduling predicates.
+			triedEmptyNode = triedEmptyNode || !estimationState.newNodesWithPods[c.node.name]
+			continue
+		}
+		c.node.free.subtract(pod.requests)
+		estimationState.trackScheduledPod(pod.pod, c.node.name)
+		return true, false, nil
+	}
+	return false, triedEmptyNode, nil
+}
+
+func (e *DominantResourceNodeEstimator) addNewNode(
+	estimationState *estimationState,
+	template *framework.NodeInfo,
+	allocatable resourceVector,
+) (*dominantResourceNode, error) {
+	newNodeInfo, err := core_utils.SanitizedNodeInfo(template, fmt.Sprintf("e-%d", estimationState.newNodeNameIndex))
+	if err != nil {
+		return nil, err
+	}
+	if err := e.clusterSnapshot.AddNodeInfo(newNodeInfo); err != nil {
+		return nil, err
+	}
+	estimationState.newNodeNameIndex++
+	estimationState.lastNodeName = newNodeInfo.Node().Name
+	estimationState.newNodeNames[estimationState.lastNodeName] = true
+	return &dominantResourceNode{name: estimationState.lastNodeName, free: slices.Clone(allocatable)}, nil
+}
+
+// templateResources returns the resources of the node template, and how much of them is left
+// to pods once the pods of the template (e.g. DaemonSet pods) are scheduled.
+func templateResources(nodeTemplate *framework.NodeInfo) ([]apiv1.ResourceName, resourceVector) {
+	var resourceNames []apiv1.ResourceName
+	for name := range nodeTemplate.Node().Status.Allocatable {
+		resourceNames = append(resourceNames, name)
+	}
+	slices.Sort(resourceNames)
+
+	allocatable := resourcesOf(nodeTemplate.Node().Status.Allocatable, resourceNames)
+	for _, podInfo := range nodeTemplate.Pods() {
+		allocatable.subtract(podRequests(podInfo.Pod, resourceNames))
+	}
+	return resourceNames, allocatable
+}
+
+// dominantResourcePods returns the pods of the groups, sorted by decreasing dominant share.
+func dominantResourcePods(podsEquivalenceGroups []PodEquivalenceGroup, resourceNames []apiv1.ResourceName, allocatable resourceVector) []*dominantResourcePod {
+	var pods []*dominantResourcePod
+	for i, group := range podsEquivalenceGroups {
+		exemplar := group.Exemplar()
+		if exemplar == nil {
+			continue
+		}
+		// Pods of an equivalence group have the same requests.
+		requests := podRequests(exemplar, resourceNames)
+		share := dominantShare(requests, allocatable)
+		for _, pod := range group.Pods {
+			pods = append(pods, &dominantResourcePod{pod: pod, group: i, requests: requests, dominantShare: share})
+		}
+	}
+	sort.SliceStable(pods, func(i, j int) bool { return pods[i].dominantShare > pods[j].dominantShare })
+	return pods
+}
+
+func podRequests(pod *apiv1.Pod, resourceNames []apiv1.ResourceName) resourceVector {
+	requests := resourcesOf(podutils.PodRequests(pod), resourceNames)
+	if i := slices.Index(resourceNames, apiv1.ResourcePods); i >= 0 {
+		requests[i] = 1
+	}
+	return requests
+}
+
+func resourcesOf(resources apiv1.ResourceList, resourceNames []apiv1.ResourceName) resourceVector {
+	result := make(resourceVector, len(resourceNames))
+	for i, name := range resourceNames {
+		quantity, found := resources[name]
+		if !found {
+			continue
+		}
+		if name == apiv1.ResourceCPU {
+			result[i] = quantity.MilliValue()
+		} else {
+			result[i] = quantity.Value()
+		}
+	}
+	return result
+}
+
+// dominantShare returns the largest fraction of a resource of the node template used by the given resources.
+func dominantShare(resources, allocatable resourceVector) float64 {
+	share := 0.0
+	for i, quantity := range resources {
+		if allocatable[i] > 0 {
+			share = max(share, float64(quantity)/float64(allocatable[i]))
+		}
+	}
+	return share
+}
+
+func fitsResources(free, requests resourceVector) bool {
+	for i, quantity := range requests {
+		if quantity > free[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// fitScore returns whether the requests fit the free resources and, if so, the dominant share of the
+// resources which would remain free. The lower the score, the better the fit.
+func fitScore(free, requests, allocatable resourceVector) (float64, bool) {
+	score := 0.0
+	for i, quantity := range requests {
+		remaining := free[i] - quantity
+		if remaining < 0 {
+			return 0, false
+		}
+		if allocatable[i] > 0 {
+			score = max(score, float64(remaining)/float64(allocatable[i]))
+		}
+	}
+	return score, true
+}
+
+func (v resourceVector) subtract(other resourceVector) {
+	for i := range v {
+		v[i] -= other[i]
+	}
+}
@@ -0,0 +1,242 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package estimator
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/clustersnapshot/testsnapshot"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/units"
+)
+
+func buildEstimateePod(name string, cpu, memMiB int64, options ...func(*apiv1.Pod)) *apiv1.Pod {
+	options = append([]func(*apiv1.Pod){WithNamespace("universe"), WithLabels(map[string]string{"app": name})}, options...)
+	return BuildTestPod(name, cpu, memMiB*units.MiB, options...)
+}
+
+func TestDominantResourceEstimate(t *testing.T) {
+	cpuHeavyPodGroup := makePodEquivalenceGroup(buildEstimateePod("cpu-heavy", 6000, 1000), 2)
+	balancedPodGroup := makePodEquivalenceGroup(buildEstimateePod("balanced", 4000, 5000), 4)
+	testCases := []struct {
+		name                 string
+		millicores           int64
+		memory               int64
+		maxNodes             int
+		templatePods         []*apiv1.Pod
+		podsEquivalenceGroup []PodEquivalenceGroup
+		expectNodeCount      int
+		expectPodCount       int
+		expectProcessedPods  []*apiv1.Pod
+	}{
+		{
+			name:                 "simple resource-based binpacking",
+			millicores:           350*3 - 50,
+			memory:               2 * 1000,
+			podsEquivalenceGroup: []PodEquivalenceGroup{makePodEquivalenceGroup(buildEstimateePod("estimatee", 350, 1000), 10)},
+			expectNodeCount:      5,
+			expectPodCount:       10,
+		},
+		{
+			name:                 "pods-per-node bound binpacking",
+			millicores:           10000,
+			memory:               20000,
+			podsEquivalenceGroup: []PodEquivalenceGroup{makePodEquivalenceGroup(buildEstimateePod("estimatee", 10, 100), 20)},
+			expectNodeCount:      2,
+			expectPodCount:       20,
+		},
+		{
+			// Binpacking processes the balanced pods first, and needs 4 nodes.
+			name:       "cpu-heavy and balanced pods share nodes",
+			millicores: 10000,
+			memory:     10000,
+			podsEquivalenceGroup: []PodEquivalenceGroup{
+				balancedPodGroup,
+				cpuHeavyPodGroup,
+			},
+			expectNodeCount: 3,
+			expectPodCount:  6,
+		},
+		{
+			name:       "complementary pods share nodes",
+			millicores: 10000,
+			memory:     10000,
+			podsEquivalenceGroup: []PodEquivalenceGroup{
+				makePodEquivalenceGroup(buildEstimateePod("cpu-heavy", 8000, 1000), 1),
+				makePodEquivalenceGroup(buildEstimateePod("memory-heavy", 1000, 8000), 1),
+				makePodEquivalenceGroup(buildEstimateePod("memory-filler", 1000, 6000), 1),
+				makePodEquivalenceGroup(buildEstimateePod("cpu-filler", 6000, 1000), 1),
+			},
+			expectNodeCount: 2,
+			expectPodCount:  4,
+		},
+		{
+			name:                 "template pods are taken into account",
+			millicores:           1000,
+			memory:               5000,
+			templatePods:         []*apiv1.Pod{buildEstimateePod("daemonset", 500, 100)},
+			podsEquivalenceGroup: []PodEquivalenceGroup{makePodEquivalenceGroup(buildEstimateePod("estimatee", 250, 1000), 4)},
+			expectNodeCount:      2,
+			expectPodCount:       4,
+		},
+		{
+			name:                 "pods not fitting the template aren't scheduled",
+			millicores:           1000,
+			memory:               5000,
+			podsEquivalenceGroup: []PodEquivalenceGroup{makePodEquivalenceGroup(buildEstimateePod("estimatee", 2000, 1000), 4)},
+			expectNodeCount:      0,
+			expectPodCount:       0,
+		},
+		{
+			name:                 "hostport conflict forces pod-per-node",
+			millicores:           1000,
+			memory:               5000,
+			podsEquivalenceGroup: []PodEquivalenceGroup{makePodEquivalenceGroup(buildEstimateePod("estimatee", 200, 1000, WithHostPort(5555)), 8)},
+			expectNodeCount:      8,
+			expectPodCount:       8,
+		},
+		{
+			name:                 "limiter cuts binpacking",
+			millicores:           1000,
+			memory:               5000,
+			podsEquivalenceGroup: []PodEquivalenceGroup{makePodEquivalenceGroup(buildEstimateePod("estimatee", 500, 1000), 20)},
+			maxNodes:             5,
+			expectNodeCount:      5,
+			expectPodCount:       10,
+		},
+		{
+			name:       "pods with a larger dominant share are processed first",
+			millicores: 10000,
+			memory:     10000,
+			podsEquivalenceGroup: []PodEquivalenceGroup{
+				balancedPodGroup,
+				cpuHeavyPodGroup,
+			},
+			maxNodes:            1,
+			expectNodeCount:     1,
+			expectPodCount:      2,
+			expectProcessedPods: []*apiv1.Pod{cpuHeavyPodGroup.Pods[0], balancedPodGroup.Pods[0]},
+		},
+		{
+			name:                 "hostname topology spreading with maxSkew=2 forces 2 pods/node",
+			millicores:           1000,
+			memory:               5000,
+			podsEquivalenceGroup: []PodEquivalenceGroup{makePodEquivalenceGroup(buildEstimateePod("estimatee", 200, 200, WithMaxSkew(2, "kubernetes.io/hostname", 1)), 8)},
+			expectNodeCount:      4,
+			expectPodCount:       8,
+		},
+		{
+			name:                 "zonal topology spreading with maxSkew=2 only allows 2 pods to schedule",
+			millicores:           1000,
+			memory:               5000,
+			podsEquivalenceGroup: []PodEquivalenceGroup{makePodEquivalenceGroup(buildEstimateePod("estimatee", 20, 100, WithMaxSkew(2, "topology.kubernetes.io/zone", 1)), 8)},
+			expectNodeCount:      1,
+			expectPodCount:       2,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			clusterSnapshot := testsnapshot.NewTestSnapshotOrDie(t)
+			// Add one node in different zone to trigger topology spread constraints
+			err := clusterSnapshot.AddNodeInfo(framework.NewTestNodeInfo(makeNode(100, 100, 10, "oldnode", "zone-jupiter")))
+			assert.NoError(t, err)
+
+			limiter := NewThresholdBasedEstimationLimiter([]Threshold{NewStaticThreshold(tc.maxNodes, time.Duration(0))})
+			estimator := NewDominantResourceNodeEstimator(clusterSnapshot, limiter, nil /* EstimationContext */, nil /* EstimationAnalyserFunc */)
+			node := makeNode(tc.millicores, tc.memory, 10, "template", "zone-mars")
+			nodeInfo := framework.NewTestNodeInfo(node, tc.templatePods...)
+
+			estimatedNodes, estimatedPods := estimator.Estimate(tc.podsEquivalenceGroup, nodeInfo, nil)
+			assert.Equal(t, tc.expectNodeCount, estimatedNodes)
+			assert.Equal(t, tc.expectPodCount, len(estimatedPods))
+			if tc.expectProcessedPods != nil {
+				assert.Equal(t, tc.expectProcessedPods, estimatedPods)
+			}
+		})
+	}
+}
+
+func TestNewEstimatorBuilder(t *testing.T) {
+	limiter := NewThresholdBasedEstimationLimiter(nil)
+	for _, name := range AvailableEstimators {
+		builder, err := NewEstimatorBuilder(name, limiter, NewDecreasingPodOrderer(), nil)
+		assert.NoError(t, err, name)
+		assert.NotNil(t, builder(testsnapshot.NewTestSnapshotOrDie(t), nil), name)
+	}
+	_, err := NewEstimatorBuilder("unknown", limiter, NewDecreasingPodOrderer(), nil)
+	assert.Error(t, err)
+}
+
+// BenchmarkEstimators compares the node count and the duration of the estimators on several workloads.
+// Run with: go test ./estimator/ -run=^$ -bench=BenchmarkEstimators
+func BenchmarkEstimators(b *testing.B) {
+	workloads := []struct {
+		name   string
+		groups []PodEquivalenceGroup
+	}{
+		{
+			name: "uniform",
+			groups: []PodEquivalenceGroup{
+				makePodEquivalenceGroup(buildEstimateePod("small", 50, 100), 5000),
+				makePodEquivalenceGroup(buildEstimateePod("medium", 95, 190), 100),
+			},
+		},
+		{
+			name: "mixed cpu-heavy and memory-heavy",
+			groups: []PodEquivalenceGroup{
+				makePodEquivalenceGroup(buildEstimateePod("cpu-heavy", 700, 500), 500),
+				makePodEquivalenceGroup(buildEstimateePod("memory-heavy", 150, 3500), 500),
+				makePodEquivalenceGroup(buildEstimateePod("balanced", 200, 1000), 500),
+			},
+		},
+		{
+			name: "cpu-heavy and large balanced pods",
+			groups: []PodEquivalenceGroup{
+				makePodEquivalenceGroup(buildEstimateePod("balanced", 400, 2500), 400),
+				makePodEquivalenceGroup(buildEstimateePod("cpu-heavy", 600, 500), 200),
+			},
+		},
+	}
+	estimators := map[string]func(*testing.B) Estimator{
+		BinpackingEstimatorName: func(b *testing.B) Estimator {
+			limiter := NewThresholdBasedEstimationLimiter([]Threshold{NewStaticThreshold(10000, time.Duration(0))})
+			return NewBinpackingNodeEstimator(testsnapshot.NewTestSnapshotOrDie(b), limiter, NewDecreasingPodOrderer(), nil, nil)
+		},
+		DominantResourceEstimatorName: func(b *testing.B) Estimator {
+			limiter := NewThresholdBasedEstimationLimiter([]Threshold{NewStaticThreshold(10000, time.Duration(0))})
+			return NewDominantResourceNodeEstimator(testsnapshot.NewTestSnapshotOrDie(b), limiter, nil, nil)
+		},
+	}
+	nodeInfo := framework.NewTestNodeInfo(makeNode(1000, 5000, 100, "template", "zone-mars"))
+	for _, workload := range workloads {
+		for _, name := range AvailableEstimators {
+			b.Run(fmt.Sprintf("%s/%s", workload.name, name), func(b *testing.B) {
+				var nodes int
+				for i := 0; i < b.N; i++ {
+					nodes, _ = estimators[name](b).Estimate(workload.groups, nodeInfo, nil)
+				}
+				b.ReportMetric(float64(nodes), "nodes")
+			})
+		}
+	}
+}
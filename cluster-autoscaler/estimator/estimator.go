@@ -28,10 +28,12 @@ import (
 const (
 	// BinpackingEstimatorName is the name of binpacking estimator.
 	BinpackingEstimatorName = "binpacking"
+	// DominantResourceEstimatorName is the name of the estimator packing pods by their dominant resource.
+	DominantResourceEstimatorName = "dominant-resource"
 )
 
 // AvailableEstimators is a list of available estimators.
-var AvailableEstimators = []string{BinpackingEstimatorName}
+var AvailableEstimators = []string{BinpackingEstimatorName, DominantResourceEstimatorName}
 
 // PodEquivalenceGroup represents a group of pods, which have the same scheduling
 // requirements and are managed by the same controller.
@@ -70,6 +72,13 @@ func NewEstimatorBuilder(name string, limiter EstimationLimiter, orderer Estimat
 			context EstimationContext) Estimator {
 			return NewBinpackingNodeEstimator(clusterSnapshot, limiter, orderer, context, estimationAnalyserFunc)
 		}, nil
+	case DominantResourceEstimatorName:
+		// Pods are ordered by their dominant resource, the orderer isn't used.
+		return func(
+			clusterSnapshot clustersnapshot.ClusterSnapshot,
+			context EstimationContext) Estimator {
+			return NewDominantResourceNodeEstimator(clusterSnapshot, limiter, context, estimationAnalyserFunc)
+		}, nil
 	}
 	return nil, fmt.Errorf("unknown estimator: %s", name)
 }
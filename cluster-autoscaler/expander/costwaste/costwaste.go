@@ -0,0 +1,341 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+  The cost-waste expander scores each option by its estimated hourly cost per
+  scheduled pod, combined with the fraction of allocatable CPU and memory the
+  option would leave unused:
+
+    score = cost weight * (cost per pod / cheapest paid cost per pod) + waste weight * waste
+
+  Lowest scores win. Node prices come from the price sheet stored in the
+  expander ConfigMap (by node group label, then by instance type), falling
+  back to the cloud provider PricingModel when it has one. When no option can
+  be priced, only the waste is compared.
+
+  As with the priority expander, the ConfigMap is read from the lister on each
+  call, so price and weight updates are picked up without restart. Invalid
+  updates are reported and ignored, the last valid configuration is kept.
+*/
+
+package costwaste
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	apiv1 "k8s.io/api/core/v1"
+	kube_errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/expander"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
+	podutils "k8s.io/autoscaler/cluster-autoscaler/utils/pod"
+	v1lister "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/record"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	// CostWasteConfigMapName defines a name of the ConfigMap used to store cost-waste expander configuration
+	CostWasteConfigMapName = "cluster-autoscaler-cost-waste-expander"
+	// PricesConfigMapKey defines the key used in the ConfigMap to store the price sheet
+	PricesConfigMapKey = "prices"
+	// WeightsConfigMapKey defines the key used in the ConfigMap to store the scoring weights
+	WeightsConfigMapKey = "weights"
+
+	defaultCostWeight  = 1.0
+	defaultWasteWeight = 1.0
+)
+
+// priceSheet holds hourly node prices. Labels are "key=value" selectors matched
+// against the node group template node, and take precedence over instance types.
+type priceSheet struct {
+	InstanceTypes map[string]float64 `yaml:"instanceTypes"`
+	Labels        map[string]float64 `yaml:"labels"`
+}
+
+type weights struct {
+	Cost  float64 `yaml:"cost"`
+	Waste float64 `yaml:"waste"`
+}
+
+type labelPrice struct {
+	key   string
+	value string
+	price float64
+}
+
+type config struct {
+	resourceVersion string
+	instanceTypes   map[string]float64
+	labels          []labelPrice
+	weights         weights
+}
+
+func defaultConfig() *config {
+	return &config{weights: weights{Cost: defaultCostWeight, Waste: defaultWasteWeight}}
+}
+
+type costWaste struct {
+	cloudProvider   cloudprovider.CloudProvider
+	configMapLister v1lister.ConfigMapNamespaceLister
+	logRecorder     record.EventRecorder
+	config          *config
+	// rejectedVersion is the last invalid ConfigMap version, so it's only reported once.
+	rejectedVersion string
+	// unpricedVersions holds the configuration version node groups were reported unpriced for,
+	// so they're only reported once per ConfigMap version.
+	unpricedVersions map[string]string
+}
+
+// NewFilter returns an expansion filter that picks node groups with the lowest combination
+// of hourly cost per scheduled pod and wasted allocatable resources.
+func NewFilter(cloudProvider cloudprovider.CloudProvider, configMapLister v1lister.ConfigMapNamespaceLister,
+	logRecorder record.EventRecorder) expander.Filter {
+	return &costWaste{
+		cloudProvider:    cloudProvider,
+		configMapLister:  configMapLister,
+		logRecorder:      logRecorder,
+		config:           defaultConfig(),
+		unpricedVersions: make(map[string]string),
+	}
+}
+
+// reloadConfigMap refreshes the configuration when the ConfigMap changed. It returns
+// the ConfigMap, which is nil if it doesn't exist.
+func (c *costWaste) reloadConfigMap() *apiv1.ConfigMap {
+	cm, err := c.configMapLister.Get(CostWasteConfigMapName)
+	if err != nil {
+		if !kube_errors.IsNotFound(err) {
+			klog.Warningf("Cost-waste expander failed to get config map %s: %v", CostWasteConfigMapName, err)
+			return nil
+		}
+		if c.config.resourceVersion != "" {
+			klog.Warningf("Cost-waste expander config map %s was removed, using default weights and cloud provider pricing", CostWasteConfigMapName)
+		}
+		c.config = defaultConfig()
+		return nil
+	}
+	if cm.ResourceVersion == c.config.resourceVersion || cm.ResourceVersion == c.rejectedVersion {
+		return cm
+	}
+
+	newConfig, err := parseConfig(cm)
+	if err != nil {
+		c.rejectedVersion = cm.ResourceVersion
+		c.logConfigWarning(cm, "CostWasteConfigMapInvalid", fmt.Sprintf("Wrong configuration for cost-waste expander: %v. Ignoring update.", err))
+		return cm
+	}
+	c.config = newConfig
+	klog.V(4).Info("Successfully loaded cost-waste configuration from configmap.")
+	return cm
+}
+
+func (c *costWaste) logConfigWarning(cm *apiv1.ConfigMap, reason, msg string) {
+	if cm != nil {
+		c.logRecorder.Event(cm, apiv1.EventTypeWarning, reason, msg)
+	}
+	klog.Warning(msg)
+}
+
+func parseConfig(cm *apiv1.ConfigMap) (*config, error) {
+	result := defaultConfig()
+	result.resourceVersion = cm.ResourceVersion
+
+	var sheet priceSheet
+	if err := yaml.UnmarshalStrict([]byte(cm.Data[PricesConfigMapKey]), &sheet); err != nil {
+		return nil, fmt.Errorf("can't parse YAML with %s in the configmap: %v", PricesConfigMapKey, err)
+	}
+	for instanceType, price := range sheet.InstanceTypes {
+		if price < 0 {
+			return nil, fmt.Errorf("negative price %v for instance type %s", price, instanceType)
+		}
+	}
+	result.instanceTypes = sheet.InstanceTypes
+	for selector, price := range sheet.Labels {
+		key, value, found := strings.Cut(selector, "=")
+		if !found || key == "" {
+			return nil, fmt.Errorf("invalid label selector %q, expected key=value", selector)
+		}
+		if price < 0 {
+			return nil, fmt.Errorf("negative price %v for label %s", price, selector)
+		}
+		result.labels = append(result.labels, labelPrice{key: key, value: value, price: price})
+	}
+	// Sorted, so a node group matching several labels always gets the same price.
+	sort.Slice(result.labels, func(i, j int) bool {
+		if result.labels[i].key != result.labels[j].key {
+			return result.labels[i].key < result.labels[j].key
+		}
+		return result.labels[i].value < result.labels[j].value
+	})
+
+	if weightsYAML, found := cm.Data[WeightsConfigMapKey]; found {
+		if err := yaml.UnmarshalStrict([]byte(weightsYAML), &result.weights); err != nil {
+			return nil, fmt.Errorf("can't parse YAML with %s in the configmap: %v", WeightsConfigMapKey, err)
+		}
+		if result.weights.Cost < 0 || result.weights.Waste < 0 {
+			return nil, fmt.Errorf("weights must not be negative, got cost %v and waste %v", result.weights.Cost, result.weights.Waste)
+		}
+	}
+	return result, nil
+}
+
+// nodePrice returns the hourly price of the node, and whether it is known.
+func (c *costWaste) nodePrice(node *apiv1.Node, pricingModel cloudprovider.PricingModel, now time.Time) (float64, bool) {
+	for _, label := range c.config.labels {
+		if value, found := node.Labels[label.key]; found && value == label.value {
+			return label.price, true
+		}
+	}
+	if price, found := c.config.instanceTypes[node.Labels[apiv1.LabelInstanceTypeStable]]; found {
+		return price, true
+	}
+	if pricingModel == nil {
+		return 0, false
+	}
+	price, err := pricingModel.NodePrice(node, now, now.Add(time.Hour))
+	if err != nil {
+		klog.V(4).Infof("Cost-waste expander failed to get price of node %s: %v", node.Name, err)
+		return 0, false
+	}
+	return price, true
+}
+
+type optionScore struct {
+	option     expander.Option
+	costPerPod float64
+	priced     bool
+	waste      float64
+}
+
+// BestOptions returns the options with the lowest cost-waste score.
+func (c *costWaste) BestOptions(expansionOptions []expander.Option, nodeInfos map[string]*framework.NodeInfo) []expander.Option {
+	if len(expansionOptions) == 0 {
+		return nil
+	}
+	cm := c.reloadConfigMap()
+	pricingModel, err := c.cloudProvider.Pricing()
+	if err != nil {
+		pricingModel = nil
+	}
+
+	now := time.Now()
+	var scores []optionScore
+	var unpriced []string
+	cheapestPaid := math.MaxFloat64
+	for _, option := range expansionOptions {
+		nodeInfo, found := nodeInfos[option.NodeGroup.Id()]
+		if !found {
+			klog.Errorf("No node info for: %s", option.NodeGroup.Id())
+			continue
+		}
+		score := optionScore{option: option, waste: wasteFraction(option, nodeInfo.Node())}
+		if price, found := c.nodePrice(nodeInfo.Node(), pricingModel, now); found && len(option.Pods) > 0 {
+			score.priced = true
+			score.costPerPod = price * float64(option.NodeCount) / float64(len(option.Pods))
+			if score.costPerPod > 0 {
+				cheapestPaid = math.Min(cheapestPaid, score.costPerPod)
+			}
+			delete(c.unpricedVersions, option.NodeGroup.Id())
+		} else {
+			unpriced = append(unpriced, option.NodeGroup.Id())
+		}
+		scores = append(scores, score)
+	}
+	if len(scores) == 0 {
+		return nil
+	}
+
+	compareCost := len(unpriced) < len(scores)
+	if compareCost && len(unpriced) > 0 {
+		c.reportUnpriced(cm, unpriced)
+	}
+
+	var best []expander.Option
+	bestScore := math.MaxFloat64
+	for _, score := range scores {
+		if compareCost && !score.priced {
+			continue
+		}
+		value := c.config.weights.Waste * score.waste
+		if compareCost {
+			// Normalized against the cheapest paid option, so that weights don't depend on the currency.
+			// Free options have a relative cost of 0, and always cost less than paid ones.
+			relativeCost := 0.0
+			if score.costPerPod > 0 {
+				relativeCost = score.costPerPod / cheapestPaid
+			}
+			value += c.config.weights.Cost * relativeCost
+		}
+		klog.V(1).Infof("Expanding Node Group %s would cost %0.4f per pod per hour and waste %0.2f%% of allocatable, score %0.4f",
+			score.option.NodeGroup.Id(), score.costPerPod, score.waste*100.0, value)
+
+		if value < bestScore {
+			bestScore = value
+			best = []expander.Option{score.option}
+		} else if value == bestScore {
+			best = append(best, score.option)
+		}
+	}
+	return best
+}
+
+// reportUnpriced warns about node groups which can't be priced, once per node group and ConfigMap version.
+func (c *costWaste) reportUnpriced(cm *apiv1.ConfigMap, unpriced []string) {
+	var unreported []string
+	for _, id := range unpriced {
+		if version, found := c.unpricedVersions[id]; !found || version != c.config.resourceVersion {
+			c.unpricedVersions[id] = c.config.resourceVersion
+			unreported = append(unreported, id)
+		}
+	}
+	if len(unreported) == 0 {
+		klog.V(4).Infof("Cost-waste expander: no price found for node groups %s", strings.Join(unpriced, ", "))
+		return
+	}
+	c.logConfigWarning(cm, "CostWasteNodeGroupNotPriced", fmt.Sprintf("Cost-waste expander: no price found for node groups %s. "+
+		"The groups won't be used.", strings.Join(unreported, ", ")))
+}
+
+// wasteFraction returns the fraction of allocatable CPU and memory left unused by the option pods, averaged.
+func wasteFraction(option expander.Option, node *apiv1.Node) float64 {
+	var requestedCPU, requestedMemory int64
+	for _, pod := range option.Pods {
+		requests := podutils.PodRequests(pod)
+		requestedCPU += requests.Cpu().MilliValue()
+		requestedMemory += requests.Memory().Value()
+	}
+	allocatable := node.Status.Allocatable
+	if allocatable == nil {
+		allocatable = node.Status.Capacity
+	}
+	wastedCPU := wasted(requestedCPU, allocatable.Cpu().MilliValue()*int64(option.NodeCount))
+	wastedMemory := wasted(requestedMemory, allocatable.Memory().Value()*int64(option.NodeCount))
+	return (wastedCPU + wastedMemory) / 2
+}
+
+func wasted(requested, available int64) float64 {
+	if available <= 0 {
+		return 0
+	}
+	return math.Max(0, float64(available-requested)/float64(available))
+}
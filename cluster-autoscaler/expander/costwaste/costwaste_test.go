@@ -0,0 +1,260 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package costwaste
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	testprovider "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
+	"k8s.io/autoscaler/cluster-autoscaler/expander"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/kubernetes"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+)
+
+const (
+	testNamespace = "default"
+	prices        = `
+instanceTypes:
+  small: 0.10
+  large: 0.12
+`
+)
+
+type testPricingModel struct {
+	nodePrice map[string]float64
+}
+
+func (tpm *testPricingModel) NodePrice(node *apiv1.Node, startTime time.Time, endTime time.Time) (float64, error) {
+	if price, found := tpm.nodePrice[node.Name]; found {
+		return price, nil
+	}
+	return 0.0, fmt.Errorf("price for node %v not found", node.Name)
+}
+
+func (tpm *testPricingModel) PodPrice(pod *apiv1.Pod, startTime time.Time, endTime time.Time) (float64, error) {
+	return 0.0, fmt.Errorf("price for pod %v not found", pod.Name)
+}
+
+// testOptions returns two options scheduling the same 4 pods: on 2 small nodes, leaving
+// nothing unused (0.05 per pod), or on a large node, wasting half of it (0.03 per pod).
+func testOptions() ([]expander.Option, map[string]*framework.NodeInfo) {
+	var pods []*apiv1.Pod
+	for i := 0; i < 4; i++ {
+		pods = append(pods, BuildTestPod(fmt.Sprintf("p%d", i), 500, 500))
+	}
+	small := BuildTestNode("small", 1000, 1000)
+	small.Labels = map[string]string{apiv1.LabelInstanceTypeStable: "small", "pool": "reserved"}
+	large := BuildTestNode("large", 4000, 4000)
+	large.Labels = map[string]string{apiv1.LabelInstanceTypeStable: "large"}
+
+	options := []expander.Option{
+		{NodeGroup: testprovider.NewTestNodeGroup("ng-small", 10, 0, 0, true, false, "small", nil, nil), NodeCount: 2, Pods: pods},
+		{NodeGroup: testprovider.NewTestNodeGroup("ng-large", 10, 0, 0, true, false, "large", nil, nil), NodeCount: 1, Pods: pods},
+	}
+	nodeInfos := map[string]*framework.NodeInfo{
+		"ng-small": framework.NewTestNodeInfo(small),
+		"ng-large": framework.NewTestNodeInfo(large),
+	}
+	return options, nodeInfos
+}
+
+func getFilterInstance(t *testing.T, data map[string]string, pricingModel *testPricingModel) (expander.Filter, *record.FakeRecorder, *apiv1.ConfigMap) {
+	var configMaps []*apiv1.ConfigMap
+	var cm *apiv1.ConfigMap
+	if data != nil {
+		cm = &apiv1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:       testNamespace,
+				Name:            CostWasteConfigMapName,
+				ResourceVersion: "1",
+			},
+			Data: data,
+		}
+		configMaps = append(configMaps, cm)
+	}
+	lister, err := kubernetes.NewTestConfigMapLister(configMaps)
+	assert.NoError(t, err)
+	provider := testprovider.NewTestCloudProviderBuilder().Build()
+	if pricingModel != nil {
+		provider.SetPricingModel(pricingModel)
+	}
+	r := record.NewFakeRecorder(100)
+	return NewFilter(provider, lister.ConfigMaps(testNamespace), r), r, cm
+}
+
+func nodeGroupIds(options []expander.Option) []string {
+	var ids []string
+	for _, option := range options {
+		ids = append(ids, option.NodeGroup.Id())
+	}
+	return ids
+}
+
+func TestBestOptions(t *testing.T) {
+	for desc, test := range map[string]struct {
+		data         map[string]string
+		pricingModel *testPricingModel
+		want         []string
+	}{
+		"cheaper per pod wins with default weights": {
+			data: map[string]string{PricesConfigMapKey: prices},
+			want: []string{"ng-large"},
+		},
+		"waste weight favors the less wasteful option": {
+			data: map[string]string{PricesConfigMapKey: prices, WeightsConfigMapKey: "cost: 1\nwaste: 2\n"},
+			want: []string{"ng-small"},
+		},
+		"cost only": {
+			data: map[string]string{PricesConfigMapKey: prices, WeightsConfigMapKey: "cost: 1\nwaste: 0\n"},
+			want: []string{"ng-large"},
+		},
+		"label price overrides instance type price": {
+			data: map[string]string{PricesConfigMapKey: prices + "labels:\n  pool=reserved: 0.01\n"},
+			want: []string{"ng-small"},
+		},
+		"cloud provider pricing without config map": {
+			pricingModel: &testPricingModel{nodePrice: map[string]float64{"small": 0.01, "large": 0.12}},
+			want:         []string{"ng-small"},
+		},
+		"price sheet overrides cloud provider pricing": {
+			data:         map[string]string{PricesConfigMapKey: prices},
+			pricingModel: &testPricingModel{nodePrice: map[string]float64{"small": 0.01, "large": 0.12}},
+			want:         []string{"ng-large"},
+		},
+		"unpriced node groups aren't used": {
+			data: map[string]string{PricesConfigMapKey: "instanceTypes:\n  small: 1000\n"},
+			want: []string{"ng-small"},
+		},
+		"waste only when nothing is priced": {
+			want: []string{"ng-small"},
+		},
+		"free node groups cost less than paid ones": {
+			data: map[string]string{PricesConfigMapKey: "instanceTypes:\n  small: 0\n  large: 0.12\n", WeightsConfigMapKey: "cost: 1\nwaste: 0\n"},
+			want: []string{"ng-small"},
+		},
+		"equal scores are all returned": {
+			data: map[string]string{PricesConfigMapKey: "instanceTypes:\n  small: 0.06\n  large: 0.12\n", WeightsConfigMapKey: "cost: 1\nwaste: 0\n"},
+			want: []string{"ng-small", "ng-large"},
+		},
+	} {
+		t.Run(desc, func(t *testing.T) {
+			s, _, _ := getFilterInstance(t, test.data, test.pricingModel)
+			options, nodeInfos := testOptions()
+			assert.Equal(t, test.want, nodeGroupIds(s.BestOptions(options, nodeInfos)))
+		})
+	}
+}
+
+func TestBestOptionsWarnsAboutUnpricedNodeGroups(t *testing.T) {
+	s, r, _ := getFilterInstance(t, map[string]string{PricesConfigMapKey: "instanceTypes:\n  small: 0.1\n"}, nil)
+	options, nodeInfos := testOptions()
+	assert.Equal(t, []string{"ng-small"}, nodeGroupIds(s.BestOptions(options, nodeInfos)))
+	assert.Equal(t, "Warning CostWasteNodeGroupNotPriced Cost-waste expander: no price found for node groups ng-large. "+
+		"The groups won't be used.", <-r.Events)
+}
+
+func TestBestOptionsWarnsAboutUnpricedNodeGroupsOncePerVersion(t *testing.T) {
+	s, r, cm := getFilterInstance(t, map[string]string{PricesConfigMapKey: "instanceTypes:\n  small: 0.1\n"}, nil)
+	options, nodeInfos := testOptions()
+	s.BestOptions(options, nodeInfos)
+	s.BestOptions(options, nodeInfos)
+	assert.Len(t, r.Events, 1)
+	<-r.Events
+
+	// reported again once the config map changed
+	cm.ResourceVersion = "2"
+	s.BestOptions(options, nodeInfos)
+	s.BestOptions(options, nodeInfos)
+	assert.Len(t, r.Events, 1)
+}
+
+func TestBestOptionsHandlesConfigUpdate(t *testing.T) {
+	s, r, cm := getFilterInstance(t, map[string]string{PricesConfigMapKey: prices}, nil)
+	options, nodeInfos := testOptions()
+	assert.Equal(t, []string{"ng-large"}, nodeGroupIds(s.BestOptions(options, nodeInfos)))
+
+	// updates are ignored until the config map version changes
+	cm.Data[WeightsConfigMapKey] = "waste: 2"
+	assert.Equal(t, []string{"ng-large"}, nodeGroupIds(s.BestOptions(options, nodeInfos)))
+	cm.ResourceVersion = "2"
+	assert.Equal(t, []string{"ng-small"}, nodeGroupIds(s.BestOptions(options, nodeInfos)))
+
+	// invalid updates are reported once, and the last valid configuration is kept
+	cm.Data[WeightsConfigMapKey] = "waste: -1"
+	cm.ResourceVersion = "3"
+	assert.Equal(t, []string{"ng-small"}, nodeGroupIds(s.BestOptions(options, nodeInfos)))
+	assert.Equal(t, []string{"ng-small"}, nodeGroupIds(s.BestOptions(options, nodeInfos)))
+	assert.Equal(t, "Warning CostWasteConfigMapInvalid Wrong configuration for cost-waste expander: weights must not be "+
+		"negative, got cost 1 and waste -1. Ignoring update.", <-r.Events)
+	assert.Empty(t, r.Events)
+}
+
+func TestParseConfig(t *testing.T) {
+	for desc, test := range map[string]struct {
+		data    map[string]string
+		want    *config
+		wantErr bool
+	}{
+		"empty config map": {
+			data: map[string]string{},
+			want: &config{resourceVersion: "1", weights: weights{Cost: 1, Waste: 1}},
+		},
+		"full config": {
+			data: map[string]string{
+				PricesConfigMapKey:  "instanceTypes:\n  small: 0.1\nlabels:\n  b=x: 0.3\n  a=y=z: 0.2\n",
+				WeightsConfigMapKey: "cost: 2\n",
+			},
+			want: &config{
+				resourceVersion: "1",
+				instanceTypes:   map[string]float64{"small": 0.1},
+				labels:          []labelPrice{{key: "a", value: "y=z", price: 0.2}, {key: "b", value: "x", price: 0.3}},
+				weights:         weights{Cost: 2, Waste: 1},
+			},
+		},
+		"unknown field": {
+			data:    map[string]string{PricesConfigMapKey: "instanceType:\n  small: 0.1\n"},
+			wantErr: true,
+		},
+		"invalid label selector": {
+			data:    map[string]string{PricesConfigMapKey: "labels:\n  pool: 0.1\n"},
+			wantErr: true,
+		},
+		"negative price": {
+			data:    map[string]string{PricesConfigMapKey: "instanceTypes:\n  small: -0.1\n"},
+			wantErr: true,
+		},
+	} {
+		t.Run(desc, func(t *testing.T) {
+			cm := &apiv1.ConfigMap{ObjectMeta: metav1.ObjectMeta{ResourceVersion: "1"}, Data: test.data}
+			got, err := parseConfig(cm)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
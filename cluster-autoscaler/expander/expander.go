@@ -24,7 +24,7 @@ import (
 
 var (
 	// AvailableExpanders is a list of available expander options
-	AvailableExpanders = []string{RandomExpanderName, MostPodsExpanderName, LeastWasteExpanderName, PriceBasedExpanderName, PriorityBasedExpanderName, GRPCExpanderName}
+	AvailableExpanders = []string{RandomExpanderName, MostPodsExpanderName, LeastWasteExpanderName, PriceBasedExpanderName, PriorityBasedExpanderName, GRPCExpanderName, CostWasteExpanderName, SpotFallbackExpanderName}
 	// RandomExpanderName selects a node group at random
 	RandomExpanderName = "random"
 	// MostPodsExpanderName selects a node group that fits the most pods
@@ -40,6 +40,10 @@ var (
 	PriorityBasedExpanderName = "priority"
 	// GRPCExpanderName uses the gRPC client expander to call to an external gRPC server to select a node group for scale up
 	GRPCExpanderName = "grpc"
+	// CostWasteExpanderName selects a node group based on its hourly cost per scheduled pod and wasted allocatable resources
+	CostWasteExpanderName = "cost-waste"
+	// SpotFallbackExpanderName deprioritizes spot node groups whose instance family or zone recently ran out of capacity
+	SpotFallbackExpanderName = "spot-fallback"
 )
 
 // Option describes an option to expand the cluster.
@@ -49,6 +53,8 @@ type Option struct {
 	NodeCount         int
 	Debug             string
 	Pods              []*apiv1.Pod
+	// Explanation is a human-readable reason for choosing the option, set by expanders able to provide one.
+	Explanation string
 }
 
 // Strategy describes an interface for selecting the best option when scaling up
@@ -60,3 +66,29 @@ type Strategy interface {
 type Filter interface {
 	BestOptions(options []Option, nodeInfo map[string]*framework.NodeInfo) []Option
 }
+
+// ClusterContextObserver is implemented by expanders taking the cluster state into account.
+// ObserveClusterContext is called before options are evaluated.
+type ClusterContextObserver interface {
+	ObserveClusterContext(clusterContext *ClusterContext)
+}
+
+// ClusterContext describes the cluster at the time of a scale-up.
+type ClusterContext struct {
+	// NodeGroups is keyed by node group id.
+	NodeGroups    map[string]NodeGroupContext
+	ReadyNodes    int
+	UpcomingNodes int
+}
+
+// NodeGroupContext describes a node group at the time of a scale-up.
+type NodeGroupContext struct {
+	MinSize       int
+	MaxSize       int
+	TargetSize    int
+	ReadyNodes    int
+	UnreadyNodes  int
+	UpcomingNodes int
+	BackedOff     bool
+	BackoffReason string
+}
@@ -43,3 +43,15 @@ func (c *chainStrategy) BestOption(options []expander.Option, nodeInfo map[strin
 	}
 	return c.fallback.BestOption(filteredOptions, nodeInfo)
 }
+
+// ObserveClusterContext passes the cluster context to the filters taking it into account.
+func (c *chainStrategy) ObserveClusterContext(clusterContext *expander.ClusterContext) {
+	for _, filter := range c.filters {
+		if observer, ok := filter.(expander.ClusterContextObserver); ok {
+			observer.ObserveClusterContext(clusterContext)
+		}
+	}
+	if observer, ok := c.fallback.(expander.ClusterContextObserver); ok {
+		observer.ObserveClusterContext(clusterContext)
+	}
+}
@@ -131,3 +131,20 @@ func newOption(debug string) *expander.Option {
 		Debug: debug,
 	}
 }
+
+type observingTestFilter struct {
+	substringTestFilterStrategy
+	observed *expander.ClusterContext
+}
+
+func (f *observingTestFilter) ObserveClusterContext(clusterContext *expander.ClusterContext) {
+	f.observed = clusterContext
+}
+
+func TestChainStrategy_ObserveClusterContext(t *testing.T) {
+	observing := &observingTestFilter{}
+	subject := newChainStrategy([]expander.Filter{newSubstringTestFilterStrategy("a"), observing}, newSubstringTestFilterStrategy("b"))
+	clusterContext := &expander.ClusterContext{ReadyNodes: 3}
+	subject.(expander.ClusterContextObserver).ObserveClusterContext(clusterContext)
+	assert.Same(t, clusterContext, observing.observed)
+}
@@ -19,6 +19,7 @@ package example
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
 	"net"
 
@@ -55,6 +56,7 @@ func Serve(certPath string, keyPath string, port uint) {
 	expanderServerImpl := NewExpanderServerImpl()
 
 	protos.RegisterExpanderServer(grpcServer, expanderServerImpl)
+	protos.RegisterExpanderV2Server(grpcServer, NewExpanderV2ServerImpl())
 
 	// start the server
 	log.Println("Starting server on port ", port)
@@ -104,3 +106,64 @@ func (ServerImpl *ExpanderServerImpl) BestOptions(ctx context.Context, req *prot
 		Options: []*protos.Option{choice},
 	}, nil
 }
+
+// ExpanderV2ServerImpl is an implementation of ExpanderV2 Server from proto definition
+type ExpanderV2ServerImpl struct {
+	protos.UnimplementedExpanderV2Server
+}
+
+// NewExpanderV2ServerImpl is this Expander's implementation of the v2 server
+func NewExpanderV2ServerImpl() *ExpanderV2ServerImpl {
+	return &ExpanderV2ServerImpl{}
+}
+
+// Health tells Cluster Autoscaler that the v2 protocol can be used.
+func (ServerImpl *ExpanderV2ServerImpl) Health(ctx context.Context, req *protos.HealthRequest) (*protos.HealthResponse, error) {
+	return &protos.HealthResponse{Status: protos.ServingStatus_SERVING_STATUS_SERVING}, nil
+}
+
+// ScoreOptions scores all options passed from the gRPC Client in CA, according to the defined strategy.
+func (ServerImpl *ExpanderV2ServerImpl) ScoreOptions(ctx context.Context, req *protos.ScoreOptionsRequest) (*protos.ScoreOptionsResponse, error) {
+	log.Printf("Received ScoreOptions Request with %v options", len(req.GetOptions()))
+	return scoreOptions(req.GetOptions(), req.GetClusterContext()), nil
+}
+
+// StreamScoreOptions receives the options in several messages, and scores them all at once.
+func (ServerImpl *ExpanderV2ServerImpl) StreamScoreOptions(stream protos.ExpanderV2_StreamScoreOptionsServer) error {
+	var opts []*protos.ExpansionOption
+	var clusterContext *protos.ClusterContext
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if req.GetClusterContext() != nil {
+			clusterContext = req.GetClusterContext()
+		}
+		opts = append(opts, req.GetOptions()...)
+	}
+	log.Printf("Received StreamScoreOptions Request with %v options", len(opts))
+	return stream.SendAndClose(scoreOptions(opts, clusterContext))
+}
+
+// This strategy prefers node groups with the fewest upcoming nodes, and never picks backed off node groups,
+// but can be replaced with any arbitrary logic
+func scoreOptions(opts []*protos.ExpansionOption, clusterContext *protos.ClusterContext) *protos.ScoreOptionsResponse {
+	response := &protos.ScoreOptionsResponse{}
+	for _, opt := range opts {
+		nodeGroup := clusterContext.GetNodeGroups()[opt.NodeGroupId]
+		if nodeGroup.GetBackedOff() {
+			continue
+		}
+		response.Scores = append(response.Scores, &protos.OptionScore{
+			NodeGroupId:         opt.NodeGroupId,
+			Score:               -float64(nodeGroup.GetUpcomingNodes()),
+			Explanation:         fmt.Sprintf("%d nodes already upcoming", nodeGroup.GetUpcomingNodes()),
+			SimilarNodeGroupIds: opt.SimilarNodeGroupIds,
+		})
+	}
+	return response
+}
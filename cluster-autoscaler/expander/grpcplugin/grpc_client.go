@@ -29,28 +29,46 @@ import (
 	"k8s.io/klog/v2"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
 )
 
 const (
 	gRPCTimeout        = 10 * time.Second
 	gRPCMaxRecvMsgSize = 512 << 20
+	// negotiationInterval is how often the protocol version is negotiated again, so that
+	// upgraded (or downgraded) plugins are picked up without restart.
+	negotiationInterval = 5 * time.Minute
+)
+
+type protocolVersion int
+
+const (
+	protocolUnknown protocolVersion = iota
+	protocolV1
+	protocolV2
 )
 
 type grpcclientstrategy struct {
-	grpcClient protos.ExpanderClient
+	grpcClient   protos.ExpanderClient
+	grpcClientV2 protos.ExpanderV2Client
+	protocol     protocolVersion
+	negotiatedAt time.Time
+	// clusterContext is sent to v2 plugins, it's set before each scale-up.
+	clusterContext *expander.ClusterContext
 }
 
 // NewFilter returns an expansion filter that creates a gRPC client, and calls out to a gRPC server
 func NewFilter(expanderCert string, expanderUrl string) expander.Filter {
-	client := createGRPCClient(expanderCert, expanderUrl)
-	if client == nil {
+	conn := createGRPCClientConn(expanderCert, expanderUrl)
+	if conn == nil {
 		return &grpcclientstrategy{grpcClient: nil}
 	}
-	return &grpcclientstrategy{grpcClient: client}
+	return &grpcclientstrategy{grpcClient: protos.NewExpanderClient(conn), grpcClientV2: protos.NewExpanderV2Client(conn)}
 }
 
-func createGRPCClient(expanderCert string, expanderUrl string) protos.ExpanderClient {
+func createGRPCClientConn(expanderCert string, expanderUrl string) *grpc.ClientConn {
 	if expanderCert == "" {
 		log.Fatalf("GRPC Expander Cert not specified, insecure connections not allowed")
 		return nil
@@ -70,7 +88,12 @@ func createGRPCClient(expanderCert string, expanderUrl string) protos.ExpanderCl
 		log.Fatalf("Fail to dial server: %v", err)
 		return nil
 	}
-	return protos.NewExpanderClient(conn)
+	return conn
+}
+
+// ObserveClusterContext keeps the cluster context, to send it to v2 plugins.
+func (g *grpcclientstrategy) ObserveClusterContext(clusterContext *expander.ClusterContext) {
+	g.clusterContext = clusterContext
 }
 
 func (g *grpcclientstrategy) BestOptions(expansionOptions []expander.Option, nodeInfo map[string]*framework.NodeInfo) []expander.Option {
@@ -79,6 +102,54 @@ func (g *grpcclientstrategy) BestOptions(expansionOptions []expander.Option, nod
 		return expansionOptions
 	}
 
+	if g.negotiateProtocol() == protocolV2 {
+		options, err := g.bestOptionsV2(expansionOptions, nodeInfo)
+		if err == nil {
+			return options
+		}
+		if status.Code(err) != codes.Unimplemented {
+			klog.V(4).Infof("GRPC v2 call failed, no options filtered: %v", err)
+			return expansionOptions
+		}
+		klog.Warningf("GRPC expander doesn't implement the v2 protocol anymore, falling back to v1: %v", err)
+		g.protocol, g.negotiatedAt = protocolV1, time.Now()
+	}
+	return g.bestOptionsV1(expansionOptions, nodeInfo)
+}
+
+// negotiateProtocol returns the protocol version to use, checking which one the plugin
+// implements at most every negotiationInterval. Plugins answering the v2 health check
+// use the v2 protocol.
+func (g *grpcclientstrategy) negotiateProtocol() protocolVersion {
+	if g.grpcClientV2 == nil {
+		return protocolV1
+	}
+	if g.protocol != protocolUnknown && time.Since(g.negotiatedAt) < negotiationInterval {
+		return g.protocol
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), gRPCTimeout)
+	defer cancel()
+	response, err := g.grpcClientV2.Health(ctx, &protos.HealthRequest{})
+	switch {
+	case err == nil && response.GetStatus() == protos.ServingStatus_SERVING_STATUS_SERVING:
+		g.protocol, g.negotiatedAt = protocolV2, time.Now()
+	case err == nil:
+		klog.Warningf("GRPC expander v2 health check returned %v, using v1 protocol", response.GetStatus())
+		g.protocol, g.negotiatedAt = protocolV1, time.Now()
+	case status.Code(err) == codes.Unimplemented:
+		klog.V(2).Info("GRPC expander doesn't implement the v2 protocol, using v1 protocol")
+		g.protocol, g.negotiatedAt = protocolV1, time.Now()
+	default:
+		// The plugin may just be unavailable, negotiate again on the next call.
+		klog.V(4).Infof("GRPC expander v2 health check failed, using v1 protocol: %v", err)
+		g.protocol = protocolUnknown
+		return protocolV1
+	}
+	return g.protocol
+}
+
+func (g *grpcclientstrategy) bestOptionsV1(expansionOptions []expander.Option, nodeInfo map[string]*framework.NodeInfo) []expander.Option {
 	// Transform inputs to gRPC inputs
 	grpcOptionsSlice, nodeGroupIDOptionMap := populateOptionsForGRPC(expansionOptions)
 	grpcNodeMap, grpcNodeBytesMap := populateNodeInfoForGRPC(nodeInfo)
@@ -157,7 +228,7 @@ func transformAndSanitizeOptionsFromGRPC(bestOptionsResponseOptions []*protos.Op
 			continue
 		}
 		if expanderOption, ok := nodeGroupIDOptionMap[option.NodeGroupId]; ok {
-			expanderOption.SimilarNodeGroups = getRetainedSimilarNodegroups(option, expanderOption)
+			expanderOption.SimilarNodeGroups = getRetainedSimilarNodegroups(option.SimilarNodeGroupIds, expanderOption)
 			options = append(options, expanderOption)
 		} else {
 			klog.Errorf("GRPC server returned invalid nodeGroup ID: %s", option.NodeGroupId)
@@ -169,11 +240,11 @@ func transformAndSanitizeOptionsFromGRPC(bestOptionsResponseOptions []*protos.Op
 
 // Any similar options that were not included in the original grpcExpander request, but were added
 // as part of the response, will be ignored
-func getRetainedSimilarNodegroups(grpcOption *protos.Option, expanderOption expander.Option) []cloudprovider.NodeGroup {
+func getRetainedSimilarNodegroups(similarNodeGroupIds []string, expanderOption expander.Option) []cloudprovider.NodeGroup {
 	var retainedSimilarNodeGroups []cloudprovider.NodeGroup
 	for _, sng := range expanderOption.SimilarNodeGroups {
 		retained := false
-		for _, id := range grpcOption.SimilarNodeGroupIds {
+		for _, id := range similarNodeGroupIds {
 			if sng.Id() == id {
 				retained = true
 				continue
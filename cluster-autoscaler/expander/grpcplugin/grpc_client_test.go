@@ -180,7 +180,7 @@ func TestBestOptionsValid(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	mockClient := mocks.NewMockExpanderClient(ctrl)
-	g := &grpcclientstrategy{mockClient}
+	g := &grpcclientstrategy{grpcClient: mockClient}
 
 	nodeInfos := makeFakeNodeInfos()
 	grpcNodeInfoMap := make(map[string]*v1.Node)
@@ -208,7 +208,7 @@ func TestBestOptionsEmpty(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	mockClient := mocks.NewMockExpanderClient(ctrl)
-	g := grpcclientstrategy{mockClient}
+	g := grpcclientstrategy{grpcClient: mockClient}
 
 	testCases := []struct {
 		desc         string
@@ -248,7 +248,7 @@ func TestBestOptionsErrors(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	mockClient := mocks.NewMockExpanderClient(ctrl)
-	g := grpcclientstrategy{mockClient}
+	g := grpcclientstrategy{grpcClient: mockClient}
 
 	badProtosOption := protos.Option{
 		NodeGroupId: "badID",
@@ -266,7 +266,7 @@ func TestBestOptionsErrors(t *testing.T) {
 	}{
 		{
 			desc:         "Bad gRPC client config",
-			client:       grpcclientstrategy{nil},
+			client:       grpcclientstrategy{grpcClient: nil},
 			nodeInfo:     makeFakeNodeInfos(),
 			mockResponse: protos.BestOptionsResponse{},
 			errResponse:  nil,
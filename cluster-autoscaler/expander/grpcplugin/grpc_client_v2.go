@@ -0,0 +1,171 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcplugin
+
+import (
+	"context"
+	"math"
+
+	"k8s.io/autoscaler/cluster-autoscaler/expander"
+	"k8s.io/autoscaler/cluster-autoscaler/expander/grpcplugin/protos"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// streamChunkSize is the maximum number of options sent in a single v2 message.
+	// Requests with more options are streamed.
+	streamChunkSize = 100
+)
+
+// bestOptionsV2 scores the options with the v2 protocol, and returns the options with the highest score.
+func (g *grpcclientstrategy) bestOptionsV2(expansionOptions []expander.Option, nodeInfo map[string]*framework.NodeInfo) ([]expander.Option, error) {
+	grpcOptions, nodeGroupIDOptionMap := populateExpansionOptionsForGRPC(expansionOptions)
+	_, grpcNodeBytesMap := populateNodeInfoForGRPC(nodeInfo)
+	clusterContext := clusterContextForGRPC(g.clusterContext)
+
+	klog.V(2).Infof("GPRC v2 call to score %v options", len(grpcOptions))
+	ctx, cancel := context.WithTimeout(context.Background(), gRPCTimeout)
+	defer cancel()
+	var response *protos.ScoreOptionsResponse
+	var err error
+	if len(grpcOptions) <= streamChunkSize {
+		response, err = g.grpcClientV2.ScoreOptions(ctx, &protos.ScoreOptionsRequest{Options: grpcOptions, NodeBytesMap: grpcNodeBytesMap, ClusterContext: clusterContext})
+	} else {
+		response, err = streamScoreOptions(ctx, g.grpcClientV2, grpcOptions, grpcNodeBytesMap, clusterContext)
+	}
+	if err != nil {
+		return nil, err
+	}
+	best := bestScoredOptions(response.GetScores(), nodeGroupIDOptionMap)
+	if len(best) == 0 {
+		klog.V(4).Info("GRPC returned no valid option scores, no options filtered")
+		return expansionOptions, nil
+	}
+	return best, nil
+}
+
+// streamScoreOptions sends the options in chunks of streamChunkSize, each with the nodes of its options.
+func streamScoreOptions(ctx context.Context, client protos.ExpanderV2Client, grpcOptions []*protos.ExpansionOption, grpcNodeBytesMap map[string][]byte, clusterContext *protos.ClusterContext) (*protos.ScoreOptionsResponse, error) {
+	stream, err := client.StreamScoreOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for start := 0; start < len(grpcOptions); start += streamChunkSize {
+		end := min(start+streamChunkSize, len(grpcOptions))
+		request := &protos.ScoreOptionsRequest{Options: grpcOptions[start:end]}
+		if start == 0 {
+			request.ClusterContext = clusterContext
+		}
+		if grpcNodeBytesMap != nil {
+			request.NodeBytesMap = make(map[string][]byte)
+			for _, option := range request.Options {
+				if nodeBytes, found := grpcNodeBytesMap[option.NodeGroupId]; found {
+					request.NodeBytesMap[option.NodeGroupId] = nodeBytes
+				}
+			}
+		}
+		if err := stream.Send(request); err != nil {
+			return nil, err
+		}
+	}
+	return stream.CloseAndRecv()
+}
+
+// populateExpansionOptionsForGRPC is populateOptionsForGRPC for the v2 protocol.
+func populateExpansionOptionsForGRPC(expansionOptions []expander.Option) ([]*protos.ExpansionOption, map[string]expander.Option) {
+	grpcOptions := make([]*protos.ExpansionOption, 0, len(expansionOptions))
+	nodeGroupIDOptionMap := make(map[string]expander.Option)
+	for _, option := range expansionOptions {
+		nodeGroupIDOptionMap[option.NodeGroup.Id()] = option
+		grpcOption := &protos.ExpansionOption{
+			NodeGroupId:         option.NodeGroup.Id(),
+			NodeCount:           int32(option.NodeCount),
+			Debug:               option.Debug,
+			SimilarNodeGroupIds: getSimilarNodeGroupIds(option),
+		}
+		for _, pod := range option.Pods {
+			podBytes, err := pod.Marshal()
+			if err != nil {
+				klog.Errorf("Failed to serialize pod %s/%s for GRPC expander: %v", pod.Namespace, pod.Name, err)
+				continue
+			}
+			grpcOption.PodBytes = append(grpcOption.PodBytes, podBytes)
+		}
+		grpcOptions = append(grpcOptions, grpcOption)
+	}
+	return grpcOptions, nodeGroupIDOptionMap
+}
+
+func clusterContextForGRPC(clusterContext *expander.ClusterContext) *protos.ClusterContext {
+	if clusterContext == nil {
+		return nil
+	}
+	result := &protos.ClusterContext{
+		NodeGroups:    make(map[string]*protos.NodeGroupContext, len(clusterContext.NodeGroups)),
+		ReadyNodes:    int32(clusterContext.ReadyNodes),
+		UpcomingNodes: int32(clusterContext.UpcomingNodes),
+	}
+	for id, nodeGroup := range clusterContext.NodeGroups {
+		result.NodeGroups[id] = &protos.NodeGroupContext{
+			MinSize:       int32(nodeGroup.MinSize),
+			MaxSize:       int32(nodeGroup.MaxSize),
+			TargetSize:    int32(nodeGroup.TargetSize),
+			ReadyNodes:    int32(nodeGroup.ReadyNodes),
+			UnreadyNodes:  int32(nodeGroup.UnreadyNodes),
+			UpcomingNodes: int32(nodeGroup.UpcomingNodes),
+			BackedOff:     nodeGroup.BackedOff,
+			BackoffReason: nodeGroup.BackoffReason,
+		}
+	}
+	return result
+}
+
+// bestScoredOptions returns the options with the highest score, with the explanation of their score.
+// Scores of unknown node groups, and invalid scores, are ignored.
+func bestScoredOptions(scores []*protos.OptionScore, nodeGroupIDOptionMap map[string]expander.Option) []expander.Option {
+	var best []expander.Option
+	bestScore := math.Inf(-1)
+	seen := make(map[string]bool)
+	for _, score := range scores {
+		if score == nil {
+			klog.Error("GRPC server returned nil OptionScore")
+			continue
+		}
+		option, found := nodeGroupIDOptionMap[score.NodeGroupId]
+		if !found || seen[score.NodeGroupId] {
+			klog.Errorf("GRPC server returned invalid or duplicated nodeGroup ID: %s", score.NodeGroupId)
+			continue
+		}
+		if math.IsNaN(score.Score) {
+			klog.Errorf("GRPC server returned invalid score for nodeGroup ID: %s", score.NodeGroupId)
+			continue
+		}
+		seen[score.NodeGroupId] = true
+		klog.V(4).Infof("GRPC expander scored %s %v: %s", score.NodeGroupId, score.Score, score.Explanation)
+
+		option.SimilarNodeGroups = getRetainedSimilarNodegroups(score.SimilarNodeGroupIds, option)
+		option.Explanation = score.Explanation
+		if score.Score > bestScore {
+			bestScore = score.Score
+			best = []expander.Option{option}
+		} else if score.Score == bestScore {
+			best = append(best, option)
+		}
+	}
+	return best
+}
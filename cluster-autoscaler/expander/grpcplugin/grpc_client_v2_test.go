@@ -0,0 +1,245 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcplugin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
+	"k8s.io/autoscaler/cluster-autoscaler/expander"
+	"k8s.io/autoscaler/cluster-autoscaler/expander/grpcplugin/protos"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
+)
+
+// fakeServer implements both protocol versions. The v1 protocol always picks the first option.
+type fakeServer struct {
+	protos.UnimplementedExpanderServer
+	protos.UnimplementedExpanderV2Server
+
+	sync.Mutex
+	healthStatus  protos.ServingStatus
+	v1Calls       int
+	v2Requests    []*protos.ScoreOptionsRequest
+	scores        map[string]float64
+	explanations  map[string]string
+	failWithError error
+}
+
+func (s *fakeServer) BestOptions(_ context.Context, req *protos.BestOptionsRequest) (*protos.BestOptionsResponse, error) {
+	s.Lock()
+	defer s.Unlock()
+	s.v1Calls++
+	return &protos.BestOptionsResponse{Options: req.Options[:1]}, nil
+}
+
+func (s *fakeServer) Health(context.Context, *protos.HealthRequest) (*protos.HealthResponse, error) {
+	s.Lock()
+	defer s.Unlock()
+	return &protos.HealthResponse{Status: s.healthStatus}, nil
+}
+
+func (s *fakeServer) ScoreOptions(_ context.Context, req *protos.ScoreOptionsRequest) (*protos.ScoreOptionsResponse, error) {
+	s.Lock()
+	defer s.Unlock()
+	if s.failWithError != nil {
+		return nil, s.failWithError
+	}
+	s.v2Requests = append(s.v2Requests, req)
+	return s.score(req.Options), nil
+}
+
+func (s *fakeServer) StreamScoreOptions(stream protos.ExpanderV2_StreamScoreOptionsServer) error {
+	var options []*protos.ExpansionOption
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		s.Lock()
+		s.v2Requests = append(s.v2Requests, req)
+		s.Unlock()
+		options = append(options, req.Options...)
+	}
+	s.Lock()
+	defer s.Unlock()
+	return stream.SendAndClose(s.score(options))
+}
+
+func (s *fakeServer) score(options []*protos.ExpansionOption) *protos.ScoreOptionsResponse {
+	response := &protos.ScoreOptionsResponse{}
+	for _, option := range options {
+		if score, found := s.scores[option.NodeGroupId]; found {
+			response.Scores = append(response.Scores, &protos.OptionScore{NodeGroupId: option.NodeGroupId, Score: score, Explanation: s.explanations[option.NodeGroupId], SimilarNodeGroupIds: option.SimilarNodeGroupIds})
+		}
+	}
+	return response
+}
+
+// startFakeServer serves the fake server over an in-memory connection, with the v2 service only when implementsV2 is set.
+func startFakeServer(t *testing.T, server *fakeServer, implementsV2 bool) *grpcclientstrategy {
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer()
+	protos.RegisterExpanderServer(grpcServer, server)
+	if implementsV2 {
+		protos.RegisterExpanderV2Server(grpcServer, server)
+	}
+	go func() { _ = grpcServer.Serve(listener) }()
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return &grpcclientstrategy{grpcClient: protos.NewExpanderClient(conn), grpcClientV2: protos.NewExpanderV2Client(conn)}
+}
+
+func testNodeInfos(options []expander.Option) map[string]*framework.NodeInfo {
+	nodeInfos := make(map[string]*framework.NodeInfo)
+	for i, option := range options {
+		nodeInfos[option.NodeGroup.Id()] = framework.NewTestNodeInfo(nodes[i%len(nodes)])
+	}
+	return nodeInfos
+}
+
+func TestBestOptionsV2(t *testing.T) {
+	server := &fakeServer{
+		healthStatus: protos.ServingStatus_SERVING_STATUS_SERVING,
+		scores:       map[string]float64{eoT2Micro.NodeGroup.Id(): 1, eoT2Large.NodeGroup.Id(): 3, eoT3Large.NodeGroup.Id(): 3},
+		explanations: map[string]string{eoT2Large.NodeGroup.Id(): "cheapest spot capacity"},
+	}
+	g := startFakeServer(t, server, true)
+	g.ObserveClusterContext(&expander.ClusterContext{
+		NodeGroups:    map[string]expander.NodeGroupContext{eoT2Micro.NodeGroup.Id(): {MaxSize: 10, TargetSize: 2, BackedOff: true, BackoffReason: "out of stock"}},
+		ReadyNodes:    4,
+		UpcomingNodes: 1,
+	})
+
+	best := g.BestOptions(options, testNodeInfos(options))
+	expectedBest := eoT2Large
+	expectedBest.Explanation = "cheapest spot capacity"
+	assert.Equal(t, []expander.Option{expectedBest, eoT3Large}, best)
+	assert.Equal(t, protocolV2, g.protocol)
+	assert.Equal(t, 0, server.v1Calls)
+
+	assert.Equal(t, 1, len(server.v2Requests))
+	request := server.v2Requests[0]
+	assert.Equal(t, 4, len(request.Options))
+	assert.Equal(t, 4, len(request.NodeBytesMap))
+	assert.Equal(t, int32(4), request.ClusterContext.ReadyNodes)
+	assert.Equal(t, int32(1), request.ClusterContext.UpcomingNodes)
+	assert.Equal(t, "out of stock", request.ClusterContext.NodeGroups[eoT2Micro.NodeGroup.Id()].BackoffReason)
+
+	// no score, or only invalid ones, filter no options
+	server.scores = nil
+	assert.Equal(t, options, g.BestOptions(options, testNodeInfos(options)))
+	server.scores = map[string]float64{eoT2Micro.NodeGroup.Id(): math.NaN(), "unknown": 1}
+	assert.Equal(t, options, g.BestOptions(options, testNodeInfos(options)))
+}
+
+func TestBestOptionsV2Streaming(t *testing.T) {
+	var manyOptions []expander.Option
+	for i := 0; i < 2*streamChunkSize+1; i++ {
+		manyOptions = append(manyOptions, expander.Option{NodeGroup: test.NewTestNodeGroup(fmt.Sprintf("ng-%d", i), 10, 1, 1, true, false, "t2.micro", nil, nil)})
+	}
+	server := &fakeServer{
+		healthStatus: protos.ServingStatus_SERVING_STATUS_SERVING,
+		scores:       map[string]float64{"ng-0": 1, "ng-150": 2, "ng-200": 0.5},
+	}
+	g := startFakeServer(t, server, true)
+	g.ObserveClusterContext(&expander.ClusterContext{ReadyNodes: 3})
+
+	best := g.BestOptions(manyOptions, testNodeInfos(manyOptions))
+	assert.Equal(t, []expander.Option{manyOptions[150]}, best)
+
+	// options are split in chunks, each with their nodes, and the context is only sent once
+	assert.Equal(t, 3, len(server.v2Requests))
+	for i, request := range server.v2Requests {
+		assert.Equal(t, len(request.Options), len(request.NodeBytesMap))
+		assert.Equal(t, i == 0, request.ClusterContext != nil)
+	}
+	assert.Equal(t, []int{streamChunkSize, streamChunkSize, 1}, []int{len(server.v2Requests[0].Options), len(server.v2Requests[1].Options), len(server.v2Requests[2].Options)})
+}
+
+func TestNegotiateProtocol(t *testing.T) {
+	// plugins without the v2 service use the v1 protocol
+	server := &fakeServer{}
+	g := startFakeServer(t, server, false)
+	assert.Equal(t, []expander.Option{eoT2Micro}, g.BestOptions(options, testNodeInfos(options)))
+	assert.Equal(t, protocolV1, g.protocol)
+	assert.Equal(t, 1, server.v1Calls)
+
+	// so do v2 plugins which aren't serving, until negotiated again
+	server = &fakeServer{healthStatus: protos.ServingStatus_SERVING_STATUS_NOT_SERVING, scores: map[string]float64{eoT3Large.NodeGroup.Id(): 1}}
+	g = startFakeServer(t, server, true)
+	assert.Equal(t, []expander.Option{eoT2Micro}, g.BestOptions(options, testNodeInfos(options)))
+	assert.Equal(t, protocolV1, g.protocol)
+
+	server.healthStatus = protos.ServingStatus_SERVING_STATUS_SERVING
+	assert.Equal(t, []expander.Option{eoT2Micro}, g.BestOptions(options, testNodeInfos(options)))
+	g.negotiatedAt = time.Now().Add(-negotiationInterval)
+	assert.Equal(t, []expander.Option{eoT3Large}, g.BestOptions(options, testNodeInfos(options)))
+	assert.Equal(t, protocolV2, g.protocol)
+}
+
+func TestBestOptionsV2Errors(t *testing.T) {
+	server := &fakeServer{healthStatus: protos.ServingStatus_SERVING_STATUS_SERVING, failWithError: fmt.Errorf("boom")}
+	g := startFakeServer(t, server, true)
+
+	// failed calls filter no options
+	assert.Equal(t, options, g.BestOptions(options, testNodeInfos(options)))
+	assert.Equal(t, protocolV2, g.protocol)
+	assert.Equal(t, 0, server.v1Calls)
+
+	// unimplemented calls fall back to the v1 protocol
+	server.failWithError = protos.UnimplementedExpanderV2Server{}.StreamScoreOptions(nil)
+	assert.Equal(t, []expander.Option{eoT2Micro}, g.BestOptions(options, testNodeInfos(options)))
+	assert.Equal(t, protocolV1, g.protocol)
+	assert.Equal(t, 1, server.v1Calls)
+}
+
+func TestBestScoredOptions(t *testing.T) {
+	nodeGroupIDOptionMap := map[string]expander.Option{
+		eoT2MicroWithSimilar.NodeGroup.Id(): eoT2MicroWithSimilar,
+		eoT3Large.NodeGroup.Id():            eoT3Large,
+	}
+	expected := eoT2MicroWithSimilar
+	expected.Explanation = "best"
+	best := bestScoredOptions([]*protos.OptionScore{
+		nil,
+		{NodeGroupId: "unknown", Score: 10},
+		{NodeGroupId: eoT3Large.NodeGroup.Id(), Score: 1},
+		{NodeGroupId: eoT2Micro.NodeGroup.Id(), Score: 2, Explanation: "best", SimilarNodeGroupIds: []string{expected.SimilarNodeGroups[0].Id(), "extra-ng-id"}},
+		{NodeGroupId: eoT2Micro.NodeGroup.Id(), Score: 5},
+	}, nodeGroupIDOptionMap)
+	assert.Equal(t, []expander.Option{expected}, best)
+}
@@ -0,0 +1,719 @@
+//
+//Copyright 2026 The Kubernetes Authors.
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.33.0
+// source: expander/grpcplugin/protos/expander_v2.proto
+
+package protos
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ServingStatus int32
+
+const (
+	ServingStatus_SERVING_STATUS_UNKNOWN     ServingStatus = 0
+	ServingStatus_SERVING_STATUS_SERVING     ServingStatus = 1
+	ServingStatus_SERVING_STATUS_NOT_SERVING ServingStatus = 2
+)
+
+// Enum value maps for ServingStatus.
+var (
+	ServingStatus_name = map[int32]string{
+		0: "SERVING_STATUS_UNKNOWN",
+		1: "SERVING_STATUS_SERVING",
+		2: "SERVING_STATUS_NOT_SERVING",
+	}
+	ServingStatus_value = map[string]int32{
+		"SERVING_STATUS_UNKNOWN":     0,
+		"SERVING_STATUS_SERVING":     1,
+		"SERVING_STATUS_NOT_SERVING": 2,
+	}
+)
+
+func (x ServingStatus) Enum() *ServingStatus {
+	p := new(ServingStatus)
+	*p = x
+	return p
+}
+
+func (x ServingStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ServingStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_expander_grpcplugin_protos_expander_v2_proto_enumTypes[0].Descriptor()
+}
+
+func (ServingStatus) Type() protoreflect.EnumType {
+	return &file_expander_grpcplugin_protos_expander_v2_proto_enumTypes[0]
+}
+
+func (x ServingStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ServingStatus.Descriptor instead.
+func (ServingStatus) EnumDescriptor() ([]byte, []int) {
+	return file_expander_grpcplugin_protos_expander_v2_proto_rawDescGZIP(), []int{0}
+}
+
+type HealthRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HealthRequest) Reset() {
+	*x = HealthRequest{}
+	mi := &file_expander_grpcplugin_protos_expander_v2_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HealthRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthRequest) ProtoMessage() {}
+
+func (x *HealthRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_expander_grpcplugin_protos_expander_v2_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthRequest.ProtoReflect.Descriptor instead.
+func (*HealthRequest) Descriptor() ([]byte, []int) {
+	return file_expander_grpcplugin_protos_expander_v2_proto_rawDescGZIP(), []int{0}
+}
+
+type HealthResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        ServingStatus          `protobuf:"varint,1,opt,name=status,proto3,enum=grpcplugin.ServingStatus" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HealthResponse) Reset() {
+	*x = HealthResponse{}
+	mi := &file_expander_grpcplugin_protos_expander_v2_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HealthResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthResponse) ProtoMessage() {}
+
+func (x *HealthResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_expander_grpcplugin_protos_expander_v2_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthResponse.ProtoReflect.Descriptor instead.
+func (*HealthResponse) Descriptor() ([]byte, []int) {
+	return file_expander_grpcplugin_protos_expander_v2_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *HealthResponse) GetStatus() ServingStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ServingStatus_SERVING_STATUS_UNKNOWN
+}
+
+type ScoreOptionsRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Options []*ExpansionOption     `protobuf:"bytes,1,rep,name=options,proto3" json:"options,omitempty"`
+	// key is node group id from options.
+	// values are proto-serialized v1.Node objects.
+	NodeBytesMap   map[string][]byte `protobuf:"bytes,2,rep,name=nodeBytesMap,proto3" json:"nodeBytesMap,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	ClusterContext *ClusterContext   `protobuf:"bytes,3,opt,name=clusterContext,proto3" json:"clusterContext,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ScoreOptionsRequest) Reset() {
+	*x = ScoreOptionsRequest{}
+	mi := &file_expander_grpcplugin_protos_expander_v2_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScoreOptionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScoreOptionsRequest) ProtoMessage() {}
+
+func (x *ScoreOptionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_expander_grpcplugin_protos_expander_v2_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScoreOptionsRequest.ProtoReflect.Descriptor instead.
+func (*ScoreOptionsRequest) Descriptor() ([]byte, []int) {
+	return file_expander_grpcplugin_protos_expander_v2_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ScoreOptionsRequest) GetOptions() []*ExpansionOption {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *ScoreOptionsRequest) GetNodeBytesMap() map[string][]byte {
+	if x != nil {
+		return x.NodeBytesMap
+	}
+	return nil
+}
+
+func (x *ScoreOptionsRequest) GetClusterContext() *ClusterContext {
+	if x != nil {
+		return x.ClusterContext
+	}
+	return nil
+}
+
+type ExpansionOption struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	NodeGroupId         string                 `protobuf:"bytes,1,opt,name=nodeGroupId,proto3" json:"nodeGroupId,omitempty"`
+	NodeCount           int32                  `protobuf:"varint,2,opt,name=nodeCount,proto3" json:"nodeCount,omitempty"`
+	Debug               string                 `protobuf:"bytes,3,opt,name=debug,proto3" json:"debug,omitempty"`
+	SimilarNodeGroupIds []string               `protobuf:"bytes,4,rep,name=similarNodeGroupIds,proto3" json:"similarNodeGroupIds,omitempty"`
+	// proto-serialized v1.Pod objects
+	PodBytes      [][]byte `protobuf:"bytes,5,rep,name=podBytes,proto3" json:"podBytes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExpansionOption) Reset() {
+	*x = ExpansionOption{}
+	mi := &file_expander_grpcplugin_protos_expander_v2_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExpansionOption) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExpansionOption) ProtoMessage() {}
+
+func (x *ExpansionOption) ProtoReflect() protoreflect.Message {
+	mi := &file_expander_grpcplugin_protos_expander_v2_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExpansionOption.ProtoReflect.Descriptor instead.
+func (*ExpansionOption) Descriptor() ([]byte, []int) {
+	return file_expander_grpcplugin_protos_expander_v2_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ExpansionOption) GetNodeGroupId() string {
+	if x != nil {
+		return x.NodeGroupId
+	}
+	return ""
+}
+
+func (x *ExpansionOption) GetNodeCount() int32 {
+	if x != nil {
+		return x.NodeCount
+	}
+	return 0
+}
+
+func (x *ExpansionOption) GetDebug() string {
+	if x != nil {
+		return x.Debug
+	}
+	return ""
+}
+
+func (x *ExpansionOption) GetSimilarNodeGroupIds() []string {
+	if x != nil {
+		return x.SimilarNodeGroupIds
+	}
+	return nil
+}
+
+func (x *ExpansionOption) GetPodBytes() [][]byte {
+	if x != nil {
+		return x.PodBytes
+	}
+	return nil
+}
+
+// ClusterContext describes the cluster at the time of the scale-up.
+type ClusterContext struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// key is node group id.
+	NodeGroups    map[string]*NodeGroupContext `protobuf:"bytes,1,rep,name=nodeGroups,proto3" json:"nodeGroups,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	ReadyNodes    int32                        `protobuf:"varint,2,opt,name=readyNodes,proto3" json:"readyNodes,omitempty"`
+	UpcomingNodes int32                        `protobuf:"varint,3,opt,name=upcomingNodes,proto3" json:"upcomingNodes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClusterContext) Reset() {
+	*x = ClusterContext{}
+	mi := &file_expander_grpcplugin_protos_expander_v2_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClusterContext) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClusterContext) ProtoMessage() {}
+
+func (x *ClusterContext) ProtoReflect() protoreflect.Message {
+	mi := &file_expander_grpcplugin_protos_expander_v2_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClusterContext.ProtoReflect.Descriptor instead.
+func (*ClusterContext) Descriptor() ([]byte, []int) {
+	return file_expander_grpcplugin_protos_expander_v2_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ClusterContext) GetNodeGroups() map[string]*NodeGroupContext {
+	if x != nil {
+		return x.NodeGroups
+	}
+	return nil
+}
+
+func (x *ClusterContext) GetReadyNodes() int32 {
+	if x != nil {
+		return x.ReadyNodes
+	}
+	return 0
+}
+
+func (x *ClusterContext) GetUpcomingNodes() int32 {
+	if x != nil {
+		return x.UpcomingNodes
+	}
+	return 0
+}
+
+type NodeGroupContext struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	MinSize      int32                  `protobuf:"varint,1,opt,name=minSize,proto3" json:"minSize,omitempty"`
+	MaxSize      int32                  `protobuf:"varint,2,opt,name=maxSize,proto3" json:"maxSize,omitempty"`
+	TargetSize   int32                  `protobuf:"varint,3,opt,name=targetSize,proto3" json:"targetSize,omitempty"`
+	ReadyNodes   int32                  `protobuf:"varint,4,opt,name=readyNodes,proto3" json:"readyNodes,omitempty"`
+	UnreadyNodes int32                  `protobuf:"varint,5,opt,name=unreadyNodes,proto3" json:"unreadyNodes,omitempty"`
+	// nodes requested from the cloud provider, which haven't registered yet.
+	UpcomingNodes int32 `protobuf:"varint,6,opt,name=upcomingNodes,proto3" json:"upcomingNodes,omitempty"`
+	BackedOff     bool  `protobuf:"varint,7,opt,name=backedOff,proto3" json:"backedOff,omitempty"`
+	// error which caused the backoff, if any.
+	BackoffReason string `protobuf:"bytes,8,opt,name=backoffReason,proto3" json:"backoffReason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeGroupContext) Reset() {
+	*x = NodeGroupContext{}
+	mi := &file_expander_grpcplugin_protos_expander_v2_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeGroupContext) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeGroupContext) ProtoMessage() {}
+
+func (x *NodeGroupContext) ProtoReflect() protoreflect.Message {
+	mi := &file_expander_grpcplugin_protos_expander_v2_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeGroupContext.ProtoReflect.Descriptor instead.
+func (*NodeGroupContext) Descriptor() ([]byte, []int) {
+	return file_expander_grpcplugin_protos_expander_v2_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *NodeGroupContext) GetMinSize() int32 {
+	if x != nil {
+		return x.MinSize
+	}
+	return 0
+}
+
+func (x *NodeGroupContext) GetMaxSize() int32 {
+	if x != nil {
+		return x.MaxSize
+	}
+	return 0
+}
+
+func (x *NodeGroupContext) GetTargetSize() int32 {
+	if x != nil {
+		return x.TargetSize
+	}
+	return 0
+}
+
+func (x *NodeGroupContext) GetReadyNodes() int32 {
+	if x != nil {
+		return x.ReadyNodes
+	}
+	return 0
+}
+
+func (x *NodeGroupContext) GetUnreadyNodes() int32 {
+	if x != nil {
+		return x.UnreadyNodes
+	}
+	return 0
+}
+
+func (x *NodeGroupContext) GetUpcomingNodes() int32 {
+	if x != nil {
+		return x.UpcomingNodes
+	}
+	return 0
+}
+
+func (x *NodeGroupContext) GetBackedOff() bool {
+	if x != nil {
+		return x.BackedOff
+	}
+	return false
+}
+
+func (x *NodeGroupContext) GetBackoffReason() string {
+	if x != nil {
+		return x.BackoffReason
+	}
+	return ""
+}
+
+type ScoreOptionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Scores        []*OptionScore         `protobuf:"bytes,1,rep,name=scores,proto3" json:"scores,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScoreOptionsResponse) Reset() {
+	*x = ScoreOptionsResponse{}
+	mi := &file_expander_grpcplugin_protos_expander_v2_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScoreOptionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScoreOptionsResponse) ProtoMessage() {}
+
+func (x *ScoreOptionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_expander_grpcplugin_protos_expander_v2_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScoreOptionsResponse.ProtoReflect.Descriptor instead.
+func (*ScoreOptionsResponse) Descriptor() ([]byte, []int) {
+	return file_expander_grpcplugin_protos_expander_v2_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ScoreOptionsResponse) GetScores() []*OptionScore {
+	if x != nil {
+		return x.Scores
+	}
+	return nil
+}
+
+type OptionScore struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	NodeGroupId string                 `protobuf:"bytes,1,opt,name=nodeGroupId,proto3" json:"nodeGroupId,omitempty"`
+	// higher is better. Options with the highest score are kept.
+	Score float64 `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"`
+	// human-readable reason of the score, reported in scale-up events.
+	Explanation         string   `protobuf:"bytes,3,opt,name=explanation,proto3" json:"explanation,omitempty"`
+	SimilarNodeGroupIds []string `protobuf:"bytes,4,rep,name=similarNodeGroupIds,proto3" json:"similarNodeGroupIds,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *OptionScore) Reset() {
+	*x = OptionScore{}
+	mi := &file_expander_grpcplugin_protos_expander_v2_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OptionScore) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OptionScore) ProtoMessage() {}
+
+func (x *OptionScore) ProtoReflect() protoreflect.Message {
+	mi := &file_expander_grpcplugin_protos_expander_v2_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OptionScore.ProtoReflect.Descriptor instead.
+func (*OptionScore) Descriptor() ([]byte, []int) {
+	return file_expander_grpcplugin_protos_expander_v2_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *OptionScore) GetNodeGroupId() string {
+	if x != nil {
+		return x.NodeGroupId
+	}
+	return ""
+}
+
+func (x *OptionScore) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *OptionScore) GetExplanation() string {
+	if x != nil {
+		return x.Explanation
+	}
+	return ""
+}
+
+func (x *OptionScore) GetSimilarNodeGroupIds() []string {
+	if x != nil {
+		return x.SimilarNodeGroupIds
+	}
+	return nil
+}
+
+var File_expander_grpcplugin_protos_expander_v2_proto protoreflect.FileDescriptor
+
+const file_expander_grpcplugin_protos_expander_v2_proto_rawDesc = "" +
+	"\n" +
+	",expander/grpcplugin/protos/expander_v2.proto\x12\n" +
+	"grpcplugin\"\x0f\n" +
+	"\rHealthRequest\"C\n" +
+	"\x0eHealthResponse\x121\n" +
+	"\x06status\x18\x01 \x01(\x0e2\x19.grpcplugin.ServingStatusR\x06status\"\xa8\x02\n" +
+	"\x13ScoreOptionsRequest\x125\n" +
+	"\aoptions\x18\x01 \x03(\v2\x1b.grpcplugin.ExpansionOptionR\aoptions\x12U\n" +
+	"\fnodeBytesMap\x18\x02 \x03(\v21.grpcplugin.ScoreOptionsRequest.NodeBytesMapEntryR\fnodeBytesMap\x12B\n" +
+	"\x0eclusterContext\x18\x03 \x01(\v2\x1a.grpcplugin.ClusterContextR\x0eclusterContext\x1a?\n" +
+	"\x11NodeBytesMapEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\fR\x05value:\x028\x01\"\xb5\x01\n" +
+	"\x0fExpansionOption\x12 \n" +
+	"\vnodeGroupId\x18\x01 \x01(\tR\vnodeGroupId\x12\x1c\n" +
+	"\tnodeCount\x18\x02 \x01(\x05R\tnodeCount\x12\x14\n" +
+	"\x05debug\x18\x03 \x01(\tR\x05debug\x120\n" +
+	"\x13similarNodeGroupIds\x18\x04 \x03(\tR\x13similarNodeGroupIds\x12\x1a\n" +
+	"\bpodBytes\x18\x05 \x03(\fR\bpodBytes\"\xff\x01\n" +
+	"\x0eClusterContext\x12J\n" +
+	"\n" +
+	"nodeGroups\x18\x01 \x03(\v2*.grpcplugin.ClusterContext.NodeGroupsEntryR\n" +
+	"nodeGroups\x12\x1e\n" +
+	"\n" +
+	"readyNodes\x18\x02 \x01(\x05R\n" +
+	"readyNodes\x12$\n" +
+	"\rupcomingNodes\x18\x03 \x01(\x05R\rupcomingNodes\x1a[\n" +
+	"\x0fNodeGroupsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x122\n" +
+	"\x05value\x18\x02 \x01(\v2\x1c.grpcplugin.NodeGroupContextR\x05value:\x028\x01\"\x94\x02\n" +
+	"\x10NodeGroupContext\x12\x18\n" +
+	"\aminSize\x18\x01 \x01(\x05R\aminSize\x12\x18\n" +
+	"\amaxSize\x18\x02 \x01(\x05R\amaxSize\x12\x1e\n" +
+	"\n" +
+	"targetSize\x18\x03 \x01(\x05R\n" +
+	"targetSize\x12\x1e\n" +
+	"\n" +
+	"readyNodes\x18\x04 \x01(\x05R\n" +
+	"readyNodes\x12\"\n" +
+	"\funreadyNodes\x18\x05 \x01(\x05R\funreadyNodes\x12$\n" +
+	"\rupcomingNodes\x18\x06 \x01(\x05R\rupcomingNodes\x12\x1c\n" +
+	"\tbackedOff\x18\a \x01(\bR\tbackedOff\x12$\n" +
+	"\rbackoffReason\x18\b \x01(\tR\rbackoffReason\"G\n" +
+	"\x14ScoreOptionsResponse\x12/\n" +
+	"\x06scores\x18\x01 \x03(\v2\x17.grpcplugin.OptionScoreR\x06scores\"\x99\x01\n" +
+	"\vOptionScore\x12 \n" +
+	"\vnodeGroupId\x18\x01 \x01(\tR\vnodeGroupId\x12\x14\n" +
+	"\x05score\x18\x02 \x01(\x01R\x05score\x12 \n" +
+	"\vexplanation\x18\x03 \x01(\tR\vexplanation\x120\n" +
+	"\x13similarNodeGroupIds\x18\x04 \x03(\tR\x13similarNodeGroupIds*g\n" +
+	"\rServingStatus\x12\x1a\n" +
+	"\x16SERVING_STATUS_UNKNOWN\x10\x00\x12\x1a\n" +
+	"\x16SERVING_STATUS_SERVING\x10\x01\x12\x1e\n" +
+	"\x1aSERVING_STATUS_NOT_SERVING\x10\x022\x81\x02\n" +
+	"\n" +
+	"ExpanderV2\x12A\n" +
+	"\x06Health\x12\x19.grpcplugin.HealthRequest\x1a\x1a.grpcplugin.HealthResponse\"\x00\x12S\n" +
+	"\fScoreOptions\x12\x1f.grpcplugin.ScoreOptionsRequest\x1a .grpcplugin.ScoreOptionsResponse\"\x00\x12[\n" +
+	"\x12StreamScoreOptions\x12\x1f.grpcplugin.ScoreOptionsRequest\x1a .grpcplugin.ScoreOptionsResponse\"\x00(\x01B\x1cZ\x1aexpander/grpcplugin/protosb\x06proto3"
+
+var (
+	file_expander_grpcplugin_protos_expander_v2_proto_rawDescOnce sync.Once
+	file_expander_grpcplugin_protos_expander_v2_proto_rawDescData []byte
+)
+
+func file_expander_grpcplugin_protos_expander_v2_proto_rawDescGZIP() []byte {
+	file_expander_grpcplugin_protos_expander_v2_proto_rawDescOnce.Do(func() {
+		file_expander_grpcplugin_protos_expander_v2_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_expander_grpcplugin_protos_expander_v2_proto_rawDesc), len(file_expander_grpcplugin_protos_expander_v2_proto_rawDesc)))
+	})
+	return file_expander_grpcplugin_protos_expander_v2_proto_rawDescData
+}
+
+var file_expander_grpcplugin_protos_expander_v2_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_expander_grpcplugin_protos_expander_v2_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_expander_grpcplugin_protos_expander_v2_proto_goTypes = []any{
+	(ServingStatus)(0),           // 0: grpcplugin.ServingStatus
+	(*HealthRequest)(nil),        // 1: grpcplugin.HealthRequest
+	(*HealthResponse)(nil),       // 2: grpcplugin.HealthResponse
+	(*ScoreOptionsRequest)(nil),  // 3: grpcplugin.ScoreOptionsRequest
+	(*ExpansionOption)(nil),      // 4: grpcplugin.ExpansionOption
+	(*ClusterContext)(nil),       // 5: grpcplugin.ClusterContext
+	(*NodeGroupContext)(nil),     // 6: grpcplugin.NodeGroupContext
+	(*ScoreOptionsResponse)(nil), // 7: grpcplugin.ScoreOptionsResponse
+	(*OptionScore)(nil),          // 8: grpcplugin.OptionScore
+	nil,                          // 9: grpcplugin.ScoreOptionsRequest.NodeBytesMapEntry
+	nil,                          // 10: grpcplugin.ClusterContext.NodeGroupsEntry
+}
+var file_expander_grpcplugin_protos_expander_v2_proto_depIdxs = []int32{
+	0,  // 0: grpcplugin.HealthResponse.status:type_name -> grpcplugin.ServingStatus
+	4,  // 1: grpcplugin.ScoreOptionsRequest.options:type_name -> grpcplugin.ExpansionOption
+	9,  // 2: grpcplugin.ScoreOptionsRequest.nodeBytesMap:type_name -> grpcplugin.ScoreOptionsRequest.NodeBytesMapEntry
+	5,  // 3: grpcplugin.ScoreOptionsRequest.clusterContext:type_name -> grpcplugin.ClusterContext
+	10, // 4: grpcplugin.ClusterContext.nodeGroups:type_name -> grpcplugin.ClusterContext.NodeGroupsEntry
+	8,  // 5: grpcplugin.ScoreOptionsResponse.scores:type_name -> grpcplugin.OptionScore
+	6,  // 6: grpcplugin.ClusterContext.NodeGroupsEntry.value:type_name -> grpcplugin.NodeGroupContext
+	1,  // 7: grpcplugin.ExpanderV2.Health:input_type -> grpcplugin.HealthRequest
+	3,  // 8: grpcplugin.ExpanderV2.ScoreOptions:input_type -> grpcplugin.ScoreOptionsRequest
+	3,  // 9: grpcplugin.ExpanderV2.StreamScoreOptions:input_type -> grpcplugin.ScoreOptionsRequest
+	2,  // 10: grpcplugin.ExpanderV2.Health:output_type -> grpcplugin.HealthResponse
+	7,  // 11: grpcplugin.ExpanderV2.ScoreOptions:output_type -> grpcplugin.ScoreOptionsResponse
+	7,  // 12: grpcplugin.ExpanderV2.StreamScoreOptions:output_type -> grpcplugin.ScoreOptionsResponse
+	10, // [10:13] is the sub-list for method output_type
+	7,  // [7:10] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_expander_grpcplugin_protos_expander_v2_proto_init() }
+func file_expander_grpcplugin_protos_expander_v2_proto_init() {
+	if File_expander_grpcplugin_protos_expander_v2_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_expander_grpcplugin_protos_expander_v2_proto_rawDesc), len(file_expander_grpcplugin_protos_expander_v2_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_expander_grpcplugin_protos_expander_v2_proto_goTypes,
+		DependencyIndexes: file_expander_grpcplugin_protos_expander_v2_proto_depIdxs,
+		EnumInfos:         file_expander_grpcplugin_protos_expander_v2_proto_enumTypes,
+		MessageInfos:      file_expander_grpcplugin_protos_expander_v2_proto_msgTypes,
+	}.Build()
+	File_expander_grpcplugin_protos_expander_v2_proto = out.File
+	file_expander_grpcplugin_protos_expander_v2_proto_goTypes = nil
+	file_expander_grpcplugin_protos_expander_v2_proto_depIdxs = nil
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spotfallback
+
+import (
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/metrics"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/backoff"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// DefaultHalfLife is the time after which half of the weight of an out-of-resources error is forgotten.
+	DefaultHalfLife = 30 * time.Minute
+
+	// forgottenWeight is the weight under which errors are forgotten altogether.
+	forgottenWeight = 0.01
+)
+
+// spotLabels are the labels marking spot (or preemptible) nodes, with their value on such nodes.
+var spotLabels = map[string]string{
+	"karpenter.sh/capacity-type":            "spot",
+	"eks.amazonaws.com/capacityType":        "SPOT",
+	"cloud.google.com/gke-spot":             "true",
+	"cloud.google.com/gke-preemptible":      "true",
+	"kubernetes.azure.com/scalesetpriority": "spot",
+}
+
+// availabilityKey is an instance family in a zone: spot capacity runs out for an instance family in a
+// zone, the same family may still be available in other zones, and other families in the same zone.
+type availabilityKey struct {
+	family string
+	zone   string
+}
+
+// decayingWeight is the sum of the errors registered for a key, each error weighting 1 when it happens
+// and half of it every half-life.
+type decayingWeight struct {
+	weight    float64
+	updatedAt time.Time
+}
+
+// AvailabilityTracker learns the availability of spot capacity from out-of-resources errors, per instance
+// family and zone, so that what's learned from a node group applies to all the similar ones.
+type AvailabilityTracker struct {
+	sync.Mutex
+	halfLife time.Duration
+	weights  map[availabilityKey]*decayingWeight
+}
+
+// NewAvailabilityTracker returns an AvailabilityTracker forgetting errors with the given half-life.
+func NewAvailabilityTracker(halfLife time.Duration) *AvailabilityTracker {
+	return &AvailabilityTracker{
+		halfLife: halfLife,
+		weights:  make(map[availabilityKey]*decayingWeight),
+	}
+}
+
+// RecordError registers an error of a node group, described by its template node. Only out-of-resources
+// errors of spot node groups are taken into account.
+func (t *AvailabilityTracker) RecordError(nodeInfo *framework.NodeInfo, errorInfo cloudprovider.InstanceErrorInfo, currentTime time.Time) {
+	if errorInfo.ErrorClass != cloudprovider.OutOfResourcesErrorClass || nodeInfo == nil || !IsSpot(nodeInfo.Node()) {
+		return
+	}
+	key, ok := newAvailabilityKey(nodeInfo.Node())
+	if !ok {
+		return
+	}
+	t.Lock()
+	defer t.Unlock()
+	w, found := t.weights[key]
+	if !found {
+		w = &decayingWeight{updatedAt: currentTime}
+		t.weights[key] = w
+	}
+	t.decay(w, currentTime)
+	w.weight++
+	klog.V(4).Infof("Spot capacity of instance family %q in zone %q ran out (%s), availability is now %.2f", key.family, key.zone, errorInfo.ErrorCode, availability(w.weight))
+	metrics.UpdateSpotFallbackAvailability(key.family, key.zone, availability(w.weight))
+}
+
+// Availability returns the availability of spot capacity for a node, between 0 and 1: the one of its
+// instance family in its zone. Instance families without errors in a zone have an availability of 1.
+func (t *AvailabilityTracker) Availability(node *apiv1.Node, currentTime time.Time) float64 {
+	key, ok := newAvailabilityKey(node)
+	if !ok {
+		return 1
+	}
+	t.Lock()
+	defer t.Unlock()
+	w, found := t.weights[key]
+	if !found {
+		return 1
+	}
+	t.decay(w, currentTime)
+	return availability(w.weight)
+}
+
+// UpdateMetrics decays the learned availabilities and exports them, forgetting the recovered ones.
+func (t *AvailabilityTracker) UpdateMetrics(currentTime time.Time) {
+	t.Lock()
+	defer t.Unlock()
+	for key, w := range t.weights {
+		t.decay(w, currentTime)
+		if w.weight < forgottenWeight {
+			delete(t.weights, key)
+			metrics.DeleteSpotFallbackAvailability(key.family, key.zone)
+			continue
+		}
+		metrics.UpdateSpotFallbackAvailability(key.family, key.zone, availability(w.weight))
+	}
+}
+
+// To be executed under a lock.
+func (t *AvailabilityTracker) decay(w *decayingWeight, currentTime time.Time) {
+	if elapsed := currentTime.Sub(w.updatedAt); elapsed > 0 {
+		w.weight *= math.Exp2(-elapsed.Seconds() / t.halfLife.Seconds())
+		w.updatedAt = currentTime
+	}
+}
+
+// availability maps the weight of the errors to (0, 1]: a single recent error makes it about 0.37.
+func availability(weight float64) float64 {
+	return math.Exp(-weight)
+}
+
+// newAvailabilityKey returns the instance family and zone of a node, if it has either.
+func newAvailabilityKey(node *apiv1.Node) (availabilityKey, bool) {
+	key := availabilityKey{
+		family: InstanceFamily(node.Labels[apiv1.LabelInstanceTypeStable]),
+		zone:   node.Labels[apiv1.LabelTopologyZone],
+	}
+	return key, key.family != "" || key.zone != ""
+}
+
+// InstanceFamily returns the family of an instance type: the part before the first "." (m5.large -> m5)
+// or, failing that, before the first "-" (n2-standard-4 -> n2). Other instance types are their own family.
+func InstanceFamily(instanceType string) string {
+	if family, _, found := strings.Cut(instanceType, "."); found {
+		return family
+	}
+	family, _, _ := strings.Cut(instanceType, "-")
+	return family
+}
+
+// IsSpot returns whether a node is a spot (or preemptible) node, according to its labels.
+func IsSpot(node *apiv1.Node) bool {
+	for label, value := range spotLabels {
+		if node.Labels[label] == value {
+			return true
+		}
+	}
+	return false
+}
+
+// learningBackoff is a backoff.Backoff recording the errors it backs off node groups for in an AvailabilityTracker.
+type learningBackoff struct {
+	delegate backoff.Backoff
+	tracker  *AvailabilityTracker
+}
+
+// NewLearningBackoff returns a backoff.Backoff delegating to the given one, and recording the errors of the
+// backed off node groups in the tracker.
+func NewLearningBackoff(delegate backoff.Backoff, tracker *AvailabilityTracker) backoff.Backoff {
+	return &learningBackoff{delegate: delegate, tracker: tracker}
+}
+
+// Backoff records the error and backs off the node group.
+func (b *learningBackoff) Backoff(nodeGroup cloudprovider.NodeGroup, nodeInfo *framework.NodeInfo, errorInfo cloudprovider.InstanceErrorInfo, currentTime time.Time) time.Time {
+	b.tracker.RecordError(nodeInfo, errorInfo, currentTime)
+	return b.delegate.Backoff(nodeGroup, nodeInfo, errorInfo, currentTime)
+}
+
+// BackoffStatus returns the backoff status of the delegate.
+func (b *learningBackoff) BackoffStatus(nodeGroup cloudprovider.NodeGroup, nodeInfo *framework.NodeInfo, currentTime time.Time) backoff.Status {
+	return b.delegate.BackoffStatus(nodeGroup, nodeInfo, currentTime)
+}
+
+// RemoveBackoff removes the backoff of the delegate. The learned availability is kept, it decays on its own.
+func (b *learningBackoff) RemoveBackoff(nodeGroup cloudprovider.NodeGroup, nodeInfo *framework.NodeInfo) {
+	b.delegate.RemoveBackoff(nodeGroup, nodeInfo)
+}
+
+// RemoveStaleBackoffData removes stale backoff data of the delegate.
+func (b *learningBackoff) RemoveStaleBackoffData(currentTime time.Time) {
+	b.delegate.RemoveStaleBackoffData(currentTime)
+}
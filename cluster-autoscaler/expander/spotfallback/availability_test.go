@@ -0,0 +1,141 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spotfallback
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
+
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	testprovider "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/backoff"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+)
+
+var (
+	outOfResources = cloudprovider.InstanceErrorInfo{ErrorClass: cloudprovider.OutOfResourcesErrorClass, ErrorCode: "STOCKOUT"}
+	otherError     = cloudprovider.InstanceErrorInfo{ErrorClass: cloudprovider.OtherErrorClass, ErrorCode: "timeout"}
+)
+
+func buildTemplateNode(name, instanceType, zone string, spot bool) *apiv1.Node {
+	node := BuildTestNode(name, 1000, 1000)
+	node.Labels = map[string]string{apiv1.LabelInstanceTypeStable: instanceType, apiv1.LabelTopologyZone: zone}
+	if spot {
+		node.Labels["karpenter.sh/capacity-type"] = "spot"
+	}
+	return node
+}
+
+func TestAvailability(t *testing.T) {
+	now := time.Now()
+	for desc, test := range map[string]struct {
+		node         *apiv1.Node
+		at           time.Time
+		availability float64
+	}{
+		"same instance type": {
+			node:         buildTemplateNode("n2", "m5.large", "zone-a", true),
+			at:           now,
+			availability: 0.37,
+		},
+		"same instance family": {
+			node:         buildTemplateNode("n3", "m5.xlarge", "zone-a", true),
+			at:           now,
+			availability: 0.37,
+		},
+		"same zone, other instance family": {
+			node:         buildTemplateNode("n4", "c5.large", "zone-a", true),
+			at:           now,
+			availability: 1,
+		},
+		"same instance family, other zone": {
+			node:         buildTemplateNode("n7", "m5.large", "zone-b", true),
+			at:           now,
+			availability: 1,
+		},
+		"other family and zone": {
+			node:         buildTemplateNode("n5", "c5.large", "zone-b", true),
+			at:           now,
+			availability: 1,
+		},
+		"half decayed": {
+			node:         buildTemplateNode("n6", "m5.large", "zone-a", true),
+			at:           now.Add(time.Hour),
+			availability: 0.61,
+		},
+	} {
+		t.Run(desc, func(t *testing.T) {
+			tracker := NewAvailabilityTracker(time.Hour)
+			tracker.RecordError(framework.NewTestNodeInfo(buildTemplateNode("n1", "m5.large", "zone-a", true)), outOfResources, now)
+			assert.InDelta(t, test.availability, tracker.Availability(test.node, test.at), 0.01)
+		})
+	}
+}
+
+func TestRecordError(t *testing.T) {
+	now := time.Now()
+	onDemand := framework.NewTestNodeInfo(buildTemplateNode("n1", "m5.large", "zone-a", false))
+	spot := framework.NewTestNodeInfo(buildTemplateNode("n2", "m5.large", "zone-a", true))
+	tracker := NewAvailabilityTracker(time.Hour)
+
+	// only out-of-resources errors of spot node groups are recorded
+	tracker.RecordError(onDemand, outOfResources, now)
+	tracker.RecordError(spot, otherError, now)
+	tracker.RecordError(nil, outOfResources, now)
+	assert.Equal(t, 1.0, tracker.Availability(spot.Node(), now))
+
+	// errors add up
+	tracker.RecordError(spot, outOfResources, now)
+	tracker.RecordError(spot, outOfResources, now)
+	assert.InDelta(t, 0.14, tracker.Availability(spot.Node(), now), 0.01)
+
+	// and are eventually forgotten
+	tracker.UpdateMetrics(now.Add(10 * time.Hour))
+	assert.Empty(t, tracker.weights)
+}
+
+func TestInstanceFamily(t *testing.T) {
+	for instanceType, family := range map[string]string{
+		"m5.large":        "m5",
+		"n2-standard-4":   "n2",
+		"Standard_D4s_v3": "Standard_D4s_v3",
+		"":                "",
+	} {
+		assert.Equal(t, family, InstanceFamily(instanceType), instanceType)
+	}
+}
+
+func TestLearningBackoff(t *testing.T) {
+	now := time.Now()
+	tracker := NewAvailabilityTracker(time.Hour)
+	b := NewLearningBackoff(backoff.NewIdBasedExponentialBackoff(time.Minute, time.Hour, 3*time.Hour), tracker)
+	nodeGroup := testprovider.NewTestNodeGroup("ng", 10, 0, 0, true, false, "m5.large", nil, nil)
+	nodeInfo := framework.NewTestNodeInfo(buildTemplateNode("n1", "m5.large", "zone-a", true))
+
+	b.Backoff(nodeGroup, nodeInfo, outOfResources, now)
+	assert.True(t, b.BackoffStatus(nodeGroup, nodeInfo, now).IsBackedOff)
+	assert.InDelta(t, 0.37, tracker.Availability(nodeInfo.Node(), now), 0.01)
+
+	// removing the backoff doesn't forget the error
+	b.RemoveBackoff(nodeGroup, nodeInfo)
+	assert.False(t, b.BackoffStatus(nodeGroup, nodeInfo, now).IsBackedOff)
+	assert.InDelta(t, 0.37, tracker.Availability(nodeInfo.Node(), now), 0.01)
+}
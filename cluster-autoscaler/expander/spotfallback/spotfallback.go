@@ -0,0 +1,75 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spotfallback
+
+import (
+	"time"
+
+	"k8s.io/autoscaler/cluster-autoscaler/expander"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// MinAvailability is the availability under which spot options are deprioritized. A single
+	// out-of-resources error keeps the options under it for about half of the half-life.
+	MinAvailability = 0.5
+)
+
+type spotfallback struct {
+	tracker *AvailabilityTracker
+	now     func() time.Time
+}
+
+// NewFilter returns a filter deprioritizing the spot options whose instance family or zone recently ran out
+// of capacity, according to the tracker, in favor of the other options (e.g. on-demand ones).
+func NewFilter(tracker *AvailabilityTracker) expander.Filter {
+	return &spotfallback{tracker: tracker, now: time.Now}
+}
+
+// BestOptions keeps the on-demand options and the spot options whose capacity is available. If all the options
+// are spot ones which recently ran out of capacity, the most available ones are kept.
+func (s *spotfallback) BestOptions(expansionOptions []expander.Option, nodeInfo map[string]*framework.NodeInfo) []expander.Option {
+	now := s.now()
+	s.tracker.UpdateMetrics(now)
+
+	var available, mostAvailable []expander.Option
+	maxAvailability := -1.0
+	for _, option := range expansionOptions {
+		info, found := nodeInfo[option.NodeGroup.Id()]
+		if !found || !IsSpot(info.Node()) {
+			available = append(available, option)
+			continue
+		}
+		availability := s.tracker.Availability(info.Node(), now)
+		if availability >= MinAvailability {
+			available = append(available, option)
+			continue
+		}
+		klog.V(2).Infof("Spot capacity of node group %s recently ran out, availability %.2f, deprioritizing it", option.NodeGroup.Id(), availability)
+		if availability > maxAvailability {
+			maxAvailability = availability
+			mostAvailable = []expander.Option{option}
+		} else if availability == maxAvailability {
+			mostAvailable = append(mostAvailable, option)
+		}
+	}
+	if len(available) == 0 {
+		return mostAvailable
+	}
+	return available
+}
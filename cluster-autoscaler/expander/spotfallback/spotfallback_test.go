@@ -0,0 +1,112 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spotfallback
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	testprovider "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
+	"k8s.io/autoscaler/cluster-autoscaler/expander"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
+)
+
+func TestBestOptions(t *testing.T) {
+	now := time.Now()
+	nodeInfos := map[string]*framework.NodeInfo{
+		"spot-m5-a":      framework.NewTestNodeInfo(buildTemplateNode("n1", "m5.large", "zone-a", true)),
+		"spot-m5-b":      framework.NewTestNodeInfo(buildTemplateNode("n2", "m5.xlarge", "zone-b", true)),
+		"spot-c5-b":      framework.NewTestNodeInfo(buildTemplateNode("n3", "c5.large", "zone-b", true)),
+		"on-demand-m5-a": framework.NewTestNodeInfo(buildTemplateNode("n4", "m5.large", "zone-a", false)),
+		"spot-m5xl-a":    framework.NewTestNodeInfo(buildTemplateNode("n5", "m5.xlarge", "zone-a", true)),
+		"spot-c5-a":      framework.NewTestNodeInfo(buildTemplateNode("n6", "c5.large", "zone-a", true)),
+	}
+	option := func(id string) expander.Option {
+		return expander.Option{NodeGroup: testprovider.NewTestNodeGroup(id, 10, 0, 0, true, false, "", nil, nil), NodeCount: 1}
+	}
+
+	for desc, test := range map[string]struct {
+		errors  map[string]int
+		options []string
+		want    []string
+	}{
+		"no errors": {
+			options: []string{"spot-m5-a", "spot-c5-b", "on-demand-m5-a"},
+			want:    []string{"spot-m5-a", "spot-c5-b", "on-demand-m5-a"},
+		},
+		"spot instance family out of capacity in a zone": {
+			errors:  map[string]int{"spot-m5-a": 1},
+			options: []string{"spot-m5xl-a", "spot-m5-b", "spot-c5-a", "on-demand-m5-a"},
+			want:    []string{"spot-m5-b", "spot-c5-a", "on-demand-m5-a"},
+		},
+		"fall back to on-demand": {
+			errors:  map[string]int{"spot-m5-a": 1},
+			options: []string{"spot-m5-a", "spot-m5xl-a", "on-demand-m5-a"},
+			want:    []string{"on-demand-m5-a"},
+		},
+		"most available spot when there's nothing else": {
+			errors:  map[string]int{"spot-m5-a": 2, "spot-c5-b": 1},
+			options: []string{"spot-m5-a", "spot-c5-b"},
+			want:    []string{"spot-c5-b"},
+		},
+		"options without template node are kept": {
+			errors:  map[string]int{"spot-m5-a": 1},
+			options: []string{"spot-m5-a", "unknown"},
+			want:    []string{"unknown"},
+		},
+	} {
+		t.Run(desc, func(t *testing.T) {
+			tracker := NewAvailabilityTracker(DefaultHalfLife)
+			for id, count := range test.errors {
+				for i := 0; i < count; i++ {
+					tracker.RecordError(nodeInfos[id], outOfResources, now)
+				}
+			}
+			s := &spotfallback{tracker: tracker, now: func() time.Time { return now }}
+			var options []expander.Option
+			for _, id := range test.options {
+				options = append(options, option(id))
+			}
+			var got []string
+			for _, best := range s.BestOptions(options, nodeInfos) {
+				got = append(got, best.NodeGroup.Id())
+			}
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestBestOptionsRecovers(t *testing.T) {
+	now := time.Now()
+	spot := framework.NewTestNodeInfo(buildTemplateNode("n1", "m5.large", "zone-a", true))
+	onDemand := framework.NewTestNodeInfo(buildTemplateNode("n2", "m5.large", "zone-a", false))
+	nodeInfos := map[string]*framework.NodeInfo{"spot": spot, "on-demand": onDemand}
+	options := []expander.Option{
+		{NodeGroup: testprovider.NewTestNodeGroup("spot", 10, 0, 0, true, false, "", nil, nil)},
+		{NodeGroup: testprovider.NewTestNodeGroup("on-demand", 10, 0, 0, true, false, "", nil, nil)},
+	}
+	tracker := NewAvailabilityTracker(DefaultHalfLife)
+	tracker.RecordError(spot, outOfResources, now)
+
+	currentTime := now
+	s := &spotfallback{tracker: tracker, now: func() time.Time { return currentTime }}
+	assert.Equal(t, options[1:], s.BestOptions(options, nodeInfos))
+	currentTime = now.Add(DefaultHalfLife)
+	assert.Equal(t, options, s.BestOptions(options, nodeInfos))
+}
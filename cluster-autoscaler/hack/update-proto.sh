@@ -78,7 +78,8 @@ PATH="${protoc_bin_dir}" "${protoc_bin_dir}/protoc" \
   -I "${protoc_include_dir}" \
   --go_out=. \
   --go-grpc_out=. \
-  ./expander/grpcplugin/protos/expander.proto
+  ./expander/grpcplugin/protos/expander.proto \
+  ./expander/grpcplugin/protos/expander_v2.proto
 
 popd >/dev/null
 
@@ -32,6 +32,7 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/config/flags"
 	"k8s.io/autoscaler/cluster-autoscaler/core/scaleup/orchestrator"
 	"k8s.io/autoscaler/cluster-autoscaler/debuggingsnapshot"
+	"k8s.io/autoscaler/cluster-autoscaler/debuggingsnapshot/journal"
 	"k8s.io/autoscaler/cluster-autoscaler/loop"
 	"k8s.io/autoscaler/cluster-autoscaler/provisioningrequest/besteffortatomic"
 	"k8s.io/autoscaler/cluster-autoscaler/provisioningrequest/checkcapacity"
@@ -55,12 +56,15 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/observers/loopstart"
 	ca_processors "k8s.io/autoscaler/cluster-autoscaler/processors"
 	cbprocessor "k8s.io/autoscaler/cluster-autoscaler/processors/capacitybuffer"
+	ddcommon "k8s.io/autoscaler/cluster-autoscaler/processors/datadog/common"
 	ddnodeinfosprovider "k8s.io/autoscaler/cluster-autoscaler/processors/datadog/nodeinfosprovider"
 	ddpods "k8s.io/autoscaler/cluster-autoscaler/processors/datadog/pods"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/nodegroupconfig"
 	"k8s.io/autoscaler/cluster-autoscaler/processors/nodegroupset"
 	"k8s.io/autoscaler/cluster-autoscaler/processors/podinjection"
 	podinjectionbackoff "k8s.io/autoscaler/cluster-autoscaler/processors/podinjection/backoff"
 	"k8s.io/autoscaler/cluster-autoscaler/processors/pods"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/prescaling"
 	"k8s.io/autoscaler/cluster-autoscaler/processors/provreq"
 	"k8s.io/autoscaler/cluster-autoscaler/processors/scaledowncandidates"
 	"k8s.io/autoscaler/cluster-autoscaler/processors/scaledowncandidates/emptycandidates"
@@ -68,6 +72,7 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/processors/status"
 	provreqorchestrator "k8s.io/autoscaler/cluster-autoscaler/provisioningrequest/orchestrator"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/drainability/rules"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/drainability/rules/localpv"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/options"
 	kube_util "k8s.io/autoscaler/cluster-autoscaler/utils/kubernetes"
 	"k8s.io/autoscaler/cluster-autoscaler/version"
@@ -99,7 +104,7 @@ func registerSignalHandlers(autoscaler core.Autoscaler) {
 	}()
 }
 
-func buildAutoscaler(context ctx.Context, debuggingSnapshotter debuggingsnapshot.DebuggingSnapshotter) (core.Autoscaler, *loop.LoopTrigger, error) {
+func buildAutoscaler(context ctx.Context, debuggingSnapshotter debuggingsnapshot.DebuggingSnapshotter, decisionJournal *journal.Journal, longPendingFilter pods.PodListProcessor) (core.Autoscaler, *loop.LoopTrigger, error) {
 	// Get AutoscalingOptions from flags.
 	autoscalingOptions := flags.AutoscalingOptions()
 
@@ -118,8 +123,17 @@ func buildAutoscaler(context ctx.Context, debuggingSnapshotter debuggingsnapshot
 	if err != nil {
 		return nil, nil, err
 	}
+	localStorageClasses, err := ddcommon.ParseLocalStorageClasses(autoscalingOptions.LocalStorageClasses)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	deleteOptions := options.NewNodeDeleteOptions(autoscalingOptions)
+	pvcLister := informerFactory.Core().V1().PersistentVolumeClaims().Lister()
 	drainabilityRules := rules.Default(deleteOptions)
+	if autoscalingOptions.SkipNodesWithLocalPVs {
+		drainabilityRules = append(drainabilityRules, localpv.New(localStorageClasses, pvcLister))
+	}
 
 	var snapshotStore clustersnapshot.ClusterSnapshotStore = store.NewDeltaSnapshotStore(autoscalingOptions.ClusterSnapshotParallelism)
 	opts := core.AutoscalerOptions{
@@ -129,14 +143,19 @@ func buildAutoscaler(context ctx.Context, debuggingSnapshotter debuggingsnapshot
 		KubeClient:           kubeClient,
 		InformerFactory:      informerFactory,
 		DebuggingSnapshotter: debuggingSnapshotter,
+		DecisionJournal:      decisionJournal,
 		DeleteOptions:        deleteOptions,
 		DrainabilityRules:    drainabilityRules,
 		ScaleUpOrchestrator:  orchestrator.New(),
 	}
 
 	opts.Processors = ca_processors.DefaultProcessors(autoscalingOptions)
-	opts.Processors.TemplateNodeInfoProvider = ddnodeinfosprovider.NewTemplateOnlyNodeInfoProvider(&autoscalingOptions.NodeInfoCacheExpireTime, autoscalingOptions.ForceDaemonSets, &opts)
-	podListProcessor := ddpods.NewFilteringPodListProcessor(scheduling.ScheduleAnywhere)
+	opts.Processors.TemplateNodeInfoProvider = ddnodeinfosprovider.NewTemplateOnlyNodeInfoProvider(&autoscalingOptions.NodeInfoCacheExpireTime, autoscalingOptions.ForceDaemonSets, autoscalingOptions.NodeInfosHybridTemplates, localStorageClasses, &opts)
+	if autoscalingOptions.ScaleDownWindowsConfigMapEnabled {
+		configMapLister := kube_util.NewConfigMapListerForNamespace(kubeClient, make(chan struct{}), autoscalingOptions.ConfigNamespace)
+		opts.Processors.NodeGroupConfigProcessor = nodegroupconfig.NewScaleDownWindowsConfigMapProcessor(opts.Processors.NodeGroupConfigProcessor, configMapLister.ConfigMaps(autoscalingOptions.ConfigNamespace))
+	}
+	podListProcessor := ddpods.NewFilteringPodListProcessor(scheduling.ScheduleAnywhere, localStorageClasses, pvcLister, longPendingFilter)
 
 	var ProvisioningRequestInjector *provreq.ProvisioningRequestPodsInjector
 	if autoscalingOptions.ProvisioningRequestEnabled {
@@ -163,6 +182,7 @@ func buildAutoscaler(context ctx.Context, debuggingSnapshotter debuggingsnapshot
 		provreqOrchestrator := provreqorchestrator.New(client, []provreqorchestrator.ProvisioningClass{
 			checkcapacity.New(client, provisioningRequestPodsInjector),
 			besteffortatomic.New(client),
+			besteffortatomic.NewCapacityReservation(client),
 		})
 
 		scaleUpOrchestrator := provreqorchestrator.NewWrapperOrchestrator(provreqOrchestrator)
@@ -181,7 +201,7 @@ func buildAutoscaler(context ctx.Context, debuggingSnapshotter debuggingsnapshot
 		restConfig := kube_util.GetKubeConfig(autoscalingOptions.KubeClientOpts)
 		capacitybufferClient, capacitybufferClientError = capacityclient.NewCapacityBufferClientFromConfig(restConfig)
 		if capacitybufferClientError == nil && capacitybufferClient != nil {
-			nodeBufferController := capacitybuffer.NewDefaultBufferController(capacitybufferClient)
+			nodeBufferController := capacitybuffer.NewDefaultBufferController(capacitybufferClient, autoscalingOptions.CapacitybufferPodTemplatePaths)
 			go nodeBufferController.Run(make(chan struct{}))
 		}
 	}
@@ -192,12 +212,18 @@ func buildAutoscaler(context ctx.Context, debuggingSnapshotter debuggingsnapshot
 			capacitybufferClient, capacitybufferClientError = capacityclient.NewCapacityBufferClientFromConfig(restConfig)
 		}
 		if capacitybufferClientError == nil && capacitybufferClient != nil {
-			bufferPodInjector := cbprocessor.NewCapacityBufferPodListProcessor(capacitybufferClient, []string{common.ActiveProvisioningStrategy})
+			bufferPodInjector := cbprocessor.NewCapacityBufferPodListProcessor(capacitybufferClient, []string{common.ActiveProvisioningStrategy, common.StandbyProvisioningStrategy})
 			podListProcessor = pods.NewCombinedPodListProcessor([]pods.PodListProcessor{bufferPodInjector, podListProcessor})
-			opts.Processors.ScaleUpStatusProcessor = status.NewCombinedScaleUpStatusProcessor([]status.ScaleUpStatusProcessor{cbprocessor.NewFakePodsScaleUpStatusProcessor(), opts.Processors.ScaleUpStatusProcessor})
+			opts.Processors.ScaleUpStatusProcessor = status.NewCombinedScaleUpStatusProcessor([]status.ScaleUpStatusProcessor{cbprocessor.NewFakePodsScaleUpStatusProcessor(), cbprocessor.NewStandbyNodesScaleUpStatusProcessor(bufferPodInjector), opts.Processors.ScaleUpStatusProcessor})
 		}
 	}
 
+	if autoscalingOptions.PredictivePrescaling.Enabled {
+		prescalingPodInjector := prescaling.NewPredictivePrescalingPodListProcessor(autoscalingOptions.PredictivePrescaling)
+		podListProcessor = pods.NewCombinedPodListProcessor([]pods.PodListProcessor{prescalingPodInjector, podListProcessor})
+		opts.Processors.ScaleUpStatusProcessor = status.NewCombinedScaleUpStatusProcessor([]status.ScaleUpStatusProcessor{cbprocessor.NewFakePodsScaleUpStatusProcessorWithPredicate(prescaling.IsFakePrescalingPod), opts.Processors.ScaleUpStatusProcessor})
+	}
+
 	if autoscalingOptions.ProactiveScaleupEnabled {
 		podInjectionBackoffRegistry := podinjectionbackoff.NewFakePodControllerRegistry()
 
@@ -222,6 +248,10 @@ func buildAutoscaler(context ctx.Context, debuggingSnapshotter debuggingsnapshot
 	cp := scaledowncandidates.NewCombinedScaleDownCandidatesProcessor()
 	cp.Register(scaledowncandidates.NewScaleDownCandidatesSortingProcessor(scaleDownCandidatesComparers))
 
+	if autoscalingOptions.CapacitybufferPodInjectionEnabled {
+		cp.Register(cbprocessor.NewStandbyNodesScaleDownProcessor())
+	}
+
 	if autoscalingOptions.ScaleDownDelayTypeLocal {
 		sdp := scaledowncandidates.NewScaleDownCandidatesDelayProcessor()
 		cp.Register(sdp)
@@ -289,14 +319,15 @@ func buildAutoscaler(context ctx.Context, debuggingSnapshotter debuggingsnapshot
 	return autoscaler, trigger, nil
 }
 
-func run(healthCheck *metrics.HealthCheck, debuggingSnapshotter debuggingsnapshot.DebuggingSnapshotter) {
+func run(healthCheck *metrics.HealthCheck, debuggingSnapshotter debuggingsnapshot.DebuggingSnapshotter, decisionJournal *journal.Journal, longPendingFilter pods.PodListProcessor) {
 	autoscalingOpts := flags.AutoscalingOptions()
 
 	metrics.RegisterAll(autoscalingOpts.EmitPerNodeGroupMetrics)
+	ddnodeinfosprovider.RegisterMetrics()
 	context, cancel := ctx.WithCancel(ctx.Background())
 	defer cancel()
 
-	autoscaler, trigger, err := buildAutoscaler(context, debuggingSnapshotter)
+	autoscaler, trigger, err := buildAutoscaler(context, debuggingSnapshotter, decisionJournal, longPendingFilter)
 	if err != nil {
 		klog.Fatalf("Failed to create autoscaler: %v", err)
 	}
@@ -347,6 +378,13 @@ func main() {
 
 	logsapi.AddFlags(loggingConfig, pflag.CommandLine)
 	featureGate.AddFlag(pflag.CommandLine)
+
+	// subcommands share the autoscaler flags, so they're removed before flags parsing
+	var command string
+	if len(os.Args) > 1 && (os.Args[1] == validateNodeGroupsCommand || os.Args[1] == replayCommand) {
+		command = os.Args[1]
+		os.Args = append(os.Args[:1], os.Args[2:]...)
+	}
 	kube_flag.InitFlags()
 
 	autoscalingOpts := flags.AutoscalingOptions()
@@ -366,11 +404,20 @@ func main() {
 		klog.Fatalf("Failed to validate and apply logging configuration: %v", err)
 	}
 
+	switch command {
+	case validateNodeGroupsCommand:
+		os.Exit(runValidateNodeGroups(autoscalingOpts))
+	case replayCommand:
+		os.Exit(runReplay(autoscalingOpts, pflag.Args()))
+	}
+
 	healthCheck := metrics.NewHealthCheck(autoscalingOpts.MaxInactivityTime, autoscalingOpts.MaxFailingTime)
 
 	klog.V(1).Infof("Cluster Autoscaler %s", version.ClusterAutoscalerVersion)
 
 	debuggingSnapshotter := debuggingsnapshot.NewDebuggingSnapshotter(autoscalingOpts.DebuggingSnapshotEnabled)
+	decisionJournal := journal.New(autoscalingOpts.DecisionJournalSize)
+	longPendingFilter := ddpods.NewFilterOutLongPending()
 
 	go func() {
 		pathRecorderMux := mux.NewPathRecorderMux("cluster-autoscaler")
@@ -381,6 +428,10 @@ func main() {
 		if autoscalingOpts.DebuggingSnapshotEnabled {
 			pathRecorderMux.HandleFunc("/snapshotz", debuggingSnapshotter.ResponseHandler)
 		}
+		if decisionJournal != nil {
+			pathRecorderMux.HandleFunc("/journalz", decisionJournal.ServeHTTP)
+		}
+		pathRecorderMux.HandleFunc("/quarantinez", longPendingFilter.ServeHTTP)
 		pathRecorderMux.HandleFunc("/health-check", healthCheck.ServeHTTP)
 		if autoscalingOpts.EnableProfiling {
 			routes.Profiling{}.Install(pathRecorderMux)
@@ -390,7 +441,7 @@ func main() {
 	}()
 
 	if !leaderElection.LeaderElect {
-		run(healthCheck, debuggingSnapshotter)
+		run(healthCheck, debuggingSnapshotter, decisionJournal, longPendingFilter)
 	} else {
 		id, err := os.Hostname()
 		if err != nil {
@@ -430,7 +481,7 @@ func main() {
 				OnStartedLeading: func(_ ctx.Context) {
 					// Since we are committing a suicide after losing
 					// mastership, we can safely ignore the argument.
-					run(healthCheck, debuggingSnapshotter)
+					run(healthCheck, debuggingSnapshotter, decisionJournal, longPendingFilter)
 				},
 				OnStoppedLeading: func() {
 					klog.Fatalf("lost master")
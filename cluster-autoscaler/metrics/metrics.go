@@ -427,6 +427,46 @@ var (
 			Buckets:   k8smetrics.ExponentialBuckets(1, 2, 6), // 1, 2, 4, ..., 32
 		}, []string{"instance_type", "cpu_count", "namespace_count"},
 	)
+
+	costAwareBinpackingSkippedNodeGroups = k8smetrics.NewCounter(
+		&k8smetrics.CounterOpts{
+			Namespace: caNamespace,
+			Name:      "cost_aware_binpacking_skipped_node_groups_total",
+			Help:      "Number of node groups not evaluated in binpacking because enough good options were found.",
+		},
+	)
+
+	costAwareBinpackingAudits = k8smetrics.NewCounterVec(
+		&k8smetrics.CounterOpts{
+			Namespace: caNamespace,
+			Name:      "cost_aware_binpacking_audits_total",
+			Help:      "Number of full binpacking evaluations comparing the options the cost-aware limiter would have stopped with to the best one, by result.",
+		}, []string{"result"},
+	)
+
+	predictivePrescalingPods = k8smetrics.NewCounterVec(
+		&k8smetrics.CounterOpts{
+			Namespace: caNamespace,
+			Name:      "predictive_prescaling_pods_total",
+			Help:      "Number of pods forecast to arrive and of pods which actually arrived, over the slots predictive prescaling had a forecast for.",
+		}, []string{"type"},
+	)
+
+	predictivePrescalingInjectedPods = k8smetrics.NewGauge(
+		&k8smetrics.GaugeOpts{
+			Namespace: caNamespace,
+			Name:      "predictive_prescaling_injected_pods",
+			Help:      "Number of fake pods injected in the last loop ahead of forecast pod arrivals.",
+		},
+	)
+
+	spotFallbackAvailability = k8smetrics.NewGaugeVec(
+		&k8smetrics.GaugeOpts{
+			Namespace: caNamespace,
+			Name:      "spot_fallback_availability",
+			Help:      "Spot capacity availability learned by the spot-fallback expander from out-of-resources errors, between 0 (recently ran out of capacity) and 1, by instance family and zone.",
+		}, []string{"instance_family", "zone"},
+	)
 )
 
 // RegisterAll registers all metrics.
@@ -463,6 +503,11 @@ func RegisterAll(emitPerNodeGroupMetrics bool) {
 	legacyregistry.MustRegister(nodeTaintsCount)
 	legacyregistry.MustRegister(inconsistentInstancesMigsCount)
 	legacyregistry.MustRegister(binpackingHeterogeneity)
+	legacyregistry.MustRegister(costAwareBinpackingSkippedNodeGroups)
+	legacyregistry.MustRegister(costAwareBinpackingAudits)
+	legacyregistry.MustRegister(predictivePrescalingPods)
+	legacyregistry.MustRegister(predictivePrescalingInjectedPods)
+	legacyregistry.MustRegister(spotFallbackAvailability)
 
 	if emitPerNodeGroupMetrics {
 		legacyregistry.MustRegister(nodesGroupMinNodes)
@@ -750,3 +795,39 @@ func UpdateInconsistentInstancesMigsCount(migCount int) {
 func ObserveBinpackingHeterogeneity(instanceType, cpuCount, namespaceCount string, pegCount int) {
 	binpackingHeterogeneity.WithLabelValues(instanceType, cpuCount, namespaceCount).Observe(float64(pegCount))
 }
+
+// RegisterCostAwareBinpackingSkippedNodeGroups records node groups not evaluated in binpacking
+// because enough good options were found.
+func RegisterCostAwareBinpackingSkippedNodeGroups(count int) {
+	costAwareBinpackingSkippedNodeGroups.Add(float64(count))
+}
+
+// RegisterCostAwareBinpackingAudit records the result of a full binpacking evaluation: "hit" when the options
+// the cost-aware limiter would have stopped with include a good enough one, "miss" otherwise, and "no_stop"
+// when it wouldn't have stopped.
+func RegisterCostAwareBinpackingAudit(result string) {
+	costAwareBinpackingAudits.WithLabelValues(result).Inc()
+}
+
+// RegisterPredictivePrescalingSlot records, for a past slot predictive prescaling had a forecast for,
+// the number of pods forecast to arrive and the number of pods which actually arrived.
+func RegisterPredictivePrescalingSlot(forecast, actual float64) {
+	predictivePrescalingPods.WithLabelValues("forecast").Add(forecast)
+	predictivePrescalingPods.WithLabelValues("actual").Add(actual)
+}
+
+// UpdatePredictivePrescalingInjectedPods records the number of fake pods injected ahead of forecast pod arrivals.
+func UpdatePredictivePrescalingInjectedPods(count int) {
+	predictivePrescalingInjectedPods.Set(float64(count))
+}
+
+// UpdateSpotFallbackAvailability records the spot capacity availability learned for an instance family in a zone.
+func UpdateSpotFallbackAvailability(instanceFamily, zone string, availability float64) {
+	spotFallbackAvailability.WithLabelValues(instanceFamily, zone).Set(availability)
+}
+
+// DeleteSpotFallbackAvailability removes the spot capacity availability of an instance family in a zone which
+// recovered from its errors.
+func DeleteSpotFallbackAvailability(instanceFamily, zone string) {
+	spotFallbackAvailability.Delete(map[string]string{"instance_family": instanceFamily, "zone": zone})
+}
@@ -19,7 +19,9 @@ package binpacking
 import (
 	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
 	"k8s.io/autoscaler/cluster-autoscaler/context"
+	"k8s.io/autoscaler/cluster-autoscaler/estimator"
 	"k8s.io/autoscaler/cluster-autoscaler/expander"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
 )
 
 // CombinedLimiter combines the outcome of multiple limiters. It will limit
@@ -42,6 +44,16 @@ func (l *CombinedLimiter) InitBinpacking(context *context.AutoscalingContext, no
 	}
 }
 
+// OrderNodeGroups orders the node groups with the underline limiters implementing NodeGroupOrderer, in turn.
+func (l *CombinedLimiter) OrderNodeGroups(context *context.AutoscalingContext, nodeGroups []cloudprovider.NodeGroup, nodeInfos map[string]*framework.NodeInfo, podEquivalenceGroups []estimator.PodEquivalenceGroup) []cloudprovider.NodeGroup {
+	for _, limiter := range l.limiters {
+		if orderer, ok := limiter.(NodeGroupOrderer); ok {
+			nodeGroups = orderer.OrderNodeGroups(context, nodeGroups, nodeInfos, podEquivalenceGroups)
+		}
+	}
+	return nodeGroups
+}
+
 // MarkProcessed marks the nodegroup as processed in all underline limiters.
 func (l *CombinedLimiter) MarkProcessed(context *context.AutoscalingContext, nodegroupId string) {
 	for _, limiter := range l.limiters {
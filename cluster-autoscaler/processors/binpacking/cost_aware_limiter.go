@@ -0,0 +1,261 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binpacking
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+	"k8s.io/autoscaler/cluster-autoscaler/context"
+	"k8s.io/autoscaler/cluster-autoscaler/estimator"
+	"k8s.io/autoscaler/cluster-autoscaler/expander"
+	"k8s.io/autoscaler/cluster-autoscaler/metrics"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
+	podutils "k8s.io/autoscaler/cluster-autoscaler/utils/pod"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// PriceRanking ranks node groups by the number of pending pods a node fits per unit of its price.
+	PriceRanking = "price"
+	// FitRanking ranks node groups by the fraction of a node the pending pods it fits request.
+	FitRanking = "fit"
+
+	auditHit    = "hit"
+	auditMiss   = "miss"
+	auditNoStop = "no_stop"
+)
+
+// NodeGroupOrderer is implemented by limiters deciding the order in which node groups are evaluated.
+type NodeGroupOrderer interface {
+	// OrderNodeGroups is called before binpacking with the node groups to evaluate, and returns them in the order they should be evaluated.
+	OrderNodeGroups(context *context.AutoscalingContext, nodeGroups []cloudprovider.NodeGroup, nodeInfos map[string]*framework.NodeInfo, podEquivalenceGroups []estimator.PodEquivalenceGroup) []cloudprovider.NodeGroup
+}
+
+// CostAwareLimiter ranks node groups cheaply before binpacking, by price or by how well their template
+// fits the largest group of equivalent pending pods, and stops binpacking once enough options are
+// within a margin of the best one. Every audit interval, binpacking runs in full instead, to measure
+// how often stopping early would have missed a better option.
+type CostAwareLimiter struct {
+	options config.CostAwareBinpackingOptions
+	now     func() time.Time
+
+	lastAudit  time.Time
+	auditing   bool
+	stopped    bool
+	nodeGroups int
+	processed  map[string]bool
+	nodeInfos  map[string]*framework.NodeInfo
+	pricing    cloudprovider.PricingModel
+	// scores caches the evaluated options scores by node group, for the current binpacking
+	scores map[string]float64
+}
+
+// NewCostAwareLimiter returns an instance of a new CostAwareLimiter.
+func NewCostAwareLimiter(options config.CostAwareBinpackingOptions) *CostAwareLimiter {
+	return &CostAwareLimiter{
+		options: options,
+		now:     time.Now,
+	}
+}
+
+// InitBinpacking initialises the CostAwareLimiter, and decides whether binpacking should run in full to be audited.
+func (l *CostAwareLimiter) InitBinpacking(context *context.AutoscalingContext, nodeGroups []cloudprovider.NodeGroup) {
+	now := l.now()
+	l.auditing = l.options.AuditInterval > 0 && now.Sub(l.lastAudit) >= l.options.AuditInterval
+	if l.auditing {
+		l.lastAudit = now
+	}
+	l.stopped = false
+	l.nodeGroups = len(nodeGroups)
+	l.processed = make(map[string]bool)
+	l.nodeInfos = nil
+	l.pricing = nil
+	l.scores = make(map[string]float64)
+	if l.options.Ranking == PriceRanking {
+		pricing, err := context.CloudProvider.Pricing()
+		if err != nil {
+			klog.Warningf("Cost-aware binpacking can't rank node groups by price, ranking them by fit: %v", err)
+		} else {
+			l.pricing = pricing
+		}
+	}
+}
+
+// OrderNodeGroups ranks the node groups, the most promising ones first.
+func (l *CostAwareLimiter) OrderNodeGroups(_ *context.AutoscalingContext, nodeGroups []cloudprovider.NodeGroup, nodeInfos map[string]*framework.NodeInfo, podEquivalenceGroups []estimator.PodEquivalenceGroup) []cloudprovider.NodeGroup {
+	l.nodeGroups = len(nodeGroups)
+	l.nodeInfos = nodeInfos
+
+	var largestGroup estimator.PodEquivalenceGroup
+	for _, group := range podEquivalenceGroups {
+		if len(group.Pods) > len(largestGroup.Pods) {
+			largestGroup = group
+		}
+	}
+	pod := largestGroup.Exemplar()
+	if pod == nil {
+		return nodeGroups
+	}
+
+	scores := make(map[string]float64, len(nodeGroups))
+	for _, nodeGroup := range nodeGroups {
+		scores[nodeGroup.Id()] = l.rankingScore(nodeInfos[nodeGroup.Id()], pod)
+	}
+	ordered := make([]cloudprovider.NodeGroup, len(nodeGroups))
+	copy(ordered, nodeGroups)
+	sort.SliceStable(ordered, func(i, j int) bool { return scores[ordered[i].Id()] > scores[ordered[j].Id()] })
+	return ordered
+}
+
+// MarkProcessed marks the nodegroup as processed.
+func (l *CostAwareLimiter) MarkProcessed(_ *context.AutoscalingContext, nodegroupId string) {
+	l.processed[nodegroupId] = true
+}
+
+// StopBinpacking returns true once enough evaluated options are within the margin of the best one.
+func (l *CostAwareLimiter) StopBinpacking(_ *context.AutoscalingContext, evaluatedOptions []expander.Option) bool {
+	if l.stopped {
+		return true
+	}
+	if l.auditing || l.nodeInfos == nil || !l.goodEnough(evaluatedOptions) {
+		return false
+	}
+	l.stopped = true
+	skipped := max(l.nodeGroups-len(l.processed), 0)
+	klog.V(2).Infof("Binpacking stopped after %d good enough options, skipping %d node groups", l.options.Options, skipped)
+	metrics.RegisterCostAwareBinpackingSkippedNodeGroups(skipped)
+	return true
+}
+
+// FinalizeBinpacking compares, when binpacking was audited, the options the limiter would have stopped with to all the options.
+func (l *CostAwareLimiter) FinalizeBinpacking(_ *context.AutoscalingContext, finalOptions []expander.Option) {
+	if !l.auditing || l.nodeInfos == nil || len(finalOptions) == 0 {
+		return
+	}
+	result := l.auditResult(finalOptions)
+	klog.V(2).Infof("Cost-aware binpacking audit over %d options: %s", len(finalOptions), result)
+	metrics.RegisterCostAwareBinpackingAudit(result)
+}
+
+// auditResult returns whether binpacking, had it stopped early, would have kept an option within the margin
+// of the best of all the options, missed it, or not stopped at all.
+func (l *CostAwareLimiter) auditResult(options []expander.Option) string {
+	for i := range options {
+		if l.goodEnough(options[:i+1]) {
+			if l.bestScore(options)*(1-l.options.Margin) > l.bestScore(options[:i+1]) {
+				return auditMiss
+			}
+			return auditHit
+		}
+	}
+	return auditNoStop
+}
+
+// goodEnough returns whether at least the configured number of options are within the margin of the best one.
+func (l *CostAwareLimiter) goodEnough(options []expander.Option) bool {
+	if len(options) < l.options.Options {
+		return false
+	}
+	best := l.bestScore(options)
+	if best <= 0 {
+		return false
+	}
+	count := 0
+	for _, option := range options {
+		if l.optionScore(option) >= best*(1-l.options.Margin) {
+			count++
+		}
+	}
+	return count >= l.options.Options
+}
+
+func (l *CostAwareLimiter) bestScore(options []expander.Option) float64 {
+	best := 0.0
+	for _, option := range options {
+		best = math.Max(best, l.optionScore(option))
+	}
+	return best
+}
+
+// optionScore scores an evaluated option like node groups are ranked, higher is better. Node groups
+// have a single option per binpacking, so its score is computed once.
+func (l *CostAwareLimiter) optionScore(option expander.Option) float64 {
+	id := option.NodeGroup.Id()
+	if score, found := l.scores[id]; found {
+		return score
+	}
+	cpu, memory := int64(0), int64(0)
+	for _, pod := range option.Pods {
+		requests := podutils.PodRequests(pod)
+		cpu += requests.Cpu().MilliValue()
+		memory += requests.Memory().Value()
+	}
+	score := l.score(l.nodeInfos[id], cpu, memory, len(option.Pods), option.NodeCount)
+	if l.scores != nil {
+		l.scores[id] = score
+	}
+	return score
+}
+
+// rankingScore scores a node group template by the pods equivalent to the given one it fits.
+func (l *CostAwareLimiter) rankingScore(nodeInfo *framework.NodeInfo, pod *apiv1.Pod) float64 {
+	if nodeInfo == nil {
+		return 0
+	}
+	requests := podutils.PodRequests(pod)
+	allocatable := nodeInfo.Node().Status.Allocatable
+	fitting := math.Inf(1)
+	for _, resource := range []apiv1.ResourceName{apiv1.ResourceCPU, apiv1.ResourceMemory} {
+		request, available := requests[resource], allocatable[resource]
+		if request.MilliValue() > 0 {
+			fitting = math.Min(fitting, math.Floor(float64(available.MilliValue())/float64(request.MilliValue())))
+		}
+	}
+	if math.IsInf(fitting, 1) || fitting < 1 {
+		return 0
+	}
+	count := int(fitting)
+	return l.score(nodeInfo, int64(count)*requests.Cpu().MilliValue(), int64(count)*requests.Memory().Value(), count, 1)
+}
+
+// score returns, for pods with the given total requests scheduled on nodes of a node group, the number
+// of pods per unit of price when ranking by price, and the fraction of the nodes they request otherwise.
+func (l *CostAwareLimiter) score(nodeInfo *framework.NodeInfo, cpu, memory int64, pods, nodeCount int) float64 {
+	if nodeInfo == nil || nodeCount <= 0 {
+		return 0
+	}
+	node := nodeInfo.Node()
+	if l.pricing != nil {
+		now := l.now()
+		price, err := l.pricing.NodePrice(node, now, now.Add(time.Hour))
+		if err != nil || price <= 0 {
+			return 0
+		}
+		return float64(pods) / (price * float64(nodeCount))
+	}
+	allocatableCPU := node.Status.Allocatable.Cpu().MilliValue() * int64(nodeCount)
+	allocatableMemory := node.Status.Allocatable.Memory().Value() * int64(nodeCount)
+	if allocatableCPU <= 0 || allocatableMemory <= 0 {
+		return 0
+	}
+	return (float64(cpu)/float64(allocatableCPU) + float64(memory)/float64(allocatableMemory)) / 2
+}
@@ -0,0 +1,230 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binpacking
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	testprovider "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+	"k8s.io/autoscaler/cluster-autoscaler/context"
+	"k8s.io/autoscaler/cluster-autoscaler/estimator"
+	"k8s.io/autoscaler/cluster-autoscaler/expander"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/units"
+)
+
+type testPricingModel struct {
+	nodePrice map[string]float64
+	calls     int
+}
+
+func (tpm *testPricingModel) NodePrice(node *apiv1.Node, startTime time.Time, endTime time.Time) (float64, error) {
+	tpm.calls++
+	if price, found := tpm.nodePrice[node.Name]; found {
+		return price, nil
+	}
+	return 0, fmt.Errorf("price for node %v not found", node.Name)
+}
+
+func (tpm *testPricingModel) PodPrice(pod *apiv1.Pod, startTime time.Time, endTime time.Time) (float64, error) {
+	return 0, nil
+}
+
+var (
+	nodeInfos = map[string]*framework.NodeInfo{
+		"ng-small": framework.NewTestNodeInfo(BuildTestNode("small", 1000, 1000*units.MiB)),
+		"ng-large": framework.NewTestNodeInfo(BuildTestNode("large", 4000, 4000*units.MiB)),
+		"ng-odd":   framework.NewTestNodeInfo(BuildTestNode("odd", 1500, 1500*units.MiB)),
+	}
+	pricingModel = &testPricingModel{nodePrice: map[string]float64{"small": 0.1, "large": 1, "odd": 0.2}}
+)
+
+func buildNodeGroups(ids ...string) []cloudprovider.NodeGroup {
+	var nodeGroups []cloudprovider.NodeGroup
+	for _, id := range ids {
+		nodeGroups = append(nodeGroups, testprovider.NewTestNodeGroup(id, 10, 0, 0, true, false, "", nil, nil))
+	}
+	return nodeGroups
+}
+
+func buildPods(count int) []*apiv1.Pod {
+	var pods []*apiv1.Pod
+	for i := 0; i < count; i++ {
+		pods = append(pods, BuildTestPod(fmt.Sprintf("p%d", i), 600, 600*units.MiB))
+	}
+	return pods
+}
+
+func buildOption(id string, pods int) expander.Option {
+	return expander.Option{NodeGroup: buildNodeGroups(id)[0], NodeCount: 1, Pods: buildPods(pods)}
+}
+
+func newTestLimiter(options config.CostAwareBinpackingOptions, pricing cloudprovider.PricingModel) (*CostAwareLimiter, *context.AutoscalingContext) {
+	provider := testprovider.NewTestCloudProviderBuilder().Build()
+	if pricing != nil {
+		provider.SetPricingModel(pricing)
+	}
+	ctx := &context.AutoscalingContext{CloudProvider: provider}
+	limiter := NewCostAwareLimiter(options)
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+	return limiter, ctx
+}
+
+func TestOrderNodeGroups(t *testing.T) {
+	podGroups := []estimator.PodEquivalenceGroup{{Pods: buildPods(1)}, {Pods: buildPods(10)}}
+	for desc, test := range map[string]struct {
+		ranking string
+		pricing cloudprovider.PricingModel
+		groups  []estimator.PodEquivalenceGroup
+		want    []string
+	}{
+		"fit": {
+			ranking: FitRanking,
+			groups:  podGroups,
+			want:    []string{"ng-large", "ng-odd", "ng-small"},
+		},
+		"price": {
+			ranking: PriceRanking,
+			pricing: pricingModel,
+			groups:  podGroups,
+			want:    []string{"ng-small", "ng-odd", "ng-large"},
+		},
+		"price falls back to fit without pricing": {
+			ranking: PriceRanking,
+			groups:  podGroups,
+			want:    []string{"ng-large", "ng-odd", "ng-small"},
+		},
+		"no pending pods": {
+			ranking: FitRanking,
+			want:    []string{"ng-small", "ng-large", "ng-odd"},
+		},
+	} {
+		t.Run(desc, func(t *testing.T) {
+			limiter, ctx := newTestLimiter(config.CostAwareBinpackingOptions{Ranking: test.ranking, Options: 2}, test.pricing)
+			nodeGroups := buildNodeGroups("ng-small", "ng-large", "ng-odd")
+			limiter.InitBinpacking(ctx, nodeGroups)
+			var got []string
+			for _, nodeGroup := range limiter.OrderNodeGroups(ctx, nodeGroups, nodeInfos, test.groups) {
+				got = append(got, nodeGroup.Id())
+			}
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestStopBinpacking(t *testing.T) {
+	for desc, test := range map[string]struct {
+		options []expander.Option
+		want    bool
+	}{
+		"not enough options": {
+			options: []expander.Option{buildOption("ng-large", 6)},
+		},
+		"option not within the margin": {
+			options: []expander.Option{buildOption("ng-large", 6), buildOption("ng-small", 1)},
+		},
+		"enough options within the margin": {
+			options: []expander.Option{buildOption("ng-large", 6), buildOption("ng-small", 1), buildOption("ng-odd", 2)},
+			want:    true,
+		},
+	} {
+		t.Run(desc, func(t *testing.T) {
+			limiter, ctx := newTestLimiter(config.CostAwareBinpackingOptions{Ranking: FitRanking, Options: 2, Margin: 0.15}, nil)
+			nodeGroups := buildNodeGroups("ng-small", "ng-large", "ng-odd")
+			limiter.InitBinpacking(ctx, nodeGroups)
+			limiter.OrderNodeGroups(ctx, nodeGroups, nodeInfos, nil)
+			assert.Equal(t, test.want, limiter.StopBinpacking(ctx, test.options))
+		})
+	}
+}
+
+func TestStopBinpackingScoresOptionsOnce(t *testing.T) {
+	pricing := &testPricingModel{nodePrice: pricingModel.nodePrice}
+	limiter, ctx := newTestLimiter(config.CostAwareBinpackingOptions{Ranking: PriceRanking, Options: 2, Margin: 0.15}, pricing)
+	nodeGroups := buildNodeGroups("ng-small", "ng-large", "ng-odd")
+	limiter.InitBinpacking(ctx, nodeGroups)
+	limiter.OrderNodeGroups(ctx, nodeGroups, nodeInfos, nil)
+
+	var options []expander.Option
+	for _, option := range []expander.Option{buildOption("ng-small", 1), buildOption("ng-large", 6), buildOption("ng-odd", 2)} {
+		options = append(options, option)
+		limiter.StopBinpacking(ctx, options)
+	}
+	assert.Equal(t, 3, pricing.calls)
+
+	// scores aren't kept across binpackings
+	limiter.InitBinpacking(ctx, nodeGroups)
+	limiter.OrderNodeGroups(ctx, nodeGroups, nodeInfos, nil)
+	limiter.StopBinpacking(ctx, options)
+	assert.Equal(t, 6, pricing.calls)
+}
+
+func TestAudit(t *testing.T) {
+	limiter, ctx := newTestLimiter(config.CostAwareBinpackingOptions{Ranking: FitRanking, Options: 2, Margin: 0.15, AuditInterval: time.Hour}, nil)
+	nodeGroups := buildNodeGroups("ng-small", "ng-large", "ng-odd")
+	goodEnough := []expander.Option{buildOption("ng-large", 6), buildOption("ng-odd", 2)}
+
+	// The first binpacking is audited, and runs in full.
+	limiter.InitBinpacking(ctx, nodeGroups)
+	limiter.OrderNodeGroups(ctx, nodeGroups, nodeInfos, nil)
+	assert.False(t, limiter.StopBinpacking(ctx, goodEnough))
+
+	// The next one isn't, until the audit interval elapses.
+	limiter.InitBinpacking(ctx, nodeGroups)
+	limiter.OrderNodeGroups(ctx, nodeGroups, nodeInfos, nil)
+	assert.True(t, limiter.StopBinpacking(ctx, goodEnough))
+
+	for desc, test := range map[string]struct {
+		options []expander.Option
+		want    string
+	}{
+		"hit": {
+			options: []expander.Option{buildOption("ng-large", 6), buildOption("ng-odd", 2), buildOption("ng-small", 1)},
+			want:    auditHit,
+		},
+		"miss": {
+			options: []expander.Option{buildOption("ng-small", 1), buildOption("ng-small", 1), buildOption("ng-large", 6)},
+			want:    auditMiss,
+		},
+		"no stop": {
+			options: []expander.Option{buildOption("ng-large", 6), buildOption("ng-small", 1)},
+			want:    auditNoStop,
+		},
+	} {
+		t.Run(desc, func(t *testing.T) {
+			assert.Equal(t, test.want, limiter.auditResult(test.options))
+		})
+	}
+}
+
+func TestCombinedLimiterOrderNodeGroups(t *testing.T) {
+	costAwareLimiter, ctx := newTestLimiter(config.CostAwareBinpackingOptions{Ranking: FitRanking, Options: 2}, nil)
+	limiter := NewCombinedLimiter([]BinpackingLimiter{NewTimeLimiter(time.Minute), costAwareLimiter})
+	nodeGroups := buildNodeGroups("ng-small", "ng-large", "ng-odd")
+	limiter.InitBinpacking(ctx, nodeGroups)
+	ordered := limiter.OrderNodeGroups(ctx, nodeGroups, nodeInfos, []estimator.PodEquivalenceGroup{{Pods: buildPods(3)}})
+	assert.Equal(t, "ng-large", ordered[0].Id())
+}
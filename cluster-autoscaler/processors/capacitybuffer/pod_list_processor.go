@@ -40,12 +40,14 @@ const (
 )
 
 // CapacityBufferPodListProcessor processes the pod lists before scale up
-// and adds buffres api virtual pods.
+// and adds buffres api virtual pods. The capacity of buffers with the standby
+// provisioning strategy is held by standby nodes instead.
 type CapacityBufferPodListProcessor struct {
 	client               *client.CapacityBufferClient
 	statusFilter         buffersfilter.Filter
 	podTemplateGenFilter buffersfilter.Filter
 	provStrategies       map[string]bool
+	standbyNodes         *standbyNodes
 }
 
 // NewCapacityBufferPodListProcessor creates a new CapacityRequestPodListProcessor.
@@ -62,6 +64,7 @@ func NewCapacityBufferPodListProcessor(client *client.CapacityBufferClient, prov
 		}),
 		podTemplateGenFilter: buffersfilter.NewPodTemplateGenerationChangedFilter(client),
 		provStrategies:       provStrategiesMap,
+		standbyNodes:         newStandbyNodes(),
 	}
 }
 
@@ -77,11 +80,23 @@ func (p *CapacityBufferPodListProcessor) Process(ctx *context.AutoscalingContext
 	_, buffers = p.podTemplateGenFilter.Filter(buffers)
 
 	totalFakePods := []*apiv1.Pod{}
+	standbyFakePods := map[string][]*apiv1.Pod{}
 	for _, buffer := range buffers {
 		fakePods := p.provision(buffer)
-		totalFakePods = append(totalFakePods, fakePods...)
+		if isStandbyBuffer(buffer) {
+			bufferKey := types.NamespacedName{Namespace: buffer.Namespace, Name: buffer.Name}.String()
+			standbyFakePods[bufferKey] = withStandbyNodeToleration(fakePods)
+		} else {
+			totalFakePods = append(totalFakePods, fakePods...)
+		}
 	}
 	klog.V(2).Infof("Capacity pod processor injecting %v fake pods provisioning %v capacity buffers", len(totalFakePods), len(buffers))
+	if p.provStrategies[common.StandbyProvisioningStrategy] {
+		unschedulablePods, err = p.standbyNodes.Process(ctx, unschedulablePods, standbyFakePods)
+		if err != nil {
+			klog.Errorf("CapacityBufferPodListProcessor failed to process standby nodes with error: %v", err.Error())
+		}
+	}
 	unschedulablePods = append(unschedulablePods, totalFakePods...)
 	return unschedulablePods, nil
 }
@@ -146,6 +161,22 @@ func makeFakePods(bufferName string, samplePodTemplate *apiv1.PodTemplateSpec, p
 	return fakePods, nil
 }
 
+func isStandbyBuffer(buffer *api_v1.CapacityBuffer) bool {
+	return buffer.Status.ProvisioningStrategy != nil && *buffer.Status.ProvisioningStrategy == common.StandbyProvisioningStrategy
+}
+
+// withStandbyNodeToleration makes the fake pods tolerate the standby nodes taint, for them to be held by standby nodes.
+func withStandbyNodeToleration(pods []*apiv1.Pod) []*apiv1.Pod {
+	for _, pod := range pods {
+		pod.Spec.Tolerations = append(pod.Spec.Tolerations, apiv1.Toleration{
+			Key:      common.StandbyNodeTaintKey,
+			Operator: apiv1.TolerationOpExists,
+			Effect:   apiv1.TaintEffectNoSchedule,
+		})
+	}
+	return pods
+}
+
 func withCapacityBufferFakePodAnnotation(pod *apiv1.Pod) *apiv1.Pod {
 	if pod.Annotations == nil {
 		pod.Annotations = make(map[string]string, 1)
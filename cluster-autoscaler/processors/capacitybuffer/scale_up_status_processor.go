@@ -24,30 +24,38 @@ import (
 )
 
 // FakePodsScaleUpStatusProcessor is a ScaleUpStatusProcessor used for filtering out fake pods from scaleup status.
-type FakePodsScaleUpStatusProcessor struct{}
+type FakePodsScaleUpStatusProcessor struct {
+	isFakePod func(*apiv1.Pod) bool
+}
 
-// NewFakePodsScaleUpStatusProcessor return an instance of FakePodsScaleUpStatusProcessor
+// NewFakePodsScaleUpStatusProcessor return an instance of FakePodsScaleUpStatusProcessor filtering out capacity buffer fake pods
 func NewFakePodsScaleUpStatusProcessor() *FakePodsScaleUpStatusProcessor {
-	return &FakePodsScaleUpStatusProcessor{}
+	return NewFakePodsScaleUpStatusProcessorWithPredicate(isFakeCapacityBuffersPod)
+}
+
+// NewFakePodsScaleUpStatusProcessorWithPredicate return an instance of FakePodsScaleUpStatusProcessor filtering out
+// the fake pods isFakePod returns true for, for other processors injecting fake pods
+func NewFakePodsScaleUpStatusProcessorWithPredicate(isFakePod func(*apiv1.Pod) bool) *FakePodsScaleUpStatusProcessor {
+	return &FakePodsScaleUpStatusProcessor{isFakePod: isFakePod}
 }
 
-// Process updates scaleupStatus to remove all capacity buffer fake pods from
+// Process updates scaleupStatus to remove all fake pods from
 // PodsRemainUnschedulable, PodsAwaitEvaluation & PodsTriggeredScaleup
 func (a *FakePodsScaleUpStatusProcessor) Process(_ *ca_context.AutoscalingContext, scaleUpStatus *status.ScaleUpStatus) {
-	scaleUpStatus.PodsRemainUnschedulable = filterFakePods(scaleUpStatus.PodsRemainUnschedulable, func(noScaleUpInfo status.NoScaleUpInfo) *apiv1.Pod { return noScaleUpInfo.Pod }, "PodsRemainUnschedulable")
-	scaleUpStatus.PodsAwaitEvaluation = filterFakePods(scaleUpStatus.PodsAwaitEvaluation, func(pod *apiv1.Pod) *apiv1.Pod { return pod }, "PodsAwaitEvaluation")
-	scaleUpStatus.PodsTriggeredScaleUp = filterFakePods(scaleUpStatus.PodsTriggeredScaleUp, func(pod *apiv1.Pod) *apiv1.Pod { return pod }, "PodsTriggeredScaleUp")
+	scaleUpStatus.PodsRemainUnschedulable = filterFakePods(scaleUpStatus.PodsRemainUnschedulable, func(noScaleUpInfo status.NoScaleUpInfo) *apiv1.Pod { return noScaleUpInfo.Pod }, a.isFakePod, "PodsRemainUnschedulable")
+	scaleUpStatus.PodsAwaitEvaluation = filterFakePods(scaleUpStatus.PodsAwaitEvaluation, func(pod *apiv1.Pod) *apiv1.Pod { return pod }, a.isFakePod, "PodsAwaitEvaluation")
+	scaleUpStatus.PodsTriggeredScaleUp = filterFakePods(scaleUpStatus.PodsTriggeredScaleUp, func(pod *apiv1.Pod) *apiv1.Pod { return pod }, a.isFakePod, "PodsTriggeredScaleUp")
 }
 
-// filterFakePods removes capacity buffer fake pods from the input list of T using passed getPod(T)
+// filterFakePods removes fake pods from the input list of T using passed getPod(T) and isFakePod
 // Returns a list containing only non-fake pods
-func filterFakePods[T any](podsWrappers []T, getPod func(T) *apiv1.Pod, resourceName string) []T {
+func filterFakePods[T any](podsWrappers []T, getPod func(T) *apiv1.Pod, isFakePod func(*apiv1.Pod) bool, resourceName string) []T {
 	filteredPodsSources := make([]T, 0)
 	removedPods := make([]*apiv1.Pod, 0)
 
 	for _, podsWrapper := range podsWrappers {
 		currentPod := getPod(podsWrapper)
-		if !isFakeCapacityBuffersPod(currentPod) {
+		if !isFakePod(currentPod) {
 			filteredPodsSources = append(filteredPodsSources, podsWrapper)
 			continue
 		}
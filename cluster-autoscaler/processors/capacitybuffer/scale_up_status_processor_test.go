@@ -76,6 +76,23 @@ func TestProcess(t *testing.T) {
 	}
 }
 
+func TestProcessWithPredicate(t *testing.T) {
+	otherFakePod := BuildTestPod("other-fake-pod", 10, 10)
+	otherFakePod.Annotations = map[string]string{"podType": "otherFakePod"}
+	scaleUpStatus := &status.ScaleUpStatus{
+		PodsTriggeredScaleUp:    []*apiv1.Pod{createPod("pod-1", false), createPod("fake-pod-1", true), otherFakePod},
+		PodsRemainUnschedulable: makeNoScaleUpInfoFromPods([]*apiv1.Pod{otherFakePod}),
+	}
+
+	p := NewFakePodsScaleUpStatusProcessorWithPredicate(func(pod *apiv1.Pod) bool {
+		return pod.Annotations["podType"] == "otherFakePod"
+	})
+	p.Process(&context.AutoscalingContext{}, scaleUpStatus)
+
+	assert.ElementsMatch(t, []*apiv1.Pod{createPod("pod-1", false), createPod("fake-pod-1", true)}, scaleUpStatus.PodsTriggeredScaleUp)
+	assert.Empty(t, scaleUpStatus.PodsRemainUnschedulable)
+}
+
 func createPod(name string, isFake bool) *apiv1.Pod {
 	return BuildTestPod(name, 10, 10, func(p *apiv1.Pod) {
 		if !isFake {
@@ -0,0 +1,342 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacitybufferpodlister
+
+import (
+	"reflect"
+	"sort"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	"k8s.io/autoscaler/cluster-autoscaler/capacitybuffer/common"
+	"k8s.io/autoscaler/cluster-autoscaler/context"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/clustersnapshot"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/scheduling"
+	pod_util "k8s.io/autoscaler/cluster-autoscaler/utils/pod"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/taints"
+)
+
+// standbyNodes holds the capacity of buffers with the standby provisioning strategy as standby nodes:
+// nodes up but tainted so that no pod schedules on them, released by removing the taint once pending
+// pods fit on them. Only the cluster snapshot is updated while processing the pods, the taints are
+// written to the nodes afterwards by the StandbyNodesScaleUpStatusProcessor.
+type standbyNodes struct {
+	schedulingSimulator *scheduling.HintingSimulator
+	// taintUpdates maps the nodes to take (true) or release (false) as standby nodes to the last
+	// processed standby state, until their taints are updated.
+	taintUpdates map[string]bool
+}
+
+func newStandbyNodes() *standbyNodes {
+	return &standbyNodes{
+		schedulingSimulator: scheduling.NewHintingSimulator(),
+		taintUpdates:        map[string]bool{},
+	}
+}
+
+// Process releases the standby nodes unschedulable pods fit on, so that they are used before any scale-up,
+// and returns the remaining unschedulable pods. The fake pods of standby buffers, keyed by buffer, are then
+// held by the remaining standby nodes, releasing the ones not needed anymore and taking empty nodes as standby
+// nodes when they're not enough. The fake pods fitting on neither are returned with the unschedulable pods,
+// for the nodes to be scaled up.
+func (s *standbyNodes) Process(ctx *context.AutoscalingContext, unschedulablePods []*apiv1.Pod, fakePods map[string][]*apiv1.Pod) ([]*apiv1.Pod, error) {
+	defer s.schedulingSimulator.DropOldHints()
+
+	allNodes, err := ctx.AllNodeLister().List()
+	if err != nil {
+		return unschedulablePods, err
+	}
+	readyNodes, err := ctx.ReadyNodeLister().List()
+	if err != nil {
+		return unschedulablePods, err
+	}
+	nodeInfos, err := ctx.ClusterSnapshot.ListNodeInfos()
+	if err != nil {
+		return unschedulablePods, err
+	}
+	registered := map[string]bool{}
+	for _, node := range allNodes {
+		registered[node.Name] = true
+	}
+	// Only ready nodes of a node group hold the buffers capacity.
+	nodeGroups := map[string]string{}
+	for _, node := range readyNodes {
+		nodeGroup, err := ctx.CloudProvider.NodeGroupForNode(node)
+		if err != nil {
+			klog.Warningf("Failed to get node group for node %s: %v", node.Name, err)
+			continue
+		}
+		if nodeGroup == nil || reflect.ValueOf(nodeGroup).IsNil() {
+			continue
+		}
+		nodeGroups[node.Name] = nodeGroup.Id()
+	}
+	standby := map[string]bool{}
+	var standbyNames []string
+	for _, nodeInfo := range nodeInfos {
+		node := nodeInfo.Node()
+		// Upcoming nodes are in the snapshot as well, and may carry the standby taint of their template.
+		if registered[node.Name] && common.IsStandbyNode(node) {
+			standby[node.Name] = true
+			standbyNames = append(standbyNames, node.Name)
+		}
+	}
+	if len(standbyNames) == 0 && len(fakePods) == 0 {
+		return unschedulablePods, nil
+	}
+	sort.Strings(standbyNames)
+
+	unschedulablePods, released, err := s.release(ctx, unschedulablePods, standby, standbyNames)
+	if err != nil {
+		return unschedulablePods, err
+	}
+	for name := range released {
+		s.taintUpdates[name] = false
+	}
+
+	held, acquired, remainingFakePods, err := s.hold(ctx, unschedulablePods, fakePods, standby, released, nodeGroups)
+	if err != nil {
+		return unschedulablePods, err
+	}
+	for _, name := range standbyNames {
+		if !released[name] && !held[name] {
+			klog.V(2).Infof("Standby node %s isn't needed by capacity buffers anymore, releasing it", name)
+			if err := setStandby(ctx.ClusterSnapshot, name, false); err != nil {
+				klog.Errorf("Failed to release standby node %s in the cluster snapshot: %v", name, err)
+			}
+			s.taintUpdates[name] = false
+		}
+	}
+	for name := range acquired {
+		klog.V(2).Infof("Taking empty node %s as standby node for capacity buffers", name)
+		if err := setStandby(ctx.ClusterSnapshot, name, true); err != nil {
+			klog.Errorf("Failed to mark node %s as standby in the cluster snapshot: %v", name, err)
+		}
+		s.taintUpdates[name] = true
+	}
+	klog.V(2).Infof("Capacity buffers standby nodes: %d released for pending pods, %d held, %d acquired, %d fake pods left to scale up for",
+		len(released), len(held), len(acquired), len(remainingFakePods))
+	return append(unschedulablePods, remainingFakePods...), nil
+}
+
+// release releases the standby nodes the pods not fitting on other nodes fit on. The pods fitting on released
+// nodes are scheduled on them in the snapshot and removed from the returned pods, like schedulable pods are.
+func (s *standbyNodes) release(ctx *context.AutoscalingContext, pods []*apiv1.Pod, standby map[string]bool, standbyNames []string) ([]*apiv1.Pod, map[string]bool, error) {
+	released := map[string]bool{}
+	if len(standbyNames) == 0 || len(pods) == 0 {
+		return pods, released, nil
+	}
+
+	ctx.ClusterSnapshot.Fork()
+	statuses, _, err := s.schedulingSimulator.TrySchedulePods(ctx.ClusterSnapshot, pods, func(nodeInfo *framework.NodeInfo) bool {
+		return !standby[nodeInfo.Node().Name]
+	}, false)
+	ctx.ClusterSnapshot.Revert()
+	if err != nil {
+		return pods, released, err
+	}
+	pending := withoutScheduledPods(pods, statuses)
+
+	var scheduled []scheduling.Status
+	for _, name := range standbyNames {
+		if len(pending) == 0 {
+			break
+		}
+		ctx.ClusterSnapshot.Fork()
+		if err := setStandby(ctx.ClusterSnapshot, name, false); err != nil {
+			ctx.ClusterSnapshot.Revert()
+			return pods, released, err
+		}
+		statuses, _, err := s.schedulingSimulator.TrySchedulePods(ctx.ClusterSnapshot, pending, func(nodeInfo *framework.NodeInfo) bool {
+			return nodeInfo.Node().Name == name
+		}, false)
+		if err != nil || len(statuses) == 0 {
+			ctx.ClusterSnapshot.Revert()
+			if err != nil {
+				return pods, released, err
+			}
+			continue
+		}
+		if err := ctx.ClusterSnapshot.Commit(); err != nil {
+			return pods, released, err
+		}
+		klog.V(2).Infof("Releasing standby node %s for %d pending pods", name, len(statuses))
+		released[name] = true
+		scheduled = append(scheduled, statuses...)
+		pending = withoutScheduledPods(pending, statuses)
+	}
+	return withoutScheduledPods(pods, scheduled), released, nil
+}
+
+// hold returns the standby nodes holding the fake pods, the empty nodes to take as standby nodes for the
+// fake pods not fitting on them, and the fake pods fitting on neither. The capacity of each buffer is held
+// within a single node group.
+func (s *standbyNodes) hold(ctx *context.AutoscalingContext, unschedulablePods []*apiv1.Pod, fakePods map[string][]*apiv1.Pod, standby, released map[string]bool, nodeGroups map[string]string) (map[string]bool, map[string]bool, []*apiv1.Pod, error) {
+	ctx.ClusterSnapshot.Fork()
+	defer ctx.ClusterSnapshot.Revert()
+
+	// Pending pods go first, so that the nodes they're about to schedule on aren't taken as standby nodes.
+	if _, _, err := s.schedulingSimulator.TrySchedulePods(ctx.ClusterSnapshot, unschedulablePods, func(nodeInfo *framework.NodeInfo) bool {
+		return !standby[nodeInfo.Node().Name]
+	}, false); err != nil {
+		return nil, nil, nil, err
+	}
+	nodeInfos, err := ctx.ClusterSnapshot.ListNodeInfos()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var standbyNames, emptyNames []string
+	for _, nodeInfo := range nodeInfos {
+		node := nodeInfo.Node()
+		if nodeGroups[node.Name] == "" || released[node.Name] {
+			continue
+		}
+		if standby[node.Name] {
+			standbyNames = append(standbyNames, node.Name)
+		} else if isEmpty(nodeInfo) && !taints.HasToBeDeletedTaint(node) {
+			emptyNames = append(emptyNames, node.Name)
+		}
+	}
+	sort.Strings(standbyNames)
+	sort.Strings(emptyNames)
+
+	var buffers []string
+	for buffer := range fakePods {
+		buffers = append(buffers, buffer)
+	}
+	sort.Strings(buffers)
+
+	held, acquired := map[string]bool{}, map[string]bool{}
+	var remainingFakePods []*apiv1.Pod
+	for _, buffer := range buffers {
+		pods, bufferHeld, nodeGroup, err := s.scheduleOnNodes(ctx, fakePods[buffer], standbyNames, nodeGroups, "")
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		pods, bufferAcquired, _, err := s.scheduleOnNodes(ctx, pods, emptyNames, nodeGroups, nodeGroup)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		for name := range bufferHeld {
+			held[name] = true
+		}
+		for name := range bufferAcquired {
+			acquired[name] = true
+		}
+		remainingFakePods = append(remainingFakePods, pods...)
+	}
+	return held, acquired, remainingFakePods, nil
+}
+
+// scheduleOnNodes schedules the pods on the nodes one node at a time, in the given order, so that the same
+// nodes are picked across loops. Only the nodes of nodeGroup are used, or when it's empty, the nodes of the
+// node group of the first node used. It returns the pods not fitting on any of them, the nodes used and
+// their node group.
+func (s *standbyNodes) scheduleOnNodes(ctx *context.AutoscalingContext, pods []*apiv1.Pod, nodeNames []string, nodeGroups map[string]string, nodeGroup string) ([]*apiv1.Pod, map[string]bool, string, error) {
+	used := map[string]bool{}
+	for _, name := range nodeNames {
+		if len(pods) == 0 {
+			break
+		}
+		if nodeGroup != "" && nodeGroups[name] != nodeGroup {
+			continue
+		}
+		statuses, _, err := s.schedulingSimulator.TrySchedulePods(ctx.ClusterSnapshot, pods, func(nodeInfo *framework.NodeInfo) bool {
+			return nodeInfo.Node().Name == name
+		}, false)
+		if err != nil {
+			return pods, used, nodeGroup, err
+		}
+		if len(statuses) > 0 {
+			used[name] = true
+			nodeGroup = nodeGroups[name]
+			pods = withoutScheduledPods(pods, statuses)
+		}
+	}
+	return pods, used, nodeGroup, nil
+}
+
+// updateTaints writes the standby taints of the nodes taken or released as standby nodes since the last call.
+func (s *standbyNodes) updateTaints(ctx *context.AutoscalingContext) {
+	for name, standby := range s.taintUpdates {
+		node, err := ctx.AllNodeLister().Get(name)
+		if err != nil {
+			klog.Errorf("Failed to get node %s to update its standby taint: %v", name, err)
+			continue
+		}
+		if standby {
+			if _, err := taints.AddTaints(node, ctx.ClientSet, []apiv1.Taint{common.StandbyNodeTaint()}, false); err != nil {
+				klog.Errorf("Failed to mark node %s as standby: %v", name, err)
+			}
+		} else if _, err := taints.CleanTaints(node, ctx.ClientSet, []string{common.StandbyNodeTaintKey}, false); err != nil {
+			klog.Errorf("Failed to release standby node %s: %v", name, err)
+		}
+	}
+	s.taintUpdates = map[string]bool{}
+}
+
+// setStandby adds or removes the standby taint of the node in the snapshot.
+func setStandby(snapshot clustersnapshot.ClusterSnapshot, nodeName string, standby bool) error {
+	nodeInfo, err := snapshot.GetNodeInfo(nodeName)
+	if err != nil {
+		return err
+	}
+	nodeInfo = nodeInfo.DeepCopy()
+	node := nodeInfo.Node()
+	var nodeTaints []apiv1.Taint
+	for _, taint := range node.Spec.Taints {
+		if taint.Key != common.StandbyNodeTaintKey {
+			nodeTaints = append(nodeTaints, taint)
+		}
+	}
+	if standby {
+		nodeTaints = append(nodeTaints, common.StandbyNodeTaint())
+	}
+	node.Spec.Taints = nodeTaints
+	nodeInfo.SetNode(node)
+	if err := snapshot.RemoveNodeInfo(nodeName); err != nil {
+		return err
+	}
+	return snapshot.AddNodeInfo(nodeInfo)
+}
+
+// isEmpty returns whether only daemon set and mirror pods run on the node.
+func isEmpty(nodeInfo *framework.NodeInfo) bool {
+	for _, podInfo := range nodeInfo.Pods() {
+		if !pod_util.IsDaemonSetPod(podInfo.Pod) && !pod_util.IsMirrorPod(podInfo.Pod) {
+			return false
+		}
+	}
+	return true
+}
+
+func withoutScheduledPods(pods []*apiv1.Pod, statuses []scheduling.Status) []*apiv1.Pod {
+	scheduled := map[types.UID]bool{}
+	for _, status := range statuses {
+		scheduled[status.Pod.UID] = true
+	}
+	var remainingPods []*apiv1.Pod
+	for _, pod := range pods {
+		if !scheduled[pod.UID] {
+			remainingPods = append(remainingPods, pod)
+		}
+	}
+	return remainingPods
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacitybufferpodlister
+
+import (
+	apiv1 "k8s.io/api/core/v1"
+
+	"k8s.io/autoscaler/cluster-autoscaler/capacitybuffer/common"
+	"k8s.io/autoscaler/cluster-autoscaler/context"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/errors"
+)
+
+// StandbyNodesScaleDownProcessor is a scale down node processor excluding the standby nodes,
+// holding the capacity of standby capacity buffers, from scale down.
+type StandbyNodesScaleDownProcessor struct {
+}
+
+// NewStandbyNodesScaleDownProcessor returns a new StandbyNodesScaleDownProcessor.
+func NewStandbyNodesScaleDownProcessor() *StandbyNodesScaleDownProcessor {
+	return &StandbyNodesScaleDownProcessor{}
+}
+
+// GetPodDestinationCandidates filters out standby nodes, as pods don't schedule on them.
+func (p *StandbyNodesScaleDownProcessor) GetPodDestinationCandidates(ctx *context.AutoscalingContext,
+	nodes []*apiv1.Node) ([]*apiv1.Node, errors.AutoscalerError) {
+	return withoutStandbyNodes(nodes), nil
+}
+
+// GetScaleDownCandidates filters out standby nodes, as they're kept up until they're released.
+func (p *StandbyNodesScaleDownProcessor) GetScaleDownCandidates(ctx *context.AutoscalingContext,
+	nodes []*apiv1.Node) ([]*apiv1.Node, errors.AutoscalerError) {
+	return withoutStandbyNodes(nodes), nil
+}
+
+// CleanUp is called at CA termination.
+func (p *StandbyNodesScaleDownProcessor) CleanUp() {
+}
+
+func withoutStandbyNodes(nodes []*apiv1.Node) []*apiv1.Node {
+	result := []*apiv1.Node{}
+	for _, node := range nodes {
+		if !common.IsStandbyNode(node) {
+			result = append(result, node)
+		}
+	}
+	return result
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacitybufferpodlister
+
+import (
+	ca_context "k8s.io/autoscaler/cluster-autoscaler/context"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/status"
+)
+
+// StandbyNodesScaleUpStatusProcessor writes the standby taints of the nodes taken or released as standby nodes
+// by a CapacityBufferPodListProcessor. Scale-up status processors run once per loop, after the scale-up, so the
+// pod list processing only has to update the cluster snapshot.
+type StandbyNodesScaleUpStatusProcessor struct {
+	standbyNodes *standbyNodes
+}
+
+// NewStandbyNodesScaleUpStatusProcessor returns an instance of StandbyNodesScaleUpStatusProcessor updating the
+// standby nodes of the pod list processor.
+func NewStandbyNodesScaleUpStatusProcessor(podListProcessor *CapacityBufferPodListProcessor) *StandbyNodesScaleUpStatusProcessor {
+	return &StandbyNodesScaleUpStatusProcessor{standbyNodes: podListProcessor.standbyNodes}
+}
+
+// Process updates the standby taints of the nodes.
+func (p *StandbyNodesScaleUpStatusProcessor) Process(ctx *ca_context.AutoscalingContext, _ *status.ScaleUpStatus) {
+	p.standbyNodes.updateTaints(ctx)
+}
+
+// CleanUp is called at CA termination
+func (p *StandbyNodesScaleUpStatusProcessor) CleanUp() {}
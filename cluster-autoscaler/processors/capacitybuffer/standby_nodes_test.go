@@ -0,0 +1,242 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capacitybufferpodlister
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakeclient "k8s.io/client-go/kubernetes/fake"
+
+	"k8s.io/autoscaler/cluster-autoscaler/capacitybuffer/common"
+	testprovider "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
+	ca_context "k8s.io/autoscaler/cluster-autoscaler/context"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/clustersnapshot/testsnapshot"
+	kube_util "k8s.io/autoscaler/cluster-autoscaler/utils/kubernetes"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+)
+
+func TestStandbyNodes(t *testing.T) {
+	for desc, test := range map[string]struct {
+		standbyNodes        int
+		emptyNodes          int
+		otherGroupNodes     int
+		unreadyNodes        int
+		noGroupNodes        int
+		upcomingNodes       int
+		unschedulablePods   []*corev1.Pod
+		fakePods            map[string][]*corev1.Pod
+		expectedPods        []string
+		expectedStandbyNode map[string]bool
+	}{
+		"pending pod released a standby node": {
+			standbyNodes:        1,
+			unschedulablePods:   []*corev1.Pod{BuildTestPod("pending", 600, 100)},
+			expectedStandbyNode: map[string]bool{"standby-0": false, "busy": false},
+		},
+		"pending pod fits on a regular node": {
+			standbyNodes:        1,
+			unschedulablePods:   []*corev1.Pod{BuildTestPod("pending", 100, 100)},
+			fakePods:            map[string][]*corev1.Pod{"buffer": {standbyFakePod("fake", 600)}},
+			expectedPods:        []string{"pending"},
+			expectedStandbyNode: map[string]bool{"standby-0": true, "busy": false},
+		},
+		"pending pod fits nowhere": {
+			standbyNodes:        1,
+			unschedulablePods:   []*corev1.Pod{BuildTestPod("pending", 2000, 100)},
+			fakePods:            map[string][]*corev1.Pod{"buffer": {standbyFakePod("fake", 600)}},
+			expectedPods:        []string{"pending"},
+			expectedStandbyNode: map[string]bool{"standby-0": true, "busy": false},
+		},
+		"standby nodes are released in turn": {
+			standbyNodes:        2,
+			unschedulablePods:   []*corev1.Pod{BuildTestPod("pending", 600, 100)},
+			fakePods:            map[string][]*corev1.Pod{"buffer": {standbyFakePod("fake-1", 600), standbyFakePod("fake-2", 600)}},
+			expectedPods:        []string{"fake-2"},
+			expectedStandbyNode: map[string]bool{"standby-0": false, "standby-1": true, "busy": false},
+		},
+		"standby nodes not needed are released": {
+			standbyNodes:        2,
+			fakePods:            map[string][]*corev1.Pod{"buffer": {standbyFakePod("fake", 600)}},
+			expectedStandbyNode: map[string]bool{"standby-0": true, "standby-1": false, "busy": false},
+		},
+		"empty nodes are taken as standby nodes": {
+			emptyNodes:          2,
+			fakePods:            map[string][]*corev1.Pod{"buffer": {standbyFakePod("fake", 600)}},
+			expectedStandbyNode: map[string]bool{"empty-0": true, "empty-1": false, "busy": false},
+		},
+		"empty nodes pending pods fit on are not taken as standby nodes": {
+			emptyNodes:          1,
+			unschedulablePods:   []*corev1.Pod{BuildTestPod("pending", 600, 100)},
+			fakePods:            map[string][]*corev1.Pod{"buffer": {standbyFakePod("fake", 600)}},
+			expectedPods:        []string{"pending", "fake"},
+			expectedStandbyNode: map[string]bool{"empty-0": false, "busy": false},
+		},
+		"unready nodes are not taken as standby nodes": {
+			unreadyNodes:        1,
+			fakePods:            map[string][]*corev1.Pod{"buffer": {standbyFakePod("fake", 600)}},
+			expectedPods:        []string{"fake"},
+			expectedStandbyNode: map[string]bool{"unready-0": false, "busy": false},
+		},
+		"nodes without node group are not taken as standby nodes": {
+			noGroupNodes:        1,
+			fakePods:            map[string][]*corev1.Pod{"buffer": {standbyFakePod("fake", 600)}},
+			expectedPods:        []string{"fake"},
+			expectedStandbyNode: map[string]bool{"no-group-0": false, "busy": false},
+		},
+		"upcoming nodes are not taken as standby nodes": {
+			upcomingNodes:       1,
+			fakePods:            map[string][]*corev1.Pod{"buffer": {standbyFakePod("fake", 600)}},
+			expectedPods:        []string{"fake"},
+			expectedStandbyNode: map[string]bool{"busy": false},
+		},
+		"buffer capacity is taken from the node group of its standby nodes": {
+			standbyNodes:        1,
+			otherGroupNodes:     1,
+			fakePods:            map[string][]*corev1.Pod{"buffer": {standbyFakePod("fake-1", 600), standbyFakePod("fake-2", 600)}},
+			expectedPods:        []string{"fake-2"},
+			expectedStandbyNode: map[string]bool{"standby-0": true, "other-0": false, "busy": false},
+		},
+		"buffer capacity is taken from a single node group": {
+			emptyNodes:          1,
+			otherGroupNodes:     1,
+			fakePods:            map[string][]*corev1.Pod{"buffer": {standbyFakePod("fake-1", 600), standbyFakePod("fake-2", 600)}},
+			expectedPods:        []string{"fake-2"},
+			expectedStandbyNode: map[string]bool{"empty-0": true, "other-0": false, "busy": false},
+		},
+		"buffers capacity is taken from different node groups": {
+			emptyNodes:      1,
+			otherGroupNodes: 1,
+			fakePods: map[string][]*corev1.Pod{
+				"buffer-1": {standbyFakePod("fake-1", 600)},
+				"buffer-2": {standbyFakePod("fake-2", 600)},
+			},
+			expectedStandbyNode: map[string]bool{"empty-0": true, "other-0": true, "busy": false},
+		},
+	} {
+		t.Run(desc, func(t *testing.T) {
+			provider := testprovider.NewTestCloudProviderBuilder().Build()
+			provider.AddNodeGroup("ng1", 0, 10, 0)
+			provider.AddNodeGroup("ng2", 0, 10, 0)
+			var nodes, readyNodes []*corev1.Node
+			addNode := func(name, nodeGroup string, ready, standby bool) *corev1.Node {
+				node := BuildTestNode(name, 1000, 1000)
+				if standby {
+					node.Spec.Taints = []corev1.Taint{common.StandbyNodeTaint()}
+				}
+				SetNodeReadyState(node, ready, time.Time{})
+				if nodeGroup != "" {
+					provider.AddNode(nodeGroup, node)
+				}
+				nodes = append(nodes, node)
+				if ready {
+					readyNodes = append(readyNodes, node)
+				}
+				return node
+			}
+			busyNode := addNode("busy", "ng1", true, false)
+			for i := 0; i < test.standbyNodes; i++ {
+				addNode(fmt.Sprintf("standby-%d", i), "ng1", true, true)
+			}
+			for i := 0; i < test.emptyNodes; i++ {
+				addNode(fmt.Sprintf("empty-%d", i), "ng1", true, false)
+			}
+			for i := 0; i < test.otherGroupNodes; i++ {
+				addNode(fmt.Sprintf("other-%d", i), "ng2", true, false)
+			}
+			for i := 0; i < test.unreadyNodes; i++ {
+				addNode(fmt.Sprintf("unready-%d", i), "ng1", false, false)
+			}
+			for i := 0; i < test.noGroupNodes; i++ {
+				addNode(fmt.Sprintf("no-group-%d", i), "", true, false)
+			}
+			snapshotNodes := nodes
+			for i := 0; i < test.upcomingNodes; i++ {
+				upcomingNode := BuildTestNode(fmt.Sprintf("upcoming-%d", i), 1000, 1000)
+				SetNodeReadyState(upcomingNode, true, time.Time{})
+				snapshotNodes = append(snapshotNodes, upcomingNode)
+			}
+			busyPod := BuildTestPod("busy", 800, 100)
+			busyPod.Spec.NodeName = busyNode.Name
+
+			snapshot := testsnapshot.NewTestSnapshotOrDie(t)
+			assert.NoError(t, snapshot.SetClusterState(snapshotNodes, []*corev1.Pod{busyPod}, nil))
+			kubeClient := fakeclient.NewSimpleClientset()
+			for _, node := range nodes {
+				_, err := kubeClient.CoreV1().Nodes().Create(context.TODO(), node, metav1.CreateOptions{})
+				assert.NoError(t, err)
+			}
+			ctx := &ca_context.AutoscalingContext{
+				CloudProvider:   provider,
+				ClusterSnapshot: snapshot,
+				AutoscalingKubeClients: ca_context.AutoscalingKubeClients{
+					ClientSet:      kubeClient,
+					ListerRegistry: kube_util.NewListerRegistry(kube_util.NewTestNodeLister(nodes), kube_util.NewTestNodeLister(readyNodes), nil, nil, nil, nil, nil, nil, nil),
+				},
+			}
+
+			standbyNodes := newStandbyNodes()
+			pods, err := standbyNodes.Process(ctx, test.unschedulablePods, test.fakePods)
+			assert.NoError(t, err)
+			var podNames []string
+			for _, pod := range pods {
+				podNames = append(podNames, pod.Name)
+			}
+			assert.ElementsMatch(t, test.expectedPods, podNames)
+			for name, standby := range test.expectedStandbyNode {
+				nodeInfo, err := snapshot.GetNodeInfo(name)
+				assert.NoError(t, err)
+				assert.Equal(t, standby, common.IsStandbyNode(nodeInfo.Node()), "node %s in the snapshot", name)
+			}
+			for _, node := range nodes {
+				clusterNode, err := kubeClient.CoreV1().Nodes().Get(context.TODO(), node.Name, metav1.GetOptions{})
+				assert.NoError(t, err)
+				assert.Equal(t, common.IsStandbyNode(node), common.IsStandbyNode(clusterNode), "node %s in the cluster before the scale-up", node.Name)
+			}
+
+			NewStandbyNodesScaleUpStatusProcessor(&CapacityBufferPodListProcessor{standbyNodes: standbyNodes}).Process(ctx, nil)
+			for name, standby := range test.expectedStandbyNode {
+				node, err := kubeClient.CoreV1().Nodes().Get(context.TODO(), name, metav1.GetOptions{})
+				assert.NoError(t, err)
+				assert.Equal(t, standby, common.IsStandbyNode(node), "node %s in the cluster", name)
+			}
+		})
+	}
+}
+
+func TestStandbyNodesScaleDownProcessor(t *testing.T) {
+	standbyNode := BuildTestNode("standby", 1000, 1000)
+	standbyNode.Spec.Taints = []corev1.Taint{common.StandbyNodeTaint()}
+	regularNode := BuildTestNode("regular", 1000, 1000)
+
+	processor := NewStandbyNodesScaleDownProcessor()
+	candidates, err := processor.GetScaleDownCandidates(nil, []*corev1.Node{standbyNode, regularNode})
+	assert.Nil(t, err)
+	assert.Equal(t, []*corev1.Node{regularNode}, candidates)
+	destinations, err := processor.GetPodDestinationCandidates(nil, []*corev1.Node{standbyNode, regularNode})
+	assert.Nil(t, err)
+	assert.Equal(t, []*corev1.Node{regularNode}, destinations)
+}
+
+func standbyFakePod(name string, cpu int64) *corev1.Pod {
+	return withStandbyNodeToleration([]*corev1.Pod{BuildTestPod(name, cpu, 100)})[0]
+}
@@ -17,6 +17,10 @@ limitations under the License.
 package common
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+
 	apiv1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/klog/v2"
@@ -28,11 +32,15 @@ const (
 	// DatadogLocalStorageCapacityLabel is storing the amount of local storage a new node will have
 	// e.g. nodegroups.datadoghq.com/local-storage-capacity=100Gi
 	DatadogLocalStorageCapacityLabel = "nodegroups.datadoghq.com/local-storage-capacity"
+	// DatadogLocalStorageCountLabel is storing the number of local volumes a new node will have
+	// e.g. nodegroups.datadoghq.com/local-storage-count=4 (defaults to 1 when missing)
+	DatadogLocalStorageCountLabel = "nodegroups.datadoghq.com/local-storage-count"
 
 	// DatadogLocalDataExistsResource is a virtual resource placed on new or future
 	// nodes offering local storage, and currently injected as requests on
 	// Pending pods having a PVC for local-data volumes.
-	// This resource will always be 1 unit, since there is only 1 local-data volume per node
+	// This resource counts local-data volumes: nodes offer as many units as they
+	// have volumes (1 unless told otherwise), pods request 1 unit per local PVC.
 	DatadogLocalDataExistsResource apiv1.ResourceName = "storageclass/local-data"
 
 	// DatadogLocalStorageResource is a virtual resource placed on new or future
@@ -40,23 +48,158 @@ const (
 	// Pending pods having a PVC for local-storage volumes.
 	// This is similar to DatadogLocalDataExistsResource, but this resource will have the actual amount of storage available on a node
 	DatadogLocalStorageResource apiv1.ResourceName = "node.datadoghq.com/local-storage"
+
+	// DatadogLocalDataStorageClass is the default no-provisioner storage class
+	// backed by node local volumes.
+	DatadogLocalDataStorageClass = "local-data"
+
+	datadogNodeGroupsLabelPrefix  = "nodegroups.datadoghq.com/"
+	datadogStorageClassPrefix     = "storageclass/"
+	datadogNodeResourcePrefix     = "node.datadoghq.com/"
+	datadogCapacityLabelSuffix    = "-capacity"
+	datadogCountLabelSuffix       = "-count"
+	localStorageClassSpecFieldsNb = 6
 )
 
 var (
-	// DatadogLocalDataQuantity is used to ensure pods that have PVCs that request local-data will not be bin packed
-	// since there is only 1 local-data volume per node
+	// DatadogLocalDataQuantity is the amount of DatadogLocalDataExistsResource requested per local-data PVC,
+	// and offered by nodes not having a local-storage-count label (since they have a single local-data volume).
+	// This ensures pods requesting local-data volumes won't be bin packed beyond the number of volumes.
 	DatadogLocalDataQuantity = resource.NewQuantity(1, resource.DecimalSI)
 )
 
-// NodeHasLocalData returns true if the node holds a local-storage:true label
-func NodeHasLocalData(node *apiv1.Node) bool {
+// LocalStorageClass maps a no-provisioner storage class (backed by node local
+// volumes) to the node labels advertising it on node groups templates, and to
+// the virtual resources we inject on template nodes and on pods using that class.
+type LocalStorageClass struct {
+	// StorageClassName is the name of the storage class, eg. "local-data"
+	StorageClassName string
+	// Label is "true" on nodes offering that storage class
+	Label string
+	// CapacityLabel stores the amount of storage a new node will have for that class
+	CapacityLabel string
+	// CountLabel stores the number of volumes a new node will have for that class
+	CountLabel string
+	// ExistsResource is a virtual resource (1 unit per volume) advertising that class
+	ExistsResource apiv1.ResourceName
+	// CapacityResource is a virtual resource holding the amount of storage available for that class
+	CapacityResource apiv1.ResourceName
+}
+
+// DefaultLocalStorageClass is the historical "local-data" storage class mapping
+var DefaultLocalStorageClass = LocalStorageClass{
+	StorageClassName: DatadogLocalDataStorageClass,
+	Label:            DatadogLocalStorageLabel,
+	CapacityLabel:    DatadogLocalStorageCapacityLabel,
+	CountLabel:       DatadogLocalStorageCountLabel,
+	ExistsResource:   DatadogLocalDataExistsResource,
+	CapacityResource: DatadogLocalStorageResource,
+}
+
+// NodeOffers returns true if the node holds a "true" value for that class' label
+func (c *LocalStorageClass) NodeOffers(node *apiv1.Node) bool {
 	if node == nil {
 		return false
 	}
-	value, ok := node.GetLabels()[DatadogLocalStorageLabel]
+	value, ok := node.GetLabels()[c.Label]
 	return ok && value == "true"
 }
 
+// LocalStorageClasses indexes LocalStorageClass by storage class name
+type LocalStorageClasses map[string]*LocalStorageClass
+
+// NewDefaultLocalStorageClasses returns a LocalStorageClasses only holding the "local-data" class
+func NewDefaultLocalStorageClasses() LocalStorageClasses {
+	class := DefaultLocalStorageClass
+	return LocalStorageClasses{class.StorageClassName: &class}
+}
+
+// ParseLocalStorageClasses builds LocalStorageClasses from specs formatted as
+// <storage class>[:<label>[:<capacity label>[:<exists resource>[:<capacity resource>[:<count label>]]]]].
+// Omitted fields are derived from the storage class name, eg. "local-nvme" gets the
+// nodegroups.datadoghq.com/local-nvme, nodegroups.datadoghq.com/local-nvme-capacity and
+// nodegroups.datadoghq.com/local-nvme-count labels, and the storageclass/local-nvme and
+// node.datadoghq.com/local-nvme resources.
+// A bare "local-data" spec keeps its historical labels and resources names.
+// Without any spec, only the default "local-data" class is returned.
+func ParseLocalStorageClasses(specs []string) (LocalStorageClasses, error) {
+	if len(specs) == 0 {
+		return NewDefaultLocalStorageClasses(), nil
+	}
+
+	result := make(LocalStorageClasses)
+	for _, spec := range specs {
+		class, err := parseLocalStorageClass(spec)
+		if err != nil {
+			return nil, err
+		}
+		if _, found := result[class.StorageClassName]; found {
+			return nil, fmt.Errorf("duplicated local storage class %q", class.StorageClassName)
+		}
+		result[class.StorageClassName] = class
+	}
+	return result, nil
+}
+
+func parseLocalStorageClass(spec string) (*LocalStorageClass, error) {
+	fields := strings.Split(spec, ":")
+	if len(fields) > localStorageClassSpecFieldsNb {
+		return nil, fmt.Errorf("invalid local storage class spec %q: too many fields", spec)
+	}
+	for _, field := range fields {
+		if field == "" {
+			return nil, fmt.Errorf("invalid local storage class spec %q: empty field", spec)
+		}
+	}
+
+	name := fields[0]
+	if name == DatadogLocalDataStorageClass && len(fields) == 1 {
+		class := DefaultLocalStorageClass
+		return &class, nil
+	}
+
+	class := &LocalStorageClass{
+		StorageClassName: name,
+		Label:            datadogNodeGroupsLabelPrefix + name,
+		ExistsResource:   apiv1.ResourceName(datadogStorageClassPrefix + name),
+		CapacityResource: apiv1.ResourceName(datadogNodeResourcePrefix + name),
+	}
+	if len(fields) > 1 {
+		class.Label = fields[1]
+	}
+	class.CapacityLabel = class.Label + datadogCapacityLabelSuffix
+	class.CountLabel = class.Label + datadogCountLabelSuffix
+	if len(fields) > 2 {
+		class.CapacityLabel = fields[2]
+	}
+	if len(fields) > 3 {
+		class.ExistsResource = apiv1.ResourceName(fields[3])
+	}
+	if len(fields) > 4 {
+		class.CapacityResource = apiv1.ResourceName(fields[4])
+	}
+	if len(fields) > 5 {
+		class.CountLabel = fields[5]
+	}
+	return class, nil
+}
+
+// OfferedBy returns the local storage classes a node holds a "true" label for
+func (c LocalStorageClasses) OfferedBy(node *apiv1.Node) []*LocalStorageClass {
+	var result []*LocalStorageClass
+	for _, class := range c {
+		if class.NodeOffers(node) {
+			result = append(result, class)
+		}
+	}
+	return result
+}
+
+// NodeHasLocalData returns true if the node holds a local-storage:true label
+func NodeHasLocalData(node *apiv1.Node) bool {
+	return DefaultLocalStorageClass.NodeOffers(node)
+}
+
 // ReducedNodeInfo is a reduced NodeInfo interface mean to requires just what's
 // needed by SetNodeLocalDataResource(), and remain compatible with both
 // k8s.io/kubernetes/pkg/scheduler/framework NodeInfo object and with
@@ -68,24 +211,37 @@ type ReducedNodeInfo interface {
 
 // SetNodeLocalDataResource updates a NodeInfo with the DatadogLocalDataResource resource
 func SetNodeLocalDataResource(nodeInfo ReducedNodeInfo) {
+	SetNodeLocalStorageResource(nodeInfo, &DefaultLocalStorageClass)
+}
+
+// SetNodeLocalStorageResources updates a NodeInfo with the resources of every
+// local storage class the node is labeled as offering.
+func SetNodeLocalStorageResources(nodeInfo ReducedNodeInfo, classes LocalStorageClasses) {
+	for _, class := range classes.OfferedBy(nodeInfo.Node()) {
+		SetNodeLocalStorageResource(nodeInfo, class)
+	}
+}
+
+// SetNodeLocalStorageResource updates a NodeInfo with a local storage class' resources
+func SetNodeLocalStorageResource(nodeInfo ReducedNodeInfo, class *LocalStorageClass) {
 	node := nodeInfo.Node()
 	if node == nil {
 		return
 	}
 
-	if node.Status.Allocatable == nil {
-		node.Status.Allocatable = apiv1.ResourceList{}
-	}
-	if node.Status.Capacity == nil {
-		node.Status.Capacity = apiv1.ResourceList{}
-	}
+	count, storage := NodeLocalStorageCapacity(node, class)
+	setNodeLocalStorage(nodeInfo, class, count, storage, count, storage)
+}
 
-	// Set the local-data resource to 1 unit
-	node.Status.Capacity[DatadogLocalDataExistsResource] = DatadogLocalDataQuantity.DeepCopy()
-	node.Status.Allocatable[DatadogLocalDataExistsResource] = DatadogLocalDataQuantity.DeepCopy()
+// NodeLocalStorageCapacity returns the number of volumes and the amount of storage
+// a node offers for a local storage class, as advertised by its labels.
+func NodeLocalStorageCapacity(node *apiv1.Node, class *LocalStorageClass) (resource.Quantity, resource.Quantity) {
+	// The local-data resource is the number of volumes (1 unit unless a local-storage-count label says otherwise)
+	count := volumesCount(node, class)
 
-	// Set the local-storage resource to the value of the local-storage-capacity label
-	capacity := node.Labels[DatadogLocalStorageCapacityLabel]
+	// The local-storage resource is the value of the local-storage-capacity label
+	// (the total amount of storage, across all that class' volumes)
+	capacity := node.Labels[class.CapacityLabel]
 	capacityResource, err := resource.ParseQuantity(capacity)
 	if err != nil {
 		klog.Warningf("failed to parse local storage capacity information (%s) for node (%s): %v", capacity, node.Name, err)
@@ -93,8 +249,34 @@ func SetNodeLocalDataResource(nodeInfo ReducedNodeInfo) {
 		capacityResource = DatadogLocalDataQuantity.DeepCopy()
 	}
 
-	node.Status.Capacity[DatadogLocalStorageResource] = capacityResource.DeepCopy()
-	node.Status.Allocatable[DatadogLocalStorageResource] = capacityResource.DeepCopy()
+	return count, capacityResource
+}
+
+// SetNodeLocalStorageAllocatable updates a NodeInfo with a local storage class' resources,
+// using labels for capacity and the provided remaining volumes count and storage for allocatable.
+func SetNodeLocalStorageAllocatable(nodeInfo ReducedNodeInfo, class *LocalStorageClass, count, storage resource.Quantity) {
+	node := nodeInfo.Node()
+	if node == nil {
+		return
+	}
+
+	capacityCount, capacityStorage := NodeLocalStorageCapacity(node, class)
+	setNodeLocalStorage(nodeInfo, class, capacityCount, capacityStorage, count, storage)
+}
+
+func setNodeLocalStorage(nodeInfo ReducedNodeInfo, class *LocalStorageClass, capacityCount, capacityStorage, count, storage resource.Quantity) {
+	node := nodeInfo.Node()
+	if node.Status.Allocatable == nil {
+		node.Status.Allocatable = apiv1.ResourceList{}
+	}
+	if node.Status.Capacity == nil {
+		node.Status.Capacity = apiv1.ResourceList{}
+	}
+
+	node.Status.Capacity[class.ExistsResource] = capacityCount.DeepCopy()
+	node.Status.Allocatable[class.ExistsResource] = count.DeepCopy()
+	node.Status.Capacity[class.CapacityResource] = capacityStorage.DeepCopy()
+	node.Status.Allocatable[class.CapacityResource] = storage.DeepCopy()
 
 	// Even though we get a pointer to the original node (and the update above
 	// changes it in place), we still need to call SetNode() in order to trigger
@@ -107,3 +289,17 @@ func SetNodeLocalDataResource(nodeInfo ReducedNodeInfo) {
 	// implementations, because the NodeInfo _interface_ has no RemoveNode() method.
 	nodeInfo.SetNode(node)
 }
+
+func volumesCount(node *apiv1.Node, class *LocalStorageClass) resource.Quantity {
+	value, ok := node.Labels[class.CountLabel]
+	if !ok {
+		return DatadogLocalDataQuantity.DeepCopy()
+	}
+
+	count, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || count < 1 {
+		klog.Warningf("invalid local storage volumes count (%s) for node (%s): %v", value, node.Name, err)
+		return DatadogLocalDataQuantity.DeepCopy()
+	}
+	return *resource.NewQuantity(count, resource.DecimalSI)
+}
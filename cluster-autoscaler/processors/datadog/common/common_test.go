@@ -202,3 +202,153 @@ func TestSetNodeLocalDataResourceWithFaultyLocalStorageCapacity(t *testing.T) {
 
 	assert.Equal(t, len(ni.Pods()), 2)
 }
+
+func TestParseLocalStorageClasses(t *testing.T) {
+	tests := []struct {
+		name     string
+		specs    []string
+		expected LocalStorageClasses
+		wantErr  bool
+	}{
+		{
+			"no spec means local-data class only",
+			nil,
+			LocalStorageClasses{"local-data": &DefaultLocalStorageClass},
+			false,
+		},
+		{
+			"bare local-data spec keeps historical names",
+			[]string{"local-data"},
+			LocalStorageClasses{"local-data": &DefaultLocalStorageClass},
+			false,
+		},
+		{
+			"omitted fields are derived from storage class name",
+			[]string{"local-nvme"},
+			LocalStorageClasses{"local-nvme": {
+				StorageClassName: "local-nvme",
+				Label:            "nodegroups.datadoghq.com/local-nvme",
+				CapacityLabel:    "nodegroups.datadoghq.com/local-nvme-capacity",
+				CountLabel:       "nodegroups.datadoghq.com/local-nvme-count",
+				ExistsResource:   "storageclass/local-nvme",
+				CapacityResource: "node.datadoghq.com/local-nvme",
+			}},
+			false,
+		},
+		{
+			"explicit fields are used",
+			[]string{"local-hdd:spam/hdd:spam/hdd-size:storageclass/hdd:spam/hdd-storage:spam/hdd-volumes"},
+			LocalStorageClasses{"local-hdd": {
+				StorageClassName: "local-hdd",
+				Label:            "spam/hdd",
+				CapacityLabel:    "spam/hdd-size",
+				CountLabel:       "spam/hdd-volumes",
+				ExistsResource:   "storageclass/hdd",
+				CapacityResource: "spam/hdd-storage",
+			}},
+			false,
+		},
+		{
+			"capacity label is derived from an explicit label",
+			[]string{"local-data", "local-tmpfs:spam/tmpfs"},
+			LocalStorageClasses{
+				"local-data": &DefaultLocalStorageClass,
+				"local-tmpfs": {
+					StorageClassName: "local-tmpfs",
+					Label:            "spam/tmpfs",
+					CapacityLabel:    "spam/tmpfs-capacity",
+					CountLabel:       "spam/tmpfs-count",
+					ExistsResource:   "storageclass/local-tmpfs",
+					CapacityResource: "node.datadoghq.com/local-tmpfs",
+				},
+			},
+			false,
+		},
+		{
+			"too many fields is an error",
+			[]string{"a:b:c:d:e:f:g"},
+			nil,
+			true,
+		},
+		{
+			"empty field is an error",
+			[]string{"local-nvme::foo"},
+			nil,
+			true,
+		},
+		{
+			"duplicated class is an error",
+			[]string{"local-nvme", "local-nvme"},
+			nil,
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ParseLocalStorageClasses(tt.specs)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestSetNodeLocalStorageResources(t *testing.T) {
+	classes, err := ParseLocalStorageClasses([]string{"local-data", "local-nvme"})
+	assert.NoError(t, err)
+
+	ni := schedulerframework.NewNodeInfo(&corev1.Node{}, nil)
+	ni.SetNode(&corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				"nodegroups.datadoghq.com/local-nvme":          "true",
+				"nodegroups.datadoghq.com/local-nvme-capacity": "200Gi",
+			},
+		},
+	})
+
+	SetNodeLocalStorageResources(ni, classes)
+
+	allocatable := ni.Node().Status.Allocatable
+	assert.Equal(t, *resource.NewQuantity(1, resource.DecimalSI), allocatable["storageclass/local-nvme"])
+	assert.Equal(t, resource.MustParse("200Gi"), allocatable["node.datadoghq.com/local-nvme"])
+	assert.NotContains(t, allocatable, DatadogLocalDataExistsResource)
+	assert.NotContains(t, allocatable, DatadogLocalStorageResource)
+}
+
+func TestSetNodeLocalDataResourceWithLocalStorageCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		count    string
+		expected int64
+	}{
+		{"count label sets the number of volumes", "4", 4},
+		{"faulty count label falls back to a single volume", "foo", 1},
+		{"zero count label falls back to a single volume", "0", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ni := schedulerframework.NewNodeInfo(&corev1.Node{}, nil)
+			ni.SetNode(&corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						DatadogLocalStorageCapacityLabel: "400Gi",
+						DatadogLocalStorageCountLabel:    tt.count,
+					},
+				},
+			})
+
+			SetNodeLocalDataResource(ni)
+
+			value, ok := ni.Node().Status.Allocatable[DatadogLocalDataExistsResource]
+			assert.True(t, ok)
+			assert.Equal(t, *resource.NewQuantity(tt.expected, resource.DecimalSI), value)
+			assert.Equal(t, resource.MustParse("400Gi"), ni.Node().Status.Allocatable[DatadogLocalStorageResource])
+		})
+	}
+}
@@ -0,0 +1,226 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+  Hybrid template/real-node NodeInfos.
+
+  Templates built from ASG specs only know the instance type capacity: they
+  systematically overestimate allocatable (kubelet and system reservations
+  aren't accounted for), and only guess the DaemonSet pods a new node gets.
+  Upstream's MixedTemplateNodeInfoProvider uses real nodes instead, but that
+  breaks upscale from zero together with balance-similar, and loses our
+  custom resources.
+
+  In hybrid mode we still start from the (sanitized) template, so node groups
+  without nodes keep working as before and templates keep their labels, taints
+  and Datadog local storage resources, but when the group has healthy live nodes:
+  * allocatable and capacity are replaced by the smallest values measured on
+    those nodes (resources unknown to the live nodes, or extended resources
+    they don't advertise yet, are kept);
+  * DaemonSet and mirror pods are the ones actually running on one of those
+    nodes (plus missing DaemonSets pods, as upstream does), and PodTemplate
+    pods are only added when no matching pod already runs there;
+  * overhead reserved by PodTemplates is subtracted again from the measured
+    allocatable (kubelet doesn't know about it).
+*/
+
+package nodeinfosprovider
+
+import (
+	"reflect"
+	"sort"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/autoscaler/cluster-autoscaler/context"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator"
+	schedulerframework "k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/errors"
+	kube_util "k8s.io/autoscaler/cluster-autoscaler/utils/kubernetes"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/taints"
+	klog "k8s.io/klog/v2"
+	v1helper "k8s.io/kubernetes/pkg/apis/core/v1/helper"
+)
+
+const (
+	// liveNodeStabilizationDelay is how long a node must have been ready to be used as a measure
+	liveNodeStabilizationDelay = 1 * time.Minute
+)
+
+// healthyNodesByGroup returns the ready, stable and schedulable nodes, indexed by node group id
+func (p *TemplateOnlyNodeInfoProvider) healthyNodesByGroup(nodes []*apiv1.Node, now time.Time) map[string][]*apiv1.Node {
+	result := make(map[string][]*apiv1.Node)
+	for _, node := range nodes {
+		if !isHealthyLiveNode(node, now) {
+			continue
+		}
+		nodeGroup, err := p.cloudProvider.NodeGroupForNode(node)
+		if err != nil {
+			klog.V(4).Infof("Failed to find node group for %s: %v", node.Name, err)
+			continue
+		}
+		if nodeGroup == nil || reflect.ValueOf(nodeGroup).IsNil() {
+			continue
+		}
+		result[nodeGroup.Id()] = append(result[nodeGroup.Id()], node)
+	}
+	for _, groupNodes := range result {
+		sort.Slice(groupNodes, func(i, j int) bool {
+			return groupNodes[i].Name < groupNodes[j].Name
+		})
+	}
+	return result
+}
+
+// hybridNodeInfo overlays the values measured on live nodes onto a sanitized template NodeInfo
+func (p *TemplateOnlyNodeInfoProvider) hybridNodeInfo(ctx *context.AutoscalingContext, id string, template *schedulerframework.NodeInfo,
+	liveNodes []*apiv1.Node, daemonsets []*appsv1.DaemonSet, taintConfig taints.TaintConfig) (*schedulerframework.NodeInfo, errors.AutoscalerError) {
+	if len(liveNodes) == 0 {
+		return template, nil
+	}
+
+	node := template.Node().DeepCopy()
+	overlayAllocatable(node, liveNodes, localStorageResources(p.storageClasses))
+	result := schedulerframework.NewNodeInfo(node, template.LocalResourceSlices)
+
+	measured := p.measuredNodeInfo(ctx, id, liveNodes, daemonsets, taintConfig)
+	if measured == nil {
+		// no usable live node in the snapshot: keep the pods we guessed for the template
+		for _, podInfo := range template.Pods() {
+			result.AddPod(podInfo)
+		}
+		// and only re-apply the reserved overhead on the measured allocatable (the
+		// template recorded before the overlay remains the drift report baseline)
+		match, err := p.podTemplateProcessor.MatchPodTemplatesForNode(schedulerframework.NewNodeInfo(node, template.LocalResourceSlices), taintConfig)
+		if err != nil {
+			return nil, errors.ToAutoscalerError(errors.InternalError, err)
+		}
+		reserveResources(result, match.Reserved)
+		return result, nil
+	}
+
+	var livePods []*apiv1.Pod
+	for _, podInfo := range measured.Pods() {
+		pod := podInfo.Pod.DeepCopy()
+		pod.Spec.NodeName = node.Name
+		livePods = append(livePods, pod)
+		result.AddPod(&schedulerframework.PodInfo{Pod: pod, PodExtraInfo: podInfo.PodExtraInfo})
+	}
+
+	match, err := p.podTemplateProcessor.MatchPodTemplatesForNode(result, taintConfig)
+	if err != nil {
+		return nil, errors.ToAutoscalerError(errors.InternalError, err)
+	}
+	for _, pod := range match.Pods {
+		if runsMatchingPod(pod, livePods) {
+			continue
+		}
+		result.AddPod(&schedulerframework.PodInfo{Pod: pod})
+	}
+	reserveResources(result, match.Reserved)
+	if p.explanations != nil {
+		p.explanations[id] = match.Explanations
+	}
+
+	return result, nil
+}
+
+// measuredNodeInfo returns a sanitized NodeInfo built from the first live node found in the cluster snapshot
+func (p *TemplateOnlyNodeInfoProvider) measuredNodeInfo(ctx *context.AutoscalingContext, id string, liveNodes []*apiv1.Node,
+	daemonsets []*appsv1.DaemonSet, taintConfig taints.TaintConfig) *schedulerframework.NodeInfo {
+	if ctx.ClusterSnapshot == nil {
+		return nil
+	}
+	for _, node := range liveNodes {
+		nodeInfo, err := ctx.ClusterSnapshot.GetNodeInfo(node.Name)
+		if err != nil {
+			continue
+		}
+		measured, caErr := simulator.SanitizedTemplateNodeInfoFromNodeInfo(nodeInfo, id, daemonsets, p.forceDaemonSets, taintConfig)
+		if caErr != nil {
+			klog.Warningf("Failed to build NodeInfo for %s from live node %s: %v", id, node.Name, caErr)
+			continue
+		}
+		return measured
+	}
+	return nil
+}
+
+// overlayAllocatable replaces the node allocatable and capacity with the smallest values found on live nodes
+func overlayAllocatable(node *apiv1.Node, liveNodes []*apiv1.Node, ignoredResources map[apiv1.ResourceName]bool) {
+	allocatable := minResources(liveNodes, func(n *apiv1.Node) apiv1.ResourceList { return n.Status.Allocatable })
+	capacity := minResources(liveNodes, func(n *apiv1.Node) apiv1.ResourceList { return n.Status.Capacity })
+
+	if node.Status.Allocatable == nil {
+		node.Status.Allocatable = apiv1.ResourceList{}
+	}
+	if node.Status.Capacity == nil {
+		node.Status.Capacity = apiv1.ResourceList{}
+	}
+	for name, quantity := range allocatable {
+		if !ignoredResources[name] {
+			node.Status.Allocatable[name] = quantity
+		}
+	}
+	for name, quantity := range capacity {
+		if !ignoredResources[name] {
+			node.Status.Capacity[name] = quantity
+		}
+	}
+}
+
+// minResources returns the smallest values found on ready nodes. Extended resources (eg. GPUs) are
+// advertised as zero until their device plugin is up, so zero values don't count for those.
+func minResources(nodes []*apiv1.Node, resources func(*apiv1.Node) apiv1.ResourceList) apiv1.ResourceList {
+	result := apiv1.ResourceList{}
+	for _, node := range nodes {
+		if ready, _, _ := kube_util.GetReadinessState(node); !ready {
+			continue
+		}
+		for name, quantity := range resources(node) {
+			if quantity.IsZero() && v1helper.IsExtendedResourceName(name) {
+				continue
+			}
+			if current, found := result[name]; !found || quantity.Cmp(current) < 0 {
+				result[name] = quantity.DeepCopy()
+			}
+		}
+	}
+	return result
+}
+
+// runsMatchingPod tells if a pod matching the PodTemplate generated pod (same namespace and labels) already runs on the node
+func runsMatchingPod(pod *apiv1.Pod, livePods []*apiv1.Pod) bool {
+	if len(pod.Labels) == 0 {
+		return false
+	}
+	selector := labels.SelectorFromSet(pod.Labels)
+	for _, livePod := range livePods {
+		if livePod.Namespace == pod.Namespace && selector.Matches(labels.Set(livePod.Labels)) {
+			return true
+		}
+	}
+	return false
+}
+
+// isHealthyLiveNode tells if a node can be trusted as a measure of its node group (as upstream's isNodeGoodTemplateCandidate)
+func isHealthyLiveNode(node *apiv1.Node, now time.Time) bool {
+	ready, lastTransitionTime, _ := kube_util.GetReadinessState(node)
+	stable := lastTransitionTime.Add(liveNodeStabilizationDelay).Before(now)
+	return ready && stable && !node.Spec.Unschedulable && !taints.HasToBeDeletedTaint(node)
+}
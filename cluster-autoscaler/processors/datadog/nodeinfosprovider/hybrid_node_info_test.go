@@ -0,0 +1,134 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeinfosprovider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	testprovider "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
+	"k8s.io/autoscaler/cluster-autoscaler/context"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/datadog/common"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/clustersnapshot/testsnapshot"
+	schedulerframework "k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/gpu"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/taints"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+)
+
+func TestTemplateOnlyNodeInfoProviderHybrid(t *testing.T) {
+	now := time.Now()
+
+	tni := schedulerframework.NewNodeInfo(nil, nil)
+	tn := BuildTestNode("tn", 4000, 16000)
+	tn.SetLabels(map[string]string{"team": "foo", common.DatadogLocalStorageLabel: "true"})
+	tni.SetNode(tn)
+
+	provider := testprovider.NewTestCloudProviderBuilder().
+		WithMachineTemplates(map[string]*schedulerframework.NodeInfo{"ng1": tni, "ng2": tni}).
+		Build()
+	provider.AddNodeGroup("ng1", 1, 10, 2)
+	provider.AddNodeGroup("ng2", 0, 10, 0)
+
+	ready1 := BuildTestNode("ready1", 3800, 14000)
+	SetNodeReadyState(ready1, true, now.Add(-2*time.Minute))
+	ready2 := BuildTestNode("ready2", 3900, 13000)
+	SetNodeReadyState(ready2, true, now.Add(-2*time.Minute))
+	justReady := BuildTestNode("justReady", 1000, 1000)
+	SetNodeReadyState(justReady, true, now)
+	provider.AddNode("ng1", ready1)
+	provider.AddNode("ng1", ready2)
+	provider.AddNode("ng1", justReady)
+
+	dsPod := BuildTestPod("ds-pod", 100, 200, WithDSController())
+	dsPod.Spec.NodeName = "ready1"
+	workload := BuildTestPod("workload", 1000, 1000)
+	workload.Spec.NodeName = "ready1"
+
+	nodes := []*apiv1.Node{ready1, ready2, justReady}
+	snapshot := testsnapshot.NewTestSnapshotOrDie(t)
+	assert.NoError(t, snapshot.SetClusterState(nodes, []*apiv1.Pod{dsPod, workload}, nil))
+
+	ctx := &context.AutoscalingContext{
+		CloudProvider:   provider,
+		ClusterSnapshot: snapshot,
+	}
+
+	ttl := 5 * time.Minute
+	processor := NewTemplateOnlyNodeInfoProvider(&ttl, false, true, common.NewDefaultLocalStorageClasses(), nil)
+	res, err := processor.Process(ctx, nodes, []*appsv1.DaemonSet{}, taints.TaintConfig{}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(res))
+
+	// node groups with healthy nodes get the smallest measured allocatable, and their actual DaemonSet pods
+	node := res["ng1"].Node()
+	assert.Equal(t, int64(3800), node.Status.Allocatable.Cpu().MilliValue())
+	assert.Equal(t, int64(13000), node.Status.Allocatable.Memory().Value())
+	assert.Equal(t, "foo", node.Labels["team"])
+	assert.Contains(t, node.Status.Allocatable, common.DatadogLocalDataExistsResource)
+	assert.Equal(t, 1, len(res["ng1"].Pods()))
+	assert.Equal(t, node.Name, res["ng1"].Pods()[0].Spec.NodeName)
+	assert.Contains(t, res["ng1"].Pods()[0].Name, "ds-pod")
+
+	// node groups without nodes keep their template
+	assert.Equal(t, int64(4000), res["ng2"].Node().Status.Allocatable.Cpu().MilliValue())
+	assert.Equal(t, int64(16000), res["ng2"].Node().Status.Allocatable.Memory().Value())
+	assert.Empty(t, res["ng2"].Pods())
+}
+
+func TestRunsMatchingPod(t *testing.T) {
+	tplPod := BuildTestPod("agent-pod", 100, 100)
+	tplPod.Labels = map[string]string{"app": "agent"}
+
+	agent := BuildTestPod("agent-xyz", 100, 100)
+	agent.Labels = map[string]string{"app": "agent", "pod-template-hash": "abc"}
+	other := BuildTestPod("other", 100, 100)
+	other.Labels = map[string]string{"app": "other"}
+	elsewhere := agent.DeepCopy()
+	elsewhere.Namespace = "elsewhere"
+
+	assert.True(t, runsMatchingPod(tplPod, []*apiv1.Pod{other, agent}))
+	assert.False(t, runsMatchingPod(tplPod, []*apiv1.Pod{other, elsewhere}))
+	tplPod.Labels = nil
+	assert.False(t, runsMatchingPod(tplPod, []*apiv1.Pod{agent}))
+}
+
+func TestOverlayAllocatable(t *testing.T) {
+	now := time.Now()
+	template := BuildTestNode("template", 4000, 16000)
+	template.Status.Allocatable[gpu.ResourceNvidiaGPU] = *resource.NewQuantity(4, resource.DecimalSI)
+
+	ready := BuildTestNode("ready", 3800, 14000)
+	SetNodeReadyState(ready, true, now.Add(-2*time.Minute))
+	ready.Status.Allocatable[gpu.ResourceNvidiaGPU] = *resource.NewQuantity(0, resource.DecimalSI)
+	notReady := BuildTestNode("notReady", 1000, 1000)
+	SetNodeReadyState(notReady, false, now.Add(-2*time.Minute))
+
+	overlayAllocatable(template, []*apiv1.Node{ready, notReady}, nil)
+
+	// unready nodes aren't measured
+	assert.Equal(t, int64(3800), template.Status.Allocatable.Cpu().MilliValue())
+	assert.Equal(t, int64(14000), template.Status.Allocatable.Memory().Value())
+
+	// extended resources not advertised yet by live nodes keep the template value
+	gpus := template.Status.Allocatable[gpu.ResourceNvidiaGPU]
+	assert.Equal(t, int64(4), gpus.Value())
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeinfosprovider
+
+import (
+	"time"
+
+	k8smetrics "k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+const (
+	caNamespace = "cluster_autoscaler"
+
+	cacheHit  = "hit"
+	cacheMiss = "miss"
+
+	driftAllocatable = "allocatable"
+	driftLabels      = "labels"
+	driftTaints      = "taints"
+)
+
+var (
+	/**** Metrics related to the template NodeInfos cache ****/
+	templateCacheAge = k8smetrics.NewGaugeVec(
+		&k8smetrics.GaugeOpts{
+			Namespace: caNamespace,
+			Name:      "template_node_info_cache_age_seconds",
+			Help:      "Age of the cached template NodeInfo, per node group, in seconds.",
+		}, []string{"node_group"},
+	)
+
+	templateCacheRequests = k8smetrics.NewCounterVec(
+		&k8smetrics.CounterOpts{
+			Namespace: caNamespace,
+			Name:      "template_node_info_cache_requests_total",
+			Help:      "Number of template NodeInfo requests, per node group and result (hit or miss).",
+		}, []string{"node_group", "result"},
+	)
+
+	templateRefreshErrors = k8smetrics.NewCounterVec(
+		&k8smetrics.CounterOpts{
+			Namespace: caNamespace,
+			Name:      "template_node_info_refresh_errors_total",
+			Help:      "Number of failures to build a template NodeInfo, per node group.",
+		}, []string{"node_group"},
+	)
+
+	/**** Metrics related to templates drift from live nodes ****/
+	templateAllocatableDrift = k8smetrics.NewGaugeVec(
+		&k8smetrics.GaugeOpts{
+			Namespace: caNamespace,
+			Name:      "template_node_info_allocatable_drift_ratio",
+			Help:      "Mean relative difference between template and live nodes allocatable, per node group and resource. Positive when the template overestimates the nodes.",
+		}, []string{"node_group", "resource"},
+	)
+
+	templateDriftingNodes = k8smetrics.NewGaugeVec(
+		&k8smetrics.GaugeOpts{
+			Namespace: caNamespace,
+			Name:      "template_node_info_drifting_nodes",
+			Help:      "Number of live nodes differing from their node group template, per node group and field (allocatable, labels or taints).",
+		}, []string{"node_group", "field"},
+	)
+)
+
+// RegisterMetrics registers all template NodeInfo provider metrics.
+func RegisterMetrics() {
+	legacyregistry.MustRegister(templateCacheAge)
+	legacyregistry.MustRegister(templateCacheRequests)
+	legacyregistry.MustRegister(templateRefreshErrors)
+	legacyregistry.MustRegister(templateAllocatableDrift)
+	legacyregistry.MustRegister(templateDriftingNodes)
+}
+
+func observeTemplateCacheHit(nodeGroup string, age time.Duration) {
+	templateCacheRequests.WithLabelValues(nodeGroup, cacheHit).Inc()
+	templateCacheAge.WithLabelValues(nodeGroup).Set(age.Seconds())
+}
+
+func observeTemplateCacheMiss(nodeGroup string) {
+	templateCacheRequests.WithLabelValues(nodeGroup, cacheMiss).Inc()
+	templateCacheAge.WithLabelValues(nodeGroup).Set(0)
+}
+
+func registerTemplateRefreshError(nodeGroup string) {
+	templateRefreshErrors.WithLabelValues(nodeGroup).Inc()
+}
+
+// updateTemplateDrift replaces the exposed drift metrics with the ones from a new report
+func updateTemplateDrift(drifts map[string]*templateDrift) {
+	templateAllocatableDrift.Reset()
+	templateDriftingNodes.Reset()
+	for nodeGroup, drift := range drifts {
+		for name, ratio := range drift.allocatable {
+			templateAllocatableDrift.WithLabelValues(nodeGroup, string(name)).Set(ratio)
+		}
+		for _, field := range []string{driftAllocatable, driftLabels, driftTaints} {
+			templateDriftingNodes.WithLabelValues(nodeGroup, field).Set(float64(drift.driftingNodes[field]))
+		}
+	}
+}
@@ -36,10 +36,12 @@ const (
 	PodTemplateDaemonSetLabelKey = "cluster-autoscaler.kubernetes.io/daemonset-pod"
 	// PodTemplateDaemonSetLabelValueTrue use as PodTemplateDaemonSetLabelKey label value.
 	PodTemplateDaemonSetLabelValueTrue = "true"
+	// PodTemplateReservedResourcesAnnotationKey declares the PodTemplate as a reserved overhead (eg. "cpu=100m,memory=256Mi") rather than a pod.
+	PodTemplateReservedResourcesAnnotationKey = "cluster-autoscaler.kubernetes.io/reserved-resources"
 )
 
-// newPodTemplateLister instantiate a new Lister for PodTemplate
-func newPodTemplateLister(kubeClient client.Interface, stopchannel <-chan struct{}) v1lister.PodTemplateLister {
+// newPodTemplateLister instantiate a new Lister for PodTemplate, and a function telling if PodTemplates were listed once
+func newPodTemplateLister(kubeClient client.Interface, stopchannel <-chan struct{}) (v1lister.PodTemplateLister, cache.InformerSynced) {
 	podTemplateWatchOption := func(options *metav1.ListOptions) {
 		options.FieldSelector = fields.Everything().String()
 		options.LabelSelector = labels.SelectorFromSet(getDaemonsetPodTemplateLabelSet()).String()
@@ -48,7 +50,7 @@ func newPodTemplateLister(kubeClient client.Interface, stopchannel <-chan struct
 	store, reflector := cache.NewNamespaceKeyedIndexerAndReflector(listWatcher, &apiv1.PodTemplate{}, time.Hour)
 	lister := v1lister.NewPodTemplateLister(store)
 	go reflector.Run(stopchannel)
-	return lister
+	return lister, func() bool { return reflector.LastSyncResourceVersion() != "" }
 }
 
 // getDaemonsetPodTemplateLabelSet returns the labels.Set corresponding to the Daemonset PodTemplate
@@ -14,15 +14,40 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
+/*
+  PodTemplates selection model.
+
+  PodTemplates labeled cluster-autoscaler.kubernetes.io/daemonset-pod=true describe
+  per-node workloads that aren't managed by DaemonSets (eg. the datadog-agent
+  ExtendedDaemonSet pods), and that every new node they match will run.
+
+  A PodTemplate matches a template node when its pod spec passes the scheduler
+  predicates against that node, exactly as DaemonSet pods do: the template's
+  spec.nodeSelector and node affinity are label selectors on the node, and its
+  spec.tolerations must tolerate the node taints (resources must fit too).
+
+  A matching PodTemplate annotated with cluster-autoscaler.kubernetes.io/reserved-resources
+  (eg. "cpu=100m,memory=256Mi") declares overhead rather than a pod: the listed
+  resources are subtracted from the template node allocatable, and no pod is added.
+  This is meant for agent sidecars, node-local caches, and other consumers we
+  can't (or don't want to) model as pods.
+
+  Every decision is explained, so it can be exposed in the debugging snapshot.
+*/
+
 package podtemplate
 
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	apiv1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
 
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/labels"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 
@@ -36,8 +61,12 @@ import (
 	schedulerframework "k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
 
 	v1lister "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
 )
 
+// podTemplatesSyncTimeout is how long we wait for PodTemplates to be listed at start
+const podTemplatesSyncTimeout = 30 * time.Second
+
 // NewPodTemplateProcessor returns a default instance of NodeInfoProcessor.
 func NewPodTemplateProcessor(opts *core.AutoscalerOptions) Interface {
 	if opts == nil || !opts.NodeInfosProcessorPodTemplates {
@@ -45,20 +74,40 @@ func NewPodTemplateProcessor(opts *core.AutoscalerOptions) Interface {
 	}
 
 	internalContext, cancelFunc := context.WithCancel(context.Background())
+	lister, synced := newPodTemplateLister(opts.KubeClient, internalContext.Done())
+
+	// don't build templates without their PodTemplates on first use (eg. in one-shot validations)
+	syncContext, syncCancel := context.WithTimeout(internalContext, podTemplatesSyncTimeout)
+	defer syncCancel()
+	if !cache.WaitForCacheSync(syncContext.Done(), synced) {
+		klog.Warningf("PodTemplates weren't listed within %v, templates won't account for them until they are", podTemplatesSyncTimeout)
+	}
+
 	return &podTemplateProcessor{
 		ctx:               internalContext,
 		opts:              opts,
 		cancelFunc:        cancelFunc,
-		podTemplateLister: newPodTemplateLister(opts.KubeClient, internalContext.Done()),
+		podTemplateLister: lister,
 	}
 }
 
 // Interface define the PodTemplateProcess interface to allow having a several implementation depending on the core.AutoscalerOptions.
 type Interface interface {
 	GetDaemonSetPodsFromPodTemplateForNode(baseNodeInfo *schedulerframework.NodeInfo, taintConfig taints.TaintConfig) ([]*apiv1.Pod, error)
+	MatchPodTemplatesForNode(baseNodeInfo *schedulerframework.NodeInfo, taintConfig taints.TaintConfig) (*NodeMatch, error)
 	CleanUp()
 }
 
+// NodeMatch is the outcome of matching PodTemplates against a template node.
+type NodeMatch struct {
+	// Pods are the pods expected on the node
+	Pods []*apiv1.Pod
+	// Reserved is the overhead to subtract from the node allocatable
+	Reserved apiv1.ResourceList
+	// Explanations describe why each PodTemplate was, or wasn't, applied to the node
+	Explanations []string
+}
+
 // podTemplateProcessor add possible PodTemplates in nodeInfos.
 type podTemplateProcessor struct {
 	podTemplateLister v1lister.PodTemplateLister
@@ -70,6 +119,15 @@ type podTemplateProcessor struct {
 
 // GetDaemonSetPodsFromPodTemplateForNode return a list of apiv1.Pod from PodTemplate present in the cluster.
 func (p *podTemplateProcessor) GetDaemonSetPodsFromPodTemplateForNode(baseNodeInfo *schedulerframework.NodeInfo, taintConfig taints.TaintConfig) ([]*apiv1.Pod, error) {
+	match, err := p.MatchPodTemplatesForNode(baseNodeInfo, taintConfig)
+	if match == nil {
+		return nil, err
+	}
+	return match.Pods, err
+}
+
+// MatchPodTemplatesForNode returns the pods and reserved overhead PodTemplates present in the cluster add to a node.
+func (p *podTemplateProcessor) MatchPodTemplatesForNode(baseNodeInfo *schedulerframework.NodeInfo, taintConfig taints.TaintConfig) (*NodeMatch, error) {
 	// retrieve only once the podTemplates list.
 	// This list will be used for each NodeGroup.
 	podTemplates, err := p.podTemplateLister.List(labels.Everything())
@@ -77,10 +135,10 @@ func (p *podTemplateProcessor) GetDaemonSetPodsFromPodTemplateForNode(baseNodeIn
 		return nil, errors.ToAutoscalerError(errors.InternalError, err)
 	}
 	if len(podTemplates) == 0 {
-		return nil, nil
+		return &NodeMatch{}, nil
 	}
 
-	var result []*apiv1.Pod
+	result := &NodeMatch{}
 
 	// here we can use empty snapshot
 	clusterSnapshot := predicate.NewPredicateSnapshot(store.NewBasicSnapshotStore(),
@@ -92,25 +150,98 @@ func (p *podTemplateProcessor) GetDaemonSetPodsFromPodTemplateForNode(baseNodeIn
 		return nil, fmt.Errorf("podTemplateProcessor failed to AddNodeInfo: %w", err)
 	}
 
+	// keep the explanations stable across loops
+	sort.Slice(podTemplates, func(i, j int) bool {
+		return podTemplates[i].Namespace+"/"+podTemplates[i].Name < podTemplates[j].Namespace+"/"+podTemplates[j].Name
+	})
+
 	var errs []error
 	for _, podTpl := range podTemplates {
+		name := podTpl.Namespace + "/" + podTpl.Name
+
+		var reserved apiv1.ResourceList
+		if value, found := podTpl.GetAnnotations()[PodTemplateReservedResourcesAnnotationKey]; found {
+			reserved, err = parseReservedResources(value)
+			if err != nil {
+				klog.Warningf("ignoring PodTemplate %s: %v", name, err)
+				result.Explanations = append(result.Explanations, fmt.Sprintf("%s: ignored, invalid %s annotation: %v", name, PodTemplateReservedResourcesAnnotationKey, err))
+				continue
+			}
+		}
+
 		pod := newPod(podTpl, baseNodeInfo.Node().Name)
 		// err := clusterSnapshot.SchedulePod(pod, baseNodeInfo.Node().Name)
 		err := clusterSnapshot.CheckPredicates(pod, baseNodeInfo.Node().Name)
 		if err == nil {
-			result = append(result, pod)
+			if reserved != nil {
+				result.Reserved = addResources(result.Reserved, reserved)
+				result.Explanations = append(result.Explanations, fmt.Sprintf("%s: reserved %s", name, formatResources(reserved)))
+			} else {
+				result.Pods = append(result.Pods, pod)
+				result.Explanations = append(result.Explanations, fmt.Sprintf("%s: added pod %s", name, pod.Name))
+			}
 		} else if err.Type() != clustersnapshot.SchedulingInternalError {
 			// ok; we are just skipping this daemonset
+			result.Explanations = append(result.Explanations, fmt.Sprintf("%s: not matching, %s", name, strings.Join(err.Reasons(), ", ")))
 		} else {
 			// unexpected error
 			errs = append(errs, fmt.Errorf("unexpected error while calling PredicateChecker; %v", err))
+			result.Explanations = append(result.Explanations, fmt.Sprintf("%s: predicates error, %v", name, err))
 		}
 	}
-	klog.V(6).Infof("the podTemplateProcessor has created %d from PodTemplate(s) for the Node %s", len(result), baseNodeInfo.Node().GetName())
+	klog.V(6).Infof("the podTemplateProcessor has created %d from PodTemplate(s) for the Node %s", len(result.Pods), baseNodeInfo.Node().GetName())
 
 	return result, utilerrors.NewAggregate(errs)
 }
 
+// parseReservedResources parses a "<resource>=<quantity>[,<resource>=<quantity>...]" list
+func parseReservedResources(value string) (apiv1.ResourceList, error) {
+	result := apiv1.ResourceList{}
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		parts := strings.SplitN(item, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("expected <resource>=<quantity>, got %q", item)
+		}
+		quantity, err := resource.ParseQuantity(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid quantity for %s: %v", parts[0], err)
+		}
+		if quantity.Sign() < 0 {
+			return nil, fmt.Errorf("negative quantity for %s", parts[0])
+		}
+		result[apiv1.ResourceName(strings.TrimSpace(parts[0]))] = quantity
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("no resource listed")
+	}
+	return result, nil
+}
+
+func addResources(total, added apiv1.ResourceList) apiv1.ResourceList {
+	if total == nil {
+		total = apiv1.ResourceList{}
+	}
+	for name, quantity := range added {
+		current := total[name]
+		current.Add(quantity)
+		total[name] = current
+	}
+	return total
+}
+
+func formatResources(resources apiv1.ResourceList) string {
+	var items []string
+	for name, quantity := range resources {
+		items = append(items, fmt.Sprintf("%s=%s", name, quantity.String()))
+	}
+	sort.Strings(items)
+	return strings.Join(items, ",")
+}
+
 func newPod(pt *apiv1.PodTemplate, nodeName string) *apiv1.Pod {
 	newPod := &apiv1.Pod{Spec: pt.Template.Spec, ObjectMeta: pt.Template.ObjectMeta}
 	newPod.Namespace = pt.Namespace
@@ -131,5 +262,9 @@ func (p *dummyPodTemplateProcessor) GetDaemonSetPodsFromPodTemplateForNode(baseN
 	return nil, nil
 }
 
+func (p *dummyPodTemplateProcessor) MatchPodTemplatesForNode(baseNodeInfo *schedulerframework.NodeInfo, taintConfig taints.TaintConfig) (*NodeMatch, error) {
+	return &NodeMatch{}, nil
+}
+
 func (p *dummyPodTemplateProcessor) CleanUp() {
 }
@@ -128,6 +128,66 @@ func Test_podTemplateProcessor_GetDaemonSetPodsFromPodTemplateForNode(t *testing
 	}
 }
 
+func Test_podTemplateProcessor_MatchPodTemplatesForNode(t *testing.T) {
+	nodeName := "template-node-template-for-node-1"
+	nodeInfo := newNodeInfo(nodeName, 42)
+	node := nodeInfo.Node().DeepCopy()
+	node.Labels["team"] = "foo"
+	node.Spec.Taints = []apiv1.Taint{{Key: "dedicated", Value: "foo", Effect: apiv1.TaintEffectNoSchedule}}
+	nodeInfo.SetNode(node)
+
+	tolerations := []apiv1.Toleration{{Key: "dedicated", Operator: apiv1.TolerationOpExists}}
+	matching := newPodTemplate("bar", "matching", &apiv1.PodTemplateSpec{Spec: apiv1.PodSpec{
+		NodeSelector: map[string]string{"team": "foo"},
+		Tolerations:  tolerations,
+	}})
+	otherTeam := newPodTemplate("bar", "other-team", &apiv1.PodTemplateSpec{Spec: apiv1.PodSpec{
+		NodeSelector: map[string]string{"team": "bar"},
+		Tolerations:  tolerations,
+	}})
+	intolerant := newPodTemplate("bar", "intolerant", nil)
+	reserved := newPodTemplate("bar", "reserved", &apiv1.PodTemplateSpec{Spec: apiv1.PodSpec{Tolerations: tolerations}})
+	reserved.Annotations = map[string]string{PodTemplateReservedResourcesAnnotationKey: "cpu=100m, memory=256Mi"}
+	reservedOtherTeam := newPodTemplate("bar", "reserved-other-team", otherTeam.Template.DeepCopy())
+	reservedOtherTeam.Annotations = map[string]string{PodTemplateReservedResourcesAnnotationKey: "memory=1Gi"}
+	invalid := newPodTemplate("bar", "invalid", &apiv1.PodTemplateSpec{Spec: apiv1.PodSpec{Tolerations: tolerations}})
+	invalid.Annotations = map[string]string{PodTemplateReservedResourcesAnnotationKey: "memory"}
+
+	lister, err := newTestDaemonSetLister([]*apiv1.PodTemplate{matching, otherTeam, intolerant, reserved, reservedOtherTeam, invalid})
+	assert.NoError(t, err)
+	fwHandle, err := schedulerframework.NewTestFrameworkHandle()
+	assert.NoError(t, err)
+	p := &podTemplateProcessor{
+		podTemplateLister: lister,
+		opts: &core.AutoscalerOptions{
+			FrameworkHandle: fwHandle,
+		},
+	}
+
+	match, err := p.MatchPodTemplatesForNode(nodeInfo, taints.TaintConfig{})
+	assert.NoError(t, err)
+	assert.EqualValues(t, []*apiv1.Pod{newPod(matching, nodeName)}, match.Pods)
+	assert.Equal(t, "cpu=100m,memory=256Mi", formatResources(match.Reserved))
+	assert.Equal(t, 6, len(match.Explanations))
+	assert.Contains(t, match.Explanations, "bar/matching: added pod matching-pod")
+	assert.Contains(t, match.Explanations, "bar/reserved: reserved cpu=100m,memory=256Mi")
+	assert.Contains(t, match.Explanations[0], "bar/intolerant: not matching")
+}
+
+func Test_parseReservedResources(t *testing.T) {
+	reserved, err := parseReservedResources("cpu=1, memory=1Gi,")
+	assert.NoError(t, err)
+	assert.Equal(t, apiv1.ResourceList{
+		apiv1.ResourceCPU:    resource.MustParse("1"),
+		apiv1.ResourceMemory: resource.MustParse("1Gi"),
+	}, reserved)
+
+	for _, value := range []string{"", "cpu", "=1", "cpu=spam", "cpu=-1"} {
+		_, err = parseReservedResources(value)
+		assert.Error(t, err, value)
+	}
+}
+
 func newTestDaemonSetLister(pts []*apiv1.PodTemplate) (v1lister.PodTemplateLister, error) {
 	store := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
 	for _, pt := range pts {
@@ -0,0 +1,198 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+  Template drift report: compares node groups templates (the NodeInfos we
+  build from ASG specs, and use to decide what an upscale would provide) with
+  the live nodes of those groups.
+
+  A template advertising more allocatable, or other labels or taints, than
+  what the real nodes end up having causes wrong or useless upscales (pods
+  triggering an upscale but not fitting on the new node). Such drifts are
+  exposed as metrics and reported as events on the status configmap.
+
+  Only meaningful fields are compared: per-node labels (hostname, zone, ...),
+  transient taints, and the Datadog local storage resources (which only exist
+  on templates) are ignored.
+*/
+
+package nodeinfosprovider
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/datadog/common"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/nodegroupset"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/taints"
+)
+
+const (
+	// allocatableDriftTolerance is the relative allocatable difference above which a node is considered drifting
+	allocatableDriftTolerance = 0.05
+)
+
+// templateDrift describes how a node group template differs from the group's live nodes
+type templateDrift struct {
+	// nodes is the number of live nodes compared with the template
+	nodes int
+	// allocatable holds the mean relative difference (template - live) / live, per resource
+	allocatable map[apiv1.ResourceName]float64
+	// labels counts live nodes not having the template value, per template label
+	labels map[string]int
+	// taints counts live nodes where the taint is missing (template only) or unexpected (live only)
+	taints map[string]int
+	// driftingNodes counts live nodes differing from the template, per field
+	driftingNodes map[string]int
+}
+
+// computeTemplateDrift compares a template node with live nodes from the same node group
+func computeTemplateDrift(template *apiv1.Node, nodes []*apiv1.Node, ignoredResources map[apiv1.ResourceName]bool, taintConfig taints.TaintConfig) *templateDrift {
+	drift := &templateDrift{
+		nodes:         len(nodes),
+		allocatable:   make(map[apiv1.ResourceName]float64),
+		labels:        make(map[string]int),
+		taints:        make(map[string]int),
+		driftingNodes: make(map[string]int),
+	}
+
+	templateTaints := taintsSet(taints.SanitizeTaints(template.Spec.Taints, taintConfig))
+	compared := make(map[apiv1.ResourceName]int)
+
+	for _, node := range nodes {
+		allocatableDrifts := false
+		for name, templateQuantity := range template.Status.Allocatable {
+			if ignoredResources[name] {
+				continue
+			}
+			liveQuantity, found := node.Status.Allocatable[name]
+			if !found || liveQuantity.IsZero() {
+				continue
+			}
+			ratio := (templateQuantity.AsApproximateFloat64() - liveQuantity.AsApproximateFloat64()) / liveQuantity.AsApproximateFloat64()
+			drift.allocatable[name] += ratio
+			compared[name]++
+			if ratio > allocatableDriftTolerance || ratio < -allocatableDriftTolerance {
+				allocatableDrifts = true
+			}
+		}
+		if allocatableDrifts {
+			drift.driftingNodes[driftAllocatable]++
+		}
+
+		labelsDrift := false
+		for key, value := range template.GetLabels() {
+			if nodegroupset.BasicIgnoredLabels[key] {
+				continue
+			}
+			if liveValue, found := node.GetLabels()[key]; !found || liveValue != value {
+				drift.labels[key]++
+				labelsDrift = true
+			}
+		}
+		if labelsDrift {
+			drift.driftingNodes[driftLabels]++
+		}
+
+		taintsDrift := false
+		liveTaints := taintsSet(taints.SanitizeTaints(node.Spec.Taints, taintConfig))
+		for taint := range templateTaints {
+			if !liveTaints[taint] {
+				drift.taints[taint+" (template only)"]++
+				taintsDrift = true
+			}
+		}
+		for taint := range liveTaints {
+			if !templateTaints[taint] {
+				drift.taints[taint+" (live only)"]++
+				taintsDrift = true
+			}
+		}
+		if taintsDrift {
+			drift.driftingNodes[driftTaints]++
+		}
+	}
+
+	for name, count := range compared {
+		drift.allocatable[name] /= float64(count)
+	}
+
+	return drift
+}
+
+// drifting tells if any of the live nodes differs from the template
+func (d *templateDrift) drifting() bool {
+	for _, count := range d.driftingNodes {
+		if count > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns a human readable summary of the drift
+func (d *templateDrift) String() string {
+	var details []string
+
+	var resources []string
+	for name, ratio := range d.allocatable {
+		if ratio > allocatableDriftTolerance || ratio < -allocatableDriftTolerance {
+			resources = append(resources, fmt.Sprintf("%s %+.1f%%", name, ratio*100))
+		}
+	}
+	if len(resources) > 0 {
+		sort.Strings(resources)
+		details = append(details, "allocatable: "+strings.Join(resources, ", "))
+	}
+
+	if labels := d.countsSummary(d.labels); labels != "" {
+		details = append(details, "labels: "+labels)
+	}
+	if taints := d.countsSummary(d.taints); taints != "" {
+		details = append(details, "taints: "+taints)
+	}
+
+	return strings.Join(details, "; ")
+}
+
+func (d *templateDrift) countsSummary(counts map[string]int) string {
+	var items []string
+	for key, count := range counts {
+		items = append(items, fmt.Sprintf("%s on %d/%d nodes", key, count, d.nodes))
+	}
+	sort.Strings(items)
+	return strings.Join(items, ", ")
+}
+
+// localStorageResources returns the custom resources only carried by templates
+func localStorageResources(classes common.LocalStorageClasses) map[apiv1.ResourceName]bool {
+	resources := make(map[apiv1.ResourceName]bool)
+	for _, class := range classes {
+		resources[class.ExistsResource] = true
+		resources[class.CapacityResource] = true
+	}
+	return resources
+}
+
+func taintsSet(nodeTaints []apiv1.Taint) map[string]bool {
+	set := make(map[string]bool)
+	for _, taint := range nodeTaints {
+		set[fmt.Sprintf("%s=%s:%s", taint.Key, taint.Value, taint.Effect)] = true
+	}
+	return set
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeinfosprovider
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	testprovider "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
+	"k8s.io/autoscaler/cluster-autoscaler/clusterstate/utils"
+	"k8s.io/autoscaler/cluster-autoscaler/context"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/datadog/common"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/clustersnapshot/testsnapshot"
+	schedulerframework "k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/taints"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestComputeTemplateDrift(t *testing.T) {
+	template := BuildTestNode("template", 2000, 1000)
+	template.SetLabels(map[string]string{
+		apiv1.LabelHostname:             "template",
+		"team":                          "foo",
+		common.DatadogLocalStorageLabel: "true",
+	})
+	template.Spec.Taints = []apiv1.Taint{{Key: "dedicated", Value: "foo", Effect: apiv1.TaintEffectNoSchedule}}
+	template.Status.Allocatable[common.DatadogLocalDataExistsResource] = resource.MustParse("1")
+	ignored := localStorageResources(common.NewDefaultLocalStorageClasses())
+
+	// identical nodes (besides per-node labels and transient taints) don't drift
+	similar := BuildTestNode("similar", 2000, 1000)
+	similar.SetLabels(map[string]string{apiv1.LabelHostname: "similar", "team": "foo", common.DatadogLocalStorageLabel: "true"})
+	similar.Spec.Taints = []apiv1.Taint{
+		{Key: "dedicated", Value: "foo", Effect: apiv1.TaintEffectNoSchedule},
+		{Key: taints.ToBeDeletedTaint, Effect: apiv1.TaintEffectNoSchedule},
+	}
+	drift := computeTemplateDrift(template, []*apiv1.Node{similar}, ignored, taints.TaintConfig{})
+	assert.False(t, drift.drifting())
+	assert.Equal(t, "", drift.String())
+
+	// nodes having less memory, another label value and no taint do
+	smaller := BuildTestNode("smaller", 2000, 800)
+	smaller.SetLabels(map[string]string{apiv1.LabelHostname: "smaller", "team": "bar", common.DatadogLocalStorageLabel: "true"})
+	drift = computeTemplateDrift(template, []*apiv1.Node{similar, smaller}, ignored, taints.TaintConfig{})
+	assert.True(t, drift.drifting())
+	assert.Equal(t, 2, drift.nodes)
+	assert.InDelta(t, 0.125, drift.allocatable[apiv1.ResourceMemory], 0.001)
+	assert.InDelta(t, 0, drift.allocatable[apiv1.ResourceCPU], 0.001)
+	assert.NotContains(t, drift.allocatable, common.DatadogLocalDataExistsResource)
+	assert.Equal(t, map[string]int{"team": 1}, drift.labels)
+	assert.Equal(t, map[string]int{"dedicated=foo:NoSchedule (template only)": 1}, drift.taints)
+	assert.Equal(t, map[string]int{driftAllocatable: 1, driftLabels: 1, driftTaints: 1}, drift.driftingNodes)
+	assert.Equal(t, "allocatable: memory +12.5%; labels: team on 1/2 nodes; taints: dedicated=foo:NoSchedule (template only) on 1/2 nodes", drift.String())
+}
+
+func TestTemplateOnlyNodeInfoProviderDriftReport(t *testing.T) {
+	tni := schedulerframework.NewNodeInfo(nil, nil)
+	tn := BuildTestNode("tn", 1000, 1000)
+	tni.SetNode(tn)
+
+	provider := testprovider.NewTestCloudProviderBuilder().
+		WithMachineTemplates(map[string]*schedulerframework.NodeInfo{"ng1": tni, "ng2": tni}).
+		Build()
+	provider.AddNodeGroup("ng1", 1, 10, 1)
+	provider.AddNodeGroup("ng2", 1, 10, 1)
+	n1 := BuildTestNode("n1", 1000, 1000)
+	n2 := BuildTestNode("n2", 1000, 500)
+	provider.AddNode("ng1", n1)
+	provider.AddNode("ng2", n2)
+
+	fakeRecorder := record.NewFakeRecorder(10)
+	logRecorder, err := utils.NewStatusMapRecorder(fake.NewSimpleClientset(), "kube-system", fakeRecorder, true, "my-cool-configmap")
+	assert.NoError(t, err)
+	ctx := &context.AutoscalingContext{
+		CloudProvider: provider,
+		AutoscalingKubeClients: context.AutoscalingKubeClients{
+			LogRecorder: logRecorder,
+		},
+	}
+
+	ttl := 5 * time.Minute
+	processor := NewTemplateOnlyNodeInfoProvider(&ttl, true, false, common.NewDefaultLocalStorageClasses(), nil)
+	now := time.Now()
+	_, err = processor.Process(ctx, []*apiv1.Node{n1, n2}, nil, taints.TaintConfig{}, now)
+	assert.NoError(t, err)
+
+	// only the drifting node group is reported
+	assert.Equal(t, 1, len(fakeRecorder.Events))
+	event := <-fakeRecorder.Events
+	assert.True(t, strings.Contains(event, "TemplateDrift"))
+	assert.True(t, strings.Contains(event, "ng2"))
+	assert.True(t, strings.Contains(event, "memory +100.0%"))
+
+	// and reports aren't repeated before the next interval
+	_, err = processor.Process(ctx, []*apiv1.Node{n1, n2}, nil, taints.TaintConfig{}, now.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(fakeRecorder.Events))
+	_, err = processor.Process(ctx, []*apiv1.Node{n1, n2}, nil, taints.TaintConfig{}, now.Add(templateDriftReportInterval))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(fakeRecorder.Events))
+}
+
+func TestTemplateOnlyNodeInfoProviderHybridDriftReport(t *testing.T) {
+	now := time.Now()
+
+	tni := schedulerframework.NewNodeInfo(nil, nil)
+	tni.SetNode(BuildTestNode("tn", 1000, 1000))
+
+	provider := testprovider.NewTestCloudProviderBuilder().
+		WithMachineTemplates(map[string]*schedulerframework.NodeInfo{"ng1": tni}).
+		Build()
+	provider.AddNodeGroup("ng1", 1, 10, 1)
+	n1 := BuildTestNode("n1", 1000, 500)
+	SetNodeReadyState(n1, true, now.Add(-2*time.Minute))
+	provider.AddNode("ng1", n1)
+
+	snapshot := testsnapshot.NewTestSnapshotOrDie(t)
+	assert.NoError(t, snapshot.SetClusterState([]*apiv1.Node{n1}, nil, nil))
+	fakeRecorder := record.NewFakeRecorder(10)
+	logRecorder, err := utils.NewStatusMapRecorder(fake.NewSimpleClientset(), "kube-system", fakeRecorder, true, "my-cool-configmap")
+	assert.NoError(t, err)
+	ctx := &context.AutoscalingContext{
+		CloudProvider:   provider,
+		ClusterSnapshot: snapshot,
+		AutoscalingKubeClients: context.AutoscalingKubeClients{
+			LogRecorder: logRecorder,
+		},
+	}
+
+	ttl := 5 * time.Minute
+	processor := NewTemplateOnlyNodeInfoProvider(&ttl, true, true, common.NewDefaultLocalStorageClasses(), nil)
+	res, err := processor.Process(ctx, []*apiv1.Node{n1}, nil, taints.TaintConfig{}, now)
+	assert.NoError(t, err)
+
+	// the NodeInfo uses the measured allocatable, but the drift is still the template's
+	assert.Equal(t, int64(500), res["ng1"].Node().Status.Allocatable.Memory().Value())
+	if !assert.Equal(t, 1, len(fakeRecorder.Events)) {
+		return
+	}
+	event := <-fakeRecorder.Events
+	assert.True(t, strings.Contains(event, "ng1"))
+	assert.True(t, strings.Contains(event, "memory +100.0%"))
+}
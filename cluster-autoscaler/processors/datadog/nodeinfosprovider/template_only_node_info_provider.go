@@ -18,6 +18,7 @@ package nodeinfosprovider
 
 import (
 	"math/rand"
+	"reflect"
 	"sync"
 	"time"
 
@@ -40,6 +41,9 @@ import (
 
 const (
 	templateOnlyFuncLabel metrics.FunctionLabel = "TemplateOnlyNodeInfoProvider"
+
+	// templateDriftReportInterval is the delay between two templates vs live nodes comparisons
+	templateDriftReportInterval = 10 * time.Minute
 )
 
 type nodeInfoCacheEntry struct {
@@ -55,6 +59,13 @@ type TemplateOnlyNodeInfoProvider struct {
 	cloudProvider   cloudprovider.CloudProvider
 	interrupt       chan struct{}
 	forceDaemonSets bool
+	hybrid          bool
+	storageClasses  common.LocalStorageClasses
+	lastDriftReport time.Time
+	// explanations holds, per node group, how PodTemplates were applied during the last loop
+	explanations map[string][]string
+	// unreservedNodes holds, per node group, the template node before PodTemplates reserved overhead was subtracted
+	unreservedNodes map[string]*apiv1.Node
 
 	podTemplateProcessor podtemplate.Interface
 }
@@ -65,6 +76,7 @@ type TemplateOnlyNodeInfoProvider struct {
 // * We have to alter nodes in order to support accounting for local-data volumes
 // A downside of building nodeInfos from templates (nodegroups sppecs) only is that it's more costly than
 // using real nodes, which is why we're doing it asynchronously.
+// In hybrid mode, values measured on the node groups healthy live nodes are overlaid onto templates.
 func (p *TemplateOnlyNodeInfoProvider) Process(ctx *context.AutoscalingContext, nodes []*apiv1.Node, daemonsets []*appsv1.DaemonSet, taintConfig taints.TaintConfig, currentTime time.Time) (map[string]*schedulerframework.NodeInfo, errors.AutoscalerError) {
 	defer metrics.UpdateDurationFromStart(templateOnlyFuncLabel, time.Now())
 	p.init(ctx.CloudProvider)
@@ -72,28 +84,47 @@ func (p *TemplateOnlyNodeInfoProvider) Process(ctx *context.AutoscalingContext,
 	p.Lock()
 	defer p.Unlock()
 
+	p.explanations = make(map[string][]string)
+	p.unreservedNodes = make(map[string]*apiv1.Node)
+	var liveNodes map[string][]*apiv1.Node
+	if p.hybrid {
+		liveNodes = p.healthyNodesByGroup(nodes, currentTime)
+	}
+
 	result := make(map[string]*schedulerframework.NodeInfo)
 	for _, nodeGroup := range p.cloudProvider.NodeGroups() {
 		var err error
 		var nodeInfo *schedulerframework.NodeInfo
 
 		id := nodeGroup.Id()
+		var baseNodeInfo *schedulerframework.NodeInfo
 		if cacheEntry, found := p.nodeInfoCache[id]; found {
-			nodeInfo, err = p.SanitizedTemplateNodeInfoFromNodeGroupCached(id, cacheEntry.nodeInfo, daemonsets, taintConfig)
-			if err != nil {
-				klog.Warningf("Failed to obtain NodeInfo template from cache for %s: %v", id, err)
-				continue
-			}
+			observeTemplateCacheHit(id, currentTime.Sub(cacheEntry.lastRefresh))
+			baseNodeInfo = cacheEntry.nodeInfo
 		} else {
 			// new nodegroup: this can be slow (locked) but allows discovering new nodegroups faster
 			klog.V(4).Infof("No cached base NodeInfo for %s yet", id)
-			nodeInfo, err = simulator.SanitizedTemplateNodeInfoFromNodeGroup(nodeGroup, daemonsets, taintConfig)
+			observeTemplateCacheMiss(id)
+			baseNodeInfo, err = nodeGroup.TemplateNodeInfo()
 			if err != nil {
 				klog.Warningf("Failed to build NodeInfo template for %s: %v", id, err)
+				registerTemplateRefreshError(id)
 				continue
 			}
-			if common.NodeHasLocalData(nodeInfo.Node()) {
-				common.SetNodeLocalDataResource(nodeInfo)
+			common.SetNodeLocalStorageResources(baseNodeInfo, p.storageClasses)
+		}
+
+		nodeInfo, err = p.SanitizedTemplateNodeInfoFromNodeGroupCached(id, baseNodeInfo, daemonsets, taintConfig)
+		if err != nil {
+			klog.Warningf("Failed to obtain NodeInfo template for %s: %v", id, err)
+			continue
+		}
+
+		if p.hybrid {
+			nodeInfo, err = p.hybridNodeInfo(ctx, id, nodeInfo, liveNodes[id], daemonsets, taintConfig)
+			if err != nil {
+				klog.Warningf("Failed to overlay live nodes onto NodeInfo template for %s: %v", id, err)
+				continue
 			}
 		}
 
@@ -101,9 +132,57 @@ func (p *TemplateOnlyNodeInfoProvider) Process(ctx *context.AutoscalingContext,
 		result[id] = nodeInfo
 	}
 
+	if currentTime.Sub(p.lastDriftReport) >= templateDriftReportInterval {
+		p.reportTemplateDrift(ctx, p.unreservedNodes, nodes, taintConfig)
+		p.lastDriftReport = currentTime
+	}
+
+	if ctx.DebuggingSnapshotter != nil && ctx.DebuggingSnapshotter.IsDataCollectionAllowed() {
+		ctx.DebuggingSnapshotter.SetTemplateNodesExplanations(p.explanations)
+	}
+
 	return result, nil
 }
 
+// reportTemplateDrift compares node groups templates with their live nodes, and exposes
+// the differences as metrics and status configmap events. Templates are compared before
+// the PodTemplates reserved overhead is subtracted, as kubelet doesn't know about it.
+func (p *TemplateOnlyNodeInfoProvider) reportTemplateDrift(ctx *context.AutoscalingContext, templates map[string]*apiv1.Node,
+	nodes []*apiv1.Node, taintConfig taints.TaintConfig) {
+	nodesByGroup := make(map[string][]*apiv1.Node)
+	for _, node := range nodes {
+		nodeGroup, err := p.cloudProvider.NodeGroupForNode(node)
+		if err != nil {
+			klog.V(4).Infof("Failed to find node group for %s: %v", node.Name, err)
+			continue
+		}
+		if nodeGroup == nil || reflect.ValueOf(nodeGroup).IsNil() {
+			continue
+		}
+		nodesByGroup[nodeGroup.Id()] = append(nodesByGroup[nodeGroup.Id()], node)
+	}
+
+	ignoredResources := localStorageResources(p.storageClasses)
+	drifts := make(map[string]*templateDrift)
+	for id, groupNodes := range nodesByGroup {
+		template, found := templates[id]
+		if !found {
+			continue
+		}
+		drift := computeTemplateDrift(template, groupNodes, ignoredResources, taintConfig)
+		drifts[id] = drift
+		if !drift.drifting() {
+			continue
+		}
+		klog.Warningf("Template for node group %s differs from its live nodes: %s", id, drift)
+		if ctx.LogRecorder != nil {
+			ctx.LogRecorder.Eventf(apiv1.EventTypeWarning, "TemplateDrift", "Template for node group %s differs from its live nodes: %s", id, drift)
+		}
+	}
+
+	updateTemplateDrift(drifts)
+}
+
 // init starts a background refresh loop (and a shutdown channel).
 // we unfortunately can't do or call that from NewTemplateOnlyNodeInfoProvider(),
 // because don't have cloudProvider yet at New time.
@@ -140,14 +219,14 @@ func (p *TemplateOnlyNodeInfoProvider) refresh() {
 		nodeInfo, err := nodeGroup.TemplateNodeInfo()
 		if err != nil {
 			klog.Warningf("Unable to build template node for %s: %v", id, err)
+			registerTemplateRefreshError(id)
 			continue
 		}
 
 		// Virtual nodes in NodeInfo templates (built from ASG / MIGS / VMSS) having the
-		// local-storage:true label now also gets the Datadog local-storage custom resource
-		if common.NodeHasLocalData(nodeInfo.Node()) {
-			common.SetNodeLocalDataResource(nodeInfo)
-		}
+		// local-storage:true label (or any configured local storage class label) now
+		// also gets the matching Datadog local-storage custom resources
+		common.SetNodeLocalStorageResources(nodeInfo, p.storageClasses)
 
 		result[id] = &nodeInfoCacheEntry{
 			nodeInfo:    nodeInfo,
@@ -162,7 +241,8 @@ func (p *TemplateOnlyNodeInfoProvider) refresh() {
 
 // SanitizedTemplateNodeInfoFromNodeGroupCached is a copy of simulator.SanitizedTemplateNodeInfoFromNodeGroup,
 // but using a provided nodeInfo rather than calling TemplateNodeInfo() (which is costly) + injecting
-// the datadog-agent pod(s) inferred from a podTemplate (== the agents pods that aren't managed by DaemonSets).
+// the datadog-agent pod(s) inferred from a podTemplate (== the agents pods that aren't managed by DaemonSets),
+// and subtracting the overhead reserved by podTemplates from the node allocatable.
 func (p *TemplateOnlyNodeInfoProvider) SanitizedTemplateNodeInfoFromNodeGroupCached(id string, baseNodeInfo *schedulerframework.NodeInfo,
 	daemonsets []*appsv1.DaemonSet, taintConfig taints.TaintConfig) (*schedulerframework.NodeInfo, errors.AutoscalerError) {
 	labels.UpdateDeprecatedLabels(baseNodeInfo.Node().ObjectMeta.Labels)
@@ -173,17 +253,50 @@ func (p *TemplateOnlyNodeInfoProvider) SanitizedTemplateNodeInfoFromNodeGroupCac
 	}
 
 	// this is only meant to support main agent EDS nowadays (whose resources usage are discovered from a podTemplate)
-	podTpls, err2 := p.podTemplateProcessor.GetDaemonSetPodsFromPodTemplateForNode(sim, taintConfig)
+	match, err2 := p.podTemplateProcessor.MatchPodTemplatesForNode(sim, taintConfig)
 	if err2 != nil {
 		return nil, errors.ToAutoscalerError(errors.InternalError, err2)
 	}
-	for _, pod := range podTpls {
+	for _, pod := range match.Pods {
 		sim.AddPod(&schedulerframework.PodInfo{Pod: pod})
 	}
+	p.reserveResources(id, sim, match.Reserved)
+	if p.explanations != nil {
+		p.explanations[id] = match.Explanations
+	}
 
 	return sim, nil
 }
 
+// reserveResources subtracts the reserved overhead from the node group template allocatable,
+// and remembers the template node as it was before (for drift reports).
+func (p *TemplateOnlyNodeInfoProvider) reserveResources(id string, nodeInfo *schedulerframework.NodeInfo, reserved apiv1.ResourceList) {
+	if p.unreservedNodes != nil {
+		p.unreservedNodes[id] = nodeInfo.Node()
+	}
+	reserveResources(nodeInfo, reserved)
+}
+
+// reserveResources subtracts the reserved overhead from the node allocatable
+func reserveResources(nodeInfo *schedulerframework.NodeInfo, reserved apiv1.ResourceList) {
+	if len(reserved) == 0 {
+		return
+	}
+	node := nodeInfo.Node().DeepCopy()
+	for name, quantity := range reserved {
+		allocatable, found := node.Status.Allocatable[name]
+		if !found {
+			continue
+		}
+		allocatable.Sub(quantity)
+		if allocatable.Sign() < 0 {
+			allocatable.Set(0)
+		}
+		node.Status.Allocatable[name] = allocatable
+	}
+	nodeInfo.SetNode(node)
+}
+
 // CleanUp cleans up processor's internal structures.
 func (p *TemplateOnlyNodeInfoProvider) CleanUp() {
 	p.podTemplateProcessor.CleanUp()
@@ -191,11 +304,13 @@ func (p *TemplateOnlyNodeInfoProvider) CleanUp() {
 }
 
 // NewTemplateOnlyNodeInfoProvider returns a NodeInfoProcessor generating NodeInfos from node group templates.
-func NewTemplateOnlyNodeInfoProvider(t *time.Duration, forceDaemonSets bool, opts *core.AutoscalerOptions) *TemplateOnlyNodeInfoProvider {
+func NewTemplateOnlyNodeInfoProvider(t *time.Duration, forceDaemonSets bool, hybrid bool, storageClasses common.LocalStorageClasses, opts *core.AutoscalerOptions) *TemplateOnlyNodeInfoProvider {
 	return &TemplateOnlyNodeInfoProvider{
 		ttl:                  *t,
 		nodeInfoCache:        make(map[string]*nodeInfoCacheEntry),
 		forceDaemonSets:      forceDaemonSets,
+		hybrid:               hybrid,
+		storageClasses:       storageClasses,
 		podTemplateProcessor: podtemplate.NewPodTemplateProcessor(opts),
 	}
 }
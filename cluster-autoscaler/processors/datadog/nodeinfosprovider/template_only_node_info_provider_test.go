@@ -24,8 +24,14 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	testprovider "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
+	"k8s.io/autoscaler/cluster-autoscaler/clusterstate/utils"
 	"k8s.io/autoscaler/cluster-autoscaler/context"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/datadog/common"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/datadog/nodeinfosprovider/podtemplate"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
 
 	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
 
@@ -50,7 +56,7 @@ func TestTemplateOnlyNodeInfoProviderProcess(t *testing.T) {
 	}
 
 	ttl := 5 * time.Minute
-	processor := NewTemplateOnlyNodeInfoProvider(&ttl, true, nil)
+	processor := NewTemplateOnlyNodeInfoProvider(&ttl, true, false, common.NewDefaultLocalStorageClasses(), nil)
 	res, err := processor.Process(ctx, nil, nil, taints.TaintConfig{}, time.Now())
 
 	// nodegroups providing templates
@@ -61,3 +67,83 @@ func TestTemplateOnlyNodeInfoProviderProcess(t *testing.T) {
 	assert.Contains(t, res["ng1"].Node().GetLabels(), apiv1.LabelZoneFailureDomain)
 	assert.Equal(t, res["ng1"].Node().GetLabels()[apiv1.LabelZoneFailureDomain], "planet-earth")
 }
+
+func TestReserveResources(t *testing.T) {
+	nodeInfo := schedulerframework.NewNodeInfo(BuildTestNode("n", 1000, 1000), nil)
+	reserveResources(nodeInfo, apiv1.ResourceList{
+		apiv1.ResourceCPU:    *resource.NewMilliQuantity(100, resource.DecimalSI),
+		apiv1.ResourceMemory: *resource.NewQuantity(2000, resource.DecimalSI),
+		"example.com/gpu":    *resource.NewQuantity(1, resource.DecimalSI),
+	})
+
+	allocatable := nodeInfo.Node().Status.Allocatable
+	assert.Equal(t, int64(900), allocatable.Cpu().MilliValue())
+	assert.Equal(t, int64(0), allocatable.Memory().Value())
+	assert.NotContains(t, allocatable, apiv1.ResourceName("example.com/gpu"))
+	assert.Equal(t, int64(1000), nodeInfo.Node().Status.Capacity.Cpu().MilliValue())
+}
+
+type fakePodTemplateProcessor struct {
+	match podtemplate.NodeMatch
+}
+
+func (p *fakePodTemplateProcessor) GetDaemonSetPodsFromPodTemplateForNode(baseNodeInfo *schedulerframework.NodeInfo, taintConfig taints.TaintConfig) ([]*apiv1.Pod, error) {
+	return p.match.Pods, nil
+}
+
+func (p *fakePodTemplateProcessor) MatchPodTemplatesForNode(baseNodeInfo *schedulerframework.NodeInfo, taintConfig taints.TaintConfig) (*podtemplate.NodeMatch, error) {
+	match := p.match
+	return &match, nil
+}
+
+func (p *fakePodTemplateProcessor) CleanUp() {
+}
+
+func TestTemplateOnlyNodeInfoProviderPodTemplates(t *testing.T) {
+	tni := schedulerframework.NewNodeInfo(nil, nil)
+	tni.SetNode(BuildTestNode("tn", 1000, 1000))
+
+	provider := testprovider.NewTestCloudProviderBuilder().
+		WithMachineTemplates(map[string]*schedulerframework.NodeInfo{"ng1": tni, "ng2": tni}).
+		Build()
+	provider.AddNodeGroup("ng1", 1, 10, 1)
+	n1 := BuildTestNode("n1", 1000, 1000)
+	provider.AddNode("ng1", n1)
+
+	fakeRecorder := record.NewFakeRecorder(10)
+	logRecorder, err := utils.NewStatusMapRecorder(fake.NewSimpleClientset(), "kube-system", fakeRecorder, true, "my-cool-configmap")
+	assert.NoError(t, err)
+	ctx := &context.AutoscalingContext{
+		CloudProvider: provider,
+		AutoscalingKubeClients: context.AutoscalingKubeClients{
+			LogRecorder: logRecorder,
+		},
+	}
+
+	ttl := 5 * time.Minute
+	processor := NewTemplateOnlyNodeInfoProvider(&ttl, true, false, common.NewDefaultLocalStorageClasses(), nil)
+	processor.podTemplateProcessor = &fakePodTemplateProcessor{match: podtemplate.NodeMatch{
+		Pods:         []*apiv1.Pod{BuildTestPod("agent-pod", 100, 100)},
+		Reserved:     apiv1.ResourceList{apiv1.ResourceCPU: *resource.NewMilliQuantity(100, resource.DecimalSI)},
+		Explanations: []string{"default/agent: added pod agent-pod"},
+	}}
+	now := time.Now()
+
+	// cached templates get the PodTemplates pods and reserved overhead
+	res, err := processor.Process(ctx, []*apiv1.Node{n1}, nil, taints.TaintConfig{}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(900), res["ng1"].Node().Status.Allocatable.Cpu().MilliValue())
+	assert.Equal(t, 1, len(res["ng1"].Pods()))
+
+	// the reserved overhead isn't reported as a drift from the live nodes
+	assert.Equal(t, 0, len(fakeRecorder.Events))
+
+	// and so do templates of node groups not cached yet
+	provider.AddNodeGroup("ng2", 0, 10, 0)
+	res, err = processor.Process(ctx, []*apiv1.Node{n1}, nil, taints.TaintConfig{}, now.Add(templateDriftReportInterval))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(900), res["ng2"].Node().Status.Allocatable.Cpu().MilliValue())
+	assert.Equal(t, 1, len(res["ng2"].Pods()))
+	assert.Equal(t, []string{"default/agent: added pod agent-pod"}, processor.explanations["ng2"])
+	assert.Equal(t, 0, len(fakeRecorder.Events))
+}
@@ -27,20 +27,33 @@ limitations under the License.
   all pods from workloads that didn't had new pods recently (all that
   workload's pods are "old"), and were already considered several times
   for upscale.
+
+  The quarantine state is checkpointed to a ConfigMap (see
+  filter_long_pending_checkpoint.go), and quarantined workloads can be
+  listed through the processor's ServeHTTP handler.
+
+  Quarantine settings can be tuned per workload or namespace with annotations
+  (see filter_long_pending_policy.go), and every quarantine and release
+  decision is emitted as an event on the workload's controller.
 */
 
 package pods
 
 import (
+	"encoding/json"
 	"math/rand"
+	"net/http"
 	"os"
 	"strconv"
+	"sync"
 	"time"
 
 	apiv1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/autoscaler/cluster-autoscaler/context"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/prescaling"
 	klog "k8s.io/klog/v2"
 )
 
@@ -58,6 +71,10 @@ var (
 )
 
 type pendingTracker struct {
+	// owner workload identity, for display purposes
+	namespace string
+	kind      string
+	name      string
 	// last time we saw a new pod for that workload (or most recent
 	// pod's creation time, whichever is the most recent)
 	lastSeen time.Time
@@ -68,8 +85,12 @@ type pendingTracker struct {
 }
 
 type filterOutLongPending struct {
+	mu           sync.Mutex
 	seen         map[types.UID]*pendingTracker
-	deadlineFunc func(int, time.Duration) time.Time
+	deadlineFunc func(int, time.Duration, quarantinePolicy) time.Time
+	checkpointer quarantineCheckpointer
+	resolver     *policyResolver
+	stopChannel  chan struct{}
 }
 
 // NewFilterOutLongPending returns a processor slowing down retries on long pending pods
@@ -78,19 +99,42 @@ func NewFilterOutLongPending() *filterOutLongPending {
 	return &filterOutLongPending{
 		seen:         make(map[types.UID]*pendingTracker),
 		deadlineFunc: buildDeadline,
+		stopChannel:  make(chan struct{}),
 	}
 }
 
 // CleanUp tears down the FilterOutLongPending processor
-func (p *filterOutLongPending) CleanUp() {}
+func (p *filterOutLongPending) CleanUp() {
+	close(p.stopChannel)
+}
 
 // Process slows down upscales retries on long pending pods
 func (p *filterOutLongPending) Process(ctx *context.AutoscalingContext, pending []*apiv1.Pod) ([]*apiv1.Pod, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.checkpointer == nil && ctx.ClientSet != nil {
+		p.restore(newConfigMapCheckpointer(ctx.ClientSet, ctx.ConfigNamespace))
+	}
+	if p.resolver == nil {
+		p.resolver = &policyResolver{}
+		if ctx.ClientSet != nil {
+			p.resolver.namespaceLister = NewNamespaceLister(ctx.ClientSet, p.stopChannel)
+		}
+	}
+
 	coolDownDelay := ctx.AutoscalingOptions.ScaleDownDelayAfterAdd
 	allowedPods := make([]*apiv1.Pod, 0)
 	refsToPods := make(map[types.UID][]*apiv1.Pod)
 
 	for _, pod := range pending {
+		// prescaling fake pods carry their workload's controller ref, but
+		// mustn't count as that workload's pending pods
+		if prescaling.IsFakePrescalingPod(pod) {
+			allowedPods = append(allowedPods, pod)
+			continue
+		}
+
 		refID := pod.GetUID()
 		controllerRef := metav1.GetControllerOf(pod)
 		if controllerRef != nil {
@@ -105,32 +149,47 @@ func (p *filterOutLongPending) Process(ctx *context.AutoscalingContext, pending
 
 		// track upscale attempts for a given ownerref
 		if _, ok := p.seen[refID]; !ok {
-			p.seen[refID] = &pendingTracker{
-				lastSeen: now(),
-			}
+			p.seen[refID] = newPendingTracker(pod, now())
 		}
 
 		// reset counters whenever a workload has new pods
 		if p.seen[refID].lastSeen.Before(pod.GetCreationTimestamp().Time) {
-			p.seen[refID] = &pendingTracker{
-				lastSeen: pod.GetCreationTimestamp().Time,
-			}
+			p.seen[refID] = newPendingTracker(pod, pod.GetCreationTimestamp().Time)
 		}
 	}
 
 	for ref, pods := range refsToPods {
+		policy, owner := p.resolver.resolve(ctx, pods[0])
+
+		// opted-out workloads are never quarantined
+		if policy.disabled {
+			if !p.seen[ref].nextTry.IsZero() {
+				p.seen[ref].nextTry = time.Time{}
+				recordEvent(ctx, owner, apiv1.EventTypeNormal, "LongPendingReleased",
+					"Released %d long pending pod(s) from quarantine: quarantine disabled", len(pods))
+			}
+			allowedPods = append(allowedPods, pods...)
+			continue
+		}
+
 		// skip (don't attempt upscales for) quarantined pods
 		if p.seen[ref].nextTry.After(now()) {
 			logSkipped(ref, p.seen[ref], pods)
 			continue
 		}
 
+		if !p.seen[ref].nextTry.IsZero() {
+			p.seen[ref].nextTry = time.Time{}
+			recordEvent(ctx, owner, apiv1.EventTypeNormal, "LongPendingReleased",
+				"Released %d long pending pod(s) from quarantine for an upscale attempt (attempted %d times)", len(pods), p.seen[ref].attempts)
+		}
+
 		// let autoscaler attempt upscales for non-quarantined pods
 		allowedPods = append(allowedPods, pods...)
 		p.seen[ref].attempts++
 
 		// don't quarantine pods until they had several upscale opportunities
-		if p.seen[ref].attempts < minAttempts {
+		if p.seen[ref].attempts < policy.minAttempts {
 			continue
 		}
 
@@ -141,7 +200,10 @@ func (p *filterOutLongPending) Process(ctx *context.AutoscalingContext, pending
 
 		// pending pods from that workload already had several upscale attempts
 		// opportunities, and aren't recent: will delay after current loop.
-		p.seen[ref].nextTry = p.deadlineFunc(p.seen[ref].attempts, coolDownDelay)
+		p.seen[ref].nextTry = p.deadlineFunc(p.seen[ref].attempts, coolDownDelay, policy)
+		recordEvent(ctx, owner, apiv1.EventTypeWarning, "LongPendingQuarantined",
+			"Quarantined %d long pending pod(s) until %s: no upscale attempt before then (attempted %d times)",
+			len(pods), p.seen[ref].nextTry.Format(time.RFC3339), p.seen[ref].attempts)
 	}
 
 	// reap stale entries
@@ -151,9 +213,69 @@ func (p *filterOutLongPending) Process(ctx *context.AutoscalingContext, pending
 		}
 	}
 
+	if p.checkpointer != nil {
+		if err := p.checkpointer.Save(p.seen); err != nil {
+			klog.Warningf("failed to checkpoint long pending pods quarantine state: %v", err)
+		}
+	}
+
 	return allowedPods, nil
 }
 
+// restore loads the quarantine state from a checkpoint, and keeps that checkpointer for later saves.
+// The checkpointer is only kept once the state was loaded, so that a failed load is retried on next
+// loop rather than overwritten by the next save.
+func (p *filterOutLongPending) restore(checkpointer quarantineCheckpointer) {
+	seen, err := checkpointer.Load()
+	if err != nil {
+		klog.Warningf("failed to restore long pending pods quarantine state: %v", err)
+		return
+	}
+	p.checkpointer = checkpointer
+	for ref, tracker := range seen {
+		if current, ok := p.seen[ref]; !ok || current.nextTry.IsZero() {
+			p.seen[ref] = tracker
+		}
+	}
+	klog.V(2).Infof("restored quarantine state for %d long pending workload(s)", len(seen))
+}
+
+// ServeHTTP lists the currently quarantined workloads, and until when
+func (p *filterOutLongPending) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	quarantined := make(map[types.UID]*pendingTracker)
+	for ref, tracker := range p.seen {
+		if tracker.nextTry.After(now()) {
+			quarantined[ref] = tracker
+		}
+	}
+	body, err := json.MarshalIndent(trackedWorkloads(quarantined), "", "  ")
+	p.mu.Unlock()
+
+	if err != nil {
+		klog.Errorf("failed to marshal quarantined workloads: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
+
+func newPendingTracker(pod *apiv1.Pod, lastSeen time.Time) *pendingTracker {
+	tracker := &pendingTracker{
+		namespace: pod.GetNamespace(),
+		kind:      "Pod",
+		name:      pod.GetName(),
+		lastSeen:  lastSeen,
+	}
+	if controllerRef := metav1.GetControllerOf(pod); controllerRef != nil {
+		tracker.kind = controllerRef.Kind
+		tracker.name = controllerRef.Name
+	}
+	return tracker
+}
+
 func logSkipped(ref types.UID, tracker *pendingTracker, pods []*apiv1.Pod) {
 	klog.Warningf(
 		"ignoring %d long pending pod(s) (eg. %s/%s) "+
@@ -163,13 +285,24 @@ func logSkipped(ref types.UID, tracker *pendingTracker, pods []*apiv1.Pod) {
 	)
 }
 
-func buildDeadline(attempts int, coolDownDelay time.Duration) time.Time {
-	increment := time.Duration(attempts-minAttempts) * retriesIncrement
-	delay := minDuration(increment, time.Duration(delayFactor)*coolDownDelay.Abs())
-	delay -= jitterDuration(coolDownDelay.Abs())
+func buildDeadline(attempts int, coolDownDelay time.Duration, policy quarantinePolicy) time.Time {
+	maxDelay := policy.maxDelay
+	if maxDelay == 0 {
+		maxDelay = time.Duration(delayFactor) * coolDownDelay.Abs()
+	}
+	increment := time.Duration(attempts-policy.minAttempts) * policy.retriesIncrement
+	delay := minDuration(increment, maxDelay)
+	delay -= jitterDuration(minDuration(coolDownDelay.Abs(), maxDelay))
 	return now().Add(delay.Abs())
 }
 
+func recordEvent(ctx *context.AutoscalingContext, object runtime.Object, eventType, reason, messageFmt string, args ...interface{}) {
+	if ctx.Recorder == nil {
+		return
+	}
+	ctx.Recorder.Eventf(object, eventType, reason, messageFmt, args...)
+}
+
 func minDuration(a, b time.Duration) time.Duration {
 	if a <= b {
 		return a
@@ -0,0 +1,177 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+  Checkpoints the filterOutLongPending quarantine state to a ConfigMap, so
+  workloads backoff survives autoscaler restarts and leader changes (which is
+  when the runaway upscale protection matters the most).
+
+  The state is loaded once, on the first loop (so by the current leader), and
+  written back whenever it changed. Only quarantined workloads are saved: the
+  attempts counters of other workloads change at every loop, and losing them
+  only delays their quarantine by a few loops.
+*/
+
+package pods
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	kube_errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kube_client "k8s.io/client-go/kubernetes"
+)
+
+const (
+	// QuarantineConfigMapName is the name of the ConfigMap holding the long pending workloads quarantine state
+	QuarantineConfigMapName = "cluster-autoscaler-quarantine"
+	// quarantineConfigMapKey is the ConfigMap data key holding the serialized state
+	quarantineConfigMapKey = "state"
+)
+
+// trackedWorkload is the serializable form of a pendingTracker
+type trackedWorkload struct {
+	UID       types.UID `json:"uid"`
+	Namespace string    `json:"namespace,omitempty"`
+	Kind      string    `json:"kind,omitempty"`
+	Name      string    `json:"name,omitempty"`
+	LastSeen  time.Time `json:"lastSeen"`
+	NextTry   time.Time `json:"nextTry"`
+	Attempts  int       `json:"attempts"`
+}
+
+type quarantineCheckpointer interface {
+	Load() (map[types.UID]*pendingTracker, error)
+	Save(seen map[types.UID]*pendingTracker) error
+}
+
+type configMapCheckpointer struct {
+	kubeClient kube_client.Interface
+	namespace  string
+	name       string
+	lastSaved  string
+}
+
+func newConfigMapCheckpointer(kubeClient kube_client.Interface, namespace string) *configMapCheckpointer {
+	return &configMapCheckpointer{
+		kubeClient: kubeClient,
+		namespace:  namespace,
+		name:       QuarantineConfigMapName,
+	}
+}
+
+// Load returns the quarantine state stored in the ConfigMap, or an empty state if there's none
+func (c *configMapCheckpointer) Load() (map[types.UID]*pendingTracker, error) {
+	seen := make(map[types.UID]*pendingTracker)
+	configMap, err := c.kubeClient.CoreV1().ConfigMaps(c.namespace).Get(context.TODO(), c.name, metav1.GetOptions{})
+	if kube_errors.IsNotFound(err) {
+		return seen, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve quarantine configmap: %v", err)
+	}
+
+	data, ok := configMap.Data[quarantineConfigMapKey]
+	if !ok {
+		return seen, nil
+	}
+	var workloads []trackedWorkload
+	if err := json.Unmarshal([]byte(data), &workloads); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal quarantine configmap: %v", err)
+	}
+	for _, workload := range workloads {
+		seen[workload.UID] = &pendingTracker{
+			namespace: workload.Namespace,
+			kind:      workload.Kind,
+			name:      workload.Name,
+			lastSeen:  workload.LastSeen,
+			nextTry:   workload.NextTry,
+			attempts:  workload.Attempts,
+		}
+	}
+	c.lastSaved = data
+	return seen, nil
+}
+
+// Save writes the quarantined workloads to the ConfigMap, unless they didn't change since last write
+func (c *configMapCheckpointer) Save(seen map[types.UID]*pendingTracker) error {
+	quarantined := make(map[types.UID]*pendingTracker)
+	for uid, tracker := range seen {
+		if !tracker.nextTry.IsZero() {
+			quarantined[uid] = tracker
+		}
+	}
+	data, err := json.Marshal(trackedWorkloads(quarantined))
+	if err != nil {
+		return fmt.Errorf("failed to marshal quarantine state: %v", err)
+	}
+	if string(data) == c.lastSaved {
+		return nil
+	}
+
+	maps := c.kubeClient.CoreV1().ConfigMaps(c.namespace)
+	configMap, err := maps.Get(context.TODO(), c.name, metav1.GetOptions{})
+	if err == nil {
+		if configMap.Data == nil {
+			configMap.Data = make(map[string]string)
+		}
+		configMap.Data[quarantineConfigMapKey] = string(data)
+		_, err = maps.Update(context.TODO(), configMap, metav1.UpdateOptions{})
+	} else if kube_errors.IsNotFound(err) {
+		configMap = &apiv1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: c.namespace,
+				Name:      c.name,
+			},
+			Data: map[string]string{
+				quarantineConfigMapKey: string(data),
+			},
+		}
+		_, err = maps.Create(context.TODO(), configMap, metav1.CreateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write quarantine configmap: %v", err)
+	}
+
+	c.lastSaved = string(data)
+	return nil
+}
+
+// trackedWorkloads returns the serializable form of a quarantine state, sorted by UID
+func trackedWorkloads(seen map[types.UID]*pendingTracker) []trackedWorkload {
+	workloads := make([]trackedWorkload, 0, len(seen))
+	for uid, tracker := range seen {
+		workloads = append(workloads, trackedWorkload{
+			UID:       uid,
+			Namespace: tracker.namespace,
+			Kind:      tracker.kind,
+			Name:      tracker.name,
+			LastSeen:  tracker.lastSeen,
+			NextTry:   tracker.nextTry,
+			Attempts:  tracker.attempts,
+		})
+	}
+	sort.Slice(workloads, func(i, j int) bool {
+		return workloads[i].UID < workloads[j].UID
+	})
+	return workloads
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pods
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+	cacontext "k8s.io/autoscaler/cluster-autoscaler/context"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestConfigMapCheckpointer(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	checkpointer := newConfigMapCheckpointer(client, "kube-system")
+
+	// Case 1: missing configmap means empty state
+	seen, err := checkpointer.Load()
+	assert.NoError(t, err)
+	assert.Empty(t, seen)
+
+	// Case 2: only quarantined workloads are saved, and restored
+	nextTry := time.Now().Add(time.Hour).Round(time.Second)
+	err = checkpointer.Save(map[types.UID]*pendingTracker{
+		"foo": {namespace: "ns", kind: "ReplicaSet", name: "foo", lastSeen: nextTry, nextTry: nextTry, attempts: 4},
+		"bar": {namespace: "ns", kind: "ReplicaSet", name: "bar", lastSeen: nextTry, attempts: 1},
+	})
+	assert.NoError(t, err)
+
+	seen, err = newConfigMapCheckpointer(client, "kube-system").Load()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(seen))
+	assert.Contains(t, seen, types.UID("foo"))
+	assert.Equal(t, 4, seen["foo"].attempts)
+	assert.Equal(t, "ReplicaSet", seen["foo"].kind)
+	assert.True(t, nextTry.Equal(seen["foo"].nextTry))
+
+	// Case 3: unchanged state isn't written again
+	configMap, err := client.CoreV1().ConfigMaps("kube-system").Get(context.TODO(), QuarantineConfigMapName, metav1.GetOptions{})
+	assert.NoError(t, err)
+	configMap.Data[quarantineConfigMapKey] = "[]"
+	_, err = client.CoreV1().ConfigMaps("kube-system").Update(context.TODO(), configMap, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+	err = checkpointer.Save(map[types.UID]*pendingTracker{
+		"foo": {namespace: "ns", kind: "ReplicaSet", name: "foo", lastSeen: nextTry, nextTry: nextTry, attempts: 4},
+	})
+	assert.NoError(t, err)
+	configMap, err = client.CoreV1().ConfigMaps("kube-system").Get(context.TODO(), QuarantineConfigMapName, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "[]", configMap.Data[quarantineConfigMapKey])
+}
+
+func TestProcessRestoresQuarantine(t *testing.T) {
+	ctx := &cacontext.AutoscalingContext{
+		AutoscalingOptions: config.AutoscalingOptions{
+			ScaleDownDelayAfterAdd: testScaleDownDelay,
+			ConfigNamespace:        "kube-system",
+		},
+		AutoscalingKubeClients: cacontext.AutoscalingKubeClients{
+			ClientSet: fake.NewSimpleClientset(),
+		},
+	}
+
+	now = func() time.Time { return time.Now().Add(-time.Hour) }
+	defer func() { now = time.Now }()
+
+	pendingPods := buildPendingPods(1, "foo", time.Now().Add(-time.Hour))
+	p := NewFilterOutLongPending()
+	var allowedPods []*apiv1.Pod
+	for i := 1; i < 2*minAttempts; i++ {
+		allowedPods, _ = p.Process(ctx, pendingPods)
+		now = time.Now
+	}
+	assert.Empty(t, allowedPods)
+
+	// a fresh processor (eg. after a restart) keeps the workload quarantined
+	p = NewFilterOutLongPending()
+	allowedPods, _ = p.Process(ctx, pendingPods)
+	assert.Empty(t, allowedPods)
+
+	// and lists it on its debug endpoint
+	recorder := httptest.NewRecorder()
+	p.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/quarantinez", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var quarantined []trackedWorkload
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &quarantined))
+	assert.Equal(t, 1, len(quarantined))
+	assert.Equal(t, types.UID("foo-0"), quarantined[0].UID)
+	assert.Equal(t, "foo-0", quarantined[0].Name)
+	assert.True(t, quarantined[0].NextTry.After(time.Now()))
+}
+
+func TestProcessRetriesFailedRestore(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	err := newConfigMapCheckpointer(client, "kube-system").Save(map[types.UID]*pendingTracker{
+		"foo-0": {namespace: "default", kind: "Pod", name: "foo-0", lastSeen: time.Now(), nextTry: time.Now().Add(time.Hour), attempts: 2 * minAttempts},
+	})
+	assert.NoError(t, err)
+
+	failures := 1
+	client.PrependReactor("get", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if failures > 0 {
+			failures--
+			return true, nil, fmt.Errorf("apiserver unavailable")
+		}
+		return false, nil, nil
+	})
+	ctx := &cacontext.AutoscalingContext{
+		AutoscalingOptions: config.AutoscalingOptions{
+			ScaleDownDelayAfterAdd: testScaleDownDelay,
+			ConfigNamespace:        "kube-system",
+		},
+		AutoscalingKubeClients: cacontext.AutoscalingKubeClients{
+			ClientSet: client,
+		},
+	}
+
+	// the state failing to load isn't overwritten
+	pendingPods := buildPendingPods(1, "foo", time.Now().Add(-time.Hour))
+	p := NewFilterOutLongPending()
+	allowedPods, err := p.Process(ctx, pendingPods)
+	assert.NoError(t, err)
+	assert.Equal(t, pendingPods, allowedPods)
+	seen, err := newConfigMapCheckpointer(client, "kube-system").Load()
+	assert.NoError(t, err)
+	assert.Contains(t, seen, types.UID("foo-0"))
+
+	// and is restored on next loop
+	allowedPods, err = p.Process(ctx, pendingPods)
+	assert.NoError(t, err)
+	assert.Empty(t, allowedPods)
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+  Per-workload quarantine policy for the filterOutLongPending processor.
+
+  The QUARANTINE_MIN_ATTEMPTS and QUARANTINE_DELAY_FACTOR env vars (and the
+  default retries increment) provide cluster wide defaults, which can be
+  overridden by annotations on the pods' namespace, themselves overridden by
+  annotations on the pods' controller (eg. the ReplicaSet, which inherits
+  its Deployment annotations, StatefulSet, Job, ...):
+  * quarantine.datadoghq.com/disabled: "true" never quarantines the workload
+  * quarantine.datadoghq.com/min-attempts: upscale attempts before quarantine
+  * quarantine.datadoghq.com/max-delay: longest quarantine (eg. "10m")
+  * quarantine.datadoghq.com/retries-increment: quarantine duration added at
+    each new attempt (eg. "30s")
+*/
+
+package pods
+
+import (
+	ctx "context"
+	"strconv"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/autoscaler/cluster-autoscaler/context"
+	client "k8s.io/client-go/kubernetes"
+	v1lister "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	// QuarantineDisabledAnnotation opts a workload (or a namespace) out of long pending quarantine
+	QuarantineDisabledAnnotation = "quarantine.datadoghq.com/disabled"
+	// QuarantineMinAttemptsAnnotation overrides the number of upscale attempts before quarantine
+	QuarantineMinAttemptsAnnotation = "quarantine.datadoghq.com/min-attempts"
+	// QuarantineMaxDelayAnnotation overrides the longest quarantine duration
+	QuarantineMaxDelayAnnotation = "quarantine.datadoghq.com/max-delay"
+	// QuarantineRetriesIncrementAnnotation overrides the quarantine duration increment per attempt
+	QuarantineRetriesIncrementAnnotation = "quarantine.datadoghq.com/retries-increment"
+)
+
+// quarantinePolicy holds the quarantine settings applying to a workload
+type quarantinePolicy struct {
+	disabled    bool
+	minAttempts int
+	// maxDelay caps quarantines durations; zero means delayFactor times the scale down cooldown
+	maxDelay         time.Duration
+	retriesIncrement time.Duration
+}
+
+func defaultQuarantinePolicy() quarantinePolicy {
+	return quarantinePolicy{
+		minAttempts:      minAttempts,
+		retriesIncrement: retriesIncrement,
+	}
+}
+
+// withAnnotations returns a copy of the policy overridden by the provided annotations
+func (q quarantinePolicy) withAnnotations(annotations map[string]string, source string) quarantinePolicy {
+	if value, ok := annotations[QuarantineDisabledAnnotation]; ok {
+		disabled, err := strconv.ParseBool(value)
+		if err != nil {
+			klog.Warningf("ignoring invalid %s annotation %q on %s: %v", QuarantineDisabledAnnotation, value, source, err)
+		} else {
+			q.disabled = disabled
+		}
+	}
+	if value, ok := annotations[QuarantineMinAttemptsAnnotation]; ok {
+		attempts, err := strconv.Atoi(value)
+		if err != nil || attempts < 0 {
+			klog.Warningf("ignoring invalid %s annotation %q on %s: %v", QuarantineMinAttemptsAnnotation, value, source, err)
+		} else {
+			q.minAttempts = attempts
+		}
+	}
+	if value, ok := annotations[QuarantineMaxDelayAnnotation]; ok {
+		delay, err := time.ParseDuration(value)
+		if err != nil || delay <= 0 {
+			klog.Warningf("ignoring invalid %s annotation %q on %s: %v", QuarantineMaxDelayAnnotation, value, source, err)
+		} else {
+			q.maxDelay = delay
+		}
+	}
+	if value, ok := annotations[QuarantineRetriesIncrementAnnotation]; ok {
+		increment, err := time.ParseDuration(value)
+		if err != nil || increment < 0 {
+			klog.Warningf("ignoring invalid %s annotation %q on %s: %v", QuarantineRetriesIncrementAnnotation, value, source, err)
+		} else {
+			q.retriesIncrement = increment
+		}
+	}
+	return q
+}
+
+// policyResolver finds the quarantine policy applying to pods, and the object
+// (their controller when known, or the pod itself) quarantine events are attached to.
+type policyResolver struct {
+	namespaceLister v1lister.NamespaceLister
+}
+
+func (r *policyResolver) resolve(ctx *context.AutoscalingContext, pod *apiv1.Pod) (quarantinePolicy, runtime.Object) {
+	policy := defaultQuarantinePolicy()
+
+	if r.namespaceLister != nil {
+		if namespace, err := r.namespaceLister.Get(pod.GetNamespace()); err == nil {
+			policy = policy.withAnnotations(namespace.GetAnnotations(), "namespace "+namespace.GetName())
+		}
+	}
+
+	owner := controllerOf(ctx, pod)
+	if owner == nil {
+		return policy, pod
+	}
+	accessor, ok := owner.(metav1.Object)
+	if !ok {
+		return policy, pod
+	}
+	return policy.withAnnotations(accessor.GetAnnotations(), "controller "+accessor.GetNamespace()+"/"+accessor.GetName()), owner
+}
+
+// controllerOf returns the pod's controller, when it's a known kind we have a lister for
+func controllerOf(ctx *context.AutoscalingContext, pod *apiv1.Pod) runtime.Object {
+	controllerRef := metav1.GetControllerOf(pod)
+	if controllerRef == nil || ctx.ListerRegistry == nil {
+		return nil
+	}
+
+	var owner runtime.Object
+	var err error
+	namespace := pod.GetNamespace()
+	switch controllerRef.Kind {
+	case "ReplicaSet":
+		owner, err = ctx.ListerRegistry.ReplicaSetLister().ReplicaSets(namespace).Get(controllerRef.Name)
+	case "StatefulSet":
+		owner, err = ctx.ListerRegistry.StatefulSetLister().StatefulSets(namespace).Get(controllerRef.Name)
+	case "Job":
+		owner, err = ctx.ListerRegistry.JobLister().Jobs(namespace).Get(controllerRef.Name)
+	case "ReplicationController":
+		owner, err = ctx.ListerRegistry.ReplicationControllerLister().ReplicationControllers(namespace).Get(controllerRef.Name)
+	case "DaemonSet":
+		owner, err = ctx.ListerRegistry.DaemonSetLister().DaemonSets(namespace).Get(controllerRef.Name)
+	default:
+		return nil
+	}
+	if err != nil {
+		klog.V(4).Infof("failed to fetch %s %s/%s controller of pod %s: %v", controllerRef.Kind, namespace, controllerRef.Name, pod.GetName(), err)
+		return nil
+	}
+	return owner
+}
+
+// NewNamespaceLister builds a namespace lister.
+func NewNamespaceLister(kubeClient client.Interface, stopchannel <-chan struct{}) v1lister.NamespaceLister {
+	listWatcher := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			return kubeClient.CoreV1().Namespaces().List(ctx.TODO(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			return kubeClient.CoreV1().Namespaces().Watch(ctx.TODO(), options)
+		},
+	}
+	store, reflector := cache.NewNamespaceKeyedIndexerAndReflector(listWatcher, &apiv1.Namespace{}, time.Hour)
+	lister := v1lister.NewNamespaceLister(store)
+	go reflector.Run(stopchannel)
+	return lister
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pods
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+	cacontext "k8s.io/autoscaler/cluster-autoscaler/context"
+	kube_util "k8s.io/autoscaler/cluster-autoscaler/utils/kubernetes"
+	v1appslister "k8s.io/client-go/listers/apps/v1"
+	v1lister "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestQuarantinePolicyWithAnnotations(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expected    quarantinePolicy
+	}{
+		{
+			"no annotations keep defaults",
+			nil,
+			defaultQuarantinePolicy(),
+		},
+		{
+			"all annotations are applied",
+			map[string]string{
+				QuarantineDisabledAnnotation:         "true",
+				QuarantineMinAttemptsAnnotation:      "5",
+				QuarantineMaxDelayAnnotation:         "10m",
+				QuarantineRetriesIncrementAnnotation: "30s",
+			},
+			quarantinePolicy{
+				disabled:         true,
+				minAttempts:      5,
+				maxDelay:         10 * time.Minute,
+				retriesIncrement: 30 * time.Second,
+			},
+		},
+		{
+			"invalid annotations are ignored",
+			map[string]string{
+				QuarantineDisabledAnnotation:         "spam",
+				QuarantineMinAttemptsAnnotation:      "-1",
+				QuarantineMaxDelayAnnotation:         "egg",
+				QuarantineRetriesIncrementAnnotation: "-1s",
+			},
+			defaultQuarantinePolicy(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, defaultQuarantinePolicy().withAnnotations(tt.annotations, "test"))
+		})
+	}
+}
+
+func TestPolicyResolverResolve(t *testing.T) {
+	namespaces := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	assert.NoError(t, namespaces.Add(&apiv1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name: "batch",
+		Annotations: map[string]string{
+			QuarantineMinAttemptsAnnotation: "10",
+			QuarantineMaxDelayAnnotation:    "1m",
+		},
+	}}))
+	replicaSets := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	assert.NoError(t, replicaSets.Add(&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+		Name:        "foo-0",
+		Namespace:   "batch",
+		Annotations: map[string]string{QuarantineMinAttemptsAnnotation: "1"},
+	}}))
+
+	ctx := &cacontext.AutoscalingContext{
+		AutoscalingKubeClients: cacontext.AutoscalingKubeClients{
+			ListerRegistry: kube_util.NewListerRegistry(nil, nil, nil, nil, nil, nil, nil, v1appslister.NewReplicaSetLister(replicaSets), nil),
+		},
+	}
+	resolver := &policyResolver{namespaceLister: v1lister.NewNamespaceLister(namespaces)}
+
+	// controller annotations override namespace annotations, which override defaults
+	pod := buildPendingPods(1, "foo", time.Now())[0]
+	pod.Namespace = "batch"
+	pod.OwnerReferences[0].Kind = "ReplicaSet"
+	policy, owner := resolver.resolve(ctx, pod)
+	assert.Equal(t, 1, policy.minAttempts)
+	assert.Equal(t, time.Minute, policy.maxDelay)
+	assert.Equal(t, retriesIncrement, policy.retriesIncrement)
+	assert.IsType(t, &appsv1.ReplicaSet{}, owner)
+
+	// pods whose controller can't be found get events themselves
+	pod = buildPendingPods(1, "bar", time.Now())[0]
+	pod.Namespace = "batch"
+	policy, owner = resolver.resolve(ctx, pod)
+	assert.Equal(t, 10, policy.minAttempts)
+	assert.Equal(t, pod, owner)
+}
+
+func TestProcessWithQuarantinePolicies(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	ctx := &cacontext.AutoscalingContext{
+		AutoscalingOptions: config.AutoscalingOptions{
+			ScaleDownDelayAfterAdd: testScaleDownDelay,
+		},
+		AutoscalingKubeClients: cacontext.AutoscalingKubeClients{
+			Recorder: recorder,
+		},
+	}
+	defer func() { now = time.Now }()
+
+	// opted-out workloads are never quarantined
+	namespaces := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	assert.NoError(t, namespaces.Add(&apiv1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        "optout",
+		Annotations: map[string]string{QuarantineDisabledAnnotation: "true"},
+	}}))
+	now = func() time.Time { return time.Now().Add(-time.Hour) }
+	p := NewFilterOutLongPending()
+	p.resolver = &policyResolver{namespaceLister: v1lister.NewNamespaceLister(namespaces)}
+	pendingPods := buildPendingPods(1, "foo", time.Now().Add(-time.Hour))
+	pendingPods[0].Namespace = "optout"
+	var allowedPods []*apiv1.Pod
+	for i := 1; i < 2*minAttempts; i++ {
+		allowedPods, _ = p.Process(ctx, pendingPods)
+		now = time.Now
+	}
+	assert.ElementsMatch(t, pendingPods, allowedPods)
+	assert.Empty(t, recorder.Events)
+
+	// quarantine and release decisions are emitted as events
+	now = func() time.Time { return time.Now().Add(-time.Hour) }
+	p = NewFilterOutLongPending()
+	p.resolver = &policyResolver{}
+	p.deadlineFunc = func(int, time.Duration, quarantinePolicy) time.Time { return time.Now().Add(time.Minute) }
+	pendingPods = buildPendingPods(1, "foo", time.Now().Add(-time.Hour))
+	for i := 1; i < 2*minAttempts; i++ {
+		allowedPods, _ = p.Process(ctx, pendingPods)
+		now = time.Now
+	}
+	assert.Empty(t, allowedPods)
+	assert.True(t, strings.Contains(<-recorder.Events, "LongPendingQuarantined"))
+
+	p.seen["foo-0"].nextTry = time.Now().Add(-time.Second)
+	allowedPods, _ = p.Process(ctx, pendingPods)
+	assert.ElementsMatch(t, pendingPods, allowedPods)
+	assert.True(t, strings.Contains(<-recorder.Events, "LongPendingReleased"))
+	assert.True(t, strings.Contains(<-recorder.Events, "LongPendingQuarantined"))
+}
@@ -27,6 +27,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/autoscaler/cluster-autoscaler/config"
 	cacontext "k8s.io/autoscaler/cluster-autoscaler/context"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/prescaling"
 )
 
 var testScaleDownDelay = time.Minute
@@ -110,6 +111,17 @@ func TestProcess(t *testing.T) {
 	assert.ElementsMatch(t, newPendingPods, allowedPods)
 	assert.Equal(t, len(newPendingPods), len(p.seen))
 
+	// Case 8: Prescaling fake pods are neither tracked nor quarantined
+	now = func() time.Time { return time.Now().Add(-time.Hour) }
+	p = NewFilterOutLongPending()
+	pendingPods = buildPendingPods(1, "foo", time.Now().Add(-time.Hour))
+	pendingPods[0].Annotations = map[string]string{prescaling.FakePrescalingPodAnnotationKey: prescaling.FakePrescalingPodAnnotationValue}
+	for i := 1; i < 2*minAttempts; i++ {
+		allowedPods, _ = p.Process(testCtx, pendingPods)
+		now = func() time.Time { return time.Now() }
+	}
+	assert.ElementsMatch(t, pendingPods, allowedPods)
+	assert.Empty(t, p.seen)
 }
 
 func TestBuildDeadline(t *testing.T) {
@@ -147,7 +159,7 @@ func TestBuildDeadline(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			now = func() time.Time { return time.Time{} }
 			maxExpected := now().Add(time.Duration(delayFactor+1) * test.coolDownDelay.Abs())
-			result := buildDeadline(test.attempts, test.coolDownDelay)
+			result := buildDeadline(test.attempts, test.coolDownDelay, defaultQuarantinePolicy())
 			if result.Before(now()) || result.After(maxExpected) {
 				t.Errorf("buildDeadline(%v, %v) = %v, should be in [%v-%v] range",
 					test.attempts, test.coolDownDelay, result, now(), maxExpected)
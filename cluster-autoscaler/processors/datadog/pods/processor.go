@@ -18,20 +18,24 @@ package pods
 
 import (
 	"k8s.io/autoscaler/cluster-autoscaler/core/podlistprocessor"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/datadog/common"
 	"k8s.io/autoscaler/cluster-autoscaler/processors/pods"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
+	v1lister "k8s.io/client-go/listers/core/v1"
 )
 
 // NewFilteringPodListProcessor returns an aggregated podlist
 // processor, which wraps and sequentially runs other sub-processors.
 // That's a slight modification of the orignal NewDefaultPodListProcessor()
-// (from core/podlistprocessor/pod_list_processor.go) with NewTransformLocalData()
-// processor on top, to support local-data persistent volumes.
-func NewFilteringPodListProcessor(nodeFilter func(*framework.NodeInfo) bool) *pods.CombinedPodListProcessor {
+// (from core/podlistprocessor/pod_list_processor.go) with NewTransformDataNodes()
+// and NewTransformLocalData() processors on top, to support local-data persistent
+// volumes (and other local storage classes), and the provided long pending pods
+// filter (see NewFilterOutLongPending()).
+func NewFilteringPodListProcessor(nodeFilter func(*framework.NodeInfo) bool, classes common.LocalStorageClasses, pvcLister v1lister.PersistentVolumeClaimLister, longPendingFilter pods.PodListProcessor) *pods.CombinedPodListProcessor {
 	return pods.NewCombinedPodListProcessor([]pods.PodListProcessor{
-		NewTransformLocalData(),
-		NewTransformDataNodes(),
-		NewFilterOutLongPending(),
+		NewTransformDataNodes(classes, pvcLister),
+		NewTransformLocalData(classes, pvcLister),
+		longPendingFilter,
 		podlistprocessor.NewClearTPURequestsPodListProcessor(),
 		podlistprocessor.NewFilterOutExpendablePodListProcessor(),
 		podlistprocessor.NewCurrentlyDrainedNodesPodListProcessor(),
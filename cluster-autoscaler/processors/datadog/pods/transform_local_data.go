@@ -16,7 +16,8 @@ limitations under the License.
 
 /*
   This provides support for local-data persistent volumes, by two means:
-  * Removes volumes using "local-data" from the internal pods copy, for the
+  * Removes volumes using "local-data" (or any other configured local storage
+    class, see --local-storage-class) from the internal pods copy, for the
     duration of the current autoscaler RunOnce loop. local-data volumes (as
     any volume using a no-provisioner storage class) breaks the VolumeBinding
     predicate used during Scheduler Framework's evaluations.
@@ -25,27 +26,23 @@ limitations under the License.
     built from ASG/MIG/VMSS when they offer nodes with local-data storage.
     Those virtual NodeInfos are used when the autoscaler evaluates upscale
     candidates. Injecting those requests on pods allows us to upscale only
-    nodes having local data, and to know those nodes can host a single pod
-    requesting a local-data volume (because allocatable qty = request = 1).
+    nodes having local data, and to know how many pods requesting local-data
+    volumes those nodes can host (one unit requested per local PVC, and one
+    unit offered per local volume, see the local-storage-count label).
 	We also inject an additional custom resource node.datadoghq.com/local-storage
 	which represents the actual amount of storage available on a node.
 	We use this resource to ensure that pods that have PVCs that request local-storage
-	will get scheduled on nodes that have enough storage available.
+	will get scheduled on nodes that have enough storage available (requests from
+	all the pod's local PVCs are summed).
 
   Caveats:
   * That's obviously not upstreamable
-  * With that resource req, none of the existing real nodes can be considered
-    by autoscaler as schedulable for pods requesting local-data volumes: the
-    "storageclass/local-data" resource is only available on virtual nodes built
-    from asg templates (so, during upscale simulations and for upcoming nodes),
-    but not at "filter out pods schedulables on existing real nodes" phase.
-    Hence the need for an other patch: once those nodes just became ready but
-    the pod is not scheduled on them yet (eg. when their local data volume
-    isn't built or bound yet): the autoscaler wouldn't consider those fresh
-    nodes (now evaluated by using nodeInfos built from real/live nodes)
-    suitable for their pendind pods anymore, since now the nodes don't have
-    the requested custom resource. Which would lead to spurious re-upscales
-    during the "node became Ready -> pod now scheduled to that node" phase.
+  * With that resource req, existing real nodes can only be considered by
+    autoscaler as schedulable for pods requesting local-data volumes once
+    their own remaining local storage was accounted for (that's what the
+    transformDataNodes processor does, see transform_nodes_local_data.go):
+    the "storageclass/local-data" resource is otherwise only available on
+    virtual nodes built from asg templates.
   * Using that hack forces the use nodeinfos built from asg templates, rather
     than from real world nodes (as op. to upstream behaviour). Which we do
     also for other reasons anyway (scale from zero + balance similar).
@@ -54,45 +51,46 @@ limitations under the License.
 package pods
 
 import (
-	"time"
-
 	"k8s.io/autoscaler/cluster-autoscaler/context"
 	"k8s.io/autoscaler/cluster-autoscaler/processors/datadog/common"
 
 	apiv1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/fields"
-	client "k8s.io/client-go/kubernetes"
+	"k8s.io/apimachinery/pkg/api/resource"
 	v1lister "k8s.io/client-go/listers/core/v1"
-	"k8s.io/client-go/tools/cache"
 	klog "k8s.io/klog/v2"
 )
 
+const (
+	// defaultContainerAnnotation designates a pod's main container (as used by kubectl)
+	defaultContainerAnnotation = "kubectl.kubernetes.io/default-container"
+)
+
 type transformLocalData struct {
-	pvcLister   v1lister.PersistentVolumeClaimLister
-	stopChannel chan struct{}
+	pvcLister v1lister.PersistentVolumeClaimLister
+	classes   common.LocalStorageClasses
 }
 
 // NewTransformLocalData instantiate a transformLocalData processor
-func NewTransformLocalData() *transformLocalData {
+func NewTransformLocalData(classes common.LocalStorageClasses, pvcLister v1lister.PersistentVolumeClaimLister) *transformLocalData {
 	return &transformLocalData{
-		stopChannel: make(chan struct{}),
+		pvcLister: pvcLister,
+		classes:   classes,
 	}
 }
 
-// CleanUp shuts down the pv lister
+// CleanUp is called at CA termination
 func (p *transformLocalData) CleanUp() {
-	close(p.stopChannel)
 }
 
-// Process replace volumes to local-data pv by our custom resource
+// Process replace volumes to local-data pv by our custom resource. Pods are shared
+// with the informers cache (and read again by transformDataNodes on next loops), so
+// the rewritten pods are copies.
 func (p *transformLocalData) Process(ctx *context.AutoscalingContext, pods []*apiv1.Pod) ([]*apiv1.Pod, error) {
-	if p.pvcLister == nil {
-		p.pvcLister = NewPersistentVolumeClaimLister(ctx.ClientSet, p.stopChannel)
-	}
-
-	for _, po := range pods {
+	var result []*apiv1.Pod
+	for i, po := range pods {
 		var volumes []apiv1.Volume
+		requests := apiv1.ResourceList{}
 		for _, vol := range po.Spec.Volumes {
 			if vol.PersistentVolumeClaim == nil {
 				volumes = append(volumes, vol)
@@ -106,43 +104,89 @@ func (p *transformLocalData) Process(ctx *context.AutoscalingContext, pods []*ap
 				volumes = append(volumes, vol)
 				continue
 			}
-			if *pvc.Spec.StorageClassName != "local-data" {
+			if pvc.Spec.StorageClassName == nil {
 				volumes = append(volumes, vol)
 				continue
 			}
-
-			if len(po.Spec.Containers[0].Resources.Requests) == 0 {
-				po.Spec.Containers[0].Resources.Requests = apiv1.ResourceList{}
-			}
-			if len(po.Spec.Containers[0].Resources.Limits) == 0 {
-				po.Spec.Containers[0].Resources.Limits = apiv1.ResourceList{}
-			}
-			if len(pvc.Spec.Resources.Requests) == 0 {
-				pvc.Spec.Resources.Requests = apiv1.ResourceList{}
+			class, ok := p.classes[*pvc.Spec.StorageClassName]
+			if !ok {
+				volumes = append(volumes, vol)
+				continue
 			}
 
+			// one volume slot, and the requested amount of storage, per local PVC
+			addQuantity(requests, class.ExistsResource, *common.DatadogLocalDataQuantity)
 			if storage, ok := pvc.Spec.Resources.Requests[apiv1.ResourceStorage]; ok {
-				po.Spec.Containers[0].Resources.Requests[common.DatadogLocalStorageResource] = storage.DeepCopy()
-				po.Spec.Containers[0].Resources.Limits[common.DatadogLocalStorageResource] = storage.DeepCopy()
+				addQuantity(requests, class.CapacityResource, storage)
 			} else {
-				po.Spec.Containers[0].Resources.Requests[common.DatadogLocalStorageResource] = common.DatadogLocalDataQuantity.DeepCopy()
-				po.Spec.Containers[0].Resources.Limits[common.DatadogLocalStorageResource] = common.DatadogLocalDataQuantity.DeepCopy()
+				addQuantity(requests, class.CapacityResource, *common.DatadogLocalDataQuantity)
 			}
+		}
+		if len(volumes) == len(po.Spec.Volumes) && len(requests) == 0 {
+			continue
+		}
 
-			po.Spec.Containers[0].Resources.Requests[common.DatadogLocalDataExistsResource] = common.DatadogLocalDataQuantity.DeepCopy()
-			po.Spec.Containers[0].Resources.Limits[common.DatadogLocalDataExistsResource] = common.DatadogLocalDataQuantity.DeepCopy()
+		// copy the slice only when we need to replace some pods
+		if result == nil {
+			result = make([]*apiv1.Pod, len(pods))
+			copy(result, pods)
 		}
+		po = po.DeepCopy()
 		po.Spec.Volumes = volumes
+		if len(requests) > 0 {
+			injectRequests(po, requests)
+		}
+		result[i] = po
+	}
+
+	if result == nil {
+		return pods, nil
+	}
+	return result, nil
+}
+
+// injectRequests adds resources requests (and limits) to the pod's main container.
+// Scheduler sums regular containers requests, so which one carries them doesn't
+// matter much, but we avoid sidecars when a default container is advertised.
+func injectRequests(po *apiv1.Pod, requests apiv1.ResourceList) {
+	container := mainContainer(po)
+	if container == nil {
+		klog.Warningf("can't inject local storage requests on pod %s/%s: no container", po.GetNamespace(), po.GetName())
+		return
+	}
+
+	if len(container.Resources.Requests) == 0 {
+		container.Resources.Requests = apiv1.ResourceList{}
+	}
+	if len(container.Resources.Limits) == 0 {
+		container.Resources.Limits = apiv1.ResourceList{}
 	}
+	for name, quantity := range requests {
+		addQuantity(container.Resources.Requests, name, quantity)
+		addQuantity(container.Resources.Limits, name, quantity)
+	}
+}
 
-	return pods, nil
+// mainContainer returns the container named by the kubectl default-container
+// annotation if any, or the pod's first container.
+func mainContainer(po *apiv1.Pod) *apiv1.Container {
+	if len(po.Spec.Containers) == 0 {
+		return nil
+	}
+	if name, ok := po.GetAnnotations()[defaultContainerAnnotation]; ok {
+		for i := range po.Spec.Containers {
+			if po.Spec.Containers[i].Name == name {
+				return &po.Spec.Containers[i]
+			}
+		}
+	}
+	return &po.Spec.Containers[0]
 }
 
-// NewPersistentVolumeClaimLister builds a persistentvolumeclaim lister.
-func NewPersistentVolumeClaimLister(kubeClient client.Interface, stopchannel <-chan struct{}) v1lister.PersistentVolumeClaimLister {
-	listWatcher := cache.NewListWatchFromClient(kubeClient.CoreV1().RESTClient(), "persistentvolumeclaims", apiv1.NamespaceAll, fields.Everything())
-	store, reflector := cache.NewNamespaceKeyedIndexerAndReflector(listWatcher, &apiv1.PersistentVolumeClaim{}, time.Hour)
-	lister := v1lister.NewPersistentVolumeClaimLister(store)
-	go reflector.Run(stopchannel)
-	return lister
+func addQuantity(resources apiv1.ResourceList, name apiv1.ResourceName, quantity resource.Quantity) {
+	sum := quantity.DeepCopy()
+	if current, ok := resources[name]; ok {
+		sum.Add(current)
+	}
+	resources[name] = sum
 }
@@ -34,6 +34,7 @@ import (
 var (
 	testRemoteClass        = "remote-data"
 	testLocalClass         = "local-data"
+	testNvmeClass          = "local-nvme"
 	testNamespace          = "foons"
 	testEmptyResources     = corev1.ResourceList{}
 	testDefaultLdResources = corev1.ResourceList{
@@ -46,6 +47,15 @@ var (
 		common.DatadogLocalDataExistsResource: common.DatadogLocalDataQuantity.DeepCopy(),
 		common.DatadogLocalStorageResource:    localStorageCapacity.DeepCopy(),
 	}
+	twoLocalStorageCapacity = resource.MustParse("200Gi")
+	testTwoLdResources      = corev1.ResourceList{
+		common.DatadogLocalDataExistsResource: *resource.NewQuantity(2, resource.DecimalSI),
+		common.DatadogLocalStorageResource:    twoLocalStorageCapacity.DeepCopy(),
+	}
+	testNvmeResources = corev1.ResourceList{
+		"storageclass/local-nvme":       common.DatadogLocalDataQuantity.DeepCopy(),
+		"node.datadoghq.com/local-nvme": localStorageCapacity.DeepCopy(),
+	}
 )
 
 func TestTransformLocalDataProcess(t *testing.T) {
@@ -94,6 +104,41 @@ func TestTransformLocalDataProcess(t *testing.T) {
 			[]*corev1.Pod{buildPod("pod1", testDefaultLdResources, testDefaultLdResources, "pvc-1", "pvc-3")},
 		},
 
+		{
+			"several local-data volumes requests are summed",
+			[]*corev1.Pod{buildPod("pod1", testEmptyResources, testEmptyResources, "pvc-1", "pvc-2", "pvc-3")},
+			[]*corev1.PersistentVolumeClaim{
+				buildPVCWithStorage("pvc-1", testLocalClass, localStorage),
+				buildPVC("pvc-2", testRemoteClass),
+				buildPVCWithStorage("pvc-3", testLocalClass, localStorage),
+			},
+			[]*corev1.Pod{buildPod("pod1", testTwoLdResources, testTwoLdResources, "pvc-2")},
+		},
+
+		{
+			"requests are injected on the default container",
+			[]*corev1.Pod{withSidecar(buildPod("pod1", testEmptyResources, testEmptyResources, "pvc-1"), true)},
+			[]*corev1.PersistentVolumeClaim{buildPVCWithStorage("pvc-1", testLocalClass, localStorage)},
+			[]*corev1.Pod{withContainerRequests(withSidecar(buildPod("pod1", testEmptyResources, testEmptyResources), true), 1, testLdResources)},
+		},
+
+		{
+			"requests are injected on the first container without a default container annotation",
+			[]*corev1.Pod{withSidecar(buildPod("pod1", testEmptyResources, testEmptyResources, "pvc-1"), false)},
+			[]*corev1.PersistentVolumeClaim{buildPVCWithStorage("pvc-1", testLocalClass, localStorage)},
+			[]*corev1.Pod{withContainerRequests(withSidecar(buildPod("pod1", testEmptyResources, testEmptyResources), false), 0, testLdResources)},
+		},
+
+		{
+			"configured local storage classes get their own custom resources",
+			[]*corev1.Pod{buildPod("pod1", testEmptyResources, testEmptyResources, "pvc-1", "pvc-2")},
+			[]*corev1.PersistentVolumeClaim{
+				buildPVCWithStorage("pvc-1", testNvmeClass, localStorage),
+				buildPVC("pvc-2", testRemoteClass),
+			},
+			[]*corev1.Pod{buildPod("pod1", testNvmeResources, testNvmeResources, "pvc-2")},
+		},
+
 		{
 			"volumes using missing pvcs are conserved",
 			[]*corev1.Pod{buildPod("pod1", testEmptyResources, testEmptyResources, "pvc-1")},
@@ -114,8 +159,12 @@ func TestTransformLocalDataProcess(t *testing.T) {
 			pvcLister, err := newTestPVCLister(tt.pvcs)
 			assert.NoError(t, err)
 
+			classes, err := common.ParseLocalStorageClasses([]string{testLocalClass, testNvmeClass})
+			assert.NoError(t, err)
+
 			tld := transformLocalData{
 				pvcLister: pvcLister,
+				classes:   classes,
 			}
 			actual, err := tld.Process(&context.AutoscalingContext{}, tt.pods)
 			assert.NoError(t, err)
@@ -170,6 +219,30 @@ func buildPod(name string, requests, limits corev1.ResourceList, claimNames ...s
 	return pod
 }
 
+// withSidecar prepends a sidecar container to the pod, and marks the
+// original container as the default one when annotate is true.
+func withSidecar(pod *corev1.Pod, annotate bool) *corev1.Pod {
+	pod.Spec.Containers[0].Name = "main"
+	sidecar := corev1.Container{
+		Name: "sidecar",
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{},
+			Limits:   corev1.ResourceList{},
+		},
+	}
+	pod.Spec.Containers = append([]corev1.Container{sidecar}, pod.Spec.Containers...)
+	if annotate {
+		pod.Annotations = map[string]string{defaultContainerAnnotation: "main"}
+	}
+	return pod
+}
+
+func withContainerRequests(pod *corev1.Pod, index int, requests corev1.ResourceList) *corev1.Pod {
+	pod.Spec.Containers[index].Resources.Requests = requests.DeepCopy()
+	pod.Spec.Containers[index].Resources.Limits = requests.DeepCopy()
+	return pod
+}
+
 func buildPVC(name string, storageClassName string) *corev1.PersistentVolumeClaim {
 	return &corev1.PersistentVolumeClaim{
 		ObjectMeta: metav1.ObjectMeta{
@@ -15,32 +15,33 @@ limitations under the License.
 */
 
 /*
-  This hack completes the other sub-processor that add storageclass/local-data
+  This completes the other sub-processor that add storageclass/local-data
   custom resources requests to pods requesting local-data.
 
-  Use case being addressed here is: when a node with local-storage just joined,
-  its PV isn't immediately available, and pods that triggered the upscale will
-  remain pending for a moment. The autoscaler might run during that window,
-  evaluate those pods against the just joined nodes, and would consider them
-  unschedulable as the fresh real nodes don't have the custom local-data
-  resource allocatable (as op. to virtual nodes built from ASG templates).
-  Which would cause a spurious re-upscale.
-
-  Decorating those fresh nodes with the requested resources they were expected
-  to offer when created will help the autoscaler to consider them usable for
-  still pending pods, which then won't be flagged as unschedulable and won't
-  trigger an other upscale.
-
-  Theorically all nodes labeled local-storage:true could be injected that custom
-  resource, for a better accuracy. But side effects of that generalisation
-  needs to be evaluated carefully. For instance, absence of the custom resource
-  prevents the autoscaler to even try to repack stateful pods (as they wouldn't
-  fit anywhere). That's why we limit the change to recent local-data nodes for
-  now (later: evaluate if we can safely apply to all local-data real nodes).
-
-  This replaces a previous workaround that did set those new nodes as "unready"
-  until an LVP pod was running there for 90s, but in a way that don't require
-  altering core, and better mimic normal nodes behaviour.
+  Real (live) nodes don't carry the local storage custom resources: only the
+  virtual nodes built from ASG templates do. So without help, none of the
+  existing nodes could be considered by the "filter out pods schedulable on
+  existing nodes" phase for pods requesting local-data volumes, and pods that
+  triggered an upscale would cause spurious re-upscales during the "node became
+  Ready -> local volume discovered and bound -> pod scheduled" window.
+
+  This processor accounts for the remaining local storage of every real node
+  labeled as offering a local storage class, and sets it as allocatable on the
+  nodes from the cluster snapshot (for the duration of the current loop):
+  * When local PVs of that class are already pinned to the node (through their
+    node affinity), the remaining capacity is what the unclaimed PVs offer: one
+    volume slot and their storage capacity for each unclaimed PV.
+  * When no PV was discovered yet for that node (eg. it just joined and the
+    local volume provisioner didn't create them yet), we use the volumes count
+    and capacity advertised by the node labels (as for templates), minus the
+    storage requested by PVCs the scheduler already selected that node for.
+  Claims of the pending pods are considered unclaimed: those pods are given
+  local storage requests for them (see transform_local_data.go), so they must
+  still fit on the node their volumes are bound to. This processor must thus
+  run before transformLocalData removes the local volumes from the pods.
+
+  Pending pods requesting local-data volumes can then be placed onto existing
+  nodes having enough free local storage, rather than triggering upscales.
 */
 
 package pods
@@ -52,49 +53,218 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/processors/datadog/common"
 
 	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	client "k8s.io/client-go/kubernetes"
+	v1lister "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
+	klog "k8s.io/klog/v2"
 )
 
 const (
-	// NodeReadyGraceDelay is time during which we inject custom resource after a node becomes ready
-	NodeReadyGraceDelay = 5 * time.Minute
+	// selectedNodeAnnotation is set by the scheduler on PVCs using a WaitForFirstConsumer storage class
+	selectedNodeAnnotation = "volume.kubernetes.io/selected-node"
 )
 
-type transformDataNodes struct{}
+type transformDataNodes struct {
+	classes     common.LocalStorageClasses
+	pvLister    v1lister.PersistentVolumeLister
+	pvcLister   v1lister.PersistentVolumeClaimLister
+	stopChannel chan struct{}
+}
 
-// NewTransformDataNodes returns a processor injecting local data custom resource
-func NewTransformDataNodes() *transformDataNodes {
-	return &transformDataNodes{}
+// NewTransformDataNodes returns a processor accounting for real nodes local storage
+func NewTransformDataNodes(classes common.LocalStorageClasses, pvcLister v1lister.PersistentVolumeClaimLister) *transformDataNodes {
+	return &transformDataNodes{
+		classes:     classes,
+		pvcLister:   pvcLister,
+		stopChannel: make(chan struct{}),
+	}
 }
 
 // CleanUp tears down a transformDataNodes processor
-func (p *transformDataNodes) CleanUp() {}
+func (p *transformDataNodes) CleanUp() {
+	close(p.stopChannel)
+}
 
-// Process injects local data custom resources to nodes offering local-data storage, that became ready since less than 5mn
+// Process sets the remaining local storage as allocatable on nodes offering local storage classes
 func (p *transformDataNodes) Process(ctx *context.AutoscalingContext, pods []*apiv1.Pod) ([]*apiv1.Pod, error) {
+	if p.pvLister == nil {
+		p.pvLister = NewPersistentVolumeLister(ctx.ClientSet, p.stopChannel)
+	}
+
 	nodeInfos, err := ctx.ClusterSnapshot.NodeInfos().List()
 	if err != nil {
 		return pods, err
 	}
 
+	pvs, err := p.pvLister.List(labels.Everything())
+	if err != nil {
+		return pods, err
+	}
+	pvcs, err := p.pvcLister.List(labels.Everything())
+	if err != nil {
+		return pods, err
+	}
+
+	nodes := make([]*apiv1.Node, 0, len(nodeInfos))
+	for _, nodeInfo := range nodeInfos {
+		nodes = append(nodes, nodeInfo.Node())
+	}
+	var localPVs []*apiv1.PersistentVolume
+	for _, pv := range pvs {
+		if _, ok := p.classes[pv.Spec.StorageClassName]; ok {
+			localPVs = append(localPVs, pv)
+		}
+	}
+	pvsByNode := localPVsByNode(localPVs, nodes)
+	pvcsByNode := make(map[string][]*apiv1.PersistentVolumeClaim)
+	for _, pvc := range pvcs {
+		if nodeName, ok := pvc.GetAnnotations()[selectedNodeAnnotation]; ok && pvc.Spec.VolumeName == "" {
+			pvcsByNode[nodeName] = append(pvcsByNode[nodeName], pvc)
+		}
+	}
+	pendingClaims := make(map[types.NamespacedName]bool)
+	for _, po := range pods {
+		for _, vol := range po.Spec.Volumes {
+			if vol.PersistentVolumeClaim != nil {
+				pendingClaims[types.NamespacedName{Namespace: po.Namespace, Name: vol.PersistentVolumeClaim.ClaimName}] = true
+			}
+		}
+	}
+
 	for _, nodeInfo := range nodeInfos {
 		node := nodeInfo.Node()
-		if !common.NodeHasLocalData(node) {
+		for _, class := range p.classes.OfferedBy(node) {
+			count, storage := remainingLocalStorage(node, class, pvsByNode[node.Name], pvcsByNode[node.Name], pendingClaims)
+			common.SetNodeLocalStorageAllocatable(nodeInfo, class, count, storage)
+		}
+	}
+
+	return pods, nil
+}
+
+// remainingLocalStorage returns the number of volumes and the amount of storage
+// still available on a node, for a given local storage class. Volumes and claims
+// of pending pods count as available, as those pods request them again.
+func remainingLocalStorage(node *apiv1.Node, class *common.LocalStorageClass, pvs []*apiv1.PersistentVolume, pvcs []*apiv1.PersistentVolumeClaim, pendingClaims map[types.NamespacedName]bool) (resource.Quantity, resource.Quantity) {
+	count := *resource.NewQuantity(0, resource.DecimalSI)
+	storage := *resource.NewQuantity(0, resource.BinarySI)
+
+	discovered := false
+	for _, pv := range pvs {
+		if pv.Spec.StorageClassName != class.StorageClassName {
+			continue
+		}
+		discovered = true
+		if ref := pv.Spec.ClaimRef; ref != nil && !pendingClaims[types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}] {
 			continue
 		}
+		count.Add(*common.DatadogLocalDataQuantity)
+		if capacity, ok := pv.Spec.Capacity[apiv1.ResourceStorage]; ok {
+			storage.Add(capacity)
+		}
+	}
+	if discovered {
+		return count, storage
+	}
 
-		// TODO: evaluate if that age check is really needed
-		readyTimestamp := time.Now()
-		for _, condition := range node.Status.Conditions {
-			if condition.Type == apiv1.NodeReady && condition.Status == apiv1.ConditionTrue {
-				readyTimestamp = condition.LastTransitionTime.Time
-			}
+	// local volumes weren't discovered yet: trust the node labels
+	count, storage = common.NodeLocalStorageCapacity(node, class)
+	for _, pvc := range pvcs {
+		if pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName != class.StorageClassName {
+			continue
 		}
-		if readyTimestamp.Add(NodeReadyGraceDelay).Before(time.Now()) {
+		if pendingClaims[types.NamespacedName{Namespace: pvc.Namespace, Name: pvc.Name}] {
 			continue
 		}
+		count.Sub(*common.DatadogLocalDataQuantity)
+		if request, ok := pvc.Spec.Resources.Requests[apiv1.ResourceStorage]; ok {
+			storage.Sub(request)
+		}
+	}
+	if count.Sign() < 0 {
+		count.Set(0)
+	}
+	if storage.Sign() < 0 {
+		storage.Set(0)
+	}
+
+	return count, storage
+}
 
-		common.SetNodeLocalDataResource(nodeInfo)
+// localPVsByNode returns the local PVs pinned to each node. Local PVs node affinity
+// usually selects a hostname, so they're only matched against the nodes having the
+// selected hostnames, rather than against every node.
+func localPVsByNode(pvs []*apiv1.PersistentVolume, nodes []*apiv1.Node) map[string][]*apiv1.PersistentVolume {
+	nodesByHostname := make(map[string][]*apiv1.Node)
+	for _, node := range nodes {
+		if hostname, ok := node.Labels[apiv1.LabelHostname]; ok {
+			nodesByHostname[hostname] = append(nodesByHostname[hostname], node)
+		}
 	}
 
-	return pods, nil
+	pvsByNode := make(map[string][]*apiv1.PersistentVolume)
+	for _, pv := range pvs {
+		candidates := nodes
+		if hostnames, ok := selectedHostnames(pv); ok {
+			candidates = nil
+			for _, hostname := range hostnames {
+				candidates = append(candidates, nodesByHostname[hostname]...)
+			}
+		}
+		for _, node := range candidates {
+			if pvOnNode(pv, node) {
+				pvsByNode[node.Name] = append(pvsByNode[node.Name], pv)
+			}
+		}
+	}
+	return pvsByNode
+}
+
+// selectedHostnames returns the hostnames the PV node affinity restricts it to, when
+// each of its node selector terms requires one of a set of hostnames.
+func selectedHostnames(pv *apiv1.PersistentVolume) ([]string, bool) {
+	if pv.Spec.NodeAffinity == nil || pv.Spec.NodeAffinity.Required == nil {
+		return nil, false
+	}
+	var hostnames []string
+	for _, term := range pv.Spec.NodeAffinity.Required.NodeSelectorTerms {
+		found := false
+		for _, req := range term.MatchExpressions {
+			if req.Key == apiv1.LabelHostname && req.Operator == apiv1.NodeSelectorOpIn {
+				hostnames = append(hostnames, req.Values...)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+	return hostnames, true
+}
+
+func pvOnNode(pv *apiv1.PersistentVolume, node *apiv1.Node) bool {
+	if pv.Spec.NodeAffinity == nil || pv.Spec.NodeAffinity.Required == nil {
+		return false
+	}
+	match, err := corev1helpers.MatchNodeSelectorTerms(node, pv.Spec.NodeAffinity.Required)
+	if err != nil {
+		klog.Warningf("failed to match pv %s node affinity against node %s: %v", pv.Name, node.Name, err)
+		return false
+	}
+	return match
+}
+
+// NewPersistentVolumeLister builds a persistentvolume lister.
+func NewPersistentVolumeLister(kubeClient client.Interface, stopchannel <-chan struct{}) v1lister.PersistentVolumeLister {
+	listWatcher := cache.NewListWatchFromClient(kubeClient.CoreV1().RESTClient(), "persistentvolumes", apiv1.NamespaceAll, fields.Everything())
+	store, reflector := cache.NewNamespaceKeyedIndexerAndReflector(listWatcher, &apiv1.PersistentVolume{}, time.Hour)
+	lister := v1lister.NewPersistentVolumeLister(store)
+	go reflector.Run(stopchannel)
+	return lister
 }
@@ -19,55 +19,153 @@ package pods
 import (
 	"fmt"
 	"testing"
-	"time"
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/autoscaler/cluster-autoscaler/context"
 	"k8s.io/autoscaler/cluster-autoscaler/processors/datadog/common"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/clustersnapshot/testsnapshot"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
+	v1lister "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
 )
 
 func TestTransformDataNodesProcess(t *testing.T) {
 	localStorageValue := "20G"
 	localStorageQuantity := resource.MustParse(localStorageValue)
+	volumeQuantity := resource.MustParse("10G")
+	one := *resource.NewQuantity(1, resource.DecimalSI)
+	two := *resource.NewQuantity(2, resource.DecimalSI)
+	zero := *resource.NewQuantity(0, resource.DecimalSI)
+	zeroBytes := *resource.NewQuantity(0, resource.BinarySI)
+
 	tests := []struct {
 		name     string
 		node     *corev1.Node
+		pvs      []*corev1.PersistentVolume
+		pvcs     []*corev1.PersistentVolumeClaim
 		expected *corev1.Node
+		pods     []*corev1.Pod
 	}{
+		{
+			"Labels capacity is added to nodes without discovered local volumes",
+			buildTestNode("a", true, localStorageValue),
+			nil,
+			nil,
+			withLocalStorage(buildTestNode("a", true, localStorageValue), one, localStorageQuantity, one, localStorageQuantity),
+			nil,
+		},
+
+		{
+			"Resource is not added to nodes without local-data label",
+			buildTestNode("c", false, ""),
+			nil,
+			nil,
+			buildTestNode("c", false, ""),
+			nil,
+		},
+
+		{
+			"Resource is not added to nodes without local-data label but has local storage capacity label",
+			buildTestNode("d", false, localStorageValue),
+			nil,
+			nil,
+			buildTestNode("d", false, localStorageValue),
+			nil,
+		},
+
+		{
+			"Default resource is added to nodes without local storage capacity label",
+			buildTestNode("e", true, ""),
+			nil,
+			nil,
+			withLocalStorage(buildTestNode("e", true, ""), one, one, one, one),
+			nil,
+		},
+
+		{
+			"Unclaimed local volumes are allocatable",
+			buildTestNode("f", true, localStorageValue),
+			[]*corev1.PersistentVolume{
+				buildPV("pv-1", testLocalClass, "f", "10G", false),
+				buildPV("pv-2", testLocalClass, "f", "10G", false),
+				buildPV("pv-3", testLocalClass, "other", "10G", false),
+				buildPV("pv-4", testRemoteClass, "f", "10G", false),
+			},
+			nil,
+			withLocalStorage(buildTestNode("f", true, localStorageValue), one, localStorageQuantity, two, localStorageQuantity),
+			nil,
+		},
 
 		{
-			"Resource is added to fresh nodes having local-data label",
-			buildTestNode("a", NodeReadyGraceDelay/2, true, localStorageValue, nil),
-			buildTestNode("a", NodeReadyGraceDelay/2, true, localStorageValue, &localStorageQuantity),
+			"Claimed local volumes aren't allocatable",
+			buildTestNode("g", true, localStorageValue),
+			[]*corev1.PersistentVolume{
+				buildPV("pv-1", testLocalClass, "g", "10G", true),
+				buildPV("pv-2", testLocalClass, "g", "10G", false),
+			},
+			nil,
+			withLocalStorage(buildTestNode("g", true, localStorageValue), one, localStorageQuantity, one, volumeQuantity),
+			nil,
 		},
 
 		{
-			"Resource is not added to old nodes having local-data label",
-			buildTestNode("b", 2*NodeReadyGraceDelay, true, localStorageValue, nil),
-			buildTestNode("b", 2*NodeReadyGraceDelay, true, localStorageValue, nil),
+			"Nodes having all their local volumes claimed have no allocatable",
+			buildTestNode("h", true, localStorageValue),
+			[]*corev1.PersistentVolume{buildPV("pv-1", testLocalClass, "h", "20G", true)},
+			nil,
+			withLocalStorage(buildTestNode("h", true, localStorageValue), one, localStorageQuantity, zero, zeroBytes),
+			nil,
+		},
+
+		{
+			"Claims already selected for nodes without discovered volumes are deducted",
+			buildTestNode("i", true, localStorageValue),
+			nil,
+			[]*corev1.PersistentVolumeClaim{
+				withSelectedNode(buildPVCWithStorage("pvc-1", testLocalClass, "20G"), "i"),
+				withSelectedNode(buildPVCWithStorage("pvc-2", testRemoteClass, "20G"), "i"),
+				withSelectedNode(buildPVCWithStorage("pvc-3", testLocalClass, "20G"), "other"),
+			},
+			withLocalStorage(buildTestNode("i", true, localStorageValue), one, localStorageQuantity, zero, zeroBytes),
+			nil,
 		},
 
 		{
-			"Resource is not added to new nodes without local-data label",
-			buildTestNode("c", NodeReadyGraceDelay/2, false, "", nil),
-			buildTestNode("c", NodeReadyGraceDelay/2, false, "", nil),
+			"Local volumes bound to pending pods claims are allocatable",
+			buildTestNode("j", true, localStorageValue),
+			[]*corev1.PersistentVolume{
+				buildPV("pv-1", testLocalClass, "j", "10G", true),
+				buildPV("pv-2", testLocalClass, "j", "10G", true),
+			},
+			nil,
+			withLocalStorage(buildTestNode("j", true, localStorageValue), one, localStorageQuantity, one, volumeQuantity),
+			[]*corev1.Pod{buildPod("pod1", testEmptyResources, testEmptyResources, "claim-pv-1")},
 		},
 
 		{
-			"Resource is not added to new nodes without local-data label but has local storage capacity label",
-			buildTestNode("d", NodeReadyGraceDelay/2, false, localStorageValue, nil),
-			buildTestNode("d", NodeReadyGraceDelay/2, false, localStorageValue, nil),
+			"Claims of pending pods selected for nodes without discovered volumes aren't deducted",
+			buildTestNode("k", true, localStorageValue),
+			nil,
+			[]*corev1.PersistentVolumeClaim{
+				withSelectedNode(buildPVCWithStorage("pvc-1", testLocalClass, "20G"), "k"),
+			},
+			withLocalStorage(buildTestNode("k", true, localStorageValue), one, localStorageQuantity, one, localStorageQuantity),
+			[]*corev1.Pod{buildPod("pod1", testEmptyResources, testEmptyResources, "pvc-1")},
 		},
 
 		{
-			"Default resource is added to new nodes without local storage capacity label",
-			buildTestNode("e", NodeReadyGraceDelay/2, true, "", nil),
-			buildTestNode("e", NodeReadyGraceDelay/2, true, "", common.DatadogLocalDataQuantity),
+			"Local volumes not selecting nodes by hostname are matched",
+			buildTestNode("l", true, localStorageValue),
+			[]*corev1.PersistentVolume{
+				withZoneAffinity(buildPV("pv-1", testLocalClass, "l", "10G", false)),
+			},
+			nil,
+			withLocalStorage(buildTestNode("l", true, localStorageValue), one, localStorageQuantity, one, volumeQuantity),
+			nil,
 		},
 	}
 
@@ -81,36 +179,51 @@ func TestTransformDataNodesProcess(t *testing.T) {
 				ClusterSnapshot: clusterSnapshot,
 			}
 
-			proc := NewTransformDataNodes()
-			_, err = proc.Process(ctx, []*corev1.Pod{})
+			pvLister, err := newTestPVLister(tt.pvs)
+			assert.NoError(t, err)
+			pvcLister, err := newTestPVCLister(tt.pvcs)
+			assert.NoError(t, err)
+
+			proc := NewTransformDataNodes(common.NewDefaultLocalStorageClasses(), pvcLister)
+			proc.pvLister = pvLister
+			_, err = proc.Process(ctx, tt.pods)
 			assert.NoError(t, err)
 
 			actual, err := ctx.ClusterSnapshot.NodeInfos().Get(tt.node.GetName())
 			assert.NoError(t, err)
 
-			assert.Equal(t, tt.expected.Status.Capacity, actual.Node().Status.Capacity)
-			assert.Equal(t, tt.expected.Status.Allocatable, actual.Node().Status.Allocatable)
+			assert.True(t, apiequality.Semantic.DeepEqual(tt.expected.Status.Capacity, actual.Node().Status.Capacity))
+			assert.True(t, apiequality.Semantic.DeepEqual(tt.expected.Status.Allocatable, actual.Node().Status.Allocatable))
 		})
 	}
 
 }
 
-func buildTestNode(name string, age time.Duration, localDataLabel bool, localStorageCapacityLabel string, localDataQuantity *resource.Quantity) *corev1.Node {
+func newTestPVLister(pvs []*corev1.PersistentVolume) (v1lister.PersistentVolumeLister, error) {
+	store := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, pv := range pvs {
+		err := store.Add(pv)
+		if err != nil {
+			return nil, fmt.Errorf("Error adding object to cache: %v", err)
+		}
+	}
+	return v1lister.NewPersistentVolumeLister(store), nil
+}
+
+func buildTestNode(name string, localDataLabel bool, localStorageCapacityLabel string) *corev1.Node {
 	node := &corev1.Node{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:              name,
-			SelfLink:          fmt.Sprintf("/api/v1/nodes/%s", name),
-			Labels:            map[string]string{},
-			CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+			Name:     name,
+			SelfLink: fmt.Sprintf("/api/v1/nodes/%s", name),
+			Labels:   map[string]string{corev1.LabelHostname: name, corev1.LabelTopologyZone: "zone-a"},
 		},
 		Status: corev1.NodeStatus{
 			Capacity:    corev1.ResourceList{},
 			Allocatable: corev1.ResourceList{},
 			Conditions: []corev1.NodeCondition{
 				{
-					Type:               corev1.NodeReady,
-					Status:             corev1.ConditionTrue,
-					LastTransitionTime: metav1.NewTime(time.Now().Add(-age)),
+					Type:   corev1.NodeReady,
+					Status: corev1.ConditionTrue,
 				},
 			},
 		},
@@ -123,13 +236,103 @@ func buildTestNode(name string, age time.Duration, localDataLabel bool, localSto
 		node.ObjectMeta.Labels[common.DatadogLocalStorageCapacityLabel] = localStorageCapacityLabel
 	}
 
-	if localDataQuantity != nil {
-		node.Status.Capacity[common.DatadogLocalDataExistsResource] = common.DatadogLocalDataQuantity.DeepCopy()
-		node.Status.Allocatable[common.DatadogLocalDataExistsResource] = common.DatadogLocalDataQuantity.DeepCopy()
+	return node
+}
+
+func withLocalStorage(node *corev1.Node, capacityCount, capacityStorage, allocatableCount, allocatableStorage resource.Quantity) *corev1.Node {
+	node.Status.Capacity[common.DatadogLocalDataExistsResource] = capacityCount
+	node.Status.Capacity[common.DatadogLocalStorageResource] = capacityStorage
+	node.Status.Allocatable[common.DatadogLocalDataExistsResource] = allocatableCount
+	node.Status.Allocatable[common.DatadogLocalStorageResource] = allocatableStorage
+	return node
+}
 
-		node.Status.Capacity[common.DatadogLocalStorageResource] = localDataQuantity.DeepCopy()
-		node.Status.Allocatable[common.DatadogLocalStorageResource] = localDataQuantity.DeepCopy()
+func buildPV(name, storageClassName, nodeName, capacity string, claimed bool) *corev1.PersistentVolume {
+	pv := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: corev1.PersistentVolumeSpec{
+			StorageClassName: storageClassName,
+			Capacity: corev1.ResourceList{
+				corev1.ResourceStorage: resource.MustParse(capacity),
+			},
+			NodeAffinity: &corev1.VolumeNodeAffinity{
+				Required: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{
+						{
+							MatchExpressions: []corev1.NodeSelectorRequirement{
+								{
+									Key:      corev1.LabelHostname,
+									Operator: corev1.NodeSelectorOpIn,
+									Values:   []string{nodeName},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	if claimed {
+		pv.Spec.ClaimRef = &corev1.ObjectReference{Namespace: testNamespace, Name: "claim-" + name}
 	}
+	return pv
+}
 
-	return node
+func withZoneAffinity(pv *corev1.PersistentVolume) *corev1.PersistentVolume {
+	term := &pv.Spec.NodeAffinity.Required.NodeSelectorTerms[0]
+	term.MatchExpressions[0] = corev1.NodeSelectorRequirement{
+		Key:      corev1.LabelTopologyZone,
+		Operator: corev1.NodeSelectorOpIn,
+		Values:   []string{"zone-a"},
+	}
+	return pv
+}
+
+func withSelectedNode(pvc *corev1.PersistentVolumeClaim, nodeName string) *corev1.PersistentVolumeClaim {
+	pvc.Annotations = map[string]string{selectedNodeAnnotation: nodeName}
+	return pvc
+}
+
+func TestTransformDataNodesProcessTwice(t *testing.T) {
+	localStorageQuantity := resource.MustParse("20G")
+	volumeQuantity := resource.MustParse("10G")
+	one := *resource.NewQuantity(1, resource.DecimalSI)
+
+	node := buildTestNode("a", true, "20G")
+	clusterSnapshot := testsnapshot.NewTestSnapshotOrDie(t)
+	assert.NoError(t, clusterSnapshot.AddNodeInfo(framework.NewTestNodeInfo(node)))
+	ctx := &context.AutoscalingContext{ClusterSnapshot: clusterSnapshot}
+
+	pvLister, err := newTestPVLister([]*corev1.PersistentVolume{
+		buildPV("pv-1", testLocalClass, "a", "10G", true),
+		buildPV("pv-2", testLocalClass, "a", "10G", true),
+	})
+	assert.NoError(t, err)
+	pvcLister, err := newTestPVCLister([]*corev1.PersistentVolumeClaim{
+		buildPVCWithStorage("claim-pv-1", testLocalClass, "10G"),
+		buildPVCWithStorage("claim-pv-2", testLocalClass, "10G"),
+	})
+	assert.NoError(t, err)
+
+	classes := common.NewDefaultLocalStorageClasses()
+	nodesProc := NewTransformDataNodes(classes, pvcLister)
+	nodesProc.pvLister = pvLister
+	podsProc := NewTransformLocalData(classes, pvcLister)
+
+	// the same (informer cached) pod objects are listed on every loop
+	pods := []*corev1.Pod{buildPod("pod1", testEmptyResources, testEmptyResources, "claim-pv-1")}
+	for i := 0; i < 2; i++ {
+		_, err = nodesProc.Process(ctx, pods)
+		assert.NoError(t, err)
+		_, err = podsProc.Process(ctx, pods)
+		assert.NoError(t, err)
+
+		actual, err := ctx.ClusterSnapshot.NodeInfos().Get(node.GetName())
+		assert.NoError(t, err)
+		expected := withLocalStorage(buildTestNode("a", true, "20G"), one, localStorageQuantity, one, volumeQuantity)
+		assert.True(t, apiequality.Semantic.DeepEqual(expected.Status.Allocatable, actual.Node().Status.Allocatable), "loop %d", i)
+	}
+	assert.Len(t, pods[0].Spec.Volumes, 1)
 }
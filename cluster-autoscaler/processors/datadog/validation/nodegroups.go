@@ -0,0 +1,229 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+  Validates the Datadog labels carried by node groups templates.
+
+  Upscales of node groups offering local storage depend on the node groups
+  tags (turned into template labels) being well-formed: a typo in a capacity
+  quantity silently yields a template with a 1 unit storage capacity, and a
+  "True" instead of "true" yields a template without the local storage
+  resources, so pods needing local volumes never trigger upscales.
+
+  This builds every template NodeInfo through the Datadog processors (as the
+  autoscaler does at runtime) and reports malformed or inconsistent labels and
+  resources, so they can be caught by CI before reaching a cluster.
+*/
+
+package validation
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/context"
+	"k8s.io/autoscaler/cluster-autoscaler/core"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/datadog/common"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/datadog/nodeinfosprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/taints"
+)
+
+const (
+	datadogNodeGroupsLabelPrefix = "nodegroups.datadoghq.com/"
+)
+
+// Severity tells if a Finding breaks upscales (error), or is only suspicious (warning)
+type Severity string
+
+const (
+	// SeverityError findings yield wrong templates
+	SeverityError Severity = "error"
+	// SeverityWarning findings are ignored by the autoscaler, but likely mistakes
+	SeverityWarning Severity = "warning"
+)
+
+// Finding describes a problem found on a node group template
+type Finding struct {
+	NodeGroup    string   `json:"nodeGroup"`
+	Severity     Severity `json:"severity"`
+	StorageClass string   `json:"storageClass,omitempty"`
+	Label        string   `json:"label,omitempty"`
+	Resource     string   `json:"resource,omitempty"`
+	Value        string   `json:"value,omitempty"`
+	Message      string   `json:"message"`
+}
+
+// Report holds the outcome of a node groups validation
+type Report struct {
+	NodeGroups int       `json:"nodeGroups"`
+	Errors     int       `json:"errors"`
+	Warnings   int       `json:"warnings"`
+	Findings   []Finding `json:"findings"`
+}
+
+// HasErrors returns true if any node group template is wrong
+func (r *Report) HasErrors() bool {
+	return r.Errors > 0
+}
+
+func (r *Report) add(finding Finding) {
+	switch finding.Severity {
+	case SeverityError:
+		r.Errors++
+	case SeverityWarning:
+		r.Warnings++
+	}
+	r.Findings = append(r.Findings, finding)
+}
+
+// ValidateNodeGroups builds every node group template NodeInfo through the Datadog
+// processors, and reports malformed or inconsistent Datadog labels and resources.
+// PodTemplates are only applied to templates when opts are provided.
+func ValidateNodeGroups(cloudProvider cloudprovider.CloudProvider, classes common.LocalStorageClasses, opts *core.AutoscalerOptions) *Report {
+	ttl := time.Hour
+	provider := nodeinfosprovider.NewTemplateOnlyNodeInfoProvider(&ttl, false, false, classes, opts)
+	ctx := &context.AutoscalingContext{CloudProvider: cloudProvider}
+	templates, _ := provider.Process(ctx, nil, nil, taints.TaintConfig{}, time.Now())
+	provider.CleanUp()
+
+	nodeGroups := cloudProvider.NodeGroups()
+	sort.Slice(nodeGroups, func(i, j int) bool {
+		return nodeGroups[i].Id() < nodeGroups[j].Id()
+	})
+
+	report := &Report{NodeGroups: len(nodeGroups), Findings: []Finding{}}
+	for _, nodeGroup := range nodeGroups {
+		id := nodeGroup.Id()
+		nodeInfo, found := templates[id]
+		if !found || nodeInfo.Node() == nil {
+			message := "failed to build template NodeInfo"
+			if _, err := nodeGroup.TemplateNodeInfo(); err != nil {
+				message = fmt.Sprintf("%s: %v", message, err)
+			}
+			report.add(Finding{NodeGroup: id, Severity: SeverityError, Message: message})
+			continue
+		}
+		validateNode(report, id, nodeInfo.Node(), classes)
+	}
+	return report
+}
+
+func validateNode(report *Report, id string, node *apiv1.Node, classes common.LocalStorageClasses) {
+	known := make(map[string]bool)
+	for _, name := range sortedClassNames(classes) {
+		class := classes[name]
+		known[class.Label], known[class.CapacityLabel], known[class.CountLabel] = true, true, true
+		validateClass(report, id, node, class)
+	}
+
+	// a typo in a class name (or a class missing from --local-storage-class) means the labels are ignored
+	var keys []string
+	for key := range node.GetLabels() {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if known[key] || !strings.HasPrefix(key, datadogNodeGroupsLabelPrefix) {
+			continue
+		}
+		if strings.HasPrefix(key, datadogNodeGroupsLabelPrefix+"local-") || strings.HasSuffix(key, "-capacity") || strings.HasSuffix(key, "-count") {
+			report.add(Finding{NodeGroup: id, Severity: SeverityWarning, Label: key, Value: node.Labels[key],
+				Message: "looks like a local storage label, but doesn't match any configured local storage class"})
+		}
+	}
+}
+
+func validateClass(report *Report, id string, node *apiv1.Node, class *common.LocalStorageClass) {
+	finding := func(severity Severity, label, value, message string, args ...interface{}) {
+		report.add(Finding{NodeGroup: id, Severity: severity, StorageClass: class.StorageClassName, Label: label, Value: value, Message: fmt.Sprintf(message, args...)})
+	}
+	resourceFinding := func(name apiv1.ResourceName, message string, args ...interface{}) {
+		report.add(Finding{NodeGroup: id, Severity: SeverityError, StorageClass: class.StorageClassName, Resource: string(name), Message: fmt.Sprintf(message, args...)})
+	}
+
+	labels := node.GetLabels()
+	value, hasLabel := labels[class.Label]
+	capacityValue, hasCapacity := labels[class.CapacityLabel]
+	countValue, hasCount := labels[class.CountLabel]
+	allocatable := node.Status.Allocatable
+
+	if hasLabel && value != "true" && value != "false" {
+		finding(SeverityError, class.Label, value, "expected \"true\" or \"false\", the node group won't be considered as offering %s", class.StorageClassName)
+	}
+
+	if !class.NodeOffers(node) {
+		if hasCapacity {
+			finding(SeverityWarning, class.CapacityLabel, capacityValue, "ignored, as %s isn't \"true\"", class.Label)
+		}
+		if hasCount {
+			finding(SeverityWarning, class.CountLabel, countValue, "ignored, as %s isn't \"true\"", class.Label)
+		}
+		for _, name := range []apiv1.ResourceName{class.ExistsResource, class.CapacityResource} {
+			if _, found := allocatable[name]; found {
+				resourceFinding(name, "set on the template, while %s isn't \"true\"", class.Label)
+			}
+		}
+		return
+	}
+
+	expectedStorage, storageOk := resource.Quantity{}, false
+	if !hasCapacity {
+		finding(SeverityError, class.CapacityLabel, "", "missing, the template gets a %s storage capacity", common.DatadogLocalDataQuantity.String())
+	} else if quantity, err := resource.ParseQuantity(capacityValue); err != nil {
+		finding(SeverityError, class.CapacityLabel, capacityValue, "malformed quantity, the template gets a %s storage capacity: %v", common.DatadogLocalDataQuantity.String(), err)
+	} else if quantity.Sign() <= 0 {
+		finding(SeverityError, class.CapacityLabel, capacityValue, "the storage capacity must be positive")
+	} else {
+		expectedStorage, storageOk = quantity, true
+	}
+
+	expectedCount, countOk := *common.DatadogLocalDataQuantity, true
+	if hasCount {
+		count, err := strconv.ParseInt(countValue, 10, 64)
+		if err != nil || count < 1 {
+			finding(SeverityError, class.CountLabel, countValue, "expected a positive integer, the template gets %s volume", common.DatadogLocalDataQuantity.String())
+			countOk = false
+		} else {
+			expectedCount = *resource.NewQuantity(count, resource.DecimalSI)
+		}
+	}
+
+	if quantity, found := allocatable[class.ExistsResource]; !found {
+		resourceFinding(class.ExistsResource, "missing from the template allocatable")
+	} else if countOk && quantity.Cmp(expectedCount) != 0 {
+		resourceFinding(class.ExistsResource, "template allocatable %s doesn't match the %s label (%s)", quantity.String(), class.CountLabel, expectedCount.String())
+	}
+	if quantity, found := allocatable[class.CapacityResource]; !found {
+		resourceFinding(class.CapacityResource, "missing from the template allocatable")
+	} else if storageOk && quantity.Cmp(expectedStorage) != 0 {
+		resourceFinding(class.CapacityResource, "template allocatable %s doesn't match the %s label (%s)", quantity.String(), class.CapacityLabel, expectedStorage.String())
+	}
+}
+
+func sortedClassNames(classes common.LocalStorageClasses) []string {
+	names := make([]string, 0, len(classes))
+	for name := range classes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
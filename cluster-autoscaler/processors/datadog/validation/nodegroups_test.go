@@ -0,0 +1,122 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	testprovider "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/datadog/common"
+	schedulerframework "k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+)
+
+func TestValidateNodeGroups(t *testing.T) {
+	classes, err := common.ParseLocalStorageClasses([]string{"local-data", "local-nvme"})
+	assert.NoError(t, err)
+
+	template := func(labels map[string]string) *schedulerframework.NodeInfo {
+		node := BuildTestNode("tn", 1000, 1000)
+		node.SetLabels(labels)
+		return schedulerframework.NewNodeInfo(node, nil)
+	}
+
+	tests := []struct {
+		name     string
+		labels   map[string]string
+		expected []Finding
+	}{
+		{
+			name:     "no local storage",
+			labels:   map[string]string{"team": "foo"},
+			expected: []Finding{},
+		},
+		{
+			name: "well-formed labels",
+			labels: map[string]string{
+				common.DatadogLocalStorageLabel:         "true",
+				common.DatadogLocalStorageCapacityLabel: "100Gi",
+				common.DatadogLocalStorageCountLabel:    "2",
+			},
+			expected: []Finding{},
+		},
+		{
+			name: "malformed capacity",
+			labels: map[string]string{
+				common.DatadogLocalStorageLabel:         "true",
+				common.DatadogLocalStorageCapacityLabel: "100 Gi",
+			},
+			expected: []Finding{{
+				NodeGroup:    "ng",
+				Severity:     SeverityError,
+				StorageClass: "local-data",
+				Label:        common.DatadogLocalStorageCapacityLabel,
+				Value:        "100 Gi",
+				Message:      "malformed quantity, the template gets a 1 storage capacity: quantities must match the regular expression '^([+-]?[0-9.]+)([eEinumkKMGTP]*[-+]?[0-9]*)$'",
+			}},
+		},
+		{
+			name: "missing capacity and bad count",
+			labels: map[string]string{
+				"nodegroups.datadoghq.com/local-nvme":       "true",
+				"nodegroups.datadoghq.com/local-nvme-count": "zero",
+			},
+			expected: []Finding{
+				{NodeGroup: "ng", Severity: SeverityError, StorageClass: "local-nvme", Label: "nodegroups.datadoghq.com/local-nvme-capacity", Message: "missing, the template gets a 1 storage capacity"},
+				{NodeGroup: "ng", Severity: SeverityError, StorageClass: "local-nvme", Label: "nodegroups.datadoghq.com/local-nvme-count", Value: "zero", Message: "expected a positive integer, the template gets 1 volume"},
+			},
+		},
+		{
+			name: "malformed label value and typo in a class name",
+			labels: map[string]string{
+				common.DatadogLocalStorageLabel:                 "True",
+				common.DatadogLocalStorageCapacityLabel:         "100Gi",
+				"nodegroups.datadoghq.com/local-nvmee-capacity": "100Gi",
+			},
+			expected: []Finding{
+				{NodeGroup: "ng", Severity: SeverityError, StorageClass: "local-data", Label: common.DatadogLocalStorageLabel, Value: "True", Message: "expected \"true\" or \"false\", the node group won't be considered as offering local-data"},
+				{NodeGroup: "ng", Severity: SeverityWarning, StorageClass: "local-data", Label: common.DatadogLocalStorageCapacityLabel, Value: "100Gi", Message: "ignored, as nodegroups.datadoghq.com/local-storage isn't \"true\""},
+				{NodeGroup: "ng", Severity: SeverityWarning, Label: "nodegroups.datadoghq.com/local-nvmee-capacity", Value: "100Gi", Message: "looks like a local storage label, but doesn't match any configured local storage class"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := testprovider.NewTestCloudProviderBuilder().
+				WithMachineTemplates(map[string]*schedulerframework.NodeInfo{"ng": template(tt.labels)}).
+				Build()
+			provider.AddNodeGroup("ng", 0, 10, 0)
+
+			report := ValidateNodeGroups(provider, classes, nil)
+			assert.Equal(t, 1, report.NodeGroups)
+			assert.Equal(t, tt.expected, report.Findings)
+		})
+	}
+}
+
+func TestValidateNodeGroupsTemplateFailure(t *testing.T) {
+	provider := testprovider.NewTestCloudProviderBuilder().Build()
+	provider.AddNodeGroup("ng", 0, 10, 0)
+
+	report := ValidateNodeGroups(provider, common.NewDefaultLocalStorageClasses(), nil)
+	assert.True(t, report.HasErrors())
+	assert.Equal(t, 1, report.Errors)
+	assert.Equal(t, "ng", report.Findings[0].NodeGroup)
+	assert.Contains(t, report.Findings[0].Message, "failed to build template NodeInfo")
+}
@@ -21,6 +21,7 @@ import (
 
 	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
 	"k8s.io/autoscaler/cluster-autoscaler/config"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/schedule"
 )
 
 // NodeGroupConfigProcessor provides config values for a particular NodeGroup.
@@ -37,6 +38,8 @@ type NodeGroupConfigProcessor interface {
 	GetMaxNodeProvisionTime(nodeGroup cloudprovider.NodeGroup) (time.Duration, error)
 	// GetIgnoreDaemonSetsUtilization returns IgnoreDaemonSetsUtilization value that should be used for a given NodeGroup.
 	GetIgnoreDaemonSetsUtilization(nodeGroup cloudprovider.NodeGroup) (bool, error)
+	// GetScaleDownWindows returns ScaleDownWindows value that should be used for a given NodeGroup.
+	GetScaleDownWindows(nodeGroup cloudprovider.NodeGroup) ([]schedule.Window, error)
 	// CleanUp cleans up processor's internal structures.
 	CleanUp()
 }
@@ -120,6 +123,18 @@ func (p *DelegatingNodeGroupConfigProcessor) GetIgnoreDaemonSetsUtilization(node
 	return ngConfig.IgnoreDaemonSetsUtilization, nil
 }
 
+// GetScaleDownWindows returns ScaleDownWindows value that should be used for a given NodeGroup.
+func (p *DelegatingNodeGroupConfigProcessor) GetScaleDownWindows(nodeGroup cloudprovider.NodeGroup) ([]schedule.Window, error) {
+	ngConfig, err := nodeGroup.GetOptions(p.nodeGroupDefaults)
+	if err != nil && err != cloudprovider.ErrNotImplemented {
+		return nil, err
+	}
+	if ngConfig == nil || err == cloudprovider.ErrNotImplemented {
+		return p.nodeGroupDefaults.ScaleDownWindows, nil
+	}
+	return ngConfig.ScaleDownWindows, nil
+}
+
 // CleanUp cleans up processor's internal structures.
 func (p *DelegatingNodeGroupConfigProcessor) CleanUp() {
 }
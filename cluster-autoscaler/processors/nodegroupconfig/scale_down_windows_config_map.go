@@ -0,0 +1,139 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodegroupconfig
+
+import (
+	"fmt"
+	"regexp"
+
+	"gopkg.in/yaml.v2"
+
+	apiv1 "k8s.io/api/core/v1"
+	kube_errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/schedule"
+	v1lister "k8s.io/client-go/listers/core/v1"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	// ScaleDownWindowsConfigMapName defines a name of the ConfigMap used to store per node group scale-down windows
+	ScaleDownWindowsConfigMapName = "cluster-autoscaler-scale-down-windows"
+	// ScaleDownWindowsConfigMapKey defines the key used in the ConfigMap to store the scale-down windows
+	ScaleDownWindowsConfigMapKey = "windows"
+)
+
+// scaleDownWindowsRule gives the scale-down windows of the node groups with ids matching a regexp.
+type scaleDownWindowsRule struct {
+	nodeGroups *regexp.Regexp
+	windows    []schedule.Window
+}
+
+// ScaleDownWindowsConfigMapProcessor is a NodeGroupConfigProcessor reading the scale-down windows of node groups from
+// a ConfigMap, and delegating everything else, including the windows of node groups the ConfigMap doesn't cover.
+//
+// The ConfigMap holds an ordered list of rules, the first one matching a node group id giving its windows:
+//
+//	windows: |-
+//	  - nodeGroups: ".*-batch-.*"
+//	    windows:
+//	    - "allow 0 0 * * sat 48h"
+//	  - nodeGroups: ".*"
+//	    windows:
+//	    - "deny 0 9 * * mon-fri 8h Europe/Paris"
+type ScaleDownWindowsConfigMapProcessor struct {
+	NodeGroupConfigProcessor
+	configMapLister v1lister.ConfigMapNamespaceLister
+	rules           []scaleDownWindowsRule
+	resourceVersion string
+	// rejectedVersion is the last invalid ConfigMap version, so it's only reported once.
+	rejectedVersion string
+}
+
+// NewScaleDownWindowsConfigMapProcessor returns a new ScaleDownWindowsConfigMapProcessor.
+func NewScaleDownWindowsConfigMapProcessor(delegate NodeGroupConfigProcessor, configMapLister v1lister.ConfigMapNamespaceLister) *ScaleDownWindowsConfigMapProcessor {
+	return &ScaleDownWindowsConfigMapProcessor{
+		NodeGroupConfigProcessor: delegate,
+		configMapLister:          configMapLister,
+	}
+}
+
+// GetScaleDownWindows returns the windows of the first ConfigMap rule matching the node group, or the delegate's
+// windows if none matches.
+func (p *ScaleDownWindowsConfigMapProcessor) GetScaleDownWindows(nodeGroup cloudprovider.NodeGroup) ([]schedule.Window, error) {
+	p.reloadConfigMap()
+	for _, rule := range p.rules {
+		if rule.nodeGroups.MatchString(nodeGroup.Id()) {
+			return rule.windows, nil
+		}
+	}
+	return p.NodeGroupConfigProcessor.GetScaleDownWindows(nodeGroup)
+}
+
+// reloadConfigMap refreshes the rules when the ConfigMap changed. Invalid updates are ignored.
+func (p *ScaleDownWindowsConfigMapProcessor) reloadConfigMap() {
+	cm, err := p.configMapLister.Get(ScaleDownWindowsConfigMapName)
+	if err != nil {
+		if !kube_errors.IsNotFound(err) {
+			klog.Warningf("Failed to get scale-down windows config map %s: %v", ScaleDownWindowsConfigMapName, err)
+			return
+		}
+		if p.resourceVersion != "" {
+			klog.Warningf("Scale-down windows config map %s was removed, using node groups' options", ScaleDownWindowsConfigMapName)
+		}
+		p.rules, p.resourceVersion = nil, ""
+		return
+	}
+	if cm.ResourceVersion == p.resourceVersion || cm.ResourceVersion == p.rejectedVersion {
+		return
+	}
+	rules, err := parseScaleDownWindowsRules(cm)
+	if err != nil {
+		p.rejectedVersion = cm.ResourceVersion
+		klog.Warningf("Wrong configuration for scale-down windows: %v. Ignoring update.", err)
+		return
+	}
+	p.rules, p.resourceVersion = rules, cm.ResourceVersion
+	klog.V(4).Info("Successfully loaded scale-down windows from configmap.")
+}
+
+func parseScaleDownWindowsRules(cm *apiv1.ConfigMap) ([]scaleDownWindowsRule, error) {
+	var config []struct {
+		NodeGroups string   `yaml:"nodeGroups"`
+		Windows    []string `yaml:"windows"`
+	}
+	if err := yaml.UnmarshalStrict([]byte(cm.Data[ScaleDownWindowsConfigMapKey]), &config); err != nil {
+		return nil, fmt.Errorf("can't parse YAML with %s in the configmap: %v", ScaleDownWindowsConfigMapKey, err)
+	}
+	rules := make([]scaleDownWindowsRule, 0, len(config))
+	for _, c := range config {
+		nodeGroups, err := regexp.Compile(c.NodeGroups)
+		if err != nil {
+			return nil, fmt.Errorf("can't compile node groups regexp %q: %v", c.NodeGroups, err)
+		}
+		rule := scaleDownWindowsRule{nodeGroups: nodeGroups}
+		for _, spec := range c.Windows {
+			window, err := schedule.ParseWindow(spec)
+			if err != nil {
+				return nil, err
+			}
+			rule.windows = append(rule.windows, window)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodegroupconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testprovider "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/kubernetes"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/schedule"
+)
+
+func TestScaleDownWindowsConfigMapProcessor(t *testing.T) {
+	const rules = `
+- nodeGroups: "-batch-"
+  windows:
+  - "allow 0 0 * * sat 48h"
+- nodeGroups: "^prod-"
+  windows:
+  - "deny 0 9 * * mon-fri 8h Europe/Paris"
+  - "deny 0 0 25 12 * 24h"
+- nodeGroups: "^dev-"
+`
+	for desc, test := range map[string]struct {
+		configMap   string
+		noConfigMap bool
+		nodeGroup   string
+		want        []string
+	}{
+		"no config map": {
+			noConfigMap: true,
+			nodeGroup:   "prod-web",
+			want:        []string{"deny 0 0 1 1 * 24h"},
+		},
+		"first matching rule wins": {
+			configMap: rules,
+			nodeGroup: "prod-batch-1",
+			want:      []string{"allow 0 0 * * sat 48h"},
+		},
+		"rule with several windows": {
+			configMap: rules,
+			nodeGroup: "prod-web",
+			want:      []string{"deny 0 9 * * mon-fri 8h Europe/Paris", "deny 0 0 25 12 * 24h"},
+		},
+		"rule without windows": {
+			configMap: rules,
+			nodeGroup: "dev-web",
+		},
+		"no matching rule falls back to options": {
+			configMap: rules,
+			nodeGroup: "staging-web",
+			want:      []string{"deny 0 0 1 1 * 24h"},
+		},
+		"invalid window falls back to options": {
+			configMap: `
+- nodeGroups: ".*"
+  windows:
+  - "deny 0 9 * * mon-fri"
+`,
+			nodeGroup: "prod-web",
+			want:      []string{"deny 0 0 1 1 * 24h"},
+		},
+		"invalid regexp falls back to options": {
+			configMap: `
+- nodeGroups: "("
+`,
+			nodeGroup: "prod-web",
+			want:      []string{"deny 0 0 1 1 * 24h"},
+		},
+	} {
+		t.Run(desc, func(t *testing.T) {
+			var configMaps []*apiv1.ConfigMap
+			if !test.noConfigMap {
+				configMaps = append(configMaps, &apiv1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: ScaleDownWindowsConfigMapName, ResourceVersion: "1"},
+					Data:       map[string]string{ScaleDownWindowsConfigMapKey: test.configMap},
+				})
+			}
+			lister, err := kubernetes.NewTestConfigMapLister(configMaps)
+			assert.NoError(t, err)
+			defaults, err := schedule.ParseWindows("deny 0 0 1 1 * 24h")
+			assert.NoError(t, err)
+			p := NewScaleDownWindowsConfigMapProcessor(NewDefaultNodeGroupConfigProcessor(config.NodeGroupAutoscalingOptions{ScaleDownWindows: defaults}), lister.ConfigMaps("kube-system"))
+
+			ng := testprovider.NewTestNodeGroup(test.nodeGroup, 10, 0, 0, true, false, "", nil, nil)
+			windows, err := p.GetScaleDownWindows(ng)
+			assert.NoError(t, err)
+			var got []string
+			for _, w := range windows {
+				got = append(got, w.String())
+			}
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prescaling
+
+import (
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/autoscaler/cluster-autoscaler/metrics"
+)
+
+// ArrivalForecaster learns, per key, how many pods arrive in each slot of a period (e.g. every 10 minutes
+// of a day), and forecasts the arrivals of the coming slots from the same slots of the previous periods.
+type ArrivalForecaster struct {
+	period    time.Duration
+	slot      time.Duration
+	smoothing float64
+	keys      map[string]*arrivals
+	seen      map[types.UID]bool
+}
+
+// arrivals holds the pod arrivals learnt for a key.
+type arrivals struct {
+	// learnt holds the smoothed arrivals of each slot of the period, learnt[i] being valid when known[i] is true.
+	learnt []float64
+	known  []bool
+	// slot is the index, since the epoch, of the slot arrivals are currently counted for.
+	slot  int64
+	count float64
+	// sample is the last pod seen arriving, used as a template for fake pods.
+	sample   *apiv1.Pod
+	lastSeen time.Time
+}
+
+// NewArrivalForecaster returns a new ArrivalForecaster.
+func NewArrivalForecaster(period, slot time.Duration, smoothing float64) *ArrivalForecaster {
+	return &ArrivalForecaster{
+		period:    period,
+		slot:      slot,
+		smoothing: smoothing,
+		keys:      make(map[string]*arrivals),
+		seen:      make(map[types.UID]bool),
+	}
+}
+
+// Observe records the pending pods seen at the given time, grouped by key. Pods are counted as arriving
+// the first time they are seen, and forgotten once they are no longer pending.
+func (f *ArrivalForecaster) Observe(pendingPods map[string][]*apiv1.Pod, now time.Time) {
+	slot := f.slotIndex(now)
+	for _, a := range f.keys {
+		f.advance(a, slot)
+	}
+
+	seen := make(map[types.UID]bool, len(f.seen))
+	for key, pods := range pendingPods {
+		a, found := f.keys[key]
+		if !found {
+			a = &arrivals{learnt: make([]float64, f.slots()), known: make([]bool, f.slots()), slot: slot}
+			f.keys[key] = a
+		}
+		for _, pod := range pods {
+			seen[pod.UID] = true
+			if f.seen[pod.UID] {
+				continue
+			}
+			a.count++
+			a.sample = pod
+			a.lastSeen = now
+		}
+	}
+	f.seen = seen
+
+	// Keys without arrivals over two periods are forgotten.
+	for key, a := range f.keys {
+		if now.Sub(a.lastSeen) > 2*f.period {
+			delete(f.keys, key)
+		}
+	}
+}
+
+// Forecast returns the number of pods expected to arrive for the key between now and now + lookahead,
+// and a sample pod of the key.
+func (f *ArrivalForecaster) Forecast(key string, now time.Time, lookahead time.Duration) (float64, *apiv1.Pod) {
+	a, found := f.keys[key]
+	if !found || a.sample == nil {
+		return 0, nil
+	}
+	current := f.slotIndex(now)
+	forecast := 0.0
+	if position := f.position(current); a.known[position] {
+		// Pods which already arrived in the current slot are pending, or already running.
+		forecast += max(a.learnt[position]-a.count, 0)
+	}
+	for slot := current + 1; slot <= f.slotIndex(now.Add(lookahead)); slot++ {
+		if position := f.position(slot); a.known[position] {
+			forecast += a.learnt[position]
+		}
+	}
+	return forecast, a.sample
+}
+
+// Keys returns the keys pod arrivals are learnt for.
+func (f *ArrivalForecaster) Keys() []string {
+	keys := make([]string, 0, len(f.keys))
+	for key := range f.keys {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// advance learns the arrivals of the slots which ended before the given one.
+func (f *ArrivalForecaster) advance(a *arrivals, slot int64) {
+	// Slots without any arrival are learnt as such, up to a whole period.
+	if slot-a.slot > int64(f.slots()) {
+		a.slot = slot - int64(f.slots())
+		a.count = 0
+	}
+	for ; a.slot < slot; a.slot++ {
+		position := f.position(a.slot)
+		if a.known[position] {
+			metrics.RegisterPredictivePrescalingSlot(a.learnt[position], a.count)
+			a.learnt[position] = f.smoothing*a.count + (1-f.smoothing)*a.learnt[position]
+		} else {
+			a.learnt[position] = a.count
+			a.known[position] = true
+		}
+		a.count = 0
+	}
+}
+
+func (f *ArrivalForecaster) slots() int {
+	return max(int(f.period/f.slot), 1)
+}
+
+func (f *ArrivalForecaster) slotIndex(t time.Time) int64 {
+	return t.UnixNano() / int64(f.slot)
+}
+
+func (f *ArrivalForecaster) position(slot int64) int {
+	return int(slot % int64(f.slots()))
+}
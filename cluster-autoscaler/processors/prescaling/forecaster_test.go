@@ -0,0 +1,74 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prescaling
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+)
+
+// periodStart is aligned on the slots and periods used in tests.
+var periodStart = time.Unix(0, 0).Add(100 * time.Hour)
+
+func buildPendingPods(prefix string, count int, options ...func(*apiv1.Pod)) []*apiv1.Pod {
+	var pods []*apiv1.Pod
+	for i := 0; i < count; i++ {
+		pod := BuildTestPod(fmt.Sprintf("%s-%d", prefix, i), 100, 100, options...)
+		pod.UID = types.UID(pod.Name)
+		pods = append(pods, pod)
+	}
+	return pods
+}
+
+func TestArrivalForecaster(t *testing.T) {
+	f := NewArrivalForecaster(time.Hour, 10*time.Minute, 0.5)
+	wave := buildPendingPods("wave", 4)
+
+	// No forecast until the slot was seen once.
+	f.Observe(map[string][]*apiv1.Pod{"ns": wave}, periodStart.Add(time.Minute))
+	forecast, _ := f.Forecast("ns", periodStart.Add(time.Minute), 15*time.Minute)
+	assert.Equal(t, 0.0, forecast)
+
+	// Pods are counted once, however long they stay pending.
+	f.Observe(map[string][]*apiv1.Pod{"ns": wave}, periodStart.Add(2*time.Minute))
+	f.Observe(map[string][]*apiv1.Pod{}, periodStart.Add(50*time.Minute))
+	forecast, sample := f.Forecast("ns", periodStart.Add(50*time.Minute), 15*time.Minute)
+	assert.Equal(t, 4.0, forecast)
+	assert.Equal(t, wave[3], sample)
+
+	// Pods which already arrived in the current slot are deducted.
+	f.Observe(map[string][]*apiv1.Pod{"ns": buildPendingPods("next-wave", 1)}, periodStart.Add(time.Hour+time.Minute))
+	forecast, _ = f.Forecast("ns", periodStart.Add(time.Hour+time.Minute), 0)
+	assert.Equal(t, 3.0, forecast)
+
+	// Slots are smoothed over periods.
+	f.Observe(map[string][]*apiv1.Pod{"ns": buildPendingPods("next-wave", 2)}, periodStart.Add(time.Hour+2*time.Minute))
+	f.Observe(map[string][]*apiv1.Pod{}, periodStart.Add(time.Hour+50*time.Minute))
+	forecast, _ = f.Forecast("ns", periodStart.Add(time.Hour+50*time.Minute), 15*time.Minute)
+	assert.Equal(t, 3.0, forecast)
+
+	// Keys without arrivals are eventually forgotten.
+	f.Observe(map[string][]*apiv1.Pod{}, periodStart.Add(4*time.Hour))
+	assert.Empty(t, f.Keys())
+}
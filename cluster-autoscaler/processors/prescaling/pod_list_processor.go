@@ -0,0 +1,160 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prescaling
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+	"k8s.io/autoscaler/cluster-autoscaler/context"
+	"k8s.io/autoscaler/cluster-autoscaler/metrics"
+	capacitybufferpodlister "k8s.io/autoscaler/cluster-autoscaler/processors/capacitybuffer"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/fake"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// NamespaceKey learns pod arrivals per namespace.
+	NamespaceKey = "namespace"
+	// OwnerKey learns pod arrivals per controller owning the pods, or per namespace for pods without controller.
+	OwnerKey = "owner"
+
+	// FakePrescalingPodAnnotationKey and FakePrescalingPodAnnotationValue annotate the fake pods injected ahead of forecast pod arrivals.
+	FakePrescalingPodAnnotationKey   = "podType"
+	FakePrescalingPodAnnotationValue = "predictivePrescalingFakePod"
+)
+
+// PredictivePrescalingPodListProcessor learns pod arrival rates from the unschedulable pods it sees, and injects
+// fake pods for the pods forecast to arrive within the lookahead, so that capacity is provisioned ahead of them.
+type PredictivePrescalingPodListProcessor struct {
+	options    config.PredictivePrescalingOptions
+	forecaster *ArrivalForecaster
+	now        func() time.Time
+}
+
+// NewPredictivePrescalingPodListProcessor returns a new PredictivePrescalingPodListProcessor.
+func NewPredictivePrescalingPodListProcessor(options config.PredictivePrescalingOptions) *PredictivePrescalingPodListProcessor {
+	return &PredictivePrescalingPodListProcessor{
+		options:    options,
+		forecaster: NewArrivalForecaster(options.Period, options.SlotDuration, options.Smoothing),
+		now:        time.Now,
+	}
+}
+
+// Process learns from the unschedulable pods, and appends fake pods for the pods forecast to arrive.
+func (p *PredictivePrescalingPodListProcessor) Process(_ *context.AutoscalingContext, unschedulablePods []*apiv1.Pod) ([]*apiv1.Pod, error) {
+	now := p.now()
+	pendingPods := make(map[string][]*apiv1.Pod)
+	for _, pod := range unschedulablePods {
+		if isInjectedPod(pod) {
+			continue
+		}
+		key := p.key(pod)
+		pendingPods[key] = append(pendingPods[key], pod)
+	}
+	p.forecaster.Observe(pendingPods, now)
+
+	type expectedPods struct {
+		key    string
+		count  int
+		sample *apiv1.Pod
+	}
+	var expected []expectedPods
+	for _, key := range p.forecaster.Keys() {
+		forecast, sample := p.forecaster.Forecast(key, now, p.options.Lookahead)
+		// Pods already pending will trigger a scale-up on their own.
+		if count := int(math.Round(forecast)) - len(pendingPods[key]); count > 0 && sample != nil {
+			expected = append(expected, expectedPods{key: key, count: count, sample: sample})
+		}
+	}
+	// The budget goes to the largest forecasts first.
+	sort.Slice(expected, func(i, j int) bool {
+		if expected[i].count != expected[j].count {
+			return expected[i].count > expected[j].count
+		}
+		return expected[i].key < expected[j].key
+	})
+
+	budget := p.options.MaxPods
+	var fakePods []*apiv1.Pod
+	for _, e := range expected {
+		count := min(e.count, budget)
+		if count <= 0 {
+			break
+		}
+		klog.V(4).Infof("Predictive prescaling injecting %d fake pods for %s", count, e.key)
+		fakePods = append(fakePods, makeFakePods(e.sample, count)...)
+		budget -= count
+	}
+	klog.V(2).Infof("Predictive prescaling injecting %d fake pods for %d keys", len(fakePods), len(expected))
+	metrics.UpdatePredictivePrescalingInjectedPods(len(fakePods))
+	return append(unschedulablePods, fakePods...), nil
+}
+
+// CleanUp is called at CA termination
+func (p *PredictivePrescalingPodListProcessor) CleanUp() {
+}
+
+func (p *PredictivePrescalingPodListProcessor) key(pod *apiv1.Pod) string {
+	if p.options.Key == OwnerKey {
+		if owner := metav1.GetControllerOf(pod); owner != nil {
+			return fmt.Sprintf("%s/%s/%s", pod.Namespace, owner.Kind, owner.Name)
+		}
+	}
+	return pod.Namespace
+}
+
+// makeFakePods creates podCount copies of the sample pod, annotated as predictive prescaling fake pods.
+func makeFakePods(samplePod *apiv1.Pod, podCount int) []*apiv1.Pod {
+	var fakePods []*apiv1.Pod
+	for i := 1; i <= podCount; i++ {
+		newPod := samplePod.DeepCopy()
+		if newPod.Annotations == nil {
+			newPod.Annotations = make(map[string]string, 1)
+		}
+		newPod.Annotations[FakePrescalingPodAnnotationKey] = FakePrescalingPodAnnotationValue
+		newPod.Name = fmt.Sprintf("prescaling-%s-%d", samplePod.Name, i)
+		newPod.UID = types.UID(fmt.Sprintf("prescaling-%s-%d", string(samplePod.UID), i))
+		newPod.Spec.NodeName = ""
+		newPod.Status = apiv1.PodStatus{}
+		fakePods = append(fakePods, newPod)
+	}
+	return fakePods
+}
+
+// IsFakePrescalingPod returns whether the pod is a fake pod injected ahead of forecast pod arrivals.
+func IsFakePrescalingPod(pod *apiv1.Pod) bool {
+	if pod.Annotations == nil {
+		return false
+	}
+	return pod.Annotations[FakePrescalingPodAnnotationKey] == FakePrescalingPodAnnotationValue
+}
+
+// isInjectedPod returns whether the pod was injected by a processor, rather than created by users.
+func isInjectedPod(pod *apiv1.Pod) bool {
+	if pod.Annotations == nil {
+		return false
+	}
+	return IsFakePrescalingPod(pod) || fake.IsFake(pod) ||
+		pod.Annotations[capacitybufferpodlister.FakeCapacityBufferPodAnnotationKey] == capacitybufferpodlister.FakeCapacityBufferPodAnnotationValue
+}
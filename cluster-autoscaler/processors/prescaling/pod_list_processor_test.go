@@ -0,0 +1,82 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prescaling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+	capacitybufferpodlister "k8s.io/autoscaler/cluster-autoscaler/processors/capacitybuffer"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/status"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/fake"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+)
+
+func TestProcess(t *testing.T) {
+	p := NewPredictivePrescalingPodListProcessor(config.PredictivePrescalingOptions{
+		Key:          OwnerKey,
+		Period:       time.Hour,
+		SlotDuration: 10 * time.Minute,
+		Lookahead:    15 * time.Minute,
+		Smoothing:    1,
+		MaxPods:      3,
+	})
+	process := func(at time.Duration, pods []*apiv1.Pod) []*apiv1.Pod {
+		p.now = func() time.Time { return periodStart.Add(at) }
+		result, err := p.Process(nil, pods)
+		assert.NoError(t, err)
+		return result[len(pods):]
+	}
+	jobA := buildPendingPods("job-a", 4, WithControllerOwnerRef("job-a", "Job", "uid-a"))
+	jobB := buildPendingPods("job-b", 1, WithControllerOwnerRef("job-b", "Job", "uid-b"))
+	injected := fake.WithFakePodAnnotation(BuildTestPod("injected", 100, 100, WithControllerOwnerRef("job-b", "Job", "uid-b")))
+
+	// Nothing is injected until arrivals are learnt, and injected pods aren't learnt from.
+	assert.Empty(t, process(time.Minute, append(append(jobA, jobB...), injected)))
+
+	// Ahead of the next wave, the budget goes to the largest forecast first.
+	fakePods := process(55*time.Minute, nil)
+	assert.Len(t, fakePods, 3)
+	for _, pod := range fakePods {
+		assert.True(t, IsFakePrescalingPod(pod))
+		assert.Equal(t, "", pod.Spec.NodeName)
+		assert.Equal(t, "job-a", pod.OwnerReferences[0].Name)
+	}
+
+	// Pending pods are deducted from the forecast.
+	fakePods = process(time.Hour+time.Minute, buildPendingPods("job-a-next", 4, WithControllerOwnerRef("job-a", "Job", "uid-a")))
+	assert.Len(t, fakePods, 1)
+	assert.Equal(t, "job-b", fakePods[0].OwnerReferences[0].Name)
+}
+
+func TestFakePodsScaleUpStatusProcessor(t *testing.T) {
+	pod := BuildTestPod("pod", 100, 100)
+	fakePod := makeFakePods(pod, 1)[0]
+	scaleUpStatus := &status.ScaleUpStatus{
+		PodsRemainUnschedulable: []status.NoScaleUpInfo{{Pod: pod}, {Pod: fakePod}},
+		PodsAwaitEvaluation:     []*apiv1.Pod{pod, fakePod},
+		PodsTriggeredScaleUp:    []*apiv1.Pod{fakePod, pod},
+	}
+	capacitybufferpodlister.NewFakePodsScaleUpStatusProcessorWithPredicate(IsFakePrescalingPod).Process(nil, scaleUpStatus)
+	assert.Equal(t, []status.NoScaleUpInfo{{Pod: pod}}, scaleUpStatus.PodsRemainUnschedulable)
+	assert.Equal(t, []*apiv1.Pod{pod}, scaleUpStatus.PodsAwaitEvaluation)
+	assert.Equal(t, []*apiv1.Pod{pod}, scaleUpStatus.PodsTriggeredScaleUp)
+}
@@ -78,12 +78,20 @@ type AutoscalingProcessors struct {
 	ScaleUpEnforcer pods.ScaleUpEnforcer
 }
 
+func newBinpackingLimiter(options config.AutoscalingOptions) binpacking.BinpackingLimiter {
+	timeLimiter := binpacking.NewTimeLimiter(options.MaxBinpackingTime)
+	if options.CostAwareBinpacking.Ranking == "" {
+		return timeLimiter
+	}
+	return binpacking.NewCombinedLimiter([]binpacking.BinpackingLimiter{timeLimiter, binpacking.NewCostAwareLimiter(options.CostAwareBinpacking)})
+}
+
 // DefaultProcessors returns default set of processors.
 func DefaultProcessors(options config.AutoscalingOptions) *AutoscalingProcessors {
 	return &AutoscalingProcessors{
 		PodListProcessor:       pods.NewDefaultPodListProcessor(),
 		NodeGroupListProcessor: nodegroups.NewDefaultNodeGroupListProcessor(),
-		BinpackingLimiter:      binpacking.NewTimeLimiter(options.MaxBinpackingTime),
+		BinpackingLimiter:      newBinpackingLimiter(options),
 		NodeGroupSetProcessor: nodegroupset.NewDefaultNodeGroupSetProcessor([]string{}, config.NodeGroupDifferenceRatios{
 			MaxAllocatableDifferenceRatio:    config.DefaultMaxAllocatableDifferenceRatio,
 			MaxCapacityMemoryDifferenceRatio: config.DefaultMaxCapacityMemoryDifferenceRatio,
@@ -23,6 +23,7 @@ import (
 	apiv1 "k8s.io/api/core/v1"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	v1 "k8s.io/autoscaler/cluster-autoscaler/apis/provisioningrequest/autoscaling.x-k8s.io/v1"
 	"k8s.io/autoscaler/cluster-autoscaler/context"
 	"k8s.io/autoscaler/cluster-autoscaler/provisioningrequest"
@@ -34,6 +35,7 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/scheduling"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/klogx"
+	podutils "k8s.io/autoscaler/cluster-autoscaler/utils/pod"
 	"k8s.io/klog/v2"
 )
 
@@ -77,7 +79,7 @@ func (p *provReqProcessor) Refresh() {
 // refresh iterates over ProvisioningRequests and apply:
 // -BookingExpired condition for Provisioned ProvisioningRequest if capacity reservation time is expired.
 // -Failed condition for ProvisioningRequest that were not provisioned during defaultExpirationTime.
-// TODO(yaroslava): fetch reservation and expiration time from ProvisioningRequest
+// TODO(yaroslava): fetch expiration time from ProvisioningRequest
 func (p *provReqProcessor) refresh(provReqs []*provreqwrapper.ProvisioningRequest) {
 	expiredProvReq := []*provreqwrapper.ProvisioningRequest{}
 	failedProvReq := []*provreqwrapper.ProvisioningRequest{}
@@ -94,7 +96,7 @@ func (p *provReqProcessor) refresh(provReqs []*provreqwrapper.ProvisioningReques
 		}
 		provisioned := apimeta.FindStatusCondition(conditions, v1.Provisioned)
 		if provisioned != nil && provisioned.Status == metav1.ConditionTrue {
-			if provisioned.LastTransitionTime.Add(defaultReservationTime).Before(p.now()) {
+			if provisioned.LastTransitionTime.Add(reservationTime(provReq)).Before(p.now()) {
 				expiredProvReq = append(expiredProvReq, provReq)
 			}
 		} else if len(failedProvReq) < p.maxUpdated-len(expiredProvReq) {
@@ -143,6 +145,10 @@ func (p *provReqProcessor) bookCapacity(ctx *context.AutoscalingContext) error {
 	if err != nil {
 		return fmt.Errorf("couldn't fetch ProvisioningRequests in the cluster: %v", err)
 	}
+	consumingPods, err := scheduledConsumingPods(ctx.ClusterSnapshot)
+	if err != nil {
+		return fmt.Errorf("couldn't list pods consuming ProvisioningRequests: %v", err)
+	}
 	podsToCreate := []*apiv1.Pod{}
 	for _, provReq := range provReqs {
 		if !conditions.ShouldCapacityBeBooked(provReq, p.checkCapacityProcessorInstance) {
@@ -159,6 +165,17 @@ func (p *provReqProcessor) bookCapacity(ctx *context.AutoscalingContext) error {
 			}
 			continue
 		}
+		if provReq.Spec.ProvisioningClassName == v1.ProvisioningClassCapacityReservation {
+			// Capacity is reserved only for the pods that haven't arrived yet.
+			pods = unconsumedPods(provReq, pods, consumingPods[types.NamespacedName{Namespace: provReq.Namespace, Name: provReq.Name}])
+			if len(pods) == 0 {
+				conditions.AddOrUpdateCondition(provReq, v1.BookingExpired, metav1.ConditionTrue, conditions.CapacityReservationConsumedReason, conditions.CapacityReservationConsumedMsg, metav1.NewTime(p.now()))
+				if _, err := p.client.UpdateProvisioningRequest(provReq.ProvisioningRequest); err != nil {
+					klog.Errorf("failed to add BookingExpired condition to ProvReq %s/%s, err: %v", provReq.Namespace, provReq.Name, err)
+				}
+				continue
+			}
+		}
 		podsToCreate = append(podsToCreate, pods...)
 	}
 	if len(podsToCreate) == 0 {
@@ -171,6 +188,42 @@ func (p *provReqProcessor) bookCapacity(ctx *context.AutoscalingContext) error {
 	return nil
 }
 
+// reservationTime returns for how long capacity is booked for the Provisioned ProvisioningRequest.
+// Capacity reservation ProvisioningRequests can override the default with the ReservationTTLKey parameter.
+func reservationTime(provReq *provreqwrapper.ProvisioningRequest) time.Duration {
+	if provReq.Spec.ProvisioningClassName != v1.ProvisioningClassCapacityReservation {
+		return defaultReservationTime
+	}
+	ttl, found := provReq.Spec.Parameters[provisioningrequest.ReservationTTLKey]
+	if !found {
+		return defaultReservationTime
+	}
+	reservationTime, err := time.ParseDuration(string(ttl))
+	if err != nil || reservationTime <= 0 {
+		klog.Warningf("ProvReq %s/%s has invalid %s parameter %q, using default reservation time %v", provReq.Namespace, provReq.Name, provisioningrequest.ReservationTTLKey, ttl, defaultReservationTime)
+		return defaultReservationTime
+	}
+	return reservationTime
+}
+
+// scheduledConsumingPods lists the pods scheduled in the cluster snapshot per ProvisioningRequest they consume.
+func scheduledConsumingPods(snapshot clustersnapshot.ClusterSnapshot) (map[types.NamespacedName][]*apiv1.Pod, error) {
+	nodeInfos, err := snapshot.ListNodeInfos()
+	if err != nil {
+		return nil, err
+	}
+	consumingPods := make(map[types.NamespacedName][]*apiv1.Pod)
+	for _, nodeInfo := range nodeInfos {
+		for _, podInfo := range nodeInfo.Pods() {
+			if prName, found := provisioningRequestName(podInfo.Pod); found {
+				key := types.NamespacedName{Namespace: podInfo.Pod.Namespace, Name: prName}
+				consumingPods[key] = append(consumingPods[key], podInfo.Pod)
+			}
+		}
+	}
+	return consumingPods, nil
+}
+
 // DeleteOldProvReqs delete ProvReq that have terminal state (Provisioned/Failed == True) more than a week.
 func (p *provReqProcessor) DeleteOldProvReqs(provReqs []*provreqwrapper.ProvisioningRequest) {
 	provReqQuota := klogx.NewLoggingQuota(30)
@@ -188,3 +241,59 @@ func (p *provReqProcessor) DeleteOldProvReqs(provReqs []*provreqwrapper.Provisio
 		}
 	}
 }
+
+// unconsumedPods returns the pods of each podSet that no scheduled consuming pod accounts for. Consuming pods
+// count against the first podSet they have the requests of, and the unmatched ones against the first podSets
+// with remaining pods.
+func unconsumedPods(provReq *provreqwrapper.ProvisioningRequest, pods []*apiv1.Pod, consumingPods []*apiv1.Pod) []*apiv1.Pod {
+	podSets, err := provReq.PodSets()
+	if err != nil {
+		return pods
+	}
+	var podSetsPods [][]*apiv1.Pod
+	for _, podSet := range podSets {
+		count := min(int(podSet.Count), len(pods))
+		podSetsPods = append(podSetsPods, pods[:count])
+		pods = pods[count:]
+	}
+
+	consumed := make([]int, len(podSetsPods))
+	unmatched := 0
+	for _, consumingPod := range consumingPods {
+		requests := podutils.PodRequests(consumingPod)
+		matched := false
+		for i, podSetPods := range podSetsPods {
+			if consumed[i] < len(podSetPods) && sameRequests(requests, podutils.PodRequests(podSetPods[0])) {
+				consumed[i]++
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			unmatched++
+		}
+	}
+
+	var result []*apiv1.Pod
+	for i, podSetPods := range podSetsPods {
+		extra := min(unmatched, len(podSetPods)-consumed[i])
+		consumed[i] += extra
+		unmatched -= extra
+		result = append(result, podSetPods[consumed[i]:]...)
+	}
+	return result
+}
+
+func sameRequests(a, b apiv1.ResourceList) bool {
+	for name, quantity := range a {
+		if other := b[name]; quantity.Cmp(other) != 0 {
+			return false
+		}
+	}
+	for name, quantity := range b {
+		if other := a[name]; quantity.Cmp(other) != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -18,22 +18,27 @@ package provreq
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 
 	apiv1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	v1 "k8s.io/autoscaler/cluster-autoscaler/apis/provisioningrequest/autoscaling.x-k8s.io/v1"
 	"k8s.io/autoscaler/cluster-autoscaler/config"
 	. "k8s.io/autoscaler/cluster-autoscaler/core/test"
+	"k8s.io/autoscaler/cluster-autoscaler/provisioningrequest"
 	"k8s.io/autoscaler/cluster-autoscaler/provisioningrequest/conditions"
 	"k8s.io/autoscaler/cluster-autoscaler/provisioningrequest/provreqclient"
 	"k8s.io/autoscaler/cluster-autoscaler/provisioningrequest/provreqwrapper"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/clustersnapshot"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/scheduling"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
 )
 
 func TestRefresh(t *testing.T) {
@@ -302,3 +307,162 @@ func TestBookCapacity(t *testing.T) {
 		})
 	}
 }
+
+func TestRefreshCapacityReservation(t *testing.T) {
+	now := time.Now()
+	testCases := []struct {
+		name             string
+		reservationTTL   v1.Parameter
+		provisionedSince time.Duration
+		wantExpired      bool
+	}{
+		{
+			name:             "default reservation time not expired",
+			provisionedSince: 5 * time.Minute,
+		},
+		{
+			name:             "default reservation time expired",
+			provisionedSince: 15 * time.Minute,
+			wantExpired:      true,
+		},
+		{
+			name:             "reservation TTL not expired",
+			reservationTTL:   "30m",
+			provisionedSince: 15 * time.Minute,
+		},
+		{
+			name:             "reservation TTL expired",
+			reservationTTL:   "30m",
+			provisionedSince: 45 * time.Minute,
+			wantExpired:      true,
+		},
+		{
+			name:             "invalid reservation TTL falls back to default",
+			reservationTTL:   "invalid",
+			provisionedSince: 15 * time.Minute,
+			wantExpired:      true,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			pr := provreqclient.ProvisioningRequestWrapperForTesting("namespace", "name-1")
+			pr.CreationTimestamp = metav1.NewTime(now.Add(-1 * time.Hour))
+			pr.Spec.ProvisioningClassName = v1.ProvisioningClassCapacityReservation
+			if test.reservationTTL != "" {
+				pr.Spec.Parameters = map[string]v1.Parameter{provisioningrequest.ReservationTTLKey: test.reservationTTL}
+			}
+			conditions.AddOrUpdateCondition(pr, v1.Provisioned, metav1.ConditionTrue, conditions.CapacityIsReservedReason, conditions.CapacityIsReservedMsg, metav1.NewTime(now.Add(-1*test.provisionedSince)))
+
+			processor := provReqProcessor{func() time.Time { return now }, 1, provreqclient.NewFakeProvisioningRequestClient(nil, t, pr), nil, ""}
+			processor.refresh([]*provreqwrapper.ProvisioningRequest{pr})
+
+			expired := apimeta.FindStatusCondition(pr.Status.Conditions, v1.BookingExpired)
+			if test.wantExpired {
+				assert.NotNil(t, expired)
+				assert.Equal(t, conditions.CapacityReservationTimeExpiredReason, expired.Reason)
+			} else {
+				assert.Nil(t, expired)
+			}
+		})
+	}
+}
+
+func TestBookCapacityReservation(t *testing.T) {
+	testCases := []struct {
+		name             string
+		consumingPods    int
+		wantBookedPods   int
+		wantConsumedCond bool
+	}{
+		{
+			name:           "no consuming pods, whole capacity is booked",
+			wantBookedPods: 3,
+		},
+		{
+			name:           "some consuming pods, remaining capacity is booked",
+			consumingPods:  2,
+			wantBookedPods: 1,
+		},
+		{
+			name:             "all consuming pods arrived, reservation is consumed",
+			consumingPods:    3,
+			wantConsumedCond: true,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			provReq := provreqwrapper.BuildTestProvisioningRequest("ns", "pr", "1", "100m", "", 3, false, time.Now(), v1.ProvisioningClassCapacityReservation)
+			conditions.AddOrUpdateCondition(provReq, v1.Provisioned, metav1.ConditionTrue, conditions.CapacityIsReservedReason, conditions.CapacityIsReservedMsg, metav1.Now())
+			injector := &fakeInjector{pods: []*apiv1.Pod{}}
+			client := provreqclient.NewFakeProvisioningRequestClient(context.Background(), t, provReq)
+			processor := &provReqProcessor{
+				now:        func() time.Time { return time.Now() },
+				client:     client,
+				maxUpdated: 20,
+				injector:   injector,
+			}
+
+			ctx, _ := NewScaleTestAutoscalingContext(config.AutoscalingOptions{}, nil, nil, nil, nil, nil)
+			node := BuildTestNode("node", 10000, 10000)
+			var pods []*apiv1.Pod
+			for i := 0; i < test.consumingPods; i++ {
+				pod := BuildTestPod(fmt.Sprintf("consumer-%d", i), 1000, 100)
+				pod.Namespace = "ns"
+				pod.Spec.NodeName = node.Name
+				pod.Annotations = map[string]string{v1.ProvisioningRequestPodAnnotationKey: "pr"}
+				pods = append(pods, pod)
+			}
+			err := ctx.ClusterSnapshot.SetClusterState([]*apiv1.Node{node}, pods, nil)
+			assert.NoError(t, err)
+
+			err = processor.bookCapacity(&ctx)
+			assert.NoError(t, err)
+			assert.Len(t, injector.pods, test.wantBookedPods)
+
+			updated, err := client.ProvisioningRequestNoCache("ns", "pr")
+			assert.NoError(t, err)
+			consumed := apimeta.FindStatusCondition(updated.Status.Conditions, v1.BookingExpired)
+			if test.wantConsumedCond {
+				assert.NotNil(t, consumed)
+				assert.Equal(t, conditions.CapacityReservationConsumedReason, consumed.Reason)
+			} else {
+				assert.Nil(t, consumed)
+			}
+		})
+	}
+}
+
+func TestBookCapacityReservationMultiplePodSets(t *testing.T) {
+	// 3 small pods and 2 large pods, all the large ones arrived
+	provReq := provreqwrapper.BuildTestProvisioningRequest("ns", "pr", "1", "100m", "", 3, false, time.Now(), v1.ProvisioningClassCapacityReservation)
+	large := provreqwrapper.BuildTestProvisioningRequest("ns", "pr-large", "2", "100m", "", 2, false, time.Now(), v1.ProvisioningClassCapacityReservation)
+	provReq.Spec.PodSets = append(provReq.Spec.PodSets, large.Spec.PodSets...)
+	provReq.PodTemplates = append(provReq.PodTemplates, large.PodTemplates...)
+	conditions.AddOrUpdateCondition(provReq, v1.Provisioned, metav1.ConditionTrue, conditions.CapacityIsReservedReason, conditions.CapacityIsReservedMsg, metav1.Now())
+	injector := &fakeInjector{pods: []*apiv1.Pod{}}
+	processor := &provReqProcessor{
+		now:        func() time.Time { return time.Now() },
+		client:     provreqclient.NewFakeProvisioningRequestClient(context.Background(), t, provReq),
+		maxUpdated: 20,
+		injector:   injector,
+	}
+
+	ctx, _ := NewScaleTestAutoscalingContext(config.AutoscalingOptions{}, nil, nil, nil, nil, nil)
+	node := BuildTestNode("node", 10000, 10000)
+	var pods []*apiv1.Pod
+	for i := 0; i < 2; i++ {
+		pod := BuildTestPod(fmt.Sprintf("consumer-%d", i), 2000, 0)
+		pod.Spec.Containers[0].Resources.Requests[apiv1.ResourceMemory] = resource.MustParse("100m")
+		pod.Namespace = "ns"
+		pod.Spec.NodeName = node.Name
+		pod.Annotations = map[string]string{v1.ProvisioningRequestPodAnnotationKey: "pr"}
+		pods = append(pods, pod)
+	}
+	assert.NoError(t, ctx.ClusterSnapshot.SetClusterState([]*apiv1.Node{node}, pods, nil))
+
+	assert.NoError(t, processor.bookCapacity(&ctx))
+	assert.Len(t, injector.pods, 3)
+	for _, pod := range injector.pods {
+		assert.Equal(t, int64(1000), pod.Spec.Containers[0].Resources.Requests.Cpu().MilliValue())
+	}
+}
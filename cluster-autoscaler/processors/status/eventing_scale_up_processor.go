@@ -48,9 +48,12 @@ func (p *EventingScaleUpStatusProcessor) Process(context *context.AutoscalingCon
 			" ScaleUp attempt this loop")
 	}
 	if len(status.ScaleUpInfos) > 0 {
+		message := fmt.Sprintf("pod triggered scale-up: %v", status.ScaleUpInfos)
+		if status.ExpanderExplanation != "" {
+			message = fmt.Sprintf("%s, expander: %s", message, status.ExpanderExplanation)
+		}
 		for _, pod := range status.PodsTriggeredScaleUp {
-			context.Recorder.Eventf(pod, apiv1.EventTypeNormal, "TriggeredScaleUp",
-				"pod triggered scale-up: %v", status.ScaleUpInfos)
+			context.Recorder.Event(pod, apiv1.EventTypeNormal, "TriggeredScaleUp", message)
 		}
 	}
 }
@@ -147,6 +147,25 @@ func TestEventingScaleUpStatusProcessor(t *testing.T) {
 	}
 }
 
+func TestEventingScaleUpStatusProcessorExpanderExplanation(t *testing.T) {
+	p := &EventingScaleUpStatusProcessor{}
+	fakeRecorder := kube_record.NewFakeRecorder(5)
+	context := &context.AutoscalingContext{
+		AutoscalingKubeClients: context.AutoscalingKubeClients{
+			Recorder: fakeRecorder,
+		},
+	}
+	p.Process(context, &ScaleUpStatus{
+		Result:               ScaleUpSuccessful,
+		ScaleUpInfos:         []nodegroupset.ScaleUpInfo{{}},
+		PodsTriggeredScaleUp: []*apiv1.Pod{BuildTestPod("p1", 0, 0)},
+		ExpanderExplanation:  "cheapest spot capacity",
+	})
+	event := <-fakeRecorder.Events
+	assert.True(t, strings.HasPrefix(event, "Normal TriggeredScaleUp pod triggered scale-up: "), event)
+	assert.True(t, strings.HasSuffix(event, ", expander: cheapest spot capacity"), event)
+}
+
 func TestReasonsMessage(t *testing.T) {
 	notSchedulableReason := &testReason{"not schedulable"}
 	alsoNotSchedulableReason := &testReason{"also not schedulable"}
@@ -40,6 +40,8 @@ type ScaleUpStatus struct {
 	ConsideredNodeGroups     []cloudprovider.NodeGroup
 	FailedCreationNodeGroups []cloudprovider.NodeGroup
 	FailedResizeNodeGroups   []cloudprovider.NodeGroup
+	// ExpanderExplanation is the reason given by the expander for choosing the scaled up node group, if any.
+	ExpanderExplanation string
 }
 
 // NoScaleUpInfo contains information about a pod that didn't trigger scale-up.
@@ -44,18 +44,30 @@ import (
 // to atomically request enough resources for all pods specified in a
 // ProvisioningRequest. It's "best effort" as it admits workload immediately
 // after successful request, without waiting to verify that resources started.
+// The same flow serves the capacity reservation class, which additionally reports
+// the provisioned capacity as reserved for the pods consuming the ProvisioningRequest.
 type bestEffortAtomicProvClass struct {
-	context             *context.AutoscalingContext
-	client              *provreqclient.ProvisioningRequestClient
-	injector            *scheduling.HintingSimulator
-	scaleUpOrchestrator scaleup.Orchestrator
+	provisioningClassName string
+	context               *context.AutoscalingContext
+	client                *provreqclient.ProvisioningRequestClient
+	injector              *scheduling.HintingSimulator
+	scaleUpOrchestrator   scaleup.Orchestrator
 }
 
 // New creates best effort atomic provisioning class supporting create capacity scale-up mode.
 func New(
 	client *provreqclient.ProvisioningRequestClient,
 ) *bestEffortAtomicProvClass {
-	return &bestEffortAtomicProvClass{client: client, scaleUpOrchestrator: orchestrator.New()}
+	return &bestEffortAtomicProvClass{provisioningClassName: v1.ProvisioningClassBestEffortAtomicScaleUp, client: client, scaleUpOrchestrator: orchestrator.New()}
+}
+
+// NewCapacityReservation creates capacity reservation provisioning class. Capacity is provisioned the same way as
+// for best effort atomic class, and is then held by processors/provreq until the pods consuming it are scheduled
+// or the reservation TTL expires.
+func NewCapacityReservation(
+	client *provreqclient.ProvisioningRequestClient,
+) *bestEffortAtomicProvClass {
+	return &bestEffortAtomicProvClass{provisioningClassName: v1.ProvisioningClassCapacityReservation, client: client, scaleUpOrchestrator: orchestrator.New()}
 }
 
 func (o *bestEffortAtomicProvClass) Initialize(
@@ -82,7 +94,7 @@ func (o *bestEffortAtomicProvClass) Provision(
 		return &status.ScaleUpStatus{Result: status.ScaleUpNotTried}, nil
 	}
 	prs := provreqclient.ProvisioningRequestsForPods(o.client, unschedulablePods)
-	prs = provreqclient.FilterOutProvisioningClass(prs, v1.ProvisioningClassBestEffortAtomicScaleUp, "")
+	prs = provreqclient.FilterOutProvisioningClass(prs, o.provisioningClassName, "")
 	if len(prs) == 0 {
 		return &status.ScaleUpStatus{Result: status.ScaleUpNotTried}, nil
 	}
@@ -104,7 +116,8 @@ func (o *bestEffortAtomicProvClass) Provision(
 
 	if len(actuallyUnschedulablePods) == 0 {
 		// Nothing to do here - everything fits without scale-up.
-		conditions.AddOrUpdateCondition(pr, v1.Provisioned, metav1.ConditionTrue, conditions.CapacityIsFoundReason, conditions.CapacityIsFoundMsg, metav1.Now())
+		reason, msg := o.provisionedReasonAndMsg(conditions.CapacityIsFoundReason, conditions.CapacityIsFoundMsg)
+		conditions.AddOrUpdateCondition(pr, v1.Provisioned, metav1.ConditionTrue, reason, msg, metav1.Now())
 		if _, updateErr := o.client.UpdateProvisioningRequest(pr.ProvisioningRequest); updateErr != nil {
 			klog.Errorf("failed to add Provisioned=true condition to ProvReq %s/%s, err: %v", pr.Namespace, pr.Name, updateErr)
 			return status.UpdateScaleUpError(&status.ScaleUpStatus{}, errors.NewAutoscalerErrorf(errors.InternalError, "capacity available, but failed to admit workload: %s", updateErr.Error()))
@@ -115,7 +128,8 @@ func (o *bestEffortAtomicProvClass) Provision(
 	st, err := o.scaleUpOrchestrator.ScaleUp(actuallyUnschedulablePods, nodes, daemonSets, nodeInfos, true)
 	if err == nil && st.Result == status.ScaleUpSuccessful {
 		// Happy path - all is well.
-		conditions.AddOrUpdateCondition(pr, v1.Provisioned, metav1.ConditionTrue, conditions.CapacityIsProvisionedReason, conditions.CapacityIsProvisionedMsg, metav1.Now())
+		reason, msg := o.provisionedReasonAndMsg(conditions.CapacityIsProvisionedReason, conditions.CapacityIsProvisionedMsg)
+		conditions.AddOrUpdateCondition(pr, v1.Provisioned, metav1.ConditionTrue, reason, msg, metav1.Now())
 		if _, updateErr := o.client.UpdateProvisioningRequest(pr.ProvisioningRequest); updateErr != nil {
 			klog.Errorf("failed to add Provisioned=true condition to ProvReq %s/%s, err: %v", pr.Namespace, pr.Name, updateErr)
 			return st, errors.NewAutoscalerErrorf(errors.InternalError, "scale up requested, but failed to admit workload: %s", updateErr.Error())
@@ -134,6 +148,15 @@ func (o *bestEffortAtomicProvClass) Provision(
 	return st, nil
 }
 
+// provisionedReasonAndMsg returns the reason and message of the Provisioned=True condition,
+// which always report reserved capacity for the capacity reservation class.
+func (o *bestEffortAtomicProvClass) provisionedReasonAndMsg(reason, msg string) (string, string) {
+	if o.provisioningClassName == v1.ProvisioningClassCapacityReservation {
+		return conditions.CapacityIsReservedReason, conditions.CapacityIsReservedMsg
+	}
+	return reason, msg
+}
+
 func (o *bestEffortAtomicProvClass) filterOutSchedulable(pods []*apiv1.Pod) ([]*apiv1.Pod, error) {
 	statuses, _, err := o.injector.TrySchedulePods(o.context.ClusterSnapshot, pods, scheduling.ScheduleAnywhere, false)
 	if err != nil {
@@ -40,6 +40,10 @@ const (
 	CapacityIsProvisionedReason = "CapacityIsProvisioned"
 	// CapacityIsProvisionedMsg is added when capacity was requested successfully.
 	CapacityIsProvisionedMsg = "Capacity is found in the cluster"
+	// CapacityIsReservedReason is added when capacity was reserved for a capacity reservation ProvisioningRequest.
+	CapacityIsReservedReason = "CapacityIsReserved"
+	// CapacityIsReservedMsg is added when capacity was reserved for a capacity reservation ProvisioningRequest.
+	CapacityIsReservedMsg = "Capacity is reserved in the cluster until the pods consuming it are scheduled"
 	// FailedToCheckCapacityReason is added when CA failed to check pre-existing capacity.
 	FailedToCheckCapacityReason = "FailedToCheckCapacity"
 	// FailedToCheckCapacityMsg is added when CA failed to check pre-existing capacity.
@@ -52,6 +56,10 @@ const (
 	CapacityReservationTimeExpiredReason = "CapacityReservationTimeExpired"
 	// CapacityReservationTimeExpiredMsg is added if capacity reservation time is expired.
 	CapacityReservationTimeExpiredMsg = "Capacity reservation time is expired"
+	// CapacityReservationConsumedReason is added when all the reserved capacity is used by pods consuming the ProvisioningRequest.
+	CapacityReservationConsumedReason = "CapacityReservationConsumed"
+	// CapacityReservationConsumedMsg is added when all the reserved capacity is used by pods consuming the ProvisioningRequest.
+	CapacityReservationConsumedMsg = "Capacity reservation is consumed by the ProvisioningRequest pods"
 	// ExpiredReason is added if ProvisioningRequest is expired.
 	ExpiredReason = "Expired"
 	// ExpiredMsg is added if ProvisioningRequest is expired.
@@ -148,6 +148,14 @@ func TestScaleUp(t *testing.T) {
 			PodCount: int32(120),
 			Class:    v1.ProvisioningClassBestEffortAtomicScaleUp,
 		})
+	possibleCapacityReservationReq := provreqwrapper.BuildValidTestProvisioningRequestFromOptions(
+		provreqwrapper.TestProvReqOptions{
+			Name:     "possibleCapacityReservationReq",
+			CPU:      "100m",
+			Memory:   "1",
+			PodCount: int32(120),
+			Class:    v1.ProvisioningClassCapacityReservation,
+		})
 	autoprovisioningAtomicScaleUpReq := provreqwrapper.BuildValidTestProvisioningRequestFromOptions(
 		provreqwrapper.TestProvReqOptions{
 			Name:     "autoprovisioningAtomicScaleUpReq",
@@ -272,6 +280,12 @@ func TestScaleUp(t *testing.T) {
 			provReqToScaleUp: possibleAtomicScaleUpReq,
 			scaleUpResult:    status.ScaleUpSuccessful,
 		},
+		{
+			name:             "possible capacity reservation request triggers scale-up",
+			provReqs:         []*provreqwrapper.ProvisioningRequest{possibleCapacityReservationReq},
+			provReqToScaleUp: possibleCapacityReservationReq,
+			scaleUpResult:    status.ScaleUpSuccessful,
+		},
 		{
 			name:             "autoprovisioning atomic scale-up request triggers scale-up",
 			provReqs:         []*provreqwrapper.ProvisioningRequest{autoprovisioningAtomicScaleUpReq},
@@ -518,7 +532,7 @@ func setupTest(t *testing.T, client *provreqclient.ProvisioningRequestClient, no
 
 	orchestrator := &provReqOrchestrator{
 		client:              client,
-		provisioningClasses: []ProvisioningClass{checkcapacity.New(client, injector), besteffortatomic.New(client)},
+		provisioningClasses: []ProvisioningClass{checkcapacity.New(client, injector), besteffortatomic.New(client), besteffortatomic.NewCapacityReservation(client)},
 	}
 
 	orchestrator.Initialize(&autoscalingContext, processors, clusterState, estimatorBuilder, taints.TaintConfig{})
@@ -72,6 +72,17 @@ func TestSupportedProvisioningClass(t *testing.T) {
 			checkCapacityProcessorInstance: "instance",
 			want:                           false,
 		},
+		{
+			name:                  "Capacity reservation",
+			provisioningClassName: v1.ProvisioningClassCapacityReservation,
+			want:                  true,
+		},
+		{
+			name:                           "Capacity reservation with any instance",
+			provisioningClassName:          v1.ProvisioningClassCapacityReservation,
+			checkCapacityProcessorInstance: "instance",
+			want:                           false,
+		},
 		{
 			name:                  "Invalid class name",
 			provisioningClassName: "invalid",
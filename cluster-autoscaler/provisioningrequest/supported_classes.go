@@ -29,13 +29,17 @@ const (
 	// and if not empty, it should match CheckCapacityProcessorInstance defined in CA's options.
 	// Unrecommended: Until CA 1.35, ProvReqs with this value as prefix in their class will be also processed.
 	CheckCapacityProcessorInstanceKey = "processorInstance"
+	// ReservationTTLKey is a key for ProvReq's Parameters.
+	// Value for this key defines, as a duration string (e.g. "30m"), for how long the capacity
+	// provisioned for a capacity reservation ProvReq is held for the pods consuming it.
+	ReservationTTLKey = "reservationTTL"
 )
 
 // SupportedProvisioningClass verifies if the ProvisioningRequest with the given checkCapacityProcessorInstance is supported.
 func SupportedProvisioningClass(pr *v1.ProvisioningRequest, checkCapacityProcessorInstance string) bool {
-	if pr.Spec.ProvisioningClassName == v1.ProvisioningClassBestEffortAtomicScaleUp {
+	if pr.Spec.ProvisioningClassName == v1.ProvisioningClassBestEffortAtomicScaleUp || pr.Spec.ProvisioningClassName == v1.ProvisioningClassCapacityReservation {
 		if checkCapacityProcessorInstance != "" {
-			// If processor instance is set, BestEffortAtomicScaleUp should not be processed.
+			// If processor instance is set, BestEffortAtomicScaleUp and CapacityReservation should not be processed.
 			return false
 		}
 		return true
@@ -0,0 +1,73 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+	"k8s.io/autoscaler/cluster-autoscaler/debuggingsnapshot/replay"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	// replayCommand runs the autoscaler offline against debugging snapshots, in order, then exits:
+	//   cluster-autoscaler replay [--expander=...] [--scale-down-unneeded-time=...] snapshot.json...
+	// It prints the scale-up and scale-down decisions taken on each snapshot (as JSON).
+	replayCommand = "replay"
+
+	replayError = 2
+)
+
+// runReplay replays the given debugging snapshots files, prints the decisions
+// taken on each of them and returns the process exit code.
+func runReplay(autoscalingOpts config.AutoscalingOptions, paths []string) int {
+	if len(paths) == 0 {
+		klog.Errorf("No debugging snapshot to replay, usage: cluster-autoscaler %s [flags] snapshot.json...", replayCommand)
+		return replayError
+	}
+
+	replayer, err := replay.NewReplayer(autoscalingOpts)
+	if err != nil {
+		klog.Errorf("Failed to build replayer: %v", err)
+		return replayError
+	}
+
+	var decisions []*replay.Decisions
+	for _, path := range paths {
+		snapshot, err := replay.LoadSnapshot(path)
+		if err != nil {
+			klog.Errorf("Failed to load debugging snapshot: %v", err)
+			return replayError
+		}
+		d, err := replayer.Replay(path, snapshot)
+		if err != nil {
+			klog.Errorf("Failed to replay debugging snapshot %s: %v", path, err)
+			return replayError
+		}
+		decisions = append(decisions, d)
+	}
+
+	output, err := json.MarshalIndent(decisions, "", "  ")
+	if err != nil {
+		klog.Errorf("Failed to marshal replay decisions: %v", err)
+		return replayError
+	}
+	fmt.Println(string(output))
+	return 0
+}
@@ -95,6 +95,8 @@ const (
 	UnexpectedError
 	// NoNodeInfo - node can't be removed because it doesn't have any node info in the cluster snapshot.
 	NoNodeInfo
+	// OutsideScaleDownWindow - node can't be removed because its node group's scale-down windows don't allow it at the moment.
+	OutsideScaleDownWindow
 )
 
 // RemovalSimulator is a helper object for simulating node removal scenarios.
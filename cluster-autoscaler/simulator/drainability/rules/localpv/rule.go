@@ -0,0 +1,109 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+  Pods bound to local persistent volumes (eg. "local-data" PVs, created by
+  a local volume provisioner on the node's own disks) can't be rescheduled
+  elsewhere without losing their data: the PV is pinned to the node, and goes
+  away with it. The LocalStorage rule only knows about emptyDir and hostPath
+  volumes, so without this rule such nodes are drained like any other.
+
+  This rule blocks the drain of pods using a PVC of one of the configured
+  local storage classes, unless the pod is annotated as holding disposable
+  data (eg. caches rebuilt on startup), with:
+  * cluster-autoscaler.kubernetes.io/local-data-disposable: "true"
+*/
+
+package localpv
+
+import (
+	"fmt"
+	"strconv"
+
+	apiv1 "k8s.io/api/core/v1"
+	kube_errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/datadog/common"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/drainability"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/drain"
+	v1lister "k8s.io/client-go/listers/core/v1"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	// LocalDataDisposableAnnotation marks pods whose local persistent volumes data can be lost on scale down
+	LocalDataDisposableAnnotation = "cluster-autoscaler.kubernetes.io/local-data-disposable"
+)
+
+// Rule is a drainability rule on how to handle pods using local persistent volumes.
+type Rule struct {
+	classes   common.LocalStorageClasses
+	pvcLister v1lister.PersistentVolumeClaimLister
+}
+
+// New creates a new Rule.
+func New(classes common.LocalStorageClasses, pvcLister v1lister.PersistentVolumeClaimLister) *Rule {
+	return &Rule{
+		classes:   classes,
+		pvcLister: pvcLister,
+	}
+}
+
+// Name returns the name of the rule.
+func (r *Rule) Name() string {
+	return "LocalPersistentVolume"
+}
+
+// Drainable decides what to do with pods using local persistent volumes on node drain.
+func (r *Rule) Drainable(_ *drainability.DrainContext, pod *apiv1.Pod, _ *framework.NodeInfo) drainability.Status {
+	if len(r.classes) == 0 || hasDisposableLocalData(pod) {
+		return drainability.NewUndefinedStatus()
+	}
+
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim == nil {
+			continue
+		}
+		claimName := volume.PersistentVolumeClaim.ClaimName
+		pvc, err := r.pvcLister.PersistentVolumeClaims(pod.Namespace).Get(claimName)
+		if kube_errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return drainability.NewBlockedStatus(drain.UnexpectedError, fmt.Errorf("failed to get pvc %s/%s used by pod %s: %v", pod.Namespace, claimName, pod.Name, err))
+		}
+		if pvc.Spec.StorageClassName == nil {
+			continue
+		}
+		if _, found := r.classes[*pvc.Spec.StorageClassName]; found {
+			return drainability.NewBlockedStatus(drain.LocalPersistentVolumeRequested, fmt.Errorf("pod %s uses local persistent volume claim %s of storage class %s", pod.Name, claimName, *pvc.Spec.StorageClassName))
+		}
+	}
+	return drainability.NewUndefinedStatus()
+}
+
+func hasDisposableLocalData(pod *apiv1.Pod) bool {
+	value, found := pod.GetAnnotations()[LocalDataDisposableAnnotation]
+	if !found {
+		return false
+	}
+	disposable, err := strconv.ParseBool(value)
+	if err != nil {
+		klog.Warningf("ignoring invalid %s annotation %q on pod %s/%s: %v", LocalDataDisposableAnnotation, value, pod.Namespace, pod.Name, err)
+		return false
+	}
+	return disposable
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localpv
+
+import (
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/datadog/common"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/clustersnapshot"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/clustersnapshot/testsnapshot"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/drainability"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/drainability/rules"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/options"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/drain"
+	kube_util "k8s.io/autoscaler/cluster-autoscaler/utils/kubernetes"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+	v1lister "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDrainable(t *testing.T) {
+	pvcLister := newTestPVCLister(t,
+		buildTestPVC("data", common.DatadogLocalDataStorageClass),
+		buildTestPVC("nvme", "local-nvme"),
+		buildTestPVC("remote", "gp3"),
+	)
+	classes := common.NewDefaultLocalStorageClasses()
+
+	for desc, test := range map[string]struct {
+		pod     *apiv1.Pod
+		classes common.LocalStorageClasses
+
+		wantReason drain.BlockingPodReason
+		wantError  bool
+	}{
+		"pod without volumes": {
+			pod:     buildTestPod(),
+			classes: classes,
+		},
+		"pod with a local persistent volume": {
+			pod:        buildTestPod("data"),
+			classes:    classes,
+			wantReason: drain.LocalPersistentVolumeRequested,
+			wantError:  true,
+		},
+		"pod with remote and local persistent volumes": {
+			pod:        buildTestPod("remote", "data"),
+			classes:    classes,
+			wantReason: drain.LocalPersistentVolumeRequested,
+			wantError:  true,
+		},
+		"pod with a persistent volume of a non configured class": {
+			pod:     buildTestPod("nvme", "remote"),
+			classes: classes,
+		},
+		"pod with a persistent volume of a configured class": {
+			pod: buildTestPod("nvme"),
+			classes: common.LocalStorageClasses{
+				"local-nvme": &common.LocalStorageClass{StorageClassName: "local-nvme"},
+			},
+			wantReason: drain.LocalPersistentVolumeRequested,
+			wantError:  true,
+		},
+		"pod with a missing persistent volume claim": {
+			pod:     buildTestPod("missing"),
+			classes: classes,
+		},
+		"pod with a local persistent volume and no configured class": {
+			pod: buildTestPod("data"),
+		},
+		"pod with disposable local data": {
+			pod:     withAnnotation(buildTestPod("data"), LocalDataDisposableAnnotation, "true"),
+			classes: classes,
+		},
+		"pod with non disposable local data": {
+			pod:        withAnnotation(buildTestPod("data"), LocalDataDisposableAnnotation, "false"),
+			classes:    classes,
+			wantReason: drain.LocalPersistentVolumeRequested,
+			wantError:  true,
+		},
+		"pod with an invalid disposable annotation": {
+			pod:        withAnnotation(buildTestPod("data"), LocalDataDisposableAnnotation, "maybe"),
+			classes:    classes,
+			wantReason: drain.LocalPersistentVolumeRequested,
+			wantError:  true,
+		},
+	} {
+		t.Run(desc, func(t *testing.T) {
+			status := New(test.classes, pvcLister).Drainable(&drainability.DrainContext{}, test.pod, nil)
+			assert.Equal(t, test.wantReason, status.BlockingReason)
+			assert.Equal(t, test.wantError, status.Error != nil)
+		})
+	}
+}
+
+func TestSimulateNodeRemoval(t *testing.T) {
+	node := BuildTestNode("n1", 1000, 2000000)
+	SetNodeReadyState(node, true, time.Time{})
+	other := BuildTestNode("n2", 1000, 2000000)
+	SetNodeReadyState(other, true, time.Time{})
+
+	replicas := int32(5)
+	rsLister, err := kube_util.NewTestReplicaSetLister([]*appsv1.ReplicaSet{{
+		ObjectMeta: metav1.ObjectMeta{Name: "rs", Namespace: "default"},
+		Spec:       appsv1.ReplicaSetSpec{Replicas: &replicas},
+	}})
+	assert.NoError(t, err)
+	registry := kube_util.NewListerRegistry(nil, nil, nil, nil, nil, nil, nil, rsLister, nil)
+
+	pod := buildTestPod("data")
+	pod.Spec.NodeName = node.Name
+	pod.OwnerReferences = GenerateOwnerReferences("rs", "ReplicaSet", "apps/v1", "")
+
+	deleteOptions := options.NodeDeleteOptions{SkipNodesWithLocalStorage: true}
+	drainabilityRules := append(rules.Default(deleteOptions), New(common.NewDefaultLocalStorageClasses(), newTestPVCLister(t, buildTestPVC("data", common.DatadogLocalDataStorageClass))))
+
+	clusterSnapshot := testsnapshot.NewTestSnapshotOrDie(t)
+	clustersnapshot.InitializeClusterSnapshotOrDie(t, clusterSnapshot, []*apiv1.Node{node, other}, []*apiv1.Pod{pod})
+	r := simulator.NewRemovalSimulator(registry, clusterSnapshot, deleteOptions, drainabilityRules, false)
+
+	toRemove, unremovable := r.SimulateNodeRemoval(node.Name, map[string]bool{other.Name: true}, time.Now(), nil)
+	assert.Nil(t, toRemove)
+	assert.Equal(t, &simulator.UnremovableNode{
+		Node:        node,
+		Reason:      simulator.BlockedByPod,
+		BlockingPod: &drain.BlockingPod{Pod: pod, Reason: drain.LocalPersistentVolumeRequested},
+	}, unremovable)
+
+	// disposable local data doesn't block the node drain
+	pod.Annotations = map[string]string{LocalDataDisposableAnnotation: "true"}
+	clustersnapshot.InitializeClusterSnapshotOrDie(t, clusterSnapshot, []*apiv1.Node{node, other}, []*apiv1.Pod{pod})
+	nodeInfo, err := clusterSnapshot.GetNodeInfo(node.Name)
+	assert.NoError(t, err)
+	podsToMove, _, blockingPod, err := simulator.GetPodsToMove(nodeInfo, deleteOptions, drainabilityRules, registry, nil, time.Now())
+	assert.NoError(t, err)
+	assert.Nil(t, blockingPod)
+	assert.Equal(t, []*apiv1.Pod{pod}, podsToMove)
+}
+
+func buildTestPod(claims ...string) *apiv1.Pod {
+	pod := BuildTestPod("bar", 100, 100000)
+	for _, claim := range claims {
+		pod.Spec.Volumes = append(pod.Spec.Volumes, apiv1.Volume{
+			Name: claim,
+			VolumeSource: apiv1.VolumeSource{
+				PersistentVolumeClaim: &apiv1.PersistentVolumeClaimVolumeSource{ClaimName: claim},
+			},
+		})
+	}
+	return pod
+}
+
+func withAnnotation(pod *apiv1.Pod, key, value string) *apiv1.Pod {
+	pod.Annotations = map[string]string{key: value}
+	return pod
+}
+
+func buildTestPVC(name, storageClass string) *apiv1.PersistentVolumeClaim {
+	return &apiv1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Spec: apiv1.PersistentVolumeClaimSpec{
+			StorageClassName: &storageClass,
+		},
+	}
+}
+
+func newTestPVCLister(t *testing.T, pvcs ...*apiv1.PersistentVolumeClaim) v1lister.PersistentVolumeClaimLister {
+	store := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, pvc := range pvcs {
+		assert.NoError(t, store.Add(pvc))
+	}
+	return v1lister.NewPersistentVolumeClaimLister(store)
+}
@@ -67,6 +67,8 @@ const (
 	NotEnoughPdb
 	// UnexpectedError - pod is blocking scale down because of an unexpected error.
 	UnexpectedError
+	// LocalPersistentVolumeRequested - pod is blocking scale down because its data lives on a local persistent volume bound to the node.
+	LocalPersistentVolumeRequested
 )
 
 func (e BlockingPodReason) String() string {
@@ -89,6 +91,8 @@ func (e BlockingPodReason) String() string {
 		return "NotEnoughPdb"
 	case UnexpectedError:
 		return "UnexpectedError"
+	case LocalPersistentVolumeRequested:
+		return "LocalPersistentVolumeRequested"
 	default:
 		return fmt.Sprintf("unrecognized reason: %d", int(e))
 	}
@@ -0,0 +1,55 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedule
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduleNext(t *testing.T) {
+	for spec, test := range map[string]struct {
+		from string
+		want string
+	}{
+		"* * * * *":          {from: "2026-10-16T10:00:30Z", want: "2026-10-16T10:01:00Z"},
+		"*/15 * * * *":       {from: "2026-10-16T10:01:00Z", want: "2026-10-16T10:15:00Z"},
+		"0 9 * * mon-fri":    {from: "2026-10-16T10:00:00Z", want: "2026-10-19T09:00:00Z"},
+		"30 22 * * 7":        {from: "2026-10-16T10:00:00Z", want: "2026-10-18T22:30:00Z"},
+		"0 0 1 jan *":        {from: "2026-10-16T10:00:00Z", want: "2027-01-01T00:00:00Z"},
+		"0 0 13 * fri":       {from: "2026-10-16T10:00:00Z", want: "2026-10-23T00:00:00Z"},
+		"0 0 29 2 *":         {from: "2026-10-16T10:00:00Z", want: "2028-02-29T00:00:00Z"},
+		"0 8-18/5 * * *":     {from: "2026-10-16T10:00:00Z", want: "2026-10-16T13:00:00Z"},
+		"0,30 12 16,17 10 *": {from: "2026-10-16T12:00:00Z", want: "2026-10-16T12:30:00Z"},
+	} {
+		s, err := ParseSchedule(spec)
+		assert.NoError(t, err, spec)
+		assert.Equal(t, date(test.want), s.Next(date(test.from)), spec)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, spec := range []string{"* * * *", "60 * * * *", "* * * foo *", "5-1 * * * *", "*/0 * * * *"} {
+		_, err := ParseSchedule(spec)
+		assert.Error(t, err, spec)
+	}
+	for _, spec := range []string{"0 9 * * mon-fri 8h", "maybe 0 9 * * * 8h", "deny 0 9 * * * never", "deny 0 9 * * * -8h", "deny 0 9 * * * 8h Mars/Olympus"} {
+		_, err := ParseWindows(spec)
+		assert.Error(t, err, spec)
+	}
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// Allow windows are the only times an action is allowed at, when there's at least one.
+	Allow = "allow"
+	// Deny windows are times an action isn't allowed at.
+	Deny = "deny"
+
+	// maxTransitions bounds the windows starts and ends inspected looking for the next allowed time.
+	maxTransitions = 1000
+)
+
+// Window is a recurring time window, starting on a cron schedule and lasting for a duration.
+type Window struct {
+	spec     string
+	schedule *Schedule
+	duration time.Duration
+	location *time.Location
+	deny     bool
+}
+
+// ParseWindows parses windows separated by semicolons. Each window is made of its kind (allow or deny),
+// a 5 fields cron expression of its starts, its duration and, optionally, the time zone of the cron
+// expression, UTC by default. For instance "deny 0 9 * * mon-fri 8h Europe/Paris" denies business
+// hours in Paris, and "allow 0 0 * * sat 48h" only allows weekends.
+func ParseWindows(spec string) ([]Window, error) {
+	var windows []Window
+	for _, windowSpec := range strings.Split(spec, ";") {
+		if strings.TrimSpace(windowSpec) == "" {
+			continue
+		}
+		window, err := ParseWindow(windowSpec)
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, window)
+	}
+	return windows, nil
+}
+
+// ParseWindow parses a single window, see ParseWindows.
+func ParseWindow(spec string) (Window, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != 7 && len(fields) != 8 {
+		return Window{}, fmt.Errorf("window %q must be \"<allow|deny> <cron expression> <duration> [time zone]\"", spec)
+	}
+	if fields[0] != Allow && fields[0] != Deny {
+		return Window{}, fmt.Errorf("window %q must start with %s or %s", spec, Allow, Deny)
+	}
+	duration, err := time.ParseDuration(fields[6])
+	if err != nil {
+		return Window{}, fmt.Errorf("window %q has an invalid duration %q", spec, fields[6])
+	}
+	location := time.UTC
+	if len(fields) == 8 {
+		if location, err = time.LoadLocation(fields[7]); err != nil {
+			return Window{}, fmt.Errorf("window %q has an invalid time zone: %v", spec, err)
+		}
+	}
+	window, err := NewWindow(strings.Join(fields[1:6], " "), duration, location)
+	if err != nil {
+		return Window{}, fmt.Errorf("window %q is invalid: %v", spec, err)
+	}
+	window.spec = strings.Join(fields, " ")
+	window.deny = fields[0] == Deny
+	return window, nil
+}
+
+// NewWindow returns an allow window starting on the cron expression, in the given location, and lasting for the duration.
+func NewWindow(cron string, duration time.Duration, location *time.Location) (Window, error) {
+	if duration <= 0 {
+		return Window{}, fmt.Errorf("duration %v must be positive", duration)
+	}
+	s, err := ParseSchedule(cron)
+	if err != nil {
+		return Window{}, err
+	}
+	return Window{
+		spec:     fmt.Sprintf("%s %s %v %s", Allow, strings.Join(strings.Fields(cron), " "), duration, location),
+		schedule: s,
+		duration: duration,
+		location: location,
+	}, nil
+}
+
+// String returns the window as parsed.
+func (w Window) String() string {
+	return w.spec
+}
+
+// Active returns whether the window is open at the given time.
+func (w Window) Active(t time.Time) bool {
+	start := w.schedule.Next(t.In(w.location).Add(-w.duration))
+	return !start.IsZero() && !start.After(t)
+}
+
+// NextTransition returns the next time the window opens or closes after the given time, or the zero time if
+// there's none within 5 years.
+func (w Window) NextTransition(t time.Time) time.Time {
+	if w.Active(t) {
+		return w.activeUntil(t)
+	}
+	return w.schedule.Next(t.In(w.location))
+}
+
+// activeUntil returns when the occurrences of the window open at the given time close.
+func (w Window) activeUntil(t time.Time) time.Time {
+	var end time.Time
+	for start := w.schedule.Next(t.In(w.location).Add(-w.duration)); !start.IsZero() && !start.After(t); start = w.schedule.Next(start) {
+		end = start.Add(w.duration)
+	}
+	return end
+}
+
+// Allowed returns whether the windows allow acting at the given time: when no deny window is open and,
+// if there are allow windows, at least one of them is.
+func Allowed(windows []Window, t time.Time) bool {
+	hasAllow, allowed := false, false
+	for _, w := range windows {
+		active := w.Active(t)
+		if w.deny && active {
+			return false
+		}
+		if !w.deny {
+			hasAllow = true
+			allowed = allowed || active
+		}
+	}
+	return !hasAllow || allowed
+}
+
+// NextAllowed returns the first time from the given one the windows allow acting at, and false if
+// there's none within the next windows starts and ends.
+func NextAllowed(windows []Window, t time.Time) (time.Time, bool) {
+	for i := 0; i < maxTransitions; i++ {
+		if Allowed(windows, t) {
+			return t, true
+		}
+		// The windows allow acting again when an allow window opens, or a deny window closes.
+		var next time.Time
+		for _, w := range windows {
+			var transition time.Time
+			if w.deny {
+				transition = w.activeUntil(t)
+			} else {
+				transition = w.schedule.Next(t.In(w.location))
+			}
+			if !transition.IsZero() && transition.After(t) && (next.IsZero() || transition.Before(next)) {
+				next = transition
+			}
+		}
+		if next.IsZero() {
+			return time.Time{}, false
+		}
+		t = next
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func date(value string) time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestWindows(t *testing.T) {
+	for desc, test := range map[string]struct {
+		windows     string
+		at          string
+		allowed     bool
+		nextAllowed string
+	}{
+		"no windows": {
+			at:          "2026-10-16T10:00:00Z",
+			allowed:     true,
+			nextAllowed: "2026-10-16T10:00:00Z",
+		},
+		"during business hours": {
+			windows:     "deny 0 9 * * mon-fri 8h",
+			at:          "2026-10-16T10:00:00Z",
+			nextAllowed: "2026-10-16T17:00:00Z",
+		},
+		"after business hours": {
+			windows:     "deny 0 9 * * mon-fri 8h",
+			at:          "2026-10-16T17:00:00Z",
+			allowed:     true,
+			nextAllowed: "2026-10-16T17:00:00Z",
+		},
+		"business hours in another time zone": {
+			windows:     "deny 0 9 * * mon-fri 8h America/New_York",
+			at:          "2026-10-16T10:00:00Z",
+			allowed:     true,
+			nextAllowed: "2026-10-16T10:00:00Z",
+		},
+		"only on weekends": {
+			windows:     "allow 0 0 * * sat 48h",
+			at:          "2026-10-16T10:00:00Z",
+			nextAllowed: "2026-10-17T00:00:00Z",
+		},
+		"deny windows win over allow windows": {
+			windows:     "allow 0 0 * * sat 48h; deny 0 0 17 10 * 24h",
+			at:          "2026-10-16T10:00:00Z",
+			nextAllowed: "2026-10-18T00:00:00Z",
+		},
+		"overlapping deny windows": {
+			windows:     "deny 0 * * * * 90m",
+			at:          "2026-10-16T10:00:00Z",
+			nextAllowed: "",
+		},
+	} {
+		t.Run(desc, func(t *testing.T) {
+			windows, err := ParseWindows(test.windows)
+			assert.NoError(t, err)
+			assert.Equal(t, test.allowed, Allowed(windows, date(test.at)))
+			next, found := NextAllowed(windows, date(test.at))
+			if test.nextAllowed == "" {
+				assert.False(t, found)
+			} else {
+				assert.True(t, found)
+				assert.True(t, date(test.nextAllowed).Equal(next), "next allowed %v", next)
+			}
+		})
+	}
+}
+
+func TestNextTransition(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+	window, err := NewWindow("0 9 * * mon-fri", 8*time.Hour, newYork)
+	assert.NoError(t, err)
+	for at, next := range map[string]string{
+		// Before business hours, the window opens at 9am in New York.
+		"2026-10-16T12:00:00Z": "2026-10-16T13:00:00Z",
+		// During business hours, it closes 8 hours later.
+		"2026-10-16T14:00:00Z": "2026-10-16T21:00:00Z",
+		// On Friday evening, it opens again on Monday.
+		"2026-10-16T21:00:00Z": "2026-10-19T13:00:00Z",
+	} {
+		assert.True(t, date(next).Equal(window.NextTransition(date(at))), "next transition from %s", at)
+	}
+}
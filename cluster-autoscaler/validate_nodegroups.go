@@ -0,0 +1,105 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	cloudBuilder "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/builder"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+	"k8s.io/autoscaler/cluster-autoscaler/core"
+	ddcommon "k8s.io/autoscaler/cluster-autoscaler/processors/datadog/common"
+	ddvalidation "k8s.io/autoscaler/cluster-autoscaler/processors/datadog/validation"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
+	kube_util "k8s.io/autoscaler/cluster-autoscaler/utils/kubernetes"
+	"k8s.io/client-go/informers"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	// validateNodeGroupsCommand validates the Datadog labels of every node group template, then exits:
+	//   cluster-autoscaler validate-nodegroups --cloud-provider=aws --nodes=... [--local-storage-class=...]
+	// It exits with 1 when templates are wrong (eg. for CI gating), and 2 when validation couldn't run.
+	validateNodeGroupsCommand = "validate-nodegroups"
+
+	validateNodeGroupsFailed = 1
+	validateNodeGroupsError  = 2
+)
+
+// runValidateNodeGroups loads the configured cloud provider, prints the node groups
+// templates validation report (as JSON) and returns the process exit code.
+func runValidateNodeGroups(autoscalingOpts config.AutoscalingOptions) int {
+	classes, err := ddcommon.ParseLocalStorageClasses(autoscalingOpts.LocalStorageClasses)
+	if err != nil {
+		klog.Errorf("Failed to parse local storage classes: %v", err)
+		return validateNodeGroupsError
+	}
+
+	// most cloud providers don't need informers, so a cluster is only required when one is configured;
+	// PodTemplates are only applied to the node groups templates when it is.
+	var informerFactory informers.SharedInformerFactory
+	var opts *core.AutoscalerOptions
+	if autoscalingOpts.KubeClientOpts.KubeConfigPath != "" || autoscalingOpts.KubeClientOpts.Master != "" {
+		kubeClient := kube_util.CreateKubeClient(autoscalingOpts.KubeClientOpts)
+		informerFactory = informers.NewSharedInformerFactory(kubeClient, 0)
+		fwHandle, err := framework.NewHandle(informerFactory, autoscalingOpts.SchedulerConfig, autoscalingOpts.DynamicResourceAllocationEnabled)
+		if err != nil {
+			klog.Errorf("Failed to build scheduler framework: %v", err)
+			return validateNodeGroupsError
+		}
+		opts = &core.AutoscalerOptions{
+			AutoscalingOptions: autoscalingOpts,
+			KubeClient:         kubeClient,
+			InformerFactory:    informerFactory,
+			FrameworkHandle:    fwHandle,
+		}
+	} else if autoscalingOpts.NodeInfosProcessorPodTemplates {
+		klog.Warningf("No cluster configured, PodTemplates won't be applied to node groups templates")
+	}
+
+	cloudProvider := cloudBuilder.NewCloudProvider(autoscalingOpts, informerFactory)
+	if cloudProvider == nil {
+		klog.Errorf("Failed to build %s cloud provider", autoscalingOpts.CloudProviderName)
+		return validateNodeGroupsError
+	}
+	defer cloudProvider.Cleanup()
+	if err := cloudProvider.Refresh(); err != nil {
+		klog.Errorf("Failed to refresh %s cloud provider: %v", autoscalingOpts.CloudProviderName, err)
+		return validateNodeGroupsError
+	}
+
+	if informerFactory != nil {
+		stop := make(chan struct{})
+		defer close(stop)
+		informerFactory.Start(stop)
+		informerFactory.WaitForCacheSync(stop)
+	}
+
+	report := ddvalidation.ValidateNodeGroups(cloudProvider, classes, opts)
+	output, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		klog.Errorf("Failed to marshal validation report: %v", err)
+		return validateNodeGroupsError
+	}
+	fmt.Println(string(output))
+
+	if report.HasErrors() {
+		return validateNodeGroupsFailed
+	}
+	return 0
+}